@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/harekrishnarai/inactivity/pkg/analyzer"
+	"github.com/harekrishnarai/inactivity/pkg/config"
+	"github.com/mattn/go-runewidth"
+)
+
+// Banner levels selectable with -banner
+const (
+	bannerFull    = "full"
+	bannerMinimal = "minimal"
+	bannerNone    = "none"
+)
+
+// banner describes the heading printed when a command starts
+type banner struct {
+	title    string // Single-line title, also used on its own at the minimal level
+	subtitle string // Description printed under the title at the full level
+	art      func() // ASCII art printed before the title at the full level (optional)
+}
+
+var (
+	organizationBanner = banner{
+		title:    "Repository Inactivity Analyzer - Organization Mode",
+		subtitle: "Analyzing repositories across an entire organization",
+		art:      printOrganizationArt,
+	}
+	repositoryBanner = banner{
+		title:    "Repository Inactivity Analyzer",
+		subtitle: "Analyzing a single repository for inactivity metrics",
+		art:      printRepositoryArt,
+	}
+	multipleBanner = banner{
+		title:    "Repository Inactivity Analyzer",
+		subtitle: "Analyzing multiple repositories for inactivity metrics",
+	}
+	forksBanner = banner{
+		title:    "Repository Inactivity Analyzer - Forks Mode",
+		subtitle: "Analyzing the forks of a repository for inactivity metrics",
+	}
+	statsBanner = banner{
+		title:    "Repository Inactivity Analyzer - Organization Stats",
+		subtitle: "Measuring aggregate health across an entire organization",
+	}
+	localBanner = banner{
+		title:    "Repository Inactivity Analyzer - Local Mode",
+		subtitle: "Analyzing a local clone from its git history",
+	}
+	fileBanner = banner{
+		title:    "Repository Inactivity Analyzer - Batch Mode",
+		subtitle: "Processing repositories from file",
+		art:      printFileArt,
+	}
+)
+
+// displayBanner prints a command banner at the configured level unless silent mode is enabled
+// full prints the ASCII art and description, minimal only the title, and none nothing
+func displayBanner(b banner, cfg config.Config) {
+	if cfg.Silent || cfg.Banner == bannerNone {
+		return
+	}
+
+	yellow := color.New(color.FgYellow).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	if cfg.Banner == bannerMinimal {
+		bannerLine(yellow(b.title))
+		return
+	}
+
+	if b.art != nil {
+		b.art()
+	} else {
+		bannerLine()
+	}
+
+	bannerLine(yellow("✦ " + b.title + " ✦"))
+	bannerLine(cyan("⟹ " + b.subtitle))
+	bannerLine()
+}
+
+// bannerLine writes a line of a banner to the log output, so a -log-file receives the banner instead of stdout
+func bannerLine(a ...interface{}) {
+	analyzer.Logf("%s\n", fmt.Sprint(a...))
+}
+
+// Inner widths, in terminal cells, of the boxes drawn by the banner art
+const (
+	organizationBoxWidth = 55
+	repositoryBoxWidth   = 58
+	fileBoxWidth         = 58
+)
+
+// artSegment is a piece of text in a banner row with the color it is painted in (nil for none)
+type artSegment struct {
+	text  string
+	paint func(a ...interface{}) string
+}
+
+// artText builds a banner row segment
+func artText(text string, paint func(a ...interface{}) string) artSegment {
+	return artSegment{text: text, paint: paint}
+}
+
+// artRow renders the segments of a banner row padded to width terminal cells
+// Widths are measured in cells rather than runes, since emoji and some symbols take two cells
+func artRow(width int, segments ...artSegment) string {
+	var row strings.Builder
+	cells := 0
+	for _, segment := range segments {
+		if segment.paint != nil {
+			row.WriteString(segment.paint(segment.text))
+		} else {
+			row.WriteString(segment.text)
+		}
+		cells += runewidth.StringWidth(segment.text)
+	}
+	if cells < width {
+		row.WriteString(strings.Repeat(" ", width-cells))
+	}
+	return row.String()
+}
+
+// printOrganizationArt prints the organization analysis banner art
+func printOrganizationArt() {
+	cyan := color.New(color.FgCyan).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	purple := color.New(color.FgMagenta).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+	white := color.New(color.FgHiWhite, color.Bold).SprintFunc()
+
+	// Print a creative organization analysis banner
+	bannerLine()
+	bannerLine(red("  ╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱"))
+	bannerLine(yellow(" ╱    ") + white("ORGANIZATION HEALTH MONITOR") + yellow("                            ╱"))
+	bannerLine(green("╱                                                         ╱"))
+	bannerLine(cyan("╱") + blue("  ┌───────────────────────────────────────────────────────┐") + cyan(" ╱"))
+	bannerLine(cyan("╱") + blue("  │") + artRow(organizationBoxWidth) + blue("│") + cyan(" ╱"))
+	bannerLine(cyan("╱") + blue("  │") + artRow(organizationBoxWidth, artText("  ", nil), artText("◉", red), artText(" ORGANIZATION PORTFOLIO ANALYZER ", white), artText("◉", red)) + blue("│") + cyan(" ╱"))
+	bannerLine(cyan("╱") + blue("  │") + artRow(organizationBoxWidth) + blue("│") + cyan(" ╱"))
+	bannerLine(cyan("╱") + blue("  │") + artRow(organizationBoxWidth, artText("  ", nil), artText("⚡", purple), artText(" Scanning All Repositories", green)) + blue("│") + cyan(" ╱"))
+	bannerLine(cyan("╱") + blue("  │") + artRow(organizationBoxWidth, artText("  ", nil), artText("⚡", yellow), artText(" Detecting Inactive Projects", green)) + blue("│") + cyan(" ╱"))
+	bannerLine(cyan("╱") + blue("  │") + artRow(organizationBoxWidth, artText("  ", nil), artText("⚡", cyan), artText(" Analyzing Contributor Engagement", green)) + blue("│") + cyan(" ╱"))
+	bannerLine(cyan("╱") + blue("  │") + artRow(organizationBoxWidth) + blue("│") + cyan(" ╱"))
+	bannerLine(cyan("╱") + blue("  │") + artRow(organizationBoxWidth, artText("  ", nil), artText("REPO·PULSE ENTERPRISE", white)) + blue("│") + cyan(" ╱"))
+	bannerLine(cyan("╱") + blue("  │") + artRow(organizationBoxWidth) + blue("│") + cyan(" ╱"))
+	bannerLine(cyan("╱") + blue("  └───────────────────────────────────────────────────────┘") + cyan(" ╱"))
+	bannerLine(green("╱                                                         ╱"))
+	bannerLine(yellow("╱                                                         ╱"))
+	bannerLine(red("╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱"))
+	bannerLine()
+}
+
+// printRepositoryArt prints the single repository analysis banner art
+func printRepositoryArt() {
+	cyan := color.New(color.FgCyan).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	purple := color.New(color.FgMagenta).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+	white := color.New(color.FgHiWhite, color.Bold).SprintFunc()
+	brightGreen := color.New(color.FgHiGreen).SprintFunc()
+
+	// Print a creative ASCII art banner
+	bannerLine()
+	bannerLine(blue("╔══════════════════════════════════════════════════════════╗"))
+	bannerLine(blue("║") + artRow(repositoryBoxWidth) + blue("║"))
+	bannerLine(blue("║") + artRow(repositoryBoxWidth, artText("  ", nil), artText("   ██████╗ ███████╗██████╗  ██████╗     ██████╗ ██╗   ", red)) + blue("║"))
+	bannerLine(blue("║") + artRow(repositoryBoxWidth, artText("  ", nil), artText("   ██╔══██╗██╔════╝██╔══██╗██╔═══██╗    ██╔══██╗██║   ", brightGreen)) + blue("║"))
+	bannerLine(blue("║") + artRow(repositoryBoxWidth, artText("  ", nil), artText("   ██████╔╝█████╗  ██████╔╝██║   ██║    ██████╔╝██║   ", yellow)) + blue("║"))
+	bannerLine(blue("║") + artRow(repositoryBoxWidth, artText("  ", nil), artText("   ██╔══██╗██╔══╝  ██╔═══╝ ██║   ██║    ██╔═══╝ ██║   ", purple)) + blue("║"))
+	bannerLine(blue("║") + artRow(repositoryBoxWidth, artText("  ", nil), artText("   ██║  ██║███████╗██║     ╚██████╔╝    ██║     ███████╗", cyan)) + blue("║"))
+	bannerLine(blue("║") + artRow(repositoryBoxWidth, artText("  ", nil), artText("   ╚═╝  ╚═╝╚══════╝╚═╝      ╚═════╝     ╚═╝     ╚══════╝", white)) + blue("║"))
+	bannerLine(blue("║") + artRow(repositoryBoxWidth) + blue("║"))
+	bannerLine(blue("║") + artRow(repositoryBoxWidth, artText("  ", nil), artText("⚡", purple), artText(" PULSE MONITOR", white), artText(" ⋮ ", cyan), artText("REPOSITORY ANALYZER", yellow), artText(" ⚡", purple)) + blue("║"))
+	bannerLine(blue("║") + artRow(repositoryBoxWidth, artText("  ", nil), artText("  Single Repository Health & Activity Scanner", green)) + blue("║"))
+	bannerLine(blue("║") + artRow(repositoryBoxWidth) + blue("║"))
+	bannerLine(blue("╚══════════════════════════════════════════════════════════╝"))
+	bannerLine()
+}
+
+// printFileArt prints the file-based analysis banner art
+func printFileArt() {
+	cyan := color.New(color.FgCyan).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	purple := color.New(color.FgMagenta).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+	white := color.New(color.FgHiWhite, color.Bold).SprintFunc()
+
+	// Print a creative file analysis banner
+	bannerLine()
+	bannerLine(blue("┏━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━┓"))
+	bannerLine(blue("┃") + artRow(fileBoxWidth) + blue("┃"))
+	bannerLine(blue("┃") + artRow(fileBoxWidth, artText("   ", nil), artText("📋", purple), artText(" BATCH REPOSITORY ANALYZER ", white), artText("📋", purple)) + blue("┃"))
+	bannerLine(blue("┃") + artRow(fileBoxWidth) + blue("┃"))
+	bannerLine(blue("┃") + artRow(fileBoxWidth, artText("   ", nil), artText("🔍", yellow), artText(" ", nil), artText("Processing multiple repositories from file", green)) + blue("┃"))
+	bannerLine(blue("┃") + artRow(fileBoxWidth, artText("   ", nil), artText("📊", red), artText(" ", nil), artText("Analyzing contributor activity and commit freshness", cyan)) + blue("┃"))
+	bannerLine(blue("┃") + artRow(fileBoxWidth, artText("   ", nil), artText("📦", green), artText(" ", nil), artText("Identifying stale and abandoned repositories", yellow)) + blue("┃"))
+	bannerLine(blue("┃") + artRow(fileBoxWidth) + blue("┃"))
+	bannerLine(blue("┃") + artRow(fileBoxWidth, artText("   ", nil), artText("REPO·PULSE", white), artText(" ", nil), artText("※", purple), artText(" ", nil), artText("VERSION 2025", white)) + blue("┃"))
+	bannerLine(blue("┃") + artRow(fileBoxWidth) + blue("┃"))
+	bannerLine(blue("┗━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━┛"))
+	bannerLine()
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/analyzer"
+	"github.com/harekrishnarai/inactivity/pkg/config"
+	"github.com/mattn/go-runewidth"
+)
+
+// ansiEscape matches the color escape sequences painted into banner rows
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// captureLines returns the lines print writes to the log output
+func captureLines(t *testing.T, print func()) []string {
+	t.Helper()
+	var buf bytes.Buffer
+	output := analyzer.LogOutput()
+	analyzer.SetLogOutput(&buf)
+	defer analyzer.SetLogOutput(output)
+	print()
+	return strings.Split(buf.String(), "\n")
+}
+
+func TestArtRow(t *testing.T) {
+	cyan := func(a ...interface{}) string { return "\x1b[36m" + fmt.Sprint(a...) + "\x1b[0m" }
+
+	tests := []struct {
+		name     string
+		width    int
+		segments []artSegment
+		want     string
+	}{
+		{"ascii", 10, []artSegment{artText("║ abc", nil)}, "║ abc     "},
+		{"emoji take two cells", 10, []artSegment{artText("║ 📊 ab", nil)}, "║ 📊 ab   "},
+		{"colors take none", 10, []artSegment{artText("║ ", nil), artText("🚩 x", cyan)}, "║ 🚩 x    "},
+		{"full width", 4, []artSegment{artText("界界", nil)}, "界界"},
+		{"overflow left as is", 2, []artSegment{artText("abcd", nil)}, "abcd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := artRow(tt.width, tt.segments...)
+			if plain := ansiEscape.ReplaceAllString(got, ""); plain != tt.want {
+				t.Errorf("artRow = %q, want %q", plain, tt.want)
+			}
+		})
+	}
+}
+
+func TestBannerArtAligned(t *testing.T) {
+	// Every row of a banner box ends at the same terminal cell
+	for name, art := range map[string]func(){
+		"organization": printOrganizationArt,
+		"repository":   printRepositoryArt,
+		"file":         printFileArt,
+	} {
+		t.Run(name, func(t *testing.T) {
+			width := -1
+			for _, line := range captureLines(t, art) {
+				line = ansiEscape.ReplaceAllString(line, "")
+				if !strings.ContainsAny(line, "│┌└║╔╚┃┏┗") {
+					continue
+				}
+				if width < 0 {
+					width = runewidth.StringWidth(line)
+				}
+				if n := runewidth.StringWidth(line); n != width {
+					t.Errorf("line %q is %d cells wide, want %d", line, n, width)
+				}
+			}
+			if width < 0 {
+				t.Error("no box rows printed")
+			}
+		})
+	}
+}
+
+func TestDisplayBanner(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Config
+		want    []string
+		wantNot []string
+	}{
+		{"full", config.Config{Banner: bannerFull}, []string{"PULSE MONITOR", "✦ Repository Inactivity Analyzer ✦", "⟹ Analyzing a single repository"}, nil},
+		{"full by default", config.Config{}, []string{"PULSE MONITOR", "⟹ Analyzing a single repository"}, nil},
+		{"minimal", config.Config{Banner: bannerMinimal}, []string{"Repository Inactivity Analyzer"}, []string{"PULSE MONITOR", "✦", "⟹"}},
+		{"none", config.Config{Banner: bannerNone}, nil, []string{"Repository Inactivity Analyzer"}},
+		{"silent", config.Config{Banner: bannerFull, Silent: true}, nil, []string{"Repository Inactivity Analyzer"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := strings.Join(captureLines(t, func() { displayBanner(repositoryBanner, tt.cfg) }), "\n")
+			out = ansiEscape.ReplaceAllString(out, "")
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("banner does not contain %q:\n%s", want, out)
+				}
+			}
+			for _, unwanted := range tt.wantNot {
+				if strings.Contains(out, unwanted) {
+					t.Errorf("banner contains %q:\n%s", unwanted, out)
+				}
+			}
+		})
+	}
+}
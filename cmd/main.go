@@ -1,18 +1,24 @@
 package cmd
 
 import (
-	"bufio"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/harekrishnarai/inactivity/pkg/analyzer"
 	"github.com/harekrishnarai/inactivity/pkg/config"
+	"github.com/harekrishnarai/inactivity/pkg/email"
+	"github.com/harekrishnarai/inactivity/pkg/githubapp"
+	"github.com/harekrishnarai/inactivity/pkg/version"
 )
 
 // Main is the entry point for the application
@@ -29,144 +35,243 @@ func Main() {
 		MaxCommitAgeInDays:       180,
 		InactiveContribThreshold: 0.5,
 		OutputFormat:             "console",
+		ContributorScope:         "repo",
+		Visibility:               "all",
+		MarkdownStyle:            "table",
+		MembershipFallback:       "unknown",
+		API:                      "rest",
+		Banner:                   "full",
+		SMTPPort:                 587,
+		CacheTTL:                 time.Hour,
+		RepoListCacheTTL:         24 * time.Hour,
+		RepoCacheContributorTTL:  7 * 24 * time.Hour,
+		Heartbeat:                30 * time.Second,
+		LifecycleActiveDays:      30,
+		LifecycleAbandonedDays:   365,
 	}
 
-	// Define common flags for all commands
-	commonFlags := flag.NewFlagSet("common", flag.ExitOnError)
+	// Define the flags shared by all commands, parsed the same way whatever the command
+	commonFlags := flag.NewFlagSet("inactivity "+os.Args[1], flag.ExitOnError)
+	commonFlags.StringVar(&cfg.Organization, "org", "", "GitHub organization to analyze (org and stats commands)")
 	commonFlags.IntVar(&cfg.MaxCommitAgeInDays, "days", 180, "Maximum age of last commit in days")
+	commonFlags.IntVar(&cfg.ContributorDays, "contributor-days", 0, "Days without a commit before a contributor counts as inactive in the org scope (default: -days)")
 	commonFlags.Float64Var(&cfg.InactiveContribThreshold, "threshold", 0.5, "Threshold of inactive contributors (0.0-1.0)")
-	commonFlags.StringVar(&cfg.OutputFormat, "format", "console", "Output format: console, json, or csv")
-	commonFlags.StringVar(&cfg.OutputFile, "output", "", "Output file path (optional)")
-	commonFlags.BoolVar(&cfg.Silent, "silent", false, "Suppress banner and progress output") // Process command
-	switch os.Args[1] {
-	case "org":
-		// The original functionality: analyze an organization's repositories
-		orgCmd := flag.NewFlagSet("org", flag.ExitOnError)
-		orgCmd.StringVar(&cfg.Organization, "org", "", "GitHub organization to analyze")
-
-		// Add common flags to org command
-		orgCmd.IntVar(&cfg.MaxCommitAgeInDays, "days", 180, "Maximum age of last commit in days")
-		orgCmd.Float64Var(&cfg.InactiveContribThreshold, "threshold", 0.5, "Threshold of inactive contributors (0.0-1.0)")
-		orgCmd.StringVar(&cfg.OutputFormat, "format", "console", "Output format: console, json, or csv")
-		orgCmd.StringVar(&cfg.OutputFile, "output", "", "Output file path (optional)")
-		orgCmd.BoolVar(&cfg.Silent, "silent", false, "Suppress banner and progress output") // Parse org command flags only once
-		if err := orgCmd.Parse(os.Args[2:]); err != nil {
-			log.Fatalf("❌ Failed to parse org command flags: %v", err)
+	commonFlags.StringVar(&cfg.OutputFormat, "format", "console", "Output format: console, json, ndjson, csv, table, markdown, or task-list")
+	commonFlags.StringVar(&cfg.OutputFile, "output", "", "Output file path, or an s3:// or gs:// URL to upload to (optional)")
+	commonFlags.StringVar(&cfg.MarkdownStyle, "markdown-style", "table", "Markdown layout: table, or details for a collapsible block per flagged repository")
+	commonFlags.BoolVar(&cfg.GroupByReason, "group-by-reason", false, "Section the flagged repositories of the text and Markdown reports by flag reason")
+	commonFlags.BoolVar(&cfg.DetectDuplicates, "detect-duplicates", false, "Report flagged repositories whose names suggest copies of the same project")
+	commonFlags.BoolVar(&cfg.StreamOutput, "stream-output", false, "Write each repository to the -output file as soon as it is analyzed (csv and ndjson formats)")
+	commonFlags.BoolVar(&cfg.Redact, "redact", false, "Replace organization, repository, and user names in the report with stable pseudonyms")
+	commonFlags.StringVar(&cfg.RedactMap, "redact-map", "redaction-map.json", "Local file recording the pseudonyms of -redact, to de-anonymize reports")
+	commonFlags.BoolVar(&cfg.Silent, "silent", false, "Suppress banner and progress output")
+	commonFlags.DurationVar(&cfg.Heartbeat, "heartbeat", 30*time.Second, "How often progress is logged when the log is not a terminal (0 disables)")
+	commonFlags.DurationVar(&cfg.Deadline, "deadline", 0, "Stop starting repositories after this total run time and report the partial results (0 disables)")
+	commonFlags.StringVar(&cfg.LogFile, "log-file", "", "Write the banner, progress, warnings, and skip notices to this file instead of the terminal; the report, including stats and trend console output, stays on stdout (optional)")
+	commonFlags.StringVar(&cfg.CPUProfile, "cpuprofile", "", "Write a pprof CPU profile of the run to this file (optional)")
+	commonFlags.StringVar(&cfg.Trace, "trace", "", "Write a runtime execution trace of the run to this file, for go tool trace (optional)")
+	commonFlags.StringVar(&cfg.Banner, "banner", "full", "Banner to show: full, minimal (single-line title), or none")
+	commonFlags.BoolVar(&cfg.Humanize, "humanize", false, "Show ages as relative times (e.g. about 6 months ago) in human-readable output")
+	commonFlags.Func("as-of", "Analyze as of this date (2006-01-02) or RFC 3339 timestamp instead of now", func(value string) error {
+		asOf, err := analyzer.ParseAsOf(value)
+		if err != nil {
+			return err
 		}
-		// Check for positional arguments
-		if orgCmd.NArg() > 0 {
-			// First positional argument could be the format
-			if orgCmd.NArg() >= 1 {
-				if orgCmd.Arg(0) == "json" || orgCmd.Arg(0) == "csv" || orgCmd.Arg(0) == "console" {
-					cfg.OutputFormat = orgCmd.Arg(0)
-				}
-			}
-
-			// Check for output as a separate positional argument
-			for i := 0; i < orgCmd.NArg(); i++ {
-				if orgCmd.Arg(i) == "-output" && i+1 < orgCmd.NArg() {
-					cfg.OutputFile = orgCmd.Arg(i + 1)
-					break
-				}
+		cfg.AsOf = asOf
+		return nil
+	})
+	commonFlags.BoolVar(&cfg.Strict, "strict", false, "Stop at the first per-repository error instead of skipping it")
+	commonFlags.IntVar(&cfg.Concurrency, "concurrency", 1, "Number of repositories analyzed at a time")
+	commonFlags.StringVar(&cfg.AdminOf, "admin-of", "", "Analyze only the organization repositories this user (or @me) has admin permission on")
+	commonFlags.StringVar(&cfg.Visibility, "visibility", "all", "Repository visibility to analyze in an organization: public, private, or all")
+	commonFlags.StringVar(&cfg.ContributorScope, "contributor-scope", "repo", "Where contributor activity is measured: repo (org membership) or org (recent commits across the org)")
+	commonFlags.StringVar(&cfg.Path, "path", "", "Analyze only the commits touching this file path or subdirectory (repo and file commands)")
+	commonFlags.BoolVar(&cfg.PathContributors, "path-contributors", false, "Count only the authors of commits under -path as contributors")
+	commonFlags.StringVar(&cfg.MembershipFallback, "membership-fallback", "unknown", "When org membership is not visible to you: unknown (report contributor data as unavailable) or public (check public membership only)")
+	commonFlags.StringVar(&cfg.API, "api", "rest", "API used to check org membership: rest (one call per contributor), graphql (batched), or members (member list fetched once); graphql switches to rest for the rest of the run once a query fails, and members checks each contributor over rest when the member list cannot be listed")
+	commonFlags.Func("metrics", analyzer.MetricsHelp(), func(value string) error {
+		metrics, err := analyzer.ParseMetrics(value)
+		if err != nil {
+			return err
+		}
+		cfg.Metrics = metrics
+		return nil
+	})
+	commonFlags.BoolVar(&cfg.SubstantiveCommits, "substantive-commits", false, "Report the last commit that is neither a merge nor made by a bot")
+	commonFlags.BoolVar(&cfg.FlagOnSubstantiveCommit, "flag-on-substantive", false, "Measure repository age from the last substantive commit when flagging")
+	commonFlags.BoolVar(&cfg.SignedCommits, "signed-commits", false, "Report the share of recent commits with a verified signature")
+	commonFlags.Float64Var(&cfg.MinSignedRatio, "min-signed-ratio", 0, "Mark flagged repositories signing fewer recent commits for security review (0.0-1.0, 0 disables)")
+	commonFlags.BoolVar(&cfg.WeightedContributors, "weighted-contributors", false, "Weigh each contributor by their commits in the last year when comparing inactive contributors with -threshold")
+	commonFlags.BoolVar(&cfg.CheckSuspended, "check-suspended", false, "Count contributors whose account is suspended as inactive, even if they are still members")
+	commonFlags.BoolVar(&cfg.ContributorDetails, "contributor-details", false, "List inactive contributors with their last commit to the repository")
+	commonFlags.StringVar(&cfg.ContributorMap, "contributor-map", "", "File merging contributor aliases and dropping service accounts before contributors are counted (optional)")
+	commonFlags.BoolVar(&cfg.GroupContributors, "group-contributors", false, "Group the inactive contributors of flagged repositories by how long ago they last committed (console output)")
+	commonFlags.StringVar(&cfg.ContributorReport, "contributor-report", "", "Write one row per contributor across all analyzed repositories, with whether they left, to a CSV or .json file (optional)")
+	commonFlags.IntVar(&cfg.MinContributors, "min-contributors", 0, "Do not flag repositories with fewer total contributors (0 disables)")
+	commonFlags.IntVar(&cfg.MinInactiveCount, "min-inactive-count", 0, "Flag on inactive contributors only when at least N are inactive, besides -threshold (0 disables)")
+	commonFlags.IntVar(&cfg.MinRepoAgeDays, "min-repo-age", 0, "Do not flag repositories created fewer days ago (0 disables)")
+	commonFlags.BoolVar(&cfg.DropSmallRepos, "drop-small-repos", false, "Remove repositories below -min-contributors from the report entirely")
+	commonFlags.BoolVar(&cfg.MinContributorsIncludeArchived, "min-contributors-archived", false, "Apply -min-contributors to archived repositories too")
+	commonFlags.IntVar(&cfg.Top, "top", 0, "List only the N most inactive repositories, keeping the totals of all (0 lists all)")
+	commonFlags.Func("fields", "Comma-separated JSON field names to include in JSON/CSV output (e.g. name,daysSinceLastCommit,flagged)", func(value string) error {
+		fields, err := analyzer.ParseFields(value)
+		if err != nil {
+			return err
+		}
+		cfg.Fields = fields
+		return nil
+	})
+	commonFlags.StringVar(&cfg.RepoListCache, "repo-list-cache", "", "File caching the organization's repository list between runs (optional)")
+	commonFlags.StringVar(&cfg.RepoCache, "repo-cache", "", "File caching each repository's commit and contributor data until it is pushed to, or its contributor data is older than -repo-cache-contributor-ttl (optional)")
+	commonFlags.DurationVar(&cfg.RepoListCacheTTL, "repo-list-ttl", 24*time.Hour, "How long the cached repository list is reused")
+	commonFlags.DurationVar(&cfg.RepoCacheContributorTTL, "repo-cache-contributor-ttl", 7*24*time.Hour, "How long -repo-cache reuses contributor data before checking membership again, even without a push (0 reuses it until the next push)")
+	commonFlags.StringVar(&cfg.EmitScript, "emit-script", "", "Write a reviewable shell script to archive, delete, or transfer the flagged repositories instead of the report")
+	commonFlags.BoolVar(&cfg.PublishStatus, "publish-status", false, "Publish each result as a check run or commit status on the repository (requires write access)")
+	commonFlags.StringVar(&cfg.TrackingIssueRepo, "create-tracking-issue", "", "File the flagged repositories as a checklist issue in this repository (org/repo), updating it on later runs")
+	commonFlags.StringVar(&cfg.UpdateComment, "update-comment", "", "Replace the body of this issue comment (URL or org/repo#issuecomment-ID) with the Markdown report (optional)")
+	commonFlags.BoolVar(&cfg.DryRun, "dry-run", false, "Log the changes that would be made to repositories without making them")
+	commonFlags.BoolVar(&cfg.DumpConfig, "dump-config", false, "Print the effective configuration as JSON and exit")
+	commonFlags.BoolVar(&cfg.RefreshRepoList, "refresh-repo-list", false, "Re-fetch the repository list even if the cache is fresh")
+	commonFlags.StringVar(&cfg.InputFormat, "input-format", "", "Repository list format for the file command: lines or csv (default: csv for .csv files)")
+	commonFlags.StringVar(&cfg.RepoColumn, "repo-column", "", "CSV column holding the repository, by header name or 1-based index (default: first column)")
+	commonFlags.Func("extra-columns", "Comma-separated CSV columns to carry through to the report (e.g. team,owner)", func(value string) error {
+		cfg.ExtraColumns = nil
+		for _, column := range strings.Split(value, ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				cfg.ExtraColumns = append(cfg.ExtraColumns, column)
 			}
 		}
+		return nil
+	})
+	commonFlags.StringVar(&cfg.StateFile, "state", "", "State file used to show changes since the previous run (optional)")
+	commonFlags.BoolVar(&cfg.ShowAdmins, "show-admins", false, "List collaborators with admin permission for flagged repositories")
+	commonFlags.StringVar(&cfg.OwnersMap, "owners-map", "", "File mapping repositories and @org/teams to a contact, added to flagged repositories (optional)")
+	commonFlags.BoolVar(&cfg.AbortOnInsufficientQuota, "abort-on-insufficient-quota", false, "Abort before scanning if the remaining API quota looks too low to finish")
+	commonFlags.DurationVar(&cfg.CacheTTL, "cache-ttl", time.Hour, "How long gh caches read-only API responses (0 disables caching)")
+	commonFlags.StringVar(&cfg.GHPath, "gh-path", os.Getenv("GH_PATH"), "Path to the gh binary to run instead of the gh in PATH (default: $GH_PATH)")
+	commonFlags.IntVar(&cfg.ProgressFD, "progress-fd", 0, "File descriptor to receive JSON progress events (optional)")
+	commonFlags.BoolVar(&cfg.PrioritizeUnlicensed, "prioritize-unlicensed", false, "Mark flagged repositories without a license as high priority")
+	commonFlags.BoolVar(&cfg.Readme, "readme", false, "Report whether each repository has a README and its size")
+	commonFlags.BoolVar(&cfg.PrioritizeUndocumented, "prioritize-undocumented", false, "Mark flagged repositories without a README as high priority")
+	commonFlags.BoolVar(&cfg.CIStatus, "ci-status", false, "Report the status and date of the latest GitHub Actions run")
+	commonFlags.BoolVar(&cfg.FlagBrokenCI, "flag-broken-ci", false, "Flag old repositories whose CI is absent, failing, or older than -days")
+	commonFlags.BoolVar(&cfg.MergedPRs, "merged-prs", false, "Report when the last pull request was merged")
+	commonFlags.BoolVar(&cfg.FlagStaleReviews, "flag-stale-reviews", false, "Flag repositories whose last merged pull request is older than -days, even with recent commits")
+	commonFlags.BoolVar(&cfg.Engagement, "engagement", false, "Report the median time to the first maintainer response on recent issues (API-heavy)")
+	commonFlags.BoolVar(&cfg.FlagUnresponsive, "flag-unresponsive", false, "Flag repositories whose maintainers respond slower than -max-response-hours or leave most recent issues unanswered")
+	commonFlags.Float64Var(&cfg.MaxResponseHours, "max-response-hours", 168, "Longest acceptable median time to first maintainer response, in hours")
+	commonFlags.BoolVar(&cfg.RecentTags, "recent-tags", false, "Treat repositories whose newest tag points to a commit within -days as active")
+	commonFlags.BoolVar(&cfg.ActiveBranches, "active-branches", false, "Treat repositories with a commit within -days on any other branch as active")
+	commonFlags.BoolVar(&cfg.Momentum, "momentum", false, "Report commits in the last 90 days minus commits in the 90 days before")
+	commonFlags.BoolVar(&cfg.FlagDeclining, "flag-declining", false, "Flag repositories whose commit momentum dropped by at least -declining-momentum commits")
+	commonFlags.IntVar(&cfg.DecliningMomentum, "declining-momentum", 10, "Minimum drop in commits between the two 90-day windows flagged as declining")
+	commonFlags.BoolVar(&cfg.Cadence, "cadence", false, "Report the median gap between consecutive recent commits")
+	commonFlags.BoolVar(&cfg.FlagOnCadence, "flag-on-cadence", false, "Count repositories as old only past both -days and -cadence-multiplier times their median commit gap")
+	commonFlags.Float64Var(&cfg.CadenceMultiplier, "cadence-multiplier", 3, "Multiple of its median commit gap a repository may go without commits with -flag-on-cadence")
+	commonFlags.BoolVar(&cfg.BotPRs, "bot-prs", false, "Count the open pull requests opened by bots more than -days ago, such as unmerged dependency updates")
+	commonFlags.BoolVar(&cfg.FlagStaleBotPRs, "flag-bot-prs", false, "Flag repositories with more than -max-stale-bot-prs stale open bot pull requests, even with recent commits")
+	commonFlags.IntVar(&cfg.MaxStaleBotPRs, "max-stale-bot-prs", 10, "Most open bot pull requests older than -days tolerated before flagging")
+	commonFlags.BoolVar(&cfg.Security, "security", false, "Count open Dependabot alerts and mark flagged repositories carrying them as urgent")
+	commonFlags.BoolVar(&cfg.Governance, "governance", false, "Flag old repositories with issues disabled and show governance settings, visibility, and outside collaborators")
+	commonFlags.BoolVar(&cfg.Lifecycle, "lifecycle", false, "Classify each repository as active, maintenance, stale, abandoned, or archived, with counts per stage")
+	commonFlags.IntVar(&cfg.LifecycleActiveDays, "lifecycle-active-days", 30, "Most days since the last commit of an active repository")
+	commonFlags.IntVar(&cfg.LifecycleStaleDays, "lifecycle-stale-days", 0, "Days since the last commit past which a repository is stale rather than in maintenance (default: -days)")
+	commonFlags.IntVar(&cfg.LifecycleAbandonedDays, "lifecycle-abandoned-days", 365, "Days since the last commit past which a stale repository is abandoned, even with its contributors around")
+	commonFlags.StringVar(&cfg.ExecHook, "exec-hook", "", "Shell command receiving the JSON report on stdin after analysis (optional)")
+	commonFlags.StringVar(&cfg.EmailTo, "email-to", "", "Comma-separated recipients to email the report to (optional)")
+	commonFlags.StringVar(&cfg.EmailFrom, "email-from", "", "Sender address for the emailed report")
+	commonFlags.StringVar(&cfg.SMTPHost, "smtp-host", "", "SMTP server host for the emailed report")
+	commonFlags.IntVar(&cfg.SMTPPort, "smtp-port", 587, "SMTP server port")
+	commonFlags.StringVar(&cfg.SMTPUsername, "smtp-user", "", "SMTP username (optional)")
+	commonFlags.StringVar(&cfg.SMTPPassword, "smtp-password", "", "SMTP password (defaults to the SMTP_PASSWORD environment variable)")
+	commonFlags.Int64Var(&cfg.AppID, "app-id", 0, "GitHub App ID used to authenticate (optional)")
+	commonFlags.Int64Var(&cfg.InstallationID, "installation-id", 0, "GitHub App installation ID")
+	commonFlags.StringVar(&cfg.PrivateKeyFile, "private-key", "", "Path to the GitHub App private key (PEM)")
+	commonFlags.StringVar(&cfg.CredentialsFile, "credentials", "", "JSON file mapping repository owners to tokens (optional)")
+
+	// Process command
+	switch os.Args[1] {
+	case "org":
+		// The original functionality: analyze an organization's repositories
+		// The organization may be given with -org or as the only positional argument
+		args := parseCommandArgs(commonFlags, os.Args[2:])
+		expectArgs("org", args, 0, 1, "inactivity org [org-name] [options]")
+		setOrganization(&cfg, args, "org")
 
 		// Run the organization analysis
+		rejectPath(cfg, "org")
 		analyzeOrganization(cfg)
 
 	case "repo":
-		// New functionality: analyze a single repository
-		repoCmd := flag.NewFlagSet("repo", flag.ExitOnError)
-
-		// Parse repo command flags
-		if len(os.Args) < 3 {
-			fmt.Println("❌ Error: Repository name required")
-			fmt.Println("Usage: inactivity repo <org/repo-name> [options]")
-			os.Exit(1)
-		}
-
-		// Set the repository name
-		cfg.SingleRepository = os.Args[2]
-		// Parse remaining flags
-		if len(os.Args) > 3 {
-			// Copy common flags to repo command
-			commonFlags.VisitAll(func(f *flag.Flag) {
-				if rg := repoCmd.Lookup(f.Name); rg == nil {
-					repoCmd.Var(f.Value, f.Name, f.Usage)
-				}
-			})
-
-			// Parse repo command with common flags
-			if err := repoCmd.Parse(os.Args[3:]); err != nil {
-				log.Fatalf("❌ Error parsing command flags: %v", err)
-			} // Check for format as a positional argument
-			if repoCmd.NArg() >= 1 {
-				if repoCmd.Arg(0) == "json" || repoCmd.Arg(0) == "csv" || repoCmd.Arg(0) == "console" {
-					cfg.OutputFormat = repoCmd.Arg(0)
-				}
-			}
-
-			// Check for output as a separate positional argument
-			for i := 0; i < repoCmd.NArg(); i++ {
-				if repoCmd.Arg(i) == "-output" && i+1 < repoCmd.NArg() {
-					cfg.OutputFile = repoCmd.Arg(i + 1)
-					break
-				}
-			}
+		// New functionality: analyze one or more repositories
+		args := parseCommandArgs(commonFlags, os.Args[2:])
+		expectArgs("repo", args, 1, -1, "inactivity repo <org/repo-name> [org/repo-name...] [options]")
+		rejectOrg(cfg, "repo")
+
+		// Set the repository names
+		cfg.SingleRepository = args[0]
+		cfg.Repositories = args
+
+		if len(cfg.Repositories) > 1 {
+			// Run the combined analysis for several repositories
+			analyzeMultipleRepositories(cfg)
+		} else {
+			// Run the single repository analysis
+			analyzeSingleRepository(cfg)
 		}
 
-		// Run the single repository analysis
-		analyzeSingleRepository(cfg)
-
 	case "file":
 		// New functionality: analyze repositories from a file
-		fileCmd := flag.NewFlagSet("file", flag.ExitOnError)
-
-		// Parse file command flags
-		if len(os.Args) < 3 {
-			fmt.Println("❌ Error: File path required")
-			fmt.Println("Usage: inactivity file <file-path> [options]")
-			os.Exit(1)
-		}
+		args := parseCommandArgs(commonFlags, os.Args[2:])
+		expectArgs("file", args, 1, 1, "inactivity file <file-path> [options]")
+		rejectOrg(cfg, "file")
 
 		// Set the repository list file path
-		cfg.RepoListFile = os.Args[2]
-		// Parse remaining flags
-		if len(os.Args) > 3 {
-			// Copy common flags to file command
-			commonFlags.VisitAll(func(f *flag.Flag) {
-				if fg := fileCmd.Lookup(f.Name); fg == nil {
-					fileCmd.Var(f.Value, f.Name, f.Usage)
-				}
-			})
-
-			// Parse file command with common flags
-			if err := fileCmd.Parse(os.Args[3:]); err != nil {
-				log.Fatalf("❌ Error parsing command flags: %v", err)
-			} // Check for format as a positional argument
-			if fileCmd.NArg() >= 1 {
-				if fileCmd.Arg(0) == "json" || fileCmd.Arg(0) == "csv" || fileCmd.Arg(0) == "console" {
-					cfg.OutputFormat = fileCmd.Arg(0)
-				}
-			}
-
-			// Check for output as a separate positional argument
-			for i := 0; i < fileCmd.NArg(); i++ {
-				if fileCmd.Arg(i) == "-output" && i+1 < fileCmd.NArg() {
-					cfg.OutputFile = fileCmd.Arg(i + 1)
-					break
-				}
-			}
-		}
-
-		// Default to CSV format for file-based analysis
-		if cfg.OutputFormat == "console" {
-			cfg.OutputFormat = "csv"
-		}
+		cfg.RepoListFile = args[0]
 
 		// Run the file-based repository analysis
 		analyzeRepositoriesFromFile(cfg)
 
+	case "forks":
+		// Analyze the direct forks of a repository, reported under it
+		args := parseCommandArgs(commonFlags, os.Args[2:])
+		expectArgs("forks", args, 1, 1, "inactivity forks <org/repo-name> [options]")
+		rejectOrg(cfg, "forks")
+		cfg.ForksOf = args[0]
+
+		// Run the analysis of the forks
+		rejectPath(cfg, "forks")
+		analyzeForks(cfg)
+
+	case "stats":
+		// Aggregate organization health without per-repository detail
+		// The organization may be given with -org or as the only positional argument
+		args := parseCommandArgs(commonFlags, os.Args[2:])
+		expectArgs("stats", args, 0, 1, "inactivity stats <org-name> [options]")
+		setOrganization(&cfg, args, "stats")
+
+		// Run the aggregate organization analysis
+		rejectPath(cfg, "stats")
+		analyzeOrganizationStats(cfg)
+
+	case "trend":
+		// Chart the flagged count over the JSON reports stored by earlier runs
+		args := parseCommandArgs(commonFlags, os.Args[2:])
+		expectArgs("trend", args, 1, 1, "inactivity trend <report-dir> [options]")
+		rejectOrg(cfg, "trend")
+		showTrend(args[0], cfg)
+
+	case "local":
+		// Analyze a local clone from its git history, without the GitHub API
+		args := parseCommandArgs(commonFlags, os.Args[2:])
+		expectArgs("local", args, 1, 1, "inactivity local <path> [options]")
+		rejectOrg(cfg, "local")
+		cfg.LocalRepository = args[0]
+		analyzeLocalRepository(cfg)
+
+	case "version", "-version", "--version":
+		fmt.Println(version.String())
+
 	case "help":
 		displayUsage()
 
@@ -175,6 +280,9 @@ func Main() {
 		displayUsage()
 		os.Exit(1)
 	}
+
+	// Finish the profiles of the run, if requested
+	stopProfiling()
 }
 
 // displayUsage shows the usage information for the tool
@@ -186,93 +294,592 @@ func displayUsage() {
 
 	fmt.Printf("\n%s\n\n", cyan("Repository Inactivity Analyzer"))
 	fmt.Printf("%s\n", yellow("Usage:"))
-	fmt.Printf("  %s\n", green("inactivity org [options]"))
-	fmt.Printf("  %s\n", green("inactivity org [format] [options]  # Alternative syntax"))
-	fmt.Printf("  %s\n", green("inactivity repo <org/repo-name> [options]"))
+	fmt.Printf("  %s\n", green("inactivity org [org-name] [options]"))
+	fmt.Printf("  %s\n", green("inactivity repo <org/repo-name> [org/repo-name...] [options]"))
 	fmt.Printf("  %s\n", green("inactivity file <file-path> [options]"))
+	fmt.Printf("  %s\n", green("inactivity forks <org/repo-name> [options]"))
+	fmt.Printf("  %s\n", green("inactivity stats <org-name> [options]"))
+	fmt.Printf("  %s\n", green("inactivity trend <report-dir> [options]"))
+	fmt.Printf("  %s\n", green("inactivity local <path> [options]"))
+	fmt.Printf("  %s\n", green("inactivity version"))
 	fmt.Printf("  %s\n\n", green("inactivity help"))
 
 	fmt.Printf("%s\n", yellow("Commands:"))
 	fmt.Printf("  %s\t%s\n", green("org"), "Analyze all repositories in an organization")
-	fmt.Printf("  %s\t%s\n", green("repo"), "Analyze a single repository")
+	fmt.Printf("  %s\t%s\n", green("repo"), "Analyze one or more repositories")
 	fmt.Printf("  %s\t%s\n", green("file"), "Analyze repositories from a file")
+	fmt.Printf("  %s\t%s\n", green("forks"), "Analyze the forks of a repository")
+	fmt.Printf("  %s\t%s\n", green("stats"), "Show aggregate organization health without per-repository detail")
+	fmt.Printf("  %s\t%s\n", green("trend"), "Chart the flagged count over the JSON reports stored in a directory")
+	fmt.Printf("  %s\t%s\n", green("local"), "Analyze a local git clone from its history, without the GitHub API")
+	fmt.Printf("  %s\t%s\n", green("version"), "Show the version, commit, and build date (also --version)")
 	fmt.Printf("  %s\t%s\n\n", green("help"), "Show this help message")
 
 	fmt.Printf("%s\n", yellow("Output Formats:"))
 	fmt.Printf("  %s\t%s\n", green("console"), "Display results in human-readable format (default)")
 	fmt.Printf("  %s\t%s\n", green("json"), "Output results in JSON format")
-	fmt.Printf("  %s\t%s\n\n", green("csv"), "Output results in CSV format")
+	fmt.Printf("  %s\t%s\n", green("ndjson"), "Output a meta line, one JSON object per repository, and a summary line")
+	fmt.Printf("  %s\t%s\n", green("csv"), "Output results in CSV format")
+	fmt.Printf("  %s\t%s\n", green("table"), "Display results as an aligned table (alias: ascii-table)")
+	fmt.Printf("  %s\t%s\n", green("markdown"), "Output a Markdown report for issues and pull requests (alias: md)")
+	fmt.Printf("  %s\t%s\n\n", green("task-list"), "Output a Markdown checklist of the flagged repositories to paste into an issue")
 
 	fmt.Printf("%s\n", yellow("Options:"))
 	fmt.Printf("  %s\t%s\n", green("-days int"), "Maximum age of last commit in days (default: 180)")
+	fmt.Printf("  %s\t%s\n", green("-contributor-days int"), "Days without a commit before a contributor counts as inactive in the org scope (default: -days)")
 	fmt.Printf("  %s\t%s\n", green("-threshold float"), "Threshold of inactive contributors (0.0-1.0) (default: 0.5)")
-	fmt.Printf("  %s\t%s\n", green("-format string"), "Output format: console, json, or csv (default: console)")
-	fmt.Printf("  %s\t%s\n", green("-output string"), "Output file path (optional)")
+	fmt.Printf("  %s\t%s\n", green("-format string"), "Output format: console, json, ndjson, csv, table, markdown, or task-list (default: console)")
+	fmt.Printf("  %s\t%s\n", green("-output string"), "Output file path, or an s3:// or gs:// URL to upload to (optional)")
+	fmt.Printf("  %s\t%s\n", green("-markdown-style string"), "Markdown layout: table or details (default: table)")
+	fmt.Printf("  %s\t%s\n", green("-group-by-reason"), "Section the flagged repositories of the text and Markdown reports by flag reason")
+	fmt.Printf("  %s\t%s\n", green("-detect-duplicates"), "Report flagged repositories whose names suggest copies of the same project")
+	fmt.Printf("  %s\t%s\n", green("-stream-output"), "Write each repository to the -output file as soon as it is analyzed (csv and ndjson formats)")
+	fmt.Printf("  %s\t%s\n", green("-redact"), "Replace organization, repository, and user names in the report with stable pseudonyms, for sharing it externally")
+	fmt.Printf("  %s\t%s\n", green("-redact-map file"), "Local file mapping the pseudonyms back to the real names, reused by later runs (default: redaction-map.json)")
 	fmt.Printf("  %s\t%s\n", green("-silent"), "Suppress banner and progress output")
-	fmt.Printf("  %s\t%s\n\n", green("-org string"), "GitHub organization to analyze (for 'org' command)")
+	fmt.Printf("  %s\t%s\n", green("-heartbeat duration"), "How often \"analyzed X/Y, elapsed Z\" is logged when the log is not a terminal, e.g. in CI (default: 30s, 0 disables)")
+	fmt.Printf("  %s\t%s\n", green("-deadline duration"), "Stop starting repositories after this total run time and report the partial results (0 disables)")
+	fmt.Printf("  %s\t%s\n", green("-log-file string"), "Write the banner, progress, warnings, and skip notices to this file instead of the terminal; the report, including stats and trend console output, stays on stdout")
+	fmt.Printf("  %s\t%s\n", green("-cpuprofile string"), "Write a pprof CPU profile of the run to this file")
+	fmt.Printf("  %s\t%s\n", green("-trace string"), "Write a runtime execution trace of the run to this file, for go tool trace")
+	fmt.Printf("  %s\t%s\n", green("-banner string"), "Banner to show: full, minimal, or none; progress and results are unaffected (default: full)")
+	fmt.Printf("  %s\t%s\n", green("-humanize"), "Show ages as relative times (e.g. about 6 months ago) in human-readable output")
+	fmt.Printf("  %s\t%s\n", green("-as-of string"), "Analyze as of this date (2006-01-02) or RFC 3339 timestamp instead of now")
+	fmt.Printf("  %s\t%s\n", green("-strict"), "Stop at the first per-repository error instead of skipping it")
+	fmt.Printf("  %s\t%s\n", green("-concurrency int"), "Number of repositories analyzed at a time (default: 1)")
+	fmt.Printf("  %s\t%s\n", green("-visibility string"), "Organization repositories to analyze: public, private, or all (default: all)")
+	fmt.Printf("  %s\t%s\n", green("-admin-of user"), "Analyze only the organization repositories this user (or @me) has admin permission on")
+	fmt.Printf("  %s\t%s\n", green("-contributor-scope string"), "repo (org membership) or org (recent commits across the org) (default: repo)")
+	fmt.Printf("  %s\t%s\n", green("-path string"), "Analyze only the commits touching this file path or subdirectory (repo and file commands)")
+	fmt.Printf("  %s\t%s\n", green("-path-contributors"), "Count only the authors of commits under -path as contributors")
+	fmt.Printf("  %s\t%s\n", green("-membership-fallback string"), "When org membership is not visible to you: unknown or public (default: unknown)")
+	fmt.Printf("  %s\t%s\n", green("-api string"), "API used to check org membership: rest, graphql (batched), or members (member list fetched once per run); graphql switches to rest for the rest of the run once a query fails, and members checks each contributor over rest when the member list cannot be listed (default: rest)")
+	fmt.Printf("  %s\t%s\n", green("-metrics list"), analyzer.MetricsHelp())
+	fmt.Printf("  %s\t%s\n", green("-substantive-commits"), "Report the last commit that is neither a merge nor made by a bot")
+	fmt.Printf("  %s\t%s\n", green("-flag-on-substantive"), "Measure repository age from the last substantive commit when flagging")
+	fmt.Printf("  %s\t%s\n", green("-signed-commits"), "Report the share of recent commits with a verified signature")
+	fmt.Printf("  %s\t%s\n", green("-min-signed-ratio float"), "Mark flagged repositories signing fewer recent commits for security review (default: 0, disabled)")
+	fmt.Printf("  %s\t%s\n", green("-weighted-contributors"), "Weigh each contributor by their commits in the last year when comparing inactive contributors with -threshold")
+	fmt.Printf("  %s\t%s\n", green("-check-suspended"), "Count contributors whose account is suspended as inactive, even if they are still members")
+	fmt.Printf("  %s\t%s\n", green("-contributor-details"), "List inactive contributors with their last commit to the repository")
+	fmt.Printf("  %s\t%s\n", green("-contributor-map string"), "File merging contributor aliases and dropping service accounts before contributors are counted")
+	fmt.Printf("  %s\t%s\n", green("-group-contributors"), "Group the inactive contributors of flagged repositories by how long ago they last committed (console output)")
+	fmt.Printf("  %s\t%s\n", green("-contributor-report file"), "Write one row per contributor across all analyzed repositories, with whether they left, to a CSV or .json file")
+	fmt.Printf("  %s\t%s\n", green("-min-contributors int"), "Do not flag repositories with fewer total contributors (default: 0, disabled)")
+	fmt.Printf("  %s\t%s\n", green("-min-inactive-count int"), "Flag on inactive contributors only when at least N are inactive, besides -threshold (default: 0, disabled)")
+	fmt.Printf("  %s\t%s\n", green("-drop-small-repos"), "Remove repositories below -min-contributors from the report entirely")
+	fmt.Printf("  %s\t%s\n", green("-min-contributors-archived"), "Apply -min-contributors to archived repositories too")
+	fmt.Printf("  %s\t%s\n", green("-min-repo-age int"), "Do not flag repositories created fewer days ago (default: 0, disabled)")
+	fmt.Printf("  %s\t%s\n", green("-top int"), "List only the N most inactive repositories, keeping the totals of all (default: 0, all)")
+	fmt.Printf("  %s\t%s\n", green("-fields string"), "Comma-separated JSON field names to include in JSON/CSV output")
+	fmt.Printf("  %s\t%s\n", green("-repo-list-cache string"), "File caching the organization's repository list between runs (optional)")
+	fmt.Printf("  %s\t%s\n", green("-repo-cache string"), "File caching each repository's commit and contributor data until it is pushed to, or its contributor data is older than -repo-cache-contributor-ttl (optional)")
+	fmt.Printf("  %s\t%s\n", green("-repo-list-ttl duration"), "How long the cached repository list is reused (default: 24h)")
+	fmt.Printf("  %s\t%s\n", green("-repo-cache-contributor-ttl duration"), "How long -repo-cache reuses contributor data before checking membership again, even without a push; 0 reuses it until the next push (default: 168h)")
+	fmt.Printf("  %s\t%s\n", green("-emit-script action"), "Write a reviewable shell script to archive, delete, or transfer the flagged repositories instead of the report")
+	fmt.Printf("  %s\t%s\n", green("-publish-status"), "Publish each result as a check run or commit status on the repository (requires write access)")
+	fmt.Printf("  %s\t%s\n", green("-create-tracking-issue string"), "File the flagged repositories as a checklist issue in this repository (org/repo), updating it on later runs")
+	fmt.Printf("  %s\t%s\n", green("-update-comment string"), "Replace the body of this issue comment (URL or org/repo#issuecomment-ID) with the Markdown report")
+	fmt.Printf("  %s\t%s\n", green("-dry-run"), "Log the changes that would be made to repositories without making them")
+	fmt.Printf("  %s\t%s\n", green("-dump-config"), "Print the effective configuration as JSON and exit")
+	fmt.Printf("  %s\t%s\n", green("-refresh-repo-list"), "Re-fetch the repository list even if the cache is fresh")
+	fmt.Printf("  %s\t%s\n", green("-input-format string"), "Repository list format for the file command: lines or csv (default: csv for .csv files)")
+	fmt.Printf("  %s\t%s\n", green("-repo-column string"), "CSV column holding the repository, by header name or 1-based index (default: first column)")
+	fmt.Printf("  %s\t%s\n", green("-extra-columns list"), "Comma-separated CSV columns to carry through to the report")
+	fmt.Printf("  %s\t%s\n", green("-state string"), "State file used to show changes since the previous run (optional)")
+	fmt.Printf("  %s\t%s\n", green("-show-admins"), "List collaborators with admin permission for flagged repositories")
+	fmt.Printf("  %s\t%s\n", green("-owners-map file"), "Map repositories and @org/teams to the contact to notify, added to flagged repositories")
+	fmt.Printf("  %s\t%s\n", green("-abort-on-insufficient-quota"), "Abort before scanning if the remaining API quota looks too low to finish")
+	fmt.Printf("  %s\t%s\n", green("-cache-ttl duration"), "How long gh caches read-only API responses, 0 disables (default: 1h)")
+	fmt.Printf("  %s\t%s\n", green("-gh-path string"), "Path to the gh binary to run instead of the gh in PATH (default: $GH_PATH)")
+	fmt.Printf("  %s\t%s\n", green("-progress-fd int"), "File descriptor to receive JSON progress events (optional)")
+	fmt.Printf("  %s\t%s\n", green("-prioritize-unlicensed"), "Mark flagged repositories without a license as high priority")
+	fmt.Printf("  %s\t%s\n", green("-readme"), "Report whether each repository has a README and its size")
+	fmt.Printf("  %s\t%s\n", green("-prioritize-undocumented"), "Mark flagged repositories without a README as high priority")
+	fmt.Printf("  %s\t%s\n", green("-ci-status"), "Report the status and date of the latest GitHub Actions run")
+	fmt.Printf("  %s\t%s\n", green("-flag-broken-ci"), "Flag old repositories whose CI is absent, failing, or older than -days")
+	fmt.Printf("  %s\t%s\n", green("-merged-prs"), "Report when the last pull request was merged")
+	fmt.Printf("  %s\t%s\n", green("-flag-stale-reviews"), "Flag repositories whose last merged pull request is older than -days, even with recent commits")
+	fmt.Printf("  %s\t%s\n", green("-engagement"), "Report the median time to the first maintainer response on recent issues (API-heavy)")
+	fmt.Printf("  %s\t%s\n", green("-flag-unresponsive"), "Flag repositories whose maintainers stopped answering issues, even with recent commits")
+	fmt.Printf("  %s\t%s\n", green("-max-response-hours float"), "Longest acceptable median time to first maintainer response (default: 168)")
+	fmt.Printf("  %s\t%s\n", green("-recent-tags"), "Treat repositories whose newest tag points to a commit within -days as active")
+	fmt.Printf("  %s\t%s\n", green("-active-branches"), "Treat repositories with a commit within -days on any other branch as active")
+	fmt.Printf("  %s\t%s\n", green("-momentum"), "Report commits in the last 90 days minus commits in the 90 days before")
+	fmt.Printf("  %s\t%s\n", green("-flag-declining"), "Flag repositories whose commit momentum dropped sharply, even with recent commits")
+	fmt.Printf("  %s\t%s\n", green("-declining-momentum int"), "Minimum drop in commits flagged as declining (default: 10)")
+	fmt.Printf("  %s\t%s\n", green("-cadence"), "Report the median gap between consecutive recent commits")
+	fmt.Printf("  %s\t%s\n", green("-flag-on-cadence"), "Count repositories as old only once they are also quiet for longer than their own rhythm")
+	fmt.Printf("  %s\t%s\n", green("-cadence-multiplier float"), "Multiple of the median commit gap tolerated with -flag-on-cadence (default: 3)")
+	fmt.Printf("  %s\t%s\n", green("-bot-prs"), "Count the open pull requests opened by bots more than -days ago, such as unmerged dependency updates")
+	fmt.Printf("  %s\t%s\n", green("-flag-bot-prs"), "Flag repositories where stale bot pull requests pile up, even with recent commits")
+	fmt.Printf("  %s\t%s\n", green("-max-stale-bot-prs int"), "Most stale open bot pull requests tolerated before flagging (default: 10)")
+	fmt.Printf("  %s\t%s\n", green("-security"), "Count open Dependabot alerts and mark flagged repositories carrying them as urgent")
+	fmt.Printf("  %s\t%s\n", green("-governance"), "Flag old repositories with issues disabled and show governance settings, visibility, and outside collaborators")
+	fmt.Printf("  %s\t%s\n", green("-lifecycle"), "Classify each repository as active, maintenance, stale, abandoned, or archived, with counts per stage")
+	fmt.Printf("  %s\t%s\n", green("-lifecycle-active-days int"), "Most days since the last commit of an active repository (default: 30)")
+	fmt.Printf("  %s\t%s\n", green("-lifecycle-stale-days int"), "Days since the last commit past which a repository is stale (default: -days)")
+	fmt.Printf("  %s\t%s\n", green("-lifecycle-abandoned-days int"), "Days since the last commit past which a stale repository is abandoned (default: 365)")
+	fmt.Printf("  %s\t%s\n", green("-org string"), "GitHub organization to analyze, also accepted as the argument of the org and stats commands")
+	fmt.Printf("  %s\t%s\n", green("-exec-hook command"), "Shell command receiving the JSON report on stdin after analysis (optional)")
+	fmt.Printf("  %s\t%s\n", green("-email-to string"), "Comma-separated recipients to email the report to (optional)")
+	fmt.Printf("  %s\t%s\n", green("-email-from string"), "Sender address for the emailed report")
+	fmt.Printf("  %s\t%s\n", green("-smtp-host string"), "SMTP server host")
+	fmt.Printf("  %s\t%s\n", green("-smtp-port int"), "SMTP server port, 465 for implicit TLS (default: 587)")
+	fmt.Printf("  %s\t%s\n", green("-smtp-user string"), "SMTP username (optional)")
+	fmt.Printf("  %s\t%s\n", green("-smtp-password string"), "SMTP password (default: $SMTP_PASSWORD)")
+	fmt.Printf("  %s\t%s\n", green("-app-id int"), "GitHub App ID to authenticate as an app installation (optional)")
+	fmt.Printf("  %s\t%s\n", green("-installation-id int"), "GitHub App installation ID (with -app-id)")
+	fmt.Printf("  %s\t%s\n", green("-private-key string"), "Path to the GitHub App private key in PEM format (with -app-id)")
+	fmt.Printf("  %s\t%s\n\n", green("-credentials string"), "JSON file mapping repository owners to tokens, for scans spanning several accounts")
 
 	fmt.Printf("%s\n", yellow("Examples:"))
 	fmt.Printf("  %s\n", green("inactivity org -org mycompany"))
 	fmt.Printf("  %s\n", green("inactivity repo mycompany/myrepo -days 90"))
+	fmt.Printf("  %s\n", green("inactivity repo mycompany/repo-a mycompany/repo-b -format json"))
 	fmt.Printf("  %s\n", green("inactivity file repos.txt -format csv -output results.csv"))
+	fmt.Printf("  %s\n", green("inactivity stats mycompany -format json"))
 	fmt.Printf("  %s\n", green("inactivity org -org mycompany -format json -output results.json"))
 	fmt.Printf("  %s\n", green("inactivity repo mycompany/myrepo -format csv -output repo-result.csv"))
-	fmt.Printf("  %s\n\n", green("inactivity org csv -output results.csv  # Alternative format syntax"))
+	fmt.Printf("  %s\n\n", green("inactivity org mycompany -format csv -output results.csv"))
 }
 
-// analyzeOrganization analyzes all repositories in an organization
-func analyzeOrganization(cfg config.Config) {
-	// Display banner unless silent mode is enabled
+// isOutputFormat reports whether a positional argument names an output format
+func isOutputFormat(arg string) bool {
+	_, ok := analyzer.LookupFormatter(arg)
+	return ok
+}
+
+// parseCommandArgs parses the shared flags of a command, which may come before, between, or after its
+// positional arguments, and returns the positional arguments in order; everything after -- is positional
+func parseCommandArgs(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			log.Fatalf("❌ Failed to parse command flags: %v", err)
+		}
+		rest := flags.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...)
+		}
+		if len(rest) == 0 {
+			return positional
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// expectArgs exits with the command usage unless the command got between min and max positional
+// arguments (a negative maxArgs means any number), pointing at -format when an extra argument names a format
+func expectArgs(command string, args []string, minArgs, maxArgs int, usage string) {
+	if len(args) >= minArgs && (maxArgs < 0 || len(args) <= maxArgs) {
+		return
+	}
+
+	switch {
+	case len(args) < minArgs:
+		fmt.Printf("❌ Error: %s requires %d argument(s)\n", command, minArgs)
+	case isOutputFormat(args[maxArgs]):
+		fmt.Printf("❌ Error: unexpected argument %q, use -format %s to choose the output format\n", args[maxArgs], args[maxArgs])
+	default:
+		fmt.Printf("❌ Error: unexpected argument %q\n", args[maxArgs])
+	}
+	fmt.Printf("Usage: %s\n", usage)
+	os.Exit(1)
+}
+
+// setOrganization takes the organization of the org and stats commands from their positional argument, if any
+// A format name is refused there, as it was once accepted as the format; -org still takes any name
+func setOrganization(cfg *config.Config, args []string, command string) {
+	if len(args) == 0 {
+		return
+	}
+	if isOutputFormat(args[0]) {
+		log.Fatalf("❌ %q names an output format, use -format %s, or -org %s for an organization of that name", args[0], args[0], args[0])
+	}
+	if cfg.Organization != "" && cfg.Organization != args[0] {
+		log.Fatalf("❌ The %s command got two organizations, %s and %s", command, cfg.Organization, args[0])
+	}
+	cfg.Organization = args[0]
+}
+
+// rejectOrg stops commands that take their repositories as arguments from being given an organization,
+// which they would otherwise ignore
+func rejectOrg(cfg config.Config, command string) {
+	if cfg.Organization != "" {
+		log.Fatalf("❌ -org only applies to the org and stats commands, not %s", command)
+	}
+}
+
+// rejectPath stops commands that scan repositories the user did not name from scoping them to a path,
+// which only makes sense for repositories known to share a layout
+func rejectPath(cfg config.Config, command string) {
+	if cfg.Path != "" {
+		log.Fatalf("❌ -path only applies to the repo and file commands, not %s", command)
+	}
+}
+
+// prepareRun validates the configuration and prepares the GitHub CLI before an analysis
+func prepareRun(cfg config.Config) []analyzer.Warning {
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("❌ Invalid configuration: %v", err)
+	}
+
+	// Print the effective configuration instead of analyzing if requested
+	if cfg.DumpConfig {
+		data, err := json.MarshalIndent(cfg.Redacted(), "", "  ")
+		if err != nil {
+			log.Fatalf("❌ Failed to marshal configuration: %v", err)
+		}
+		fmt.Println(string(data))
+		os.Exit(0)
+	}
+
+	// Send diagnostic output to the log file if requested, keeping stdout for the report
+	if cfg.LogFile != "" {
+		logFile, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			log.Fatalf("❌ Failed to open log file: %v", err)
+		}
+		analyzer.SetLogOutput(logFile)
+		log.SetOutput(logFile)
+	}
+
+	// Profile the run if requested, until the command returns
+	startProfiling(cfg)
+
+	// Run gh from the configured path if any
+	analyzer.SetGHPath(cfg.GHPath)
+
+	// Bound the total run time if requested
+	analyzer.SetDeadline(cfg.Deadline)
+
+	// Scope the commit queries to a path if requested
+	analyzer.SetCommitPath(cfg.Path, cfg.PathContributors)
+
+	// Apply the response cache to every read-only gh call
+	analyzer.SetCacheTTL(cfg.CacheTTL)
+
+	// Revalidate expired responses with conditional requests, keeping their ETags in the user cache directory
+	if dir, err := os.UserCacheDir(); err == nil && cfg.CacheTTL > 0 {
+		analyzer.SetETagCacheDir(filepath.Join(dir, "inactivity", "etags"))
+	}
+
+	// Pin the analysis time if requested
+	analyzer.SetAsOf(cfg.AsOf)
+
+	// Configure GitHub App authentication if requested
+	configureAuthentication(cfg)
+
+	// Load the contributor aliases and exclusions if requested, before cached data is matched against them
+	if cfg.ContributorMap != "" {
+		m, err := analyzer.LoadContributorMap(cfg.ContributorMap)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		analyzer.SetContributorMap(m)
+	}
+
+	// Load the contacts of flagged repositories if requested
+	if cfg.OwnersMap != "" {
+		m, err := analyzer.LoadOwnersMap(cfg.OwnersMap)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		analyzer.SetOwnersMap(m)
+	}
+
+	// Check the comment to update before the scan rather than after it
+	if cfg.UpdateComment != "" {
+		if _, _, err := analyzer.ParseCommentTarget(cfg.UpdateComment); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+	}
+
+	// Load the pseudonyms of earlier redacted reports, so names keep their pseudonym across runs
+	if cfg.Redact {
+		m, err := analyzer.LoadRedactionMap(cfg.RedactMap)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		analyzer.SetRedactionMap(m)
+	}
+
+	// Keep a streamed output file well-formed if the run is interrupted
+	if cfg.StreamOutput {
+		closeOutputStreamOnInterrupt()
+	}
+
+	// Load the per-repository cache if requested
+	if cfg.RepoCache != "" {
+		if err := analyzer.OpenRepositoryCache(cfg.RepoCache); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+	}
+
+	// Warn when the selected metrics cannot flag a repository as old
+	warnings := analyzer.CheckMetrics(cfg)
+
+	// Validate GitHub CLI installation, which a local clone is analyzed without
+	if cfg.LocalRepository == "" {
+		cliWarnings, err := analyzer.ValidateGitHubCLI(cfg)
+		if err != nil {
+			log.Fatalf("❌ GitHub CLI validation failed: %v", err)
+		}
+		warnings = append(warnings, cliWarnings...)
+	}
+	return warnings
+}
+
+// deliverReport runs the requested delivery steps once a report has been output, whatever the command
+func deliverReport(analysis analyzer.Analysis, cfg config.Config) {
+	// Publish each result on its repository if requested
+	publishStatuses(analysis.Repositories, cfg)
+
+	// Email the report if requested
+	emailReport(analysis, cfg)
+
+	// File the flagged repositories in the tracking issue if requested
+	fileTrackingIssue(analysis.Repositories, cfg)
+
+	// Replace the tracked comment with the latest report if requested
+	updateComment(analysis, cfg)
+
+	// Hand the JSON report to the post-processing hook if requested
+	runExecHook(analysis, cfg)
+}
+
+// publishStatuses publishes the result of each analyzed repository on it, if requested
+// A status that cannot be published is skipped with a warning, or stops the run with -strict.
+func publishStatuses(repos []analyzer.Repository, cfg config.Config) {
+	if !cfg.PublishStatus {
+		return
+	}
+
+	for _, repo := range repos {
+		if err := analyzer.PublishStatus(repo, cfg); err != nil {
+			if cfg.Strict {
+				log.Fatalf("❌ %v", err)
+			}
+			if !cfg.Silent {
+				log.Printf("⚠️ %v", err)
+			}
+		}
+	}
+}
+
+// emailReport sends the report by email when recipients are configured
+func emailReport(analysis analyzer.Analysis, cfg config.Config) {
+	if cfg.EmailTo == "" {
+		return
+	}
+
+	password := cfg.SMTPPassword
+	if password == "" {
+		password = os.Getenv("SMTP_PASSWORD")
+	}
+
+	report, err := analyzer.RenderReport(analysis, cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to render report for email: %v", err)
+	}
+	contentType, extension := analyzer.ReportFileType(cfg.OutputFormat)
+
+	subject := "Repository inactivity report"
+	if cfg.Organization != "" {
+		subject = fmt.Sprintf("Repository inactivity report for %s", analyzer.RedactOrganization(cfg.Organization))
+	}
+
+	var recipients []string
+	for _, rcpt := range strings.Split(cfg.EmailTo, ",") {
+		if rcpt = strings.TrimSpace(rcpt); rcpt != "" {
+			recipients = append(recipients, rcpt)
+		}
+	}
+
+	err = email.Send(email.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: password,
+	}, email.Message{
+		From:    cfg.EmailFrom,
+		To:      recipients,
+		Subject: subject,
+		Body:    analyzer.RenderSummaryText(analysis, cfg),
+		Attachment: &email.Attachment{
+			Filename:    fmt.Sprintf("inactivity-report-%s%s", analyzer.Now().Format("2006-01-02"), extension),
+			ContentType: contentType,
+			Data:        report,
+		},
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to email report: %v", err)
+	}
+
 	if !cfg.Silent {
-		// Custom banner for organization analysis
-		cyan := color.New(color.FgCyan).SprintFunc()
-		yellow := color.New(color.FgYellow).SprintFunc()
-		red := color.New(color.FgRed).SprintFunc()
-		green := color.New(color.FgGreen).SprintFunc()
-		purple := color.New(color.FgMagenta).SprintFunc()
-		blue := color.New(color.FgBlue).SprintFunc()
-		white := color.New(color.FgHiWhite, color.Bold).SprintFunc()
-
-		// Print a creative organization analysis banner
-		fmt.Println()
-		fmt.Println(red("  ╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱"))
-		fmt.Println(yellow(" ╱    ") + white("ORGANIZATION HEALTH MONITOR") + yellow("                            ╱"))
-		fmt.Println(green("╱                                                         ╱"))
-		fmt.Println(cyan("╱") + blue("  ┌───────────────────────────────────────────────────────┐") + cyan(" ╱"))
-		fmt.Println(cyan("╱") + blue("  │") + "                                                       " + blue("│") + cyan(" ╱"))
-		fmt.Println(cyan("╱") + blue("  │") + "  " + red("◉") + white(" ORGANIZATION PORTFOLIO ANALYZER ") + red("◉") + "                " + blue("│") + cyan(" ╱"))
-		fmt.Println(cyan("╱") + blue("  │") + "                                                       " + blue("│") + cyan(" ╱"))
-		fmt.Println(cyan("╱") + blue("  │") + "  " + purple("⚡") + green(" Scanning All Repositories") + "                           " + blue("│") + cyan(" ╱"))
-		fmt.Println(cyan("╱") + blue("  │") + "  " + yellow("⚡") + green(" Detecting Inactive Projects") + "                         " + blue("│") + cyan(" ╱"))
-		fmt.Println(cyan("╱") + blue("  │") + "  " + cyan("⚡") + green(" Analyzing Contributor Engagement") + "                    " + blue("│") + cyan(" ╱"))
-		fmt.Println(cyan("╱") + blue("  │") + "                                                       " + blue("│") + cyan(" ╱"))
-		fmt.Println(cyan("╱") + blue("  │") + "  " + white("REPO·PULSE ENTERPRISE") + "                               " + blue("│") + cyan(" ╱"))
-		fmt.Println(cyan("╱") + blue("  │") + "                                                       " + blue("│") + cyan(" ╱"))
-		fmt.Println(cyan("╱") + blue("  └───────────────────────────────────────────────────────┘") + cyan(" ╱"))
-		fmt.Println(green("╱                                                         ╱"))
-		fmt.Println(yellow("╱                                                         ╱"))
-		fmt.Println(red("╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱╱"))
-		fmt.Println()
-
-		fmt.Println(yellow("✦ Repository Inactivity Analyzer - Organization Mode ✦"))
-		fmt.Println(cyan("⟹ Analyzing repositories across an entire organization"))
-		fmt.Println()
-	}
-
-	// Validate GitHub CLI installation
-	if err := analyzer.ValidateGitHubCLI(); err != nil {
-		log.Fatalf("❌ GitHub CLI validation failed: %v", err)
-	}
-
-	// Get available organizations
-	orgs, err := analyzer.GetUserOrganizations()
+		analyzer.Logf("📧 Report emailed to %s\n", strings.Join(recipients, ", "))
+	}
+}
+
+// fileTrackingIssue opens or updates the tracking issue listing the flagged repositories, if requested
+func fileTrackingIssue(repos []analyzer.Repository, cfg config.Config) {
+	if cfg.TrackingIssueRepo == "" {
+		return
+	}
+
+	if err := analyzer.FileTrackingIssue(analyzer.FilterRepositories(repos, cfg), cfg); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+// updateComment replaces the body of the -update-comment issue comment with the Markdown report, if requested
+func updateComment(analysis analyzer.Analysis, cfg config.Config) {
+	if cfg.UpdateComment == "" {
+		return
+	}
+
+	if err := analyzer.UpdateIssueComment(analysis, cfg); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+// runExecHook pipes the JSON report to the -exec-hook command, exiting with its status if it fails
+func runExecHook(analysis analyzer.Analysis, cfg config.Config) {
+	if cfg.ExecHook == "" {
+		return
+	}
+
+	jsonCfg := cfg
+	jsonCfg.OutputFormat = "json"
+	report, err := analyzer.RenderReport(analysis, jsonCfg)
 	if err != nil {
-		log.Fatalf("❌ Failed to get organizations: %v", err)
+		log.Fatalf("❌ Failed to render report for hook: %v", err)
 	}
 
+	if err := analyzer.RunHook(cfg.ExecHook, report); err != nil {
+		var hookErr *analyzer.HookError
+		if errors.As(err, &hookErr) {
+			log.Printf("❌ %v", hookErr)
+			stopProfiling()
+			os.Exit(hookErr.ExitCode)
+		}
+		log.Fatalf("❌ %v", err)
+	}
+
+	if !cfg.Silent {
+		analyzer.Logf("🪝 Report passed to hook %q\n", cfg.ExecHook)
+	}
+}
+
+// streamRepository writes an analyzed repository to the output stream, if one is open, returning a warning
+// when it cannot be written
+func streamRepository(repo analyzer.Repository, cfg config.Config) []analyzer.Warning {
+	err := analyzer.StreamRepository(repo)
+	if err == nil {
+		return nil
+	}
+	if !cfg.Silent {
+		log.Printf("⚠️ %v", err)
+	}
+	return []analyzer.Warning{{Repository: repo.Name, Message: err.Error(), Severity: analyzer.SeverityWarning}}
+}
+
+// failRun exits with an error once the run has been wrapped up: the output stream is closed, the
+// repositories analyzed before the failure are kept in the cache, and the pseudonyms and profiles are saved
+func failRun(format string, args ...interface{}) {
+	closeOutputStream()
+	saveRepositoryCache()
+	saveRedactionMap()
+	stopProfiling()
+	log.Fatalf(format, args...)
+}
+
+// closeOutputStream finishes and closes the output stream, if one is open
+func closeOutputStream() {
+	if err := analyzer.CloseOutputStream(); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+// closeOutputStreamOnInterrupt finishes the output stream before exiting when the run is interrupted,
+// so a streamed file ends with its last analyzed repository rather than a partial line
+func closeOutputStreamOnInterrupt() {
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-interrupts
+		if err := analyzer.CloseOutputStream(); err != nil {
+			log.Printf("❌ %v", err)
+		}
+		os.Exit(130)
+	}()
+}
+
+// saveRepositoryCache writes the per-repository cache, if one is configured
+func saveRepositoryCache() {
+	if err := analyzer.SaveRepositoryCache(); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+// saveRedactionMap writes the pseudonyms given out by -redact to the local mapping file, if reports are redacted
+func saveRedactionMap() {
+	if err := analyzer.SaveRedactionMap(); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+// configureAuthentication sets up GitHub App installation token authentication when requested
+func configureAuthentication(cfg config.Config) {
+	// Use per-owner tokens if a credentials file is given
+	if cfg.CredentialsFile != "" {
+		creds, err := analyzer.LoadCredentials(cfg.CredentialsFile)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		analyzer.SetCredentials(creds)
+	}
+
+	if cfg.AppID == 0 {
+		return
+	}
+
+	if cfg.InstallationID == 0 || cfg.PrivateKeyFile == "" {
+		log.Fatal("❌ -installation-id and -private-key are required with -app-id")
+	}
+
+	ts, err := githubapp.NewTokenSource(cfg.AppID, cfg.InstallationID, cfg.PrivateKeyFile)
+	if err != nil {
+		log.Fatalf("❌ Failed to load GitHub App credentials: %v", err)
+	}
+
+	// Generate the first token up front so credential problems fail fast
+	if _, err := ts.Token(); err != nil {
+		log.Fatalf("❌ Failed to generate GitHub App installation token: %v", err)
+	}
+
+	analyzer.SetTokenSource(ts)
+}
+
+// analyzeOrganization analyzes all repositories in an organization
+func analyzeOrganization(cfg config.Config) {
+	// Validate configuration, authentication, and the GitHub CLI
+	warnings := prepareRun(cfg)
+
+	// Display banner unless silent mode is enabled
+	displayBanner(organizationBanner, cfg)
+
 	// If organization is not provided, let the user select from available ones
 	if cfg.Organization == "" {
 		if !cfg.Silent {
+			// Get available organizations
+			orgs, err := analyzer.GetUserOrganizations()
+			if err != nil {
+				log.Fatalf("❌ Failed to get organizations: %v", err)
+			}
+
 			// Create color functions
 			cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
 			yellow := color.New(color.FgYellow, color.Bold).SprintFunc()
@@ -297,372 +904,428 @@ func analyzeOrganization(cfg config.Config) {
 	}
 
 	if !cfg.Silent {
-		fmt.Printf("\n🔬 Analyzing repositories in %s...\n", cfg.Organization)
+		analyzer.Logf("\n🔬 Analyzing repositories in %s...\n", cfg.Organization)
 	}
 
 	// Analyze repositories
-	repos, err := analyzer.AnalyzeRepositories(cfg)
+	analysis, err := analyzer.AnalyzeRepositories(cfg)
 	if err != nil {
-		log.Fatalf("❌ Analysis failed: %v", err)
+		failRun("❌ Analysis failed: %v", err)
 	}
+	closeOutputStream()
+	analysis.Warnings = append(warnings, analysis.Warnings...)
+
+	// Save the per-repository cache for the next run
+	saveRepositoryCache()
 
 	// Output results
-	if err := analyzer.OutputResults(repos, cfg); err != nil {
+	if err := analyzer.OutputResults(analysis, cfg); err != nil {
 		log.Fatalf("❌ Failed to output results: %v", err)
 	}
+
+	// Email, file, and hand off the report as requested
+	deliverReport(analysis, cfg)
+
+	// Record the pseudonyms of the redacted report
+	saveRedactionMap()
 }
 
-// analyzeSingleRepository analyzes a single repository
-func analyzeSingleRepository(cfg config.Config) { // Display banner unless silent mode is enabled
+// analyzeOrganizationStats analyzes all repositories in an organization and reports only aggregate metrics
+func analyzeOrganizationStats(cfg config.Config) {
+	// Validate configuration, authentication, and the GitHub CLI
+	prepareRun(cfg)
+
+	if !analyzer.IsStatsFormat(cfg.OutputFormat) {
+		log.Fatalf("❌ The stats command supports the console and json formats, got %s", cfg.OutputFormat)
+	}
+
+	if cfg.Organization == "" {
+		log.Fatal("❌ Organization is required: inactivity stats <org>")
+	}
+
+	// Display banner unless silent mode is enabled
+	displayBanner(statsBanner, cfg)
+
 	if !cfg.Silent {
-		// Use custom banner code here instead of calling DisplayBanner
-		cyan := color.New(color.FgCyan).SprintFunc()
-		yellow := color.New(color.FgYellow).SprintFunc()
-		red := color.New(color.FgRed).SprintFunc()
-		green := color.New(color.FgGreen).SprintFunc()
-		purple := color.New(color.FgMagenta).SprintFunc()
-		blue := color.New(color.FgBlue).SprintFunc()
-		white := color.New(color.FgHiWhite, color.Bold).SprintFunc()
-		brightGreen := color.New(color.FgHiGreen).SprintFunc()
-
-		// Print a creative ASCII art banner
-		fmt.Println()
-		fmt.Println(blue("╔══════════════════════════════════════════════════════════╗"))
-		fmt.Println(blue("║") + "                                                          " + blue("║"))
-		fmt.Println(blue("║") + "  " + red("   ██████╗ ███████╗██████╗  ██████╗     ██████╗ ██╗   ") + blue("║"))
-		fmt.Println(blue("║") + "  " + brightGreen("   ██╔══██╗██╔════╝██╔══██╗██╔═══██╗    ██╔══██╗██║   ") + blue("║"))
-		fmt.Println(blue("║") + "  " + yellow("   ██████╔╝█████╗  ██████╔╝██║   ██║    ██████╔╝██║   ") + blue("║"))
-		fmt.Println(blue("║") + "  " + purple("   ██╔══██╗██╔══╝  ██╔═══╝ ██║   ██║    ██╔═══╝ ██║   ") + blue("║"))
-		fmt.Println(blue("║") + "  " + cyan("   ██║  ██║███████╗██║     ╚██████╔╝    ██║     ███████╗") + blue("║"))
-		fmt.Println(blue("║") + "  " + white("   ╚═╝  ╚═╝╚══════╝╚═╝      ╚═════╝     ╚═╝     ╚══════╝") + blue("║"))
-		fmt.Println(blue("║") + "                                                          " + blue("║"))
-		fmt.Println(blue("║") + "  " + purple("⚡") + white(" PULSE MONITOR") + cyan(" ⋮ ") + yellow("REPOSITORY ANALYZER") + purple(" ⚡") + "                  " + blue("║"))
-		fmt.Println(blue("║") + "  " + green("  Single Repository Health & Activity Scanner") + "               " + blue("║"))
-		fmt.Println(blue("║") + "                                                          " + blue("║"))
-		fmt.Println(blue("╚══════════════════════════════════════════════════════════╝"))
-		fmt.Println()
-
-		fmt.Println(yellow("✦ Repository Inactivity Analyzer ✦"))
-		fmt.Println(cyan("⟹ Analyzing a single repository for inactivity metrics"))
-		fmt.Println()
-	}
-
-	// Validate GitHub CLI installation
-	if err := analyzer.ValidateGitHubCLI(); err != nil {
-		log.Fatalf("❌ GitHub CLI validation failed: %v", err)
+		analyzer.Logf("\n🔬 Analyzing repositories in %s...\n", cfg.Organization)
 	}
 
-	// Validate repository name format
-	if cfg.SingleRepository == "" {
-		log.Fatal("❌ Repository name is required")
+	// Analyze repositories with the same core as the org command
+	analysis, err := analyzer.AnalyzeRepositories(cfg)
+	if err != nil {
+		failRun("❌ Analysis failed: %v", err)
 	}
 
-	// Extract org/repo from URL if a full GitHub URL is provided
-	repoFullName := cfg.SingleRepository
-	if strings.HasPrefix(repoFullName, "http") {
-		// Handle URLs like https://github.com/org/repo or http://github.com/org/repo
-		urlParts := strings.Split(repoFullName, "github.com/")
-		if len(urlParts) != 2 {
-			log.Fatalf("❌ Invalid GitHub URL format: %s", repoFullName)
-		}
+	// Save the per-repository cache for the next run
+	saveRepositoryCache()
 
-		// Get the org/repo part
-		repoFullName = strings.TrimPrefix(urlParts[1], "/")
+	stats := analyzer.ComputeOrgStats(analyzer.FilterRepositories(analysis.Repositories, cfg), analysis.Skipped, cfg)
+	if err := analyzer.OutputStats(stats, cfg); err != nil {
+		log.Fatalf("❌ Failed to output results: %v", err)
+	}
+
+	// Publish each result on its repository if requested
+	publishStatuses(analysis.Repositories, cfg)
 
-		// Remove any trailing slash or .git extension
-		repoFullName = strings.TrimSuffix(repoFullName, "/")
-		repoFullName = strings.TrimSuffix(repoFullName, ".git")
+	// Record the pseudonym of the redacted organization
+	saveRedactionMap()
+}
+
+// showTrend charts the flagged count over the JSON reports stored in a directory; it makes no API calls
+func showTrend(dir string, cfg config.Config) {
+	if !analyzer.IsTrendFormat(cfg.OutputFormat) {
+		log.Fatalf("❌ The trend command supports the console, csv, and json formats, got %s", cfg.OutputFormat)
 	}
 
-	if !cfg.Silent {
-		fmt.Printf("🔍 Analyzing repository: %s\n", repoFullName)
+	trend, err := analyzer.LoadTrend(dir, cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if err := analyzer.OutputTrend(trend, cfg); err != nil {
+		log.Fatalf("❌ Failed to output results: %v", err)
 	}
+}
+
+// analyzeSingleRepository analyzes a single repository
+func analyzeSingleRepository(cfg config.Config) {
+	// Validate configuration, authentication, and the GitHub CLI
+	warnings := prepareRun(cfg)
 
-	// Get repository parts (org/repo)
-	parts := strings.Split(repoFullName, "/")
-	if len(parts) != 2 {
-		log.Fatalf("❌ Invalid repository name format. Expected 'org/repo', got: %s", repoFullName)
+	// Display banner unless silent mode is enabled
+	displayBanner(repositoryBanner, cfg)
+
+	// Validate repository name format
+	if cfg.SingleRepository == "" {
+		log.Fatal("❌ Repository name is required")
 	}
 
-	// Analyze single repository directly without calling GetUserOrganizations
-	now := time.Now()
+	// Extract org/repo and an optional branch from the argument or URL
+	repoFullName, branch, err := analyzer.ParseRepoIdentifier(cfg.SingleRepository)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	cfg.Branch = branch
 
-	// Create repository object
-	repo := analyzer.Repository{
-		Name: repoFullName,
+	if !cfg.Silent {
+		analyzer.Logf("🔍 Analyzing repository: %s\n", repoDisplayName(repoFullName, branch))
 	}
 
 	// Validate repository exists and is accessible
-	cmd := exec.Command("gh", "api",
-		fmt.Sprintf("repos/%s", repoFullName),
-		"--silent")
-
-	if err := cmd.Run(); err != nil {
+	if err := analyzer.CheckRepositoryAccess(repoFullName); err != nil {
 		log.Fatalf("❌ Repository %s not found or not accessible: %v", repoFullName, err)
 	}
-	// Get organization name from full repository name
-	orgName := parts[0]
 
-	// Check if repository is archived
-	isArchived, err := analyzer.IsRepositoryArchived(repoFullName)
-	if err != nil {
-		log.Printf("⚠️ Warning: Failed to check if repository is archived: %v", err)
-	} else {
-		repo.Archived = isArchived
+	// Validate the requested branch exists
+	if branch != "" {
+		if err := analyzer.CheckBranchExists(repoFullName, branch); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
 	}
 
-	// Get last commit date
-	lastCommitDate, err := analyzer.GetLastCommitDate(repoFullName)
+	// Make sure the API quota can cover the analysis before starting it
+	quotaWarnings, err := analyzer.CheckQuota(1, cfg)
 	if err != nil {
-		log.Fatalf("❌ Failed to get last commit date: %v", err)
+		log.Fatalf("❌ %v", err)
 	}
-	repo.LastCommitDate = lastCommitDate
-	repo.DaysSinceLastCommit = int(now.Sub(lastCommitDate).Hours() / 24)
+	warnings = append(warnings, quotaWarnings...)
 
-	// Get contributors and check if they are still in the organization
-	activeContribs, inactiveContribs, err := analyzer.GetContributorsStatus(repoFullName, orgName)
+	// Analyze single repository directly without calling GetUserOrganizations
+	repo, repoWarnings, err := analyzer.AnalyzeRepository(repoFullName, cfg)
 	if err != nil {
-		log.Fatalf("❌ Failed to analyze contributors: %v", err)
+		log.Fatalf("❌ Failed to analyze repository: %v", err)
 	}
+	warnings = append(warnings, repoWarnings...)
 
-	repo.TotalContributors = activeContribs + inactiveContribs
-	repo.InactiveContributors = inactiveContribs
+	// Save the per-repository cache for the next run
+	saveRepositoryCache()
 
-	if repo.TotalContributors > 0 {
-		repo.InactivePercentage = float64(inactiveContribs) / float64(repo.TotalContributors)
+	// Output results for single repository
+	if err := analyzer.OutputSingleRepositoryResult(repo, warnings, cfg); err != nil {
+		log.Fatalf("❌ Failed to output results: %v", err)
 	}
 
-	// Flag repository based on criteria
-	isOld := repo.DaysSinceLastCommit > cfg.MaxCommitAgeInDays
+	// Email, file, and hand off the report as requested
+	deliverReport(analyzer.Analysis{Repositories: []analyzer.Repository{repo}, Warnings: warnings}, cfg)
 
-	if isOld {
-		if repo.TotalContributors > 0 {
-			// If there are contributors, flag if the inactive percentage meets the threshold
-			if repo.InactivePercentage >= cfg.InactiveContribThreshold {
-				repo.Flagged = true
-			}
-		} else {
-			// If there are no contributors, flag it simply for being old
-			repo.Flagged = true
-		}
+	// Record the pseudonyms of the redacted report
+	saveRedactionMap()
+}
+
+// analyzeLocalRepository analyzes a local git clone from its history and outputs it like a single repository
+func analyzeLocalRepository(cfg config.Config) {
+	// Validate the configuration without requiring the GitHub CLI
+	warnings := prepareRun(cfg)
+
+	// Display banner unless silent mode is enabled
+	displayBanner(localBanner, cfg)
+
+	if !cfg.Silent {
+		analyzer.Logf("🔍 Analyzing local clone: %s\n", cfg.LocalRepository)
 	}
 
-	// Output results for single repository
-	if err := analyzer.OutputSingleRepositoryResult(repo, cfg); err != nil {
+	repo, repoWarnings, err := analyzer.AnalyzeLocalRepository(cfg.LocalRepository, cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to analyze local clone: %v", err)
+	}
+	warnings = append(warnings, repoWarnings...)
+
+	// Output results like those of a single repository
+	if err := analyzer.OutputSingleRepositoryResult(repo, warnings, cfg); err != nil {
 		log.Fatalf("❌ Failed to output results: %v", err)
 	}
+
+	// Email the report and hand it off as requested; the GitHub delivery steps are rejected by Validate
+	deliverReport(analyzer.Analysis{Repositories: []analyzer.Repository{repo}, Warnings: warnings}, cfg)
+
+	// Record the pseudonyms of the redacted report
+	saveRedactionMap()
 }
 
-// analyzeRepositoriesFromFile analyzes repositories listed in a file
-func analyzeRepositoriesFromFile(cfg config.Config) {
-	// Display banner unless silent mode is enabled
-	if !cfg.Silent {
-		// Custom banner for file-based analysis
-		cyan := color.New(color.FgCyan).SprintFunc()
-		yellow := color.New(color.FgYellow).SprintFunc()
-		red := color.New(color.FgRed).SprintFunc()
-		green := color.New(color.FgGreen).SprintFunc()
-		purple := color.New(color.FgMagenta).SprintFunc()
-		blue := color.New(color.FgBlue).SprintFunc()
-		white := color.New(color.FgHiWhite, color.Bold).SprintFunc()
-
-		// Print a creative file analysis banner
-		fmt.Println()
-		fmt.Println(blue("┏━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━┓"))
-		fmt.Println(blue("┃") + "                                                          " + blue("┃"))
-		fmt.Println(blue("┃") + "   " + purple("📋") + white(" BATCH REPOSITORY ANALYZER ") + purple("📋") + "                       " + blue("┃"))
-		fmt.Println(blue("┃") + "                                                          " + blue("┃"))
-		fmt.Println(blue("┃") + "   " + yellow("🔍") + " " + green("Processing multiple repositories from file") + "           " + blue("┃"))
-		fmt.Println(blue("┃") + "   " + red("📊") + " " + cyan("Analyzing contributor activity and commit freshness") + "    " + blue("┃"))
-		fmt.Println(blue("┃") + "   " + green("📦") + " " + yellow("Identifying stale and abandoned repositories") + "         " + blue("┃"))
-		fmt.Println(blue("┃") + "                                                          " + blue("┃"))
-		fmt.Println(blue("┃") + "   " + white("REPO·PULSE") + " " + purple("※") + " " + white("VERSION 2025") + "                             " + blue("┃"))
-		fmt.Println(blue("┃") + "                                                          " + blue("┃"))
-		fmt.Println(blue("┗━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━┛"))
-		fmt.Println()
-
-		fmt.Println(yellow("✦ Repository Inactivity Analyzer - Batch Mode ✦"))
-		fmt.Println(cyan("⟹ Processing repositories from file"))
-		fmt.Println()
-	}
-
-	// Validate GitHub CLI installation
-	if err := analyzer.ValidateGitHubCLI(); err != nil {
-		log.Fatalf("❌ GitHub CLI validation failed: %v", err)
-	}
-
-	// Open the file containing repository names
-	file, err := os.Open(cfg.RepoListFile)
+// analyzeListedRepository resolves, validates, and analyzes a repository given by name or URL, returning
+// the warnings met analyzing it
+func analyzeListedRepository(identifier string, index, total int, cfg config.Config) (analyzer.Repository, []analyzer.Warning, error) {
+	// Extract org/repo and an optional branch from the identifier or URL
+	repoFullName, branch, err := analyzer.ParseRepoIdentifier(identifier)
 	if err != nil {
-		log.Fatalf("❌ Failed to open repository list file: %v", err)
+		return analyzer.Repository{Name: identifier}, nil, err
+	}
+	cfg.Branch = branch
+
+	if !cfg.Silent {
+		analyzer.Logf("📊 [%d/%d] Analyzing repository: %s\n", index, total, repoDisplayName(repoFullName, branch))
+	}
+
+	// Skip repositories no configured credential covers, before their access check fails less clearly
+	if err := analyzer.CheckCredential(repoFullName); err != nil {
+		return analyzer.Repository{Name: repoFullName}, nil, err
 	}
-	defer file.Close()
 
-	var repos []analyzer.Repository
-	now := time.Now()
+	// Validate repository exists and is accessible
+	if err := analyzer.CheckRepositoryAccess(repoFullName); err != nil {
+		return analyzer.Repository{Name: repoFullName}, nil, fmt.Errorf("repository %s not found or not accessible", repoFullName)
+	}
 
-	// Count total number of repositories for progress reporting
-	var totalRepos int
-	preScanner := bufio.NewScanner(file)
-	for preScanner.Scan() {
-		line := strings.TrimSpace(preScanner.Text())
-		if line != "" {
-			totalRepos++
+	// Validate the requested branch exists
+	if branch != "" {
+		if err := analyzer.CheckBranchExists(repoFullName, branch); err != nil {
+			return analyzer.Repository{Name: repoFullName, Branch: branch}, nil, err
 		}
 	}
 
-	// Reset file position for main scan
-	if _, err := file.Seek(0, 0); err != nil {
-		log.Fatalf("❌ Failed to reset file position: %v", err)
+	repo, warnings, err := analyzer.AnalyzeRepository(repoFullName, cfg)
+	if err != nil {
+		return repo, warnings, fmt.Errorf("failed to analyze %s: %w", repoFullName, err)
+	}
+
+	return repo, warnings, nil
+}
+
+// listedRepositorySummary renders the progress lines shown after a listed repository is analyzed
+// They are written at once so that repositories analyzed concurrently do not interleave, and name
+// the repository when its "Analyzing" line may be far above
+func listedRepositorySummary(repo analyzer.Repository, cfg config.Config) string {
+	var b strings.Builder
+	if cfg.Concurrency > 1 {
+		fmt.Fprintf(&b, "   ↳ Repository: %s\n", repoDisplayName(repo.Name, repo.Branch))
+	}
+	switch repo.CommitStatus {
+	case analyzer.CommitStatusEmpty:
+		fmt.Fprintf(&b, "   ↳ Last commit: none (empty repository)\n")
+	case analyzer.CommitStatusRestricted:
+		fmt.Fprintf(&b, "   ↳ Last commit: %s\n", color.YellowString("⚠️ access restricted"))
+	default:
+		fmt.Fprintf(&b, "   ↳ Last commit: %s (%d days ago)\n",
+			repo.LastCommitDate.Format("2006-01-02"), repo.DaysSinceLastCommit)
+	}
+	if repo.ContributorDataComplete {
+		fmt.Fprintf(&b, "   ↳ Contributors: %d total, %d inactive (%.1f%%)\n",
+			repo.TotalContributors, repo.InactiveContributors,
+			repo.InactivePercentage*100)
+	} else {
+		fmt.Fprintf(&b, "   ↳ Contributors: %s\n", color.YellowString("⚠️ data unavailable"))
+	}
+
+	if repo.Flagged {
+		fmt.Fprintf(&b, "   ↳ Status: %s\n", color.RedString("🚩 Flagged as inactive (%s)", repo.FlagReason))
+	} else {
+		fmt.Fprintf(&b, "   ↳ Status: %s\n", color.GreenString("✅ Active"))
 	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// repoDisplayName renders a repository name with its branch for progress output
+func repoDisplayName(repoFullName, branch string) string {
+	if branch == "" {
+		return repoFullName
+	}
+	return repoFullName + "@" + branch
+}
+
+// analyzeMultipleRepositories analyzes several repositories given on the command line and reports them together
+func analyzeMultipleRepositories(cfg config.Config) {
+	// Validate configuration, authentication, and the GitHub CLI
+	warnings := prepareRun(cfg)
 
-	// Read the file line by line
-	scanner := bufio.NewScanner(file)
-	repoCount := 0
+	// Display banner unless silent mode is enabled
+	displayBanner(multipleBanner, cfg)
+
+	analyzeRepositoryList(warnings, cfg)
+}
+
+// analyzeForks analyzes the direct forks of a repository and reports them together under it
+func analyzeForks(cfg config.Config) {
+	// Validate configuration, authentication, and the GitHub CLI
+	warnings := prepareRun(cfg)
+
+	// Display banner unless silent mode is enabled
+	displayBanner(forksBanner, cfg)
 
+	forks, err := analyzer.ListForks(cfg.ForksOf)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
 	if !cfg.Silent {
-		fmt.Printf("\n🔍 Starting analysis of %d repositories from %s\n\n", totalRepos, cfg.RepoListFile)
+		analyzer.Logf("🍴 Found %d forks of %s\n", len(forks), cfg.ForksOf)
 	}
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue // Skip empty lines
-		}
+	cfg.Repositories = forks
+	analyzeRepositoryList(warnings, cfg)
+}
 
-		repoCount++
-
-		// Extract org/repo from URL if a full GitHub URL is provided
-		repoFullName := line
-		if strings.HasPrefix(repoFullName, "http") {
-			// Handle URLs like https://github.com/org/repo or http://github.com/org/repo
-			urlParts := strings.Split(repoFullName, "github.com/")
-			if len(urlParts) != 2 {
-				if !cfg.Silent {
-					log.Printf("❌ Invalid GitHub URL format: %s (skipping)", repoFullName)
-				}
-				continue
-			}
+// analyzeRepositoryList analyzes the repositories of the configuration and outputs them as one report,
+// after the warnings met preparing the run
+func analyzeRepositoryList(warnings []analyzer.Warning, cfg config.Config) {
+	entries := make([]analyzer.RepoListEntry, len(cfg.Repositories))
+	for i, name := range cfg.Repositories {
+		entries[i] = analyzer.RepoListEntry{Identifier: name}
+	}
+	analyzeRepositoryNames(entries, warnings, cfg)
+}
 
-			// Get the org/repo part
-			repoFullName = strings.TrimPrefix(urlParts[1], "/")
+// analyzeRepositoriesFromFile analyzes repositories listed in a file
+func analyzeRepositoriesFromFile(cfg config.Config) {
+	// Validate configuration, authentication, and the GitHub CLI
+	warnings := prepareRun(cfg)
 
-			// Remove any trailing slash or .git extension
-			repoFullName = strings.TrimSuffix(repoFullName, "/")
-			repoFullName = strings.TrimSuffix(repoFullName, ".git")
-		}
+	// Display banner unless silent mode is enabled
+	displayBanner(fileBanner, cfg)
 
-		if !cfg.Silent {
-			fmt.Printf("📊 [%d/%d] Analyzing repository: %s\n", repoCount, totalRepos, repoFullName)
-		}
+	// Read the repositories listed in the file, with any extra CSV columns
+	entries, err := analyzer.ReadRepoList(cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
 
-		// Get repository parts (org/repo)
-		parts := strings.Split(repoFullName, "/")
-		if len(parts) != 2 {
-			if !cfg.Silent {
-				log.Printf("❌ Invalid repository name format. Expected 'org/repo', got: %s (skipping)", repoFullName)
-			}
-			continue
-		}
+	// Analyze each repository once, however many forms it is listed in
+	entries, duplicates := analyzer.DedupRepoList(entries)
+	if duplicates > 0 && !cfg.Silent {
+		analyzer.Logf("ℹ️ Collapsed %d duplicate repository entries\n", duplicates)
+	}
 
-		// Create repository object
-		repo := analyzer.Repository{
-			Name: repoFullName,
-		}
+	if !cfg.Silent {
+		analyzer.Logf("\n🔍 Starting analysis of %d repositories from %s\n\n", len(entries), cfg.RepoListFile)
+	}
 
-		// Validate repository exists and is accessible
-		cmd := exec.Command("gh", "api",
-			fmt.Sprintf("repos/%s", repoFullName),
-			"--silent")
+	analyzeRepositoryNames(entries, warnings, cfg)
+}
 
-		if err := cmd.Run(); err != nil {
-			if !cfg.Silent {
-				log.Printf("❌ Repository %s not found or not accessible (skipping)", repoFullName)
-			}
-			continue
-		}
+// analyzeRepositoryNames analyzes the named repositories, up to the configured number at a time, and outputs
+// them as one report in the order they were named, carrying any extra columns of the entries through to it
+// Repositories that fail are skipped with a warning, or stop the run with -strict.
+// The report carries the warnings met preparing the run, followed by those met analyzing each repository.
+func analyzeRepositoryNames(entries []analyzer.RepoListEntry, warnings []analyzer.Warning, cfg config.Config) {
+	total := len(entries)
 
-		// Get organization name from full repository name
-		orgName := parts[0]
+	// Make sure the API quota can cover the scan before starting it
+	quotaWarnings, err := analyzer.CheckQuota(total, cfg)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	warnings = append(warnings, quotaWarnings...)
 
-		// Get last commit date
-		if !cfg.Silent {
-			fmt.Printf("   ↳ Getting last commit date...")
-		}
-		lastCommitDate, err := analyzer.GetLastCommitDate(repoFullName)
-		if err != nil {
-			if !cfg.Silent {
-				log.Printf("\r❌ Failed to get last commit date for %s: %v (skipping)\n", repoFullName, err)
-			}
-			continue
-		}
-		repo.LastCommitDate = lastCommitDate
-		repo.DaysSinceLastCommit = int(now.Sub(lastCommitDate).Hours() / 24)
-		if !cfg.Silent {
-			fmt.Printf("\r   ↳ Last commit: %s (%d days ago)  \n",
-				lastCommitDate.Format("2006-01-02"), repo.DaysSinceLastCommit)
-		}
+	// Write repositories to the output file as they are analyzed if requested
+	if err := analyzer.StartOutputStream(total, analyzer.RepoListExtraColumns(entries), cfg); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
 
-		// Get contributors and check if they are still in the organization
-		if !cfg.Silent {
-			fmt.Printf("   ↳ Analyzing contributors...")
+	// Emit machine-readable progress if requested
+	progress := analyzer.NewProgressEmitter(cfg.ProgressFD, total)
+
+	// Show liveness in logs that are not a terminal
+	heartbeat := analyzer.NewHeartbeat(total, cfg)
+
+	analyzed := make([]analyzer.Repository, total)
+	repoWarnings := make([][]analyzer.Warning, total)
+	failed := make([]bool, total)
+	unstarted := make([]bool, total)
+	// In strict mode the first failure stops the repositories not yet started
+	err = analyzer.ForEach(total, cfg.Concurrency, func(i int) error {
+		// Once the deadline passes, the remaining repositories are left out of a partial report
+		if !analyzer.StartRepository(cfg) {
+			unstarted[i] = true
+			return nil
 		}
-		activeContribs, inactiveContribs, err := analyzer.GetContributorsStatus(repoFullName, orgName)
+		entry := entries[i]
+		repo, listedWarnings, err := analyzeListedRepository(entry.Identifier, i+1, total, cfg)
+		repoWarnings[i] = listedWarnings
+		repo.Extra = entry.Extra
+		progress.RepoCompleted(repo.Name)
+		heartbeat.RepoCompleted()
 		if err != nil {
-			if !cfg.Silent {
-				log.Printf("\r❌ Failed to analyze contributors for %s: %v (skipping)\n", repoFullName, err)
+			if cfg.Strict {
+				return err
 			}
-			continue
-		}
-
-		repo.TotalContributors = activeContribs + inactiveContribs
-		repo.InactiveContributors = inactiveContribs
-
-		if repo.TotalContributors > 0 {
-			repo.InactivePercentage = float64(inactiveContribs) / float64(repo.TotalContributors)
-		}
-
-		// Flag repository based on criteria
-		isOld := repo.DaysSinceLastCommit > cfg.MaxCommitAgeInDays
-
-		if isOld {
-			if repo.TotalContributors > 0 {
-				// If there are contributors, flag if the inactive percentage meets the threshold
-				if repo.InactivePercentage >= cfg.InactiveContribThreshold {
-					repo.Flagged = true
-				}
-			} else {
-				// If there are no contributors, flag it simply for being old
-				repo.Flagged = true
+			repoWarnings[i] = append(repoWarnings[i], analyzer.Warning{Repository: entry.Identifier, Message: err.Error(), Severity: analyzer.SeverityError})
+			if !cfg.Silent {
+				log.Printf("❌ %v (skipping)", err)
 			}
+			failed[i] = true
+			return nil
 		}
 
 		if !cfg.Silent {
-			fmt.Printf("\r   ↳ Contributors: %d total, %d inactive (%.1f%%)  \n",
-				repo.TotalContributors, repo.InactiveContributors,
-				repo.InactivePercentage*100)
-
-			if repo.Flagged {
-				fmt.Printf("   ↳ Status: %s\n", color.RedString("🚩 Flagged as inactive"))
-			} else {
-				fmt.Printf("   ↳ Status: %s\n", color.GreenString("✅ Active"))
-			}
-			fmt.Println()
+			analyzer.Logf("%s", listedRepositorySummary(repo, cfg))
 		}
-
-		repos = append(repos, repo)
+		repoWarnings[i] = append(repoWarnings[i], streamRepository(repo, cfg)...)
+		analyzed[i] = repo
+		return nil
+	})
+	heartbeat.Stop()
+	if err != nil {
+		failRun("❌ %v", err)
 	}
+	closeOutputStream()
 
-	if err := scanner.Err(); err != nil {
-		log.Fatalf("❌ Error reading repository list file: %v", err)
+	// Keep the order of the names whatever order the repositories finished in
+	analysis := analyzer.Analysis{Warnings: warnings}
+	for i, repo := range analyzed {
+		analysis.Warnings = append(analysis.Warnings, repoWarnings[i]...)
+		if unstarted[i] {
+			continue
+		}
+		if failed[i] {
+			analysis.Skipped++
+			continue
+		}
+		analysis.Repositories = append(analysis.Repositories, repo)
 	}
 
 	if !cfg.Silent {
-		fmt.Printf("✅ Analysis completed for %d repositories\n\n", len(repos))
+		analyzer.Logf("✅ Analysis completed for %d repositories\n\n", len(analysis.Repositories))
 	}
 
-	// Output results for file-based analysis
-	if err := analyzer.OutputResults(repos, cfg); err != nil {
+	// Save the per-repository cache for the next run
+	saveRepositoryCache()
+
+	// Output the combined results
+	if err := analyzer.OutputResults(analysis, cfg); err != nil {
 		log.Fatalf("❌ Failed to output results: %v", err)
 	}
+
+	// Email, file, and hand off the report as requested
+	deliverReport(analysis, cfg)
+
+	// Record the pseudonyms of the redacted report
+	saveRedactionMap()
 }
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/analyzer"
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestParseCommandArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		want       []string
+		wantFormat string
+		wantSilent bool
+	}{
+		{"no arguments", nil, nil, "", false},
+		{"flags first", []string{"-format", "json", "o/a", "o/b"}, []string{"o/a", "o/b"}, "json", false},
+		{"flags last", []string{"o/a", "o/b", "-format", "csv", "-silent"}, []string{"o/a", "o/b"}, "csv", true},
+		{"flags between", []string{"o/a", "-silent", "o/b"}, []string{"o/a", "o/b"}, "", true},
+		{"after --", []string{"-silent", "--", "o/a", "-format"}, []string{"o/a", "-format"}, "", true},
+		{"-- after a repository", []string{"o/a", "--", "-b"}, []string{"o/a", "-b"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := flag.NewFlagSet("repo", flag.ContinueOnError)
+			format := flags.String("format", "", "")
+			silent := flags.Bool("silent", false, "")
+
+			got := parseCommandArgs(flags, tt.args)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("positional arguments = %q, want %q", got, tt.want)
+			}
+			if *format != tt.wantFormat || *silent != tt.wantSilent {
+				t.Errorf("format %q and silent %v, want %q and %v", *format, *silent, tt.wantFormat, tt.wantSilent)
+			}
+		})
+	}
+}
+
+func TestListedRepositorySummary(t *testing.T) {
+	repo := analyzer.Repository{
+		Name:                    "o/r",
+		Branch:                  "dev",
+		LastCommitDate:          time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+		DaysSinceLastCommit:     31,
+		ContributorDataComplete: true,
+		TotalContributors:       4,
+		InactiveContributors:    1,
+		InactivePercentage:      0.25,
+	}
+	empty := repo
+	empty.CommitStatus = analyzer.CommitStatusEmpty
+	empty.ContributorDataComplete = false
+
+	tests := []struct {
+		name        string
+		repo        analyzer.Repository
+		concurrency int
+		want        []string
+		wantNot     []string
+	}{
+		{"sequential", repo, 1, []string{"Last commit: 2025-05-01 (31 days ago)", "4 total, 1 inactive (25.0%)", "Active"}, []string{"Repository:"}},
+		{"concurrent names the repository", repo, 4, []string{"Repository: o/r@dev"}, nil},
+		{"empty without contributor data", empty, 1, []string{"Last commit: none (empty repository)", "Contributors: ⚠️ data unavailable"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := listedRepositorySummary(tt.repo, config.Config{Concurrency: tt.concurrency})
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("summary %q does not contain %q", got, want)
+				}
+			}
+			for _, unwanted := range tt.wantNot {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("summary %q contains %q", got, unwanted)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"runtime/pprof"
+	"runtime/trace"
+
+	"github.com/harekrishnarai/inactivity/pkg/analyzer"
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// Profile files being written, nil when profiling is off or stopped
+var (
+	cpuProfileFile *os.File
+	traceFile      *os.File
+	profileSilent  bool // Whether to skip reporting the written files
+)
+
+// startProfiling starts writing the -cpuprofile and -trace files, if requested
+func startProfiling(cfg config.Config) {
+	profileSilent = cfg.Silent
+	if cfg.CPUProfile != "" {
+		f, err := os.Create(cfg.CPUProfile)
+		if err != nil {
+			log.Fatalf("❌ Failed to create CPU profile: %v", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			log.Fatalf("❌ Failed to start CPU profile: %v", err)
+		}
+		cpuProfileFile = f
+	}
+
+	if cfg.Trace != "" {
+		f, err := os.Create(cfg.Trace)
+		if err != nil {
+			log.Fatalf("❌ Failed to create trace: %v", err)
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			log.Fatalf("❌ Failed to start trace: %v", err)
+		}
+		traceFile = f
+	}
+}
+
+// stopProfiling finishes the profile files started by startProfiling, if any; it is safe to call more than once
+func stopProfiling() {
+	if cpuProfileFile != nil {
+		pprof.StopCPUProfile()
+		closeProfile(cpuProfileFile, "CPU profile")
+		cpuProfileFile = nil
+	}
+	if traceFile != nil {
+		trace.Stop()
+		closeProfile(traceFile, "trace")
+		traceFile = nil
+	}
+}
+
+// closeProfile closes a finished profile file, reporting where it was written
+func closeProfile(f *os.File, kind string) {
+	if err := f.Close(); err != nil {
+		log.Printf("❌ Failed to write %s: %v", kind, err)
+		return
+	}
+	if !profileSilent {
+		analyzer.Logf("⏱️ Wrote %s to %s\n", kind, f.Name())
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/analyzer"
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestProfiling(t *testing.T) {
+	tests := []struct {
+		name      string
+		cpu       bool
+		trace     bool
+		wantNotes []string
+	}{
+		{"off", false, false, nil},
+		{"CPU profile", true, false, []string{"Wrote CPU profile to"}},
+		{"trace", false, true, []string{"Wrote trace to"}},
+		{"both", true, true, []string{"Wrote CPU profile to", "Wrote trace to"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var log bytes.Buffer
+			analyzer.SetLogOutput(&log)
+			t.Cleanup(func() { analyzer.SetLogOutput(os.Stdout) })
+
+			dir := t.TempDir()
+			var cfg config.Config
+			if tt.cpu {
+				cfg.CPUProfile = filepath.Join(dir, "cpu.pprof")
+			}
+			if tt.trace {
+				cfg.Trace = filepath.Join(dir, "run.trace")
+			}
+
+			startProfiling(cfg)
+			// Some work for the profiles to record
+			sum := 0
+			for i := range 1_000_000 {
+				sum += i % 7
+			}
+			_ = sum
+			stopProfiling()
+			stopProfiling()
+
+			// A pprof profile is gzipped and a trace starts with its Go version header
+			checkProfile(t, cfg.CPUProfile, "\x1f\x8b")
+			checkProfile(t, cfg.Trace, "go 1.")
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := len(tt.wantNotes); len(entries) != want {
+				t.Errorf("wrote %d files, want %d", len(entries), want)
+			}
+			for _, note := range tt.wantNotes {
+				if !strings.Contains(log.String(), note) {
+					t.Errorf("log %q does not mention %q", log.String(), note)
+				}
+			}
+		})
+	}
+}
+
+// checkProfile fails the test unless a requested profile file was written with the given header
+func checkProfile(t *testing.T, path, header string) {
+	t.Helper()
+	if path == "" {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), header) {
+		t.Errorf("%s starts with %q, want %q", filepath.Base(path), data[:min(len(data), 8)], header)
+	}
+}
@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Contributor scopes deciding where a contributor's activity is measured
+const (
+	ContributorScopeRepo = "repo"
+	ContributorScopeOrg  = "org"
+)
+
+// ContributorActivitySource reports when a contributor last committed anywhere in an organization
+// A zero time means no commit was found
+type ContributorActivitySource interface {
+	LastOrgCommitDate(login, orgName string) (time.Time, error)
+}
+
+// searchActivitySource looks up a contributor's latest org commit with the commit search API
+type searchActivitySource struct{}
+
+// LastOrgCommitDate returns the date of the contributor's most recent commit in the organization,
+// up to the pinned as-of date if any
+func (searchActivitySource) LastOrgCommitDate(login, orgName string) (time.Time, error) {
+	q := fmt.Sprintf("author:%s org:%s", login, orgName)
+	if !asOf.IsZero() {
+		q += " committer-date:<=" + asOf.UTC().Format("2006-01-02")
+	}
+	query := url.QueryEscape(q)
+	out, err := runGH("api",
+		fmt.Sprintf("search/commits?q=%s&sort=committer-date&order=desc&per_page=1", query),
+		"--jq", ".items[0].commit.committer.date // empty")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to search commits for %s: %w", login, err)
+	}
+
+	dateStr := strings.TrimSpace(string(out))
+	if dateStr == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339, dateStr)
+}
+
+// cachedActivitySource memoizes per-contributor results for the duration of a run
+type cachedActivitySource struct {
+	source  ContributorActivitySource
+	mu      sync.Mutex
+	results map[string]time.Time
+}
+
+func newCachedActivitySource(source ContributorActivitySource) *cachedActivitySource {
+	return &cachedActivitySource{
+		source:  source,
+		results: make(map[string]time.Time),
+	}
+}
+
+// LastOrgCommitDate returns the cached result or queries the underlying source once
+func (c *cachedActivitySource) LastOrgCommitDate(login, orgName string) (time.Time, error) {
+	key := strings.ToLower(orgName + "/" + login)
+
+	c.mu.Lock()
+	date, ok := c.results[key]
+	c.mu.Unlock()
+	if ok {
+		return date, nil
+	}
+
+	date, err := c.source.LastOrgCommitDate(login, orgName)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	c.mu.Lock()
+	c.results[key] = date
+	c.mu.Unlock()
+
+	return date, nil
+}
+
+// activitySource is used for org-scoped contributor classification
+var activitySource ContributorActivitySource = newCachedActivitySource(searchActivitySource{})
+
+// SetContributorActivitySource replaces the source used for org-scoped contributor activity
+func SetContributorActivitySource(source ContributorActivitySource) {
+	activitySource = newCachedActivitySource(source)
+}
+
+// GetOrgContributorsStatus classifies contributors as active when they committed to any
+// repository in the organization since the given time, returning the logins of active and inactive ones
+func GetOrgContributorsStatus(repoFullName, orgName string, since time.Time) (active, inactive []string, err error) {
+	contributors, err := GetContributors(repoFullName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, contributor := range contributors {
+		lastCommit, err := activitySource.LastOrgCommitDate(contributor, orgName)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !lastCommit.IsZero() && lastCommit.After(since) {
+			active = append(active, contributor)
+		} else {
+			inactive = append(inactive, contributor)
+		}
+	}
+
+	return active, inactive, nil
+}
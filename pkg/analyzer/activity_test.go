@@ -0,0 +1,161 @@
+package analyzer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// fakeActivitySource answers org commit dates from a map and counts its lookups
+type fakeActivitySource struct {
+	dates   map[string]time.Time
+	err     error
+	lookups int
+}
+
+func (f *fakeActivitySource) LastOrgCommitDate(login, orgName string) (time.Time, error) {
+	f.lookups++
+	return f.dates[login], f.err
+}
+
+// useActivitySource replaces the org activity source for the duration of a test
+func useActivitySource(t *testing.T, source ContributorActivitySource) {
+	t.Helper()
+	previous := activitySource
+	SetContributorActivitySource(source)
+	t.Cleanup(func() { activitySource = previous })
+}
+
+func TestGetOrgContributorsStatus(t *testing.T) {
+	since := testNow.AddDate(0, 0, -90)
+	tests := []struct {
+		name         string
+		dates        map[string]time.Time
+		err          error
+		wantActive   []string
+		wantInactive []string
+		wantErr      bool
+	}{
+		{
+			name:         "committed elsewhere in the organization",
+			dates:        map[string]time.Time{"ann": testNow.AddDate(0, 0, -5), "bob": testNow.AddDate(0, 0, -200)},
+			wantActive:   []string{"ann"},
+			wantInactive: []string{"bob", "cy"},
+		},
+		{
+			name:    "search failure",
+			err:     errors.New("search unavailable"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeGH(t, `printf 'ann\nbob\ncy\n'`)
+			SetCacheTTL(0)
+			useActivitySource(t, &fakeActivitySource{dates: tt.dates, err: tt.err})
+
+			active, inactive, err := GetOrgContributorsStatus("o/r", "o", since)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetOrgContributorsStatus error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(active, tt.wantActive) || !reflect.DeepEqual(inactive, tt.wantInactive) {
+				t.Errorf("active %q and inactive %q, want %q and %q", active, inactive, tt.wantActive, tt.wantInactive)
+			}
+		})
+	}
+}
+
+func TestCachedActivitySource(t *testing.T) {
+	source := &fakeActivitySource{dates: map[string]time.Time{"ann": testNow}}
+	cached := newCachedActivitySource(source)
+	// Logins differing only in case are looked up once
+	for _, login := range []string{"ann", "Ann", "ann"} {
+		if date, err := cached.LastOrgCommitDate(login, "o"); err != nil || !date.Equal(testNow) {
+			t.Errorf("LastOrgCommitDate(%s) = %v, %v, want %v", login, date, err, testNow)
+		}
+	}
+	if _, err := cached.LastOrgCommitDate("ann", "other"); err != nil {
+		t.Fatal(err)
+	}
+	if source.lookups != 2 {
+		t.Errorf("looked up %d times, want once per organization and login", source.lookups)
+	}
+}
+
+func TestSearchActivitySource(t *testing.T) {
+	tests := []struct {
+		name      string
+		script    string
+		asOf      time.Time
+		want      time.Time
+		wantQuery string
+		wantErr   bool
+	}{
+		{"latest commit", `echo 2025-05-20T10:00:00Z`, time.Time{}, time.Date(2025, 5, 20, 10, 0, 0, 0, time.UTC), "author%3Aann+org%3Ao&", false},
+		{"no commit", `exit 0`, time.Time{}, time.Time{}, "author%3Aann+org%3Ao&", false},
+		{"as of a date", `exit 0`, testNow, time.Time{}, "committer-date%3A%3C%3D2025-06-01", false},
+		{"failure", `echo 'gh: Forbidden (HTTP 403)' >&2; exit 1`, time.Time{}, time.Time{}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			SetCacheTTL(0)
+			SetAsOf(tt.asOf)
+			t.Cleanup(func() { SetAsOf(time.Time{}) })
+
+			got, err := searchActivitySource{}.LastOrgCommitDate("ann", "o")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LastOrgCommitDate error = %v, want error %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("LastOrgCommitDate = %v, want %v", got, tt.want)
+			}
+			if calls := ghCalls(t, logPath); len(calls) != 1 || !strings.Contains(calls[0], tt.wantQuery) {
+				t.Errorf("gh calls = %q, want a search containing %q", calls, tt.wantQuery)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepositoryContributorWindow(t *testing.T) {
+	// cy last committed in the organization 200 days ago and dee 100 days ago
+	dates := map[string]time.Time{"cy": *testDaysAgo(200), "dee": *testDaysAgo(100)}
+
+	tests := []struct {
+		name            string
+		contributorDays int
+		lastCommit      int
+		wantInactive    int
+		wantFlagged     bool
+	}{
+		{"window defaults to the commit age", 0, 300, 0, false},
+		{"shorter contributor window", 180, 300, 1, false},
+		{"commit age still decides staleness", 180, 400, 1, true},
+		{"longer contributor window", 250, 400, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pinNow(t)
+			fakeGH(t, analyzeScript(`{}`, tt.lastCommit))
+			SetCacheTTL(0)
+			useActivitySource(t, &fakeActivitySource{dates: dates})
+
+			cfg := config.Config{MaxCommitAgeInDays: 365, ContributorDays: tt.contributorDays, InactiveContribThreshold: 0.5,
+				ContributorScope: ContributorScopeOrg, Silent: true}
+			repo, _, err := AnalyzeRepository("o/r", cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if repo.TotalContributors != 2 || repo.InactiveContributors != tt.wantInactive {
+				t.Errorf("%d of %d contributors inactive, want %d of 2", repo.InactiveContributors, repo.TotalContributors, tt.wantInactive)
+			}
+			if repo.Flagged != tt.wantFlagged {
+				t.Errorf("flagged = %v (%s), want %v", repo.Flagged, repo.FlagReason, tt.wantFlagged)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetRepositoryAdmins returns the logins of collaborators with admin permission on a repository
+func GetRepositoryAdmins(repoFullName string) ([]string, error) {
+	out, err := runGH("api",
+		fmt.Sprintf("repos/%s/collaborators?permission=admin&per_page=100", repoFullName),
+		"--paginate",
+		"--jq", ".[].login")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository admins: %w", err)
+	}
+
+	return parseLogins(string(out)), nil
+}
+
+// parseLogins splits newline-separated logins, dropping empty lines
+func parseLogins(out string) []string {
+	var logins []string
+	for _, login := range strings.Split(strings.TrimSpace(out), "\n") {
+		if login = strings.TrimSpace(login); login != "" {
+			logins = append(logins, login)
+		}
+	}
+	return logins
+}
@@ -1,12 +1,13 @@
 package analyzer
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"os/exec"
+	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
@@ -17,44 +18,173 @@ import (
 // Repository represents a GitHub repository with its inactivity status
 type Repository struct {
 	Name                 string    `json:"name"`
+	Branch               string    `json:"branch,omitempty"`
+	Path                 string    `json:"path,omitempty"`   // Subdirectory the commits were scoped to
+	Parent               string    `json:"parent,omitempty"` // Repository this one was forked from
 	LastCommitDate       time.Time `json:"lastCommitDate"`
 	DaysSinceLastCommit  int       `json:"daysSinceLastCommit"`
+	CommitStatus         string    `json:"commitStatus,omitempty"` // Why commits could not be listed: empty or restricted
 	TotalContributors    int       `json:"totalContributors"`
 	InactiveContributors int       `json:"inactiveContributors"`
 	InactivePercentage   float64   `json:"inactivePercentage"`
 	Archived             bool      `json:"archived"`
+	IssuesEnabled        bool      `json:"issuesEnabled"`
+	HasDiscussions       bool      `json:"hasDiscussions"`
+	Visibility           string    `json:"visibility,omitempty"` // public, private, or internal, when governance is checked
+	License              string    `json:"license"`
+	HasReadme            *bool     `json:"hasReadme,omitempty"`       // whether GitHub shows a README, when READMEs are checked
+	ReadmeSizeBytes      int       `json:"readmeSizeBytes,omitempty"` // size of the README, when there is one
 	Flagged              bool      `json:"flagged"`
+	FlagReason           string    `json:"flagReason,omitempty"`
+	HighPriority         bool      `json:"highPriority,omitempty"`
+	Lifecycle            string    `json:"lifecycle,omitempty"`    // active, maintenance, stale, abandoned, or archived, when classified
+	Undocumented         bool      `json:"undocumented,omitempty"` // flagged without a README, when undocumented repositories are prioritized
+	Unlicensed           bool      `json:"unlicensed,omitempty"`   // flagged without a license, when unlicensed repositories are prioritized
+	Admins               []string  `json:"admins,omitempty"`
+	Contact              string    `json:"contact,omitempty"` // who to notify about a flagged repository, from the owners map
+	SinceLastRun         string    `json:"sinceLastRun,omitempty"`
+
+	// ContributorDataComplete is false when contributor data could not be retrieved
+	ContributorDataComplete bool `json:"contributorDataComplete"`
+
+	// WeightedInactivePercentage is the share of the last year's commits made by the contributors now inactive,
+	// when contributors are weighted (nil when none of them committed in that year)
+	WeightedInactivePercentage *float64 `json:"weightedInactivePercentage,omitempty"`
+
+	// SuspendedContributors counts the inactive contributors whose account is suspended, when suspensions are checked
+	SuspendedContributors int `json:"suspendedContributors,omitempty"`
+
+	// InactiveContributorDetails explains each inactive contributor when contributor details are requested
+	InactiveContributorDetails []InactiveContributor `json:"inactiveContributorDetails,omitempty"`
+
+	// ActiveContributorLogins and DepartedContributors retain the contributors of the repository for the contributor report
+	ActiveContributorLogins []string              `json:"-"`
+	DepartedContributors    []InactiveContributor `json:"-"`
+
+	// LastSubstantiveCommitDate is the last non-merge, non-bot commit, when substantive commit detection is enabled
+	LastSubstantiveCommitDate  *time.Time `json:"lastSubstantiveCommitDate,omitempty"`
+	DaysSinceSubstantiveCommit int        `json:"daysSinceSubstantiveCommit,omitempty"`
+
+	// SignedCommitRatio is the share of recent commits with a verified signature, when signing is checked
+	// SecurityReview marks flagged repositories whose ratio is below the configured minimum
+	SignedCommitRatio *float64 `json:"signedCommitRatio,omitempty"`
+	SecurityReview    bool     `json:"securityReview,omitempty"`
+
+	// LastCIStatus and LastCIDate describe the latest workflow run, when CI status is checked
+	LastCIStatus string     `json:"lastCIStatus,omitempty"`
+	LastCIDate   *time.Time `json:"lastCIDate,omitempty"`
+
+	// LastMergedPRDate is when the last pull request was merged, when merged pull requests are checked
+	// (nil when none of the recently closed pull requests was merged)
+	LastMergedPRDate *time.Time `json:"lastMergedPRDate,omitempty"`
+
+	// LastTag is the newest tag and LastTagDate the date of its commit, when tags are checked
+	// (empty and nil when the repository has no tags)
+	LastTag     string     `json:"lastTag,omitempty"`
+	LastTagDate *time.Time `json:"lastTagDate,omitempty"`
+
+	// MedianIssueResponseHours is the median time to the first maintainer comment on recent issues, when
+	// engagement is measured (nil when none was answered); UnansweredIssues counts those left without one
+	// after a week, out of IssuesSampled
+	MedianIssueResponseHours *float64 `json:"medianIssueResponseHours,omitempty"`
+	UnansweredIssues         int      `json:"unansweredIssues,omitempty"`
+	IssuesSampled            int      `json:"issuesSampled,omitempty"`
+
+	// NewestBranch is the other branch with the newest commit and NewestBranchCommitDate the date of that
+	// commit, when branches are checked (empty and nil when the analyzed branch is the only one)
+	NewestBranch           string     `json:"newestBranch,omitempty"`
+	NewestBranchCommitDate *time.Time `json:"newestBranchCommitDate,omitempty"`
+
+	// CommitMomentum is the commit count of the last 90 days minus that of the 90 days before,
+	// when momentum is measured (negative when activity is slowing down)
+	CommitMomentum *int `json:"commitMomentum,omitempty"`
+
+	// MedianCommitGapDays is the median gap between consecutive recent commits, when cadence is measured
+	// (nil when the branch has fewer than two commits)
+	MedianCommitGapDays *float64 `json:"medianCommitGapDays,omitempty"`
+
+	// StaleBotPRCount counts the open pull requests opened by bots more than the age threshold ago,
+	// when bot pull requests are checked
+	StaleBotPRCount *int `json:"staleBotPRCount,omitempty"`
+
+	// OpenSecurityAlerts is the number of open Dependabot alerts, when security alerts are checked
+	// SecurityAlertsUnknown is set instead when the alerts are disabled or not readable
+	// UrgentSecurity marks flagged repositories that still carry open alerts
+	OpenSecurityAlerts    *int `json:"openSecurityAlerts,omitempty"`
+	SecurityAlertsUnknown bool `json:"securityAlertsUnknown,omitempty"`
+	UrgentSecurity        bool `json:"urgentSecurity,omitempty"`
+
+	// OutsideCollaborators counts the collaborators who are not organization members, when governance
+	// is checked (nil when the caller may not list collaborators); ExposedToOutsiders marks flagged
+	// public repositories that have any
+	OutsideCollaborators *int `json:"outsideCollaborators,omitempty"`
+	ExposedToOutsiders   bool `json:"exposedToOutsiders,omitempty"`
+
+	// CreatedAt is when the repository was created, and RepoAgeDays its age at analysis time
+	CreatedAt   time.Time `json:"createdAt"`
+	RepoAgeDays int       `json:"repoAgeDays"`
+
+	// Extra holds the columns carried over from a CSV repository list, keyed by header name
+	Extra map[string]string `json:"extra,omitempty"`
 }
 
-// ValidateGitHubCLI checks if GitHub CLI is installed and authenticated
-func ValidateGitHubCLI() error {
-	// Check if gh is installed
-	cmd := exec.Command("gh", "--version")
+// ErrContributorDataUnavailable is returned when GitHub refuses to list a repository's contributors
+var ErrContributorDataUnavailable = errors.New("contributor data unavailable")
+
+// ValidateGitHubCLI checks if GitHub CLI is installed and authenticated, and that the token
+// has the scopes the scan needs; missing scopes are returned as a warning, or an error in strict mode
+func ValidateGitHubCLI(cfg config.Config) ([]Warning, error) {
+	// Check if gh is installed, or runs from the configured path
+	cmd := ghCommand("--version")
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("GitHub CLI (gh) is not installed or not in PATH: %w", err)
+		if cfg.GHPath != "" {
+			return nil, fmt.Errorf("GitHub CLI at %s cannot be run: %w", cfg.GHPath, err)
+		}
+		return nil, fmt.Errorf("GitHub CLI (gh) is not installed or not in PATH: %w", err)
+	}
+
+	// Tokens come from the credentials file, so gh itself need not be logged in
+	if credentials != nil {
+		return nil, nil
 	}
 
 	// Check if gh is authenticated
-	cmd = exec.Command("gh", "auth", "status")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("GitHub CLI is not authenticated: %w", err)
+	status, err := getAuthStatus()
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	// GitHub App installation tokens carry permissions rather than scopes
+	if tokenSource != nil {
+		return nil, nil
+	}
+
+	scopes, ok := parseTokenScopes(status)
+	if !ok {
+		return nil, nil
+	}
+	if missing := missingScopes(scopes, cfg); len(missing) > 0 {
+		if cfg.Strict {
+			return nil, fmt.Errorf("GitHub token is missing the %s scope(s), run: gh auth refresh -s %s",
+				strings.Join(missing, ", "), strings.Join(missing, ","))
+		}
+		warnings := &warningLog{}
+		warnings.warn(cfg, "", "GitHub token is missing the %s scope(s), so private membership or repositories may not be visible (run: gh auth refresh -s %s)",
+			strings.Join(missing, ", "), strings.Join(missing, ","))
+		return warnings.list(), nil
+	}
+
+	return nil, nil
 }
 
 // GetUserOrganizations returns a list of organizations the authenticated user has access to
 func GetUserOrganizations() ([]string, error) {
-	cmd := exec.Command("gh", "api", "user/memberships/orgs", "--jq", ".[].organization.login")
-	var out bytes.Buffer
-	cmd.Stdout = &out
-
-	err := cmd.Run()
+	out, err := runGH("api", "user/memberships/orgs", "--jq", ".[].organization.login")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get organizations: %w", err)
 	}
 
-	orgs := strings.Split(strings.TrimSpace(out.String()), "\n")
+	orgs := strings.Split(strings.TrimSpace(string(out)), "\n")
 	// Filter out empty strings
 	var result []string
 	for _, org := range orgs {
@@ -66,106 +196,48 @@ func GetUserOrganizations() ([]string, error) {
 	return result, nil
 }
 
-// DisplayBanner prints a beautiful banner for the tool unless silent mode is enabled
-// showOrgBanner controls whether to show organization-related information
-func DisplayBanner(silent bool, showOrgBanner bool) {
-	if silent {
-		return
-	}
-	// Try to use colors if available, but don't fail if they're not supported
-	cyan := color.New(color.FgCyan).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
-	green := color.New(color.FgGreen).SprintFunc()
-	purple := color.New(color.FgMagenta).SprintFunc()
-	blue := color.New(color.FgBlue).SprintFunc()
-	white := color.New(color.FgHiWhite, color.Bold).SprintFunc()
-
-	// Print a creative new banner
-	fmt.Println()
-	fmt.Println(blue("╭─────────────────────────────────────────────────────────────╮"))
-	fmt.Println(blue("│") + "                                                             " + blue("│"))
-	fmt.Println(blue("│") + "   " + purple("⚡") + white(" GITHUB REPOSITORY PULSE CHECK ") + purple("⚡") + "                      " + blue("│"))
-	fmt.Println(blue("│") + "                                                             " + blue("│"))
-	fmt.Println(blue("│") + "   " + cyan("[ ") + green("Activity") + cyan(" | ") + yellow("Contributors") + cyan(" | ") + red("Health") + cyan(" ]") + "                            " + blue("│"))
-	fmt.Println(blue("│") + "                                                             " + blue("│"))
-	fmt.Println(blue("│") + "   " + yellow("📊") + " " + white("Uncovering repository health since 2025") + "             " + blue("│"))
-	fmt.Println(blue("│") + "   " + green("🔍") + " " + cyan("Identifying inactive repositories in your organization") + " " + blue("│"))
-	fmt.Println(blue("│") + "                                                             " + blue("│"))
-	fmt.Println(blue("╰─────────────────────────────────────────────────────────────╯"))
-	fmt.Println()
-
-	fmt.Println(yellow("✦ Repository Inactivity Analyzer ✦"))
-
-	// Only show organization-related information if showOrgBanner is true
-	if showOrgBanner {
-		fmt.Println(cyan("Find and track inactive repositories in your GitHub organizations"))
-		fmt.Println()
-	} else {
-		fmt.Println(cyan("Analyzing a single repository for inactivity metrics"))
-		fmt.Println()
-	}
+// Analysis is the result of analyzing repositories, from which their report is rendered
+type Analysis struct {
+	Repositories []Repository // Analyzed repositories, in the order they were listed
+	Skipped      int          // Repositories skipped because their analysis failed
+	Warnings     []Warning    // Non-fatal issues met during the analysis, in the order they occurred
 }
 
 // AnalyzeRepositories analyzes all repositories in the given organization
-func AnalyzeRepositories(cfg config.Config) ([]Repository, error) {
-	// Use pagination to get all repositories in the organization
-	// We'll start with a higher limit and implement pagination logic
-	var allRepos []struct {
-		Name string `json:"name"`
+// The analysis also counts the repositories skipped because their analysis failed, and carries the warnings met
+func AnalyzeRepositories(cfg config.Config) (Analysis, error) {
+	// Get the repository names, from the repository list cache if it is fresh
+	allRepos, err := listOrganizationRepositories(cfg)
+	if err != nil {
+		return Analysis{}, err
 	}
 
-	page := 1
-	perPage := 100 // GitHub API typically uses 100 as maximum per page
-
-	for {
-		if !cfg.Silent {
-			fmt.Printf("📄 Fetching page %d of repositories...\n", page)
-		}
-
-		cmd := exec.Command("gh", "api",
-			fmt.Sprintf("orgs/%s/repos?per_page=%d&page=%d", cfg.Organization, perPage, page),
-			"--jq", ".[].name")
-
-		var out bytes.Buffer
-		cmd.Stdout = &out
-		cmd.Stderr = os.Stderr
-
-		if err := cmd.Run(); err != nil {
-			return nil, fmt.Errorf("failed to list repositories on page %d: %w", page, err)
-		}
-
-		// Get repo names from the output
-		repoNames := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if !cfg.Silent {
+		Logf("📂 Found %d repositories in %s\n", len(allRepos), cfg.Organization)
+	}
 
-		// If we got fewer items than perPage or empty response, we've reached the end
-		if len(repoNames) == 0 || (len(repoNames) == 1 && repoNames[0] == "") {
-			break
-		}
+	warnings := &warningLog{}
 
-		// Add repos to our collection
-		for _, name := range repoNames {
-			if name != "" { // Skip empty lines
-				allRepos = append(allRepos, struct {
-					Name string `json:"name"`
-				}{Name: name})
-			}
-		}
-
-		// Check if we got fewer items than the maximum per page, which means we're done
-		if len(repoNames) < perPage {
-			break
+	// Keep only the repositories the given user can act on if requested
+	if cfg.AdminOf != "" {
+		allRepos, err = filterAdminRepositories(allRepos, cfg, warnings)
+		if err != nil {
+			return Analysis{}, err
 		}
+	}
 
-		page++
+	// Make sure the API quota can cover the scan before starting it
+	quotaWarnings, err := CheckQuota(len(allRepos), cfg)
+	if err != nil {
+		return Analysis{}, err
 	}
+	warnings.record(quotaWarnings...)
 
-	if !cfg.Silent {
-		fmt.Printf("📂 Found %d repositories in %s\n", len(allRepos), cfg.Organization)
+	// Write repositories to the output file as they are analyzed if requested
+	if err := StartOutputStream(len(allRepos), nil, cfg); err != nil {
+		return Analysis{}, err
 	}
 
-	var results []Repository
-	now := time.Now()
 	startTime := time.Now()
 
 	// Define color functions for progress bar if not in silent mode
@@ -174,9 +246,13 @@ func AnalyzeRepositories(cfg config.Config) ([]Repository, error) {
 		cyan = color.New(color.FgCyan).SprintFunc()
 	}
 
+	// Log a periodic heartbeat instead of the animated progress bar when the log is not a terminal
+	heartbeat := NewHeartbeat(len(allRepos), cfg)
+	defer heartbeat.Stop()
+
 	// Create progress bar
 	var bar *progressbar.ProgressBar
-	if !cfg.Silent {
+	if !cfg.Silent && heartbeat == nil {
 		// Create a colorful progress bar like popular scanner tools
 		bar = progressbar.NewOptions(len(allRepos),
 			progressbar.OptionEnableColorCodes(false), // Set to false if using custom color functions for description
@@ -193,102 +269,145 @@ func AnalyzeRepositories(cfg config.Config) ([]Repository, error) {
 			progressbar.OptionThrottle(100*time.Millisecond),
 			progressbar.OptionShowIts(),
 			progressbar.OptionSetItsString("repos"),
+			progressbar.OptionSetWriter(LogOutput()),
 			progressbar.OptionClearOnFinish(),
 			progressbar.OptionSetPredictTime(true),
 			progressbar.OptionFullWidth(),
 			progressbar.OptionOnCompletion(func() {
-				fmt.Printf("\n%s\n", color.New(color.FgGreen).Sprint("✅ Analysis complete!"))
+				Logf("\n%s\n", color.New(color.FgGreen).Sprint("✅ Analysis complete!"))
 			}),
 		)
 	}
 
-	// Analyze each repository
-	for i, repo := range allRepos {
-		repoFullName := fmt.Sprintf("%s/%s", cfg.Organization, repo.Name)
-		r := Repository{
-			Name: repoFullName,
+	// Emit machine-readable progress if requested
+	progress := NewProgressEmitter(cfg.ProgressFD, len(allRepos))
+
+	// Analyze each repository, up to the configured number at a time
+	analyzed := make([]Repository, len(allRepos))
+	failures := make([]error, len(allRepos))
+	unstarted := make([]bool, len(allRepos))
+	var mu sync.Mutex
+	done := 0
+
+	// In strict mode the scan stops at the first failure, so nothing more is started
+	err = ForEach(len(allRepos), cfg.Concurrency, func(i int) error {
+		// Once the deadline passes, the remaining repositories are left out of a partial report
+		if !StartRepository(cfg) {
+			unstarted[i] = true
+			return nil
+		}
+
+		repoFullName := fmt.Sprintf("%s/%s", cfg.Organization, allRepos[i])
+
+		r, repoWarnings, err := AnalyzeRepository(repoFullName, cfg)
+		warnings.record(repoWarnings...)
+		progress.RepoCompleted(repoFullName)
+		heartbeat.RepoCompleted()
+		analyzed[i], failures[i] = r, err
+		if err != nil && cfg.Strict {
+			return fmt.Errorf("failed to analyze %s: %w", repoFullName, err)
 		}
-		// Check if repository is archived
-		isArchived, err := isRepositoryArchived(repoFullName)
 		if err != nil {
+			warnings.record(Warning{Repository: repoFullName, Message: err.Error(), Severity: SeverityError})
 			if !cfg.Silent {
-				fmt.Printf("⚠️ Warning: Failed to check if repository is archived for %s: %v\n", repoFullName, err)
+				Logf("⚠️ Warning: Failed to analyze %s: %v\n", repoFullName, err)
 			}
-			continue
+			return nil
+		}
+		if err := StreamRepository(r); err != nil {
+			warnings.warn(cfg, repoFullName, "%v", err)
 		}
-		r.Archived = isArchived
 
-		// Get last commit date
-		lastCommitDate, err := getLastCommitDate(repoFullName)
-		if err != nil {
-			if !cfg.Silent {
-				fmt.Printf("⚠️ Warning: Failed to get last commit date for %s: %v\n", repoFullName, err)
-			}
-			continue
+		// Update progress bar with elapsed time information
+		if !cfg.Silent && bar != nil {
+			mu.Lock()
+			defer mu.Unlock()
+
+			done++
+			elapsed := time.Since(startTime)
+			timePerRepo := elapsed / time.Duration(done)
+			remaining := timePerRepo * time.Duration(len(allRepos)-done)
+
+			percentDone := float64(done) / float64(len(allRepos)) * 100
+			// Apply color to the progress bar description string
+			bar.Describe(fmt.Sprintf("%s [%.1f%%] [%s elapsed, %s remaining]",
+				cyan("⚡ Analyzing repositories"), percentDone, formatDuration(elapsed), formatDuration(remaining)))
+			_ = bar.Add(1) // Use _ = to ignore error return value
 		}
-		r.LastCommitDate = lastCommitDate
-		r.DaysSinceLastCommit = int(now.Sub(lastCommitDate).Hours() / 24)
+		return nil
+	})
+	if err != nil {
+		return Analysis{}, err
+	}
 
-		// Get contributors and check if they are still in the organization
-		activeContribs, inactiveContribs, err := getContributorsStatus(repoFullName, cfg.Organization)
-		if err != nil {
-			if !cfg.Silent {
-				fmt.Printf("⚠️ Warning: Failed to analyze contributors for %s: %v\n", repoFullName, err)
-			}
+	// Keep the listing order whatever order the repositories finished in
+	var analysis Analysis
+	for i, err := range failures {
+		if unstarted[i] {
+			continue
+		}
+		if err == nil {
+			analysis.Repositories = append(analysis.Repositories, analyzed[i])
 			continue
 		}
+		analysis.Skipped++
+	}
+	analysis.Warnings = warnings.list()
 
-		r.TotalContributors = activeContribs + inactiveContribs
-		r.InactiveContributors = inactiveContribs
+	return analysis, nil
+}
 
-		if r.TotalContributors > 0 {
-			r.InactivePercentage = float64(inactiveContribs) / float64(r.TotalContributors)
-		}
+// fetchOrganizationRepositories lists the names of the organization's repositories of the configured visibility
+func fetchOrganizationRepositories(cfg config.Config) ([]string, error) {
+	// Use pagination to get all repositories in the organization
+	// We'll start with a higher limit and implement pagination logic
+	var allRepos []string
 
-		// Flag repository based on criteria
-		// 1. Repositories are flagged if they are archived
-		// 2. Repositories are flagged if they meet the age and inactive contributor criteria
+	page := 1
+	perPage := 100 // GitHub API typically uses 100 as maximum per page
 
-		// Always flag archived repositories
-		if r.Archived {
-			r.Flagged = true
-		} else {
-			// For non-archived repos, check age and contributor criteria
-			isOld := r.DaysSinceLastCommit > cfg.MaxCommitAgeInDays
-
-			if isOld {
-				if r.TotalContributors > 0 {
-					// If there are contributors, flag if the inactive percentage meets the threshold
-					if r.InactivePercentage >= cfg.InactiveContribThreshold {
-						r.Flagged = true
-					}
-				} else {
-					// If there are no contributors, flag it simply for being old
-					r.Flagged = true
-				}
-			}
+	// Restrict the listing to the requested visibility
+	repoType := cfg.Visibility
+	if repoType == "" {
+		repoType = "all"
+	}
+
+	for {
+		if !cfg.Silent {
+			Logf("📄 Fetching page %d of repositories...\n", page)
 		}
 
-		results = append(results, r)
+		out, err := runGH("api",
+			fmt.Sprintf("orgs/%s/repos?type=%s&per_page=%d&page=%d", cfg.Organization, repoType, perPage, page),
+			"--jq", ".[].name")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories on page %d: %w", page, err)
+		}
 
-		// Update progress bar with elapsed time information
-		if !cfg.Silent && bar != nil {
-			elapsed := time.Since(startTime)
-			timePerRepo := time.Duration(0)
-			if i+1 > 0 {
-				timePerRepo = elapsed / time.Duration(i+1)
+		// Get repo names from the output
+		repoNames := strings.Split(strings.TrimSpace(string(out)), "\n")
+
+		// If we got fewer items than perPage or empty response, we've reached the end
+		if len(repoNames) == 0 || (len(repoNames) == 1 && repoNames[0] == "") {
+			break
+		}
+
+		// Add repos to our collection
+		for _, name := range repoNames {
+			if name != "" { // Skip empty lines
+				allRepos = append(allRepos, name)
 			}
-			remaining := timePerRepo * time.Duration(len(allRepos)-i-1)
+		}
 
-			percentDone := float64(i+1) / float64(len(allRepos)) * 100
-			// Apply color to the progress bar description string
-			bar.Describe(fmt.Sprintf("%s [%.1f%%] [%s elapsed, %s remaining]",
-				cyan("⚡ Analyzing repositories"), percentDone, formatDuration(elapsed), formatDuration(remaining)))
-			_ = bar.Add(1) // Use _ = to ignore error return value
+		// Check if we got fewer items than the maximum per page, which means we're done
+		if len(repoNames) < perPage {
+			break
 		}
+
+		page++
 	}
 
-	return results, nil
+	return allRepos, nil
 }
 
 // formatDuration returns a human-readable string for the given duration
@@ -313,23 +432,26 @@ func formatDuration(d time.Duration) string {
 }
 
 // GetLastCommitDate retrieves the date of the last commit for a repository
-func GetLastCommitDate(repoFullName string) (time.Time, error) {
-	cmd := exec.Command("gh", "api",
-		fmt.Sprintf("repos/%s/commits", repoFullName),
+// An empty branch means the default branch
+func GetLastCommitDate(repoFullName, branch string) (time.Time, error) {
+	date, err := runGHParsed(parseLastCommitDate, "api",
+		withAsOfUntil(commitsEndpoint(repoFullName, branch)),
 		"--jq", ".[0].commit.committer.date",
 		"--method", "GET",
-		"--paginate",
-		"--cache", "1h")
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
-
-	err := cmd.Run()
-	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to get commits: %w", err)
+		"--paginate")
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return time.Time{}, fmt.Errorf("failed to get commits: %w", commitStatusError(err))
 	}
+	if err != nil && commitPath != "" {
+		return time.Time{}, fmt.Errorf("%w under %s", err, commitPath)
+	}
+	return date, err
+}
 
-	dateStr := strings.TrimSpace(out.String())
+// parseLastCommitDate parses the first date printed by the last commit query
+func parseLastCommitDate(data []byte) (time.Time, error) {
+	dateStr := strings.TrimSpace(string(data))
 	if dateStr == "" {
 		return time.Time{}, fmt.Errorf("no commits found")
 	}
@@ -343,22 +465,39 @@ func GetLastCommitDate(repoFullName string) (time.Time, error) {
 	return time.Parse(time.RFC3339, firstDate)
 }
 
-// GetContributorsStatus checks how many contributors are still active in the organization
-func GetContributorsStatus(repoFullName, orgName string) (active, inactive int, err error) {
-	// Get all contributors
-	cmd := exec.Command("gh", "api",
-		fmt.Sprintf("repos/%s/contributors", repoFullName),
-		"--jq", ".[].login")
+// commitsEndpoint returns the commits API path, restricted to a branch when given
+// and to the configured path, if any
+func commitsEndpoint(repoFullName, branch string) string {
+	if branch == "" {
+		return withCommitPath(fmt.Sprintf("repos/%s/commits", repoFullName))
+	}
+	return withCommitPath(fmt.Sprintf("repos/%s/commits?sha=%s", repoFullName, url.QueryEscape(branch)))
+}
 
-	var out bytes.Buffer
-	cmd.Stdout = &out
+// GetContributors returns the logins of a repository's contributors, or of the authors under the configured
+// path when contributors are scoped to it
+// An empty repository yields no contributors, while an access-limited one (403 or 451)
+// returns ErrContributorDataUnavailable
+func GetContributors(repoFullName string) ([]string, error) {
+	if pathContributors {
+		authors, err := getPathContributors(repoFullName)
+		if err != nil {
+			return nil, err
+		}
+		return contributorMap.Apply(authors), nil
+	}
 
-	err = cmd.Run()
+	out, err := runGH("api",
+		fmt.Sprintf("repos/%s/contributors", repoFullName),
+		"--jq", ".[].login")
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to get contributors: %w", err)
+		if StatusCode(err) == http.StatusForbidden || StatusCode(err) == http.StatusUnavailableForLegalReasons {
+			return nil, fmt.Errorf("%w: %v", ErrContributorDataUnavailable, err)
+		}
+		return nil, fmt.Errorf("failed to get contributors: %w", err)
 	}
 
-	contributors := strings.Split(strings.TrimSpace(out.String()), "\n")
+	contributors := strings.Split(strings.TrimSpace(string(out)), "\n")
 
 	// Filter out empty strings
 	var validContributors []string
@@ -368,21 +507,70 @@ func GetContributorsStatus(repoFullName, orgName string) (active, inactive int,
 		}
 	}
 
+	// Merge aliases and drop excluded accounts before anyone is counted
+	return contributorMap.Apply(validContributors), nil
+}
+
+// GetContributorsStatus returns the logins of the contributors still in the organization and of those who left
+// In strict mode a membership check that keeps failing is returned as an error instead of being skipped
+// When the caller cannot see private membership, ErrMembershipUnavailable is returned unless
+// falling back to public membership is configured
+func GetContributorsStatus(repoFullName, orgName string, cfg config.Config) (active, inactive []string, err error) {
+	return getContributorsStatus(repoFullName, orgName, cfg, nil)
+}
+
+// getContributorsStatus splits the contributors like GetContributorsStatus, recording the warnings met in a log
+func getContributorsStatus(repoFullName, orgName string, cfg config.Config, warnings *warningLog) (active, inactive []string, err error) {
+	// Get all contributors
+	validContributors, err := GetContributors(repoFullName)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	if len(validContributors) == 0 {
-		return 0, 0, nil
+		return nil, nil, nil
+	}
+
+	// Private members look like non-members to callers outside the organization
+	visible, err := canReadOrgMembership(orgName, cfg, warnings)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !visible && cfg.MembershipFallback != MembershipFallbackPublic {
+		return nil, nil, ErrMembershipUnavailable
+	}
+
+	// The member list is fetched once per organization and run, and REST picks up where it cannot be listed
+	if cfg.API == APIMembers {
+		if members, ok := orgMemberSet(orgName, !visible, cfg, warnings); ok {
+			active, inactive := classifyByMemberSet(members, validContributors)
+			return active, inactive, nil
+		}
+	}
+
+	// GraphQL checks a batch of contributors per call, and REST picks up where it is unavailable
+	if cfg.API == APIGraphQL {
+		if active, inactive, ok := classifyByMembershipGraphQL(orgName, validContributors, cfg, warnings); ok {
+			return active, inactive, nil
+		}
 	}
 
 	// Check if each contributor is still in the organization
 	for _, contributor := range validContributors {
-		cmd := exec.Command("gh", "api",
-			fmt.Sprintf("orgs/%s/members/%s", orgName, contributor),
-			"--silent")
+		isMember, err := checkOrgMembership(orgName, contributor, !visible)
+		if err != nil {
+			if cfg.Strict {
+				return nil, nil, err
+			}
+			// Membership could not be determined, so leave the contributor out of the ratio
+			continue
+		}
 
-		if err := cmd.Run(); err != nil {
-			// User is not in the organization anymore
-			inactive++
+		if isMember {
+			active = append(active, contributor)
 		} else {
-			active++
+			// User is not in the organization anymore
+			inactive = append(inactive, contributor)
 		}
 	}
 
@@ -390,236 +578,105 @@ func GetContributorsStatus(repoFullName, orgName string) (active, inactive int,
 }
 
 // OutputResults outputs the analysis results in the specified format
-func OutputResults(repos []Repository, cfg config.Config) error {
-	// Count flagged repositories
-	flaggedCount := 0
-	for _, repo := range repos {
-		if repo.Flagged {
-			flaggedCount++
+func OutputResults(analysis Analysis, cfg config.Config) error {
+	// The contributor report covers every analyzed repository, including those dropped from the report
+	if reportsContributors(cfg) {
+		if err := WriteContributorReport(analysis.Repositories, cfg); err != nil {
+			return err
 		}
 	}
 
-	if cfg.OutputFormat == "json" {
-		// Output as JSON
-		data, err := json.MarshalIndent(repos, "", "  ")
+	// Drop tiny repositories from the report if requested
+	repos := FilterRepositories(analysis.Repositories, cfg)
+	warnings := &warningLog{}
+	warnings.record(analysis.Warnings...)
+
+	// Compare against the previous run and record this one if a state file is configured
+	// A partial run would report the repositories it did not reach as removed, so it leaves the state alone
+	var removed []string
+	if cfg.StateFile != "" && NotStarted() > 0 {
+		warnings.warn(cfg, "", "Deadline reached, state file %s left unchanged", cfg.StateFile)
+	} else if cfg.StateFile != "" {
+		previous, err := LoadState(cfg.StateFile)
 		if err != nil {
-			return fmt.Errorf("failed to marshal JSON: %w", err)
+			return err
 		}
-
-		if cfg.OutputFile != "" {
-			if err := os.WriteFile(cfg.OutputFile, data, 0644); err != nil {
-				return fmt.Errorf("failed to write output file: %w", err)
-			}
-			fmt.Printf("💾 Results saved to %s\n", cfg.OutputFile)
-		} else {
-			fmt.Println(string(data))
+		removed = ApplyStateDeltas(previous, repos)
+		if err := SaveState(cfg.StateFile, previous, repos, false); err != nil {
+			return err
 		}
-	} else if cfg.OutputFormat == "csv" {
-		// Output as CSV
-		var csvBuffer bytes.Buffer
-
-		// Write CSV header
-		csvBuffer.WriteString("Repository Name,Last Commit Date,Days Since Last Commit,Total Contributors,Inactive Contributors,Inactive Percentage,Archived,Flagged\n")
-
-		// Write repository data
-		for _, repo := range repos {
-			csvBuffer.WriteString(fmt.Sprintf("%s,%s,%d,%d,%d,%.2f,%t,%t\n",
-				repo.Name,
-				repo.LastCommitDate.Format("2006-01-02"),
-				repo.DaysSinceLastCommit,
-				repo.TotalContributors,
-				repo.InactiveContributors,
-				repo.InactivePercentage*100,
-				repo.Archived,
-				repo.Flagged))
-		}
-
-		if cfg.OutputFile != "" {
-			if err := os.WriteFile(cfg.OutputFile, csvBuffer.Bytes(), 0644); err != nil {
-				return fmt.Errorf("failed to write CSV file: %w", err)
-			}
-			fmt.Printf("💾 Results saved to %s\n", cfg.OutputFile)
-		} else {
-			fmt.Println(csvBuffer.String())
-		}
-
-		// Print summary to console
-		fmt.Printf("\n📊 Analysis Results for %s\n", cfg.Organization)
-		fmt.Printf("Total repositories analyzed: %d\n", len(repos))
-		fmt.Printf("🚩 Flagged repositories: %d\n", flaggedCount)
-	} else {
-		// Output to console in human-readable format
-		fmt.Printf("\n📊 Analysis Results for %s\n", cfg.Organization)
-		fmt.Printf("Total repositories analyzed: %d\n", len(repos))
-		fmt.Printf("🚩 Flagged repositories: %d\n\n", flaggedCount)
-
-		if flaggedCount > 0 {
-			fmt.Println("🚩 Flagged Repositories:")
-			fmt.Println("---------------------")
-			for _, repo := range repos {
-				if repo.Flagged {
-					fmt.Printf("- %s\n", repo.Name)
-					fmt.Printf("  Last commit: %s (%d days ago)\n",
-						repo.LastCommitDate.Format("2006-01-02"), repo.DaysSinceLastCommit)
-					fmt.Printf("  Contributors: %d total, %d inactive (%.1f%%)\n",
-						repo.TotalContributors, repo.InactiveContributors,
-						repo.InactivePercentage*100)
-					if repo.Archived {
-						fmt.Printf("  📦 Repository Status: Archived\n\n")
-					} else {
-						fmt.Printf("  📦 Repository Status: Not Archived\n\n")
-					}
-				}
-			}
-		}
-
-		if cfg.OutputFile != "" {
-			// Create a text report
-			var reportBuf bytes.Buffer
-			reportBuf.WriteString(fmt.Sprintf("Analysis Results for %s\n", cfg.Organization))
-			reportBuf.WriteString(fmt.Sprintf("Date: %s\n", time.Now().Format("2006-01-02")))
-			reportBuf.WriteString(fmt.Sprintf("Total repositories analyzed: %d\n", len(repos)))
-			reportBuf.WriteString(fmt.Sprintf("Flagged repositories: %d\n\n", flaggedCount))
-
-			if flaggedCount > 0 {
-				reportBuf.WriteString("🚩 Flagged Repositories:\n")
-				reportBuf.WriteString("---------------------\n")
-				for _, repo := range repos {
-					if repo.Flagged {
-						reportBuf.WriteString(fmt.Sprintf("- %s\n", repo.Name))
-						reportBuf.WriteString(fmt.Sprintf("  Last commit: %s (%d days ago)\n",
-							repo.LastCommitDate.Format("2006-01-02"), repo.DaysSinceLastCommit))
-						reportBuf.WriteString(fmt.Sprintf("  Contributors: %d total, %d inactive (%.1f%%)\n",
-							repo.TotalContributors, repo.InactiveContributors,
-							repo.InactivePercentage*100))
-						if repo.Archived {
-							reportBuf.WriteString("  Repository Status: Archived\n\n")
-						} else {
-							reportBuf.WriteString("  Repository Status: Not Archived\n\n")
-						}
-					}
-				}
-			}
+	}
 
-			if err := os.WriteFile(cfg.OutputFile, reportBuf.Bytes(), 0644); err != nil {
-				return fmt.Errorf("failed to write output file: %w", err)
-			}
-			fmt.Printf("💾 Results saved to %s\n", cfg.OutputFile)
-		}
+	// Look for copies of the same project among the flagged repositories if requested
+	var duplicates []DuplicateCluster
+	if cfg.DetectDuplicates {
+		duplicates = findDuplicates(repos, cfg, warnings)
 	}
 
-	return nil
+	// Totals cover every repository even when only the most inactive are listed
+	summary := newSummary(repos, analysis.Skipped, removed, warnings.list(), false, cfg)
+	summary.Duplicates = duplicates
+	return writeReport(MostInactive(repos, cfg.Top), summary)
 }
 
-// OutputSingleRepositoryResult outputs the analysis results for a single repository
-func OutputSingleRepositoryResult(repo Repository, cfg config.Config) error {
-	if cfg.OutputFormat == "json" {
-		// Output as JSON
-		data, err := json.MarshalIndent(repo, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal JSON: %w", err)
-		}
+// csvHeader returns the CSV header line shared by all CSV outputs
+func csvHeader() string {
+	return "Repository Name,Last Commit Date,Days Since Last Commit,Total Contributors,Inactive Contributors,Inactive Percentage,Contributor Data Complete,Archived,Issues Enabled,Has Discussions,License,Flagged,Flag Reason,High Priority,Admins\n"
+}
 
-		if cfg.OutputFile != "" {
-			if err := os.WriteFile(cfg.OutputFile, data, 0644); err != nil {
-				return fmt.Errorf("failed to write output file: %w", err)
-			}
-			fmt.Printf("💾 Results saved to %s\n", cfg.OutputFile)
-		} else {
-			fmt.Println(string(data))
-		}
-	} else if cfg.OutputFormat == "csv" {
-		// Output as CSV
-		var csvBuffer bytes.Buffer
-
-		// Write CSV header
-		csvBuffer.WriteString("Repository Name,Last Commit Date,Days Since Last Commit,Total Contributors,Inactive Contributors,Inactive Percentage,Archived,Flagged\n")
-
-		// Write repository data
-		csvBuffer.WriteString(fmt.Sprintf("%s,%s,%d,%d,%d,%.2f,%t,%t\n",
-			repo.Name,
-			repo.LastCommitDate.Format("2006-01-02"),
-			repo.DaysSinceLastCommit,
-			repo.TotalContributors,
-			repo.InactiveContributors,
-			repo.InactivePercentage*100,
-			repo.Archived,
-			repo.Flagged))
-
-		if cfg.OutputFile != "" {
-			if err := os.WriteFile(cfg.OutputFile, csvBuffer.Bytes(), 0644); err != nil {
-				return fmt.Errorf("failed to write CSV file: %w", err)
-			}
-			fmt.Printf("💾 Results saved to %s\n", cfg.OutputFile)
-		} else {
-			fmt.Println(csvBuffer.String())
-		}
-	} else {
-		// Output to console in human-readable format
-		fmt.Printf("\n📊 Analysis Results for %s\n", repo.Name)
-		fmt.Printf("Last commit: %s (%d days ago)\n",
-			repo.LastCommitDate.Format("2006-01-02"), repo.DaysSinceLastCommit)
-		fmt.Printf("Contributors: %d total, %d inactive (%.1f%%)\n",
-			repo.TotalContributors, repo.InactiveContributors,
-			repo.InactivePercentage*100)
-
-		if repo.Archived {
-			fmt.Println("📦 Repository Status: Archived")
-		} else {
-			fmt.Println("📦 Repository Status: Active (Not Archived)")
-		}
+// csvRow returns the CSV line for a repository
+func csvRow(repo Repository) string {
+	return fmt.Sprintf("%s,%s,%d,%d,%d,%.2f,%t,%t,%t,%t,%s,%t,%s,%t,%s\n",
+		repo.Name,
+		lastCommitDateText(repo),
+		repo.DaysSinceLastCommit,
+		repo.TotalContributors,
+		repo.InactiveContributors,
+		repo.InactivePercentage*100,
+		repo.ContributorDataComplete,
+		repo.Archived,
+		repo.IssuesEnabled,
+		repo.HasDiscussions,
+		repo.License,
+		repo.Flagged,
+		repo.FlagReason,
+		repo.HighPriority,
+		strings.Join(repo.Admins, ";"))
+}
 
-		if repo.Flagged {
-			fmt.Println("🚩 Status: Flagged as inactive")
-		} else {
-			fmt.Println("✅ Status: Active")
+// OutputSingleRepositoryResult outputs the analysis results for a single repository with the warnings met analyzing it
+func OutputSingleRepositoryResult(repo Repository, warnings []Warning, cfg config.Config) error {
+	if reportsContributors(cfg) {
+		if err := WriteContributorReport([]Repository{repo}, cfg); err != nil {
+			return err
 		}
+	}
 
-		if cfg.OutputFile != "" {
-			// Create a text report
-			var reportBuf bytes.Buffer
-			reportBuf.WriteString(fmt.Sprintf("Analysis Results for %s\n", repo.Name))
-			reportBuf.WriteString(fmt.Sprintf("Date: %s\n", time.Now().Format("2006-01-02")))
-			reportBuf.WriteString(fmt.Sprintf("Last commit: %s (%d days ago)\n",
-				repo.LastCommitDate.Format("2006-01-02"), repo.DaysSinceLastCommit))
-			reportBuf.WriteString(fmt.Sprintf("Contributors: %d total, %d inactive (%.1f%%)\n",
-				repo.TotalContributors, repo.InactiveContributors,
-				repo.InactivePercentage*100))
-
-			if repo.Archived {
-				reportBuf.WriteString("Repository Status: Archived\n")
-			} else {
-				reportBuf.WriteString("Repository Status: Not Archived\n")
-			}
-
-			if repo.Flagged {
-				reportBuf.WriteString("Status: Flagged as inactive\n")
-			} else {
-				reportBuf.WriteString("Status: Active\n")
-			}
-
-			if err := os.WriteFile(cfg.OutputFile, reportBuf.Bytes(), 0644); err != nil {
-				return fmt.Errorf("failed to write output file: %w", err)
-			}
-			fmt.Printf("💾 Results saved to %s\n", cfg.OutputFile)
+	// Compare against the previous run and record this one if a state file is configured
+	if cfg.StateFile != "" {
+		previous, err := LoadState(cfg.StateFile)
+		if err != nil {
+			return err
+		}
+		single := []Repository{repo}
+		ApplyStateDeltas(previous, single)
+		repo = single[0]
+		if err := SaveState(cfg.StateFile, previous, single, true); err != nil {
+			return err
 		}
 	}
 
-	return nil
+	repos := []Repository{repo}
+	return writeReport(repos, newSummary(repos, 0, nil, warnings, true, cfg))
 }
 
 // isRepositoryArchived is defined in archive.go
 
 // GetRepositoryDetails retrieves various details for a repository
 func GetRepositoryDetails(repoFullName string) (time.Time, bool, error) {
-	cmd := exec.Command("gh", "api",
+	out, err := runGH("api",
 		fmt.Sprintf("repos/%s", repoFullName),
 		"--jq", "{archived: .archived, updated_at: .updated_at}")
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
-
-	err := cmd.Run()
 	if err != nil {
 		return time.Time{}, false, fmt.Errorf("failed to get repository details: %w", err)
 	}
@@ -629,7 +686,7 @@ func GetRepositoryDetails(repoFullName string) (time.Time, bool, error) {
 		UpdatedAt string `json:"updated_at"`
 	}
 
-	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+	if err := json.Unmarshal(out, &result); err != nil {
 		return time.Time{}, false, fmt.Errorf("failed to parse repository details: %w", err)
 	}
 
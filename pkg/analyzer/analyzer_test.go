@@ -0,0 +1,218 @@
+package analyzer
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestGetContributors(t *testing.T) {
+	tests := []struct {
+		name            string
+		script          string
+		want            []string
+		wantUnavailable bool
+		wantErr         bool
+	}{
+		{"contributors", `printf 'ann\nbob\n\n'`, []string{"ann", "bob"}, false, false},
+		{"empty repository", `exit 0`, nil, false, false},
+		{"access limited", `echo 'gh: Forbidden (HTTP 403)' >&2; exit 1`, nil, true, true},
+		{"unavailable for legal reasons", `echo 'gh: Repository access blocked (HTTP 451)' >&2; exit 1`, nil, true, true},
+		{"other failure", `echo 'gh: Server Error (HTTP 500)' >&2; exit 1`, nil, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeGH(t, tt.script)
+			SetCacheTTL(0)
+
+			got, err := GetContributors("o/r")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetContributors error = %v, want error %v", err, tt.wantErr)
+			}
+			if unavailable := errors.Is(err, ErrContributorDataUnavailable); unavailable != tt.wantUnavailable {
+				t.Errorf("error %v is ErrContributorDataUnavailable = %v, want %v", err, unavailable, tt.wantUnavailable)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("contributors = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContributorSummary(t *testing.T) {
+	tests := []struct {
+		name string
+		repo Repository
+		want string
+	}{
+		{"unavailable", Repository{TotalContributors: 3}, "data unavailable"},
+		{"no contributors", Repository{ContributorDataComplete: true}, "0 total, 0 inactive (0.0%)"},
+		{"counts", Repository{ContributorDataComplete: true, TotalContributors: 4, InactiveContributors: 1, InactivePercentage: 0.25}, "4 total, 1 inactive (25.0%)"},
+		{
+			name: "weighted and suspended",
+			repo: Repository{ContributorDataComplete: true, TotalContributors: 4, InactiveContributors: 2, InactivePercentage: 0.5,
+				WeightedInactivePercentage: floatPtr(0.1), SuspendedContributors: 1},
+			want: "4 total, 2 inactive (50.0%), 10.0% by commits in the last year, 1 suspended",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contributorSummary(tt.repo); got != tt.want {
+				t.Errorf("contributorSummary = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchOrganizationRepositories(t *testing.T) {
+	// A full first page of 100 names, then a last page of two
+	const script = `case "$*" in
+*page=1\ *) seq -f 'r%g' 1 100;;
+*page=2\ *) printf 'r101\nr102\n';;
+*) exit 1;;
+esac`
+
+	tests := []struct {
+		name       string
+		visibility string
+		wantType   string
+	}{
+		{"all by default", "", "type=all&"},
+		{"public", "public", "type=public&"},
+		{"private", "private", "type=private&"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, script)
+			SetCacheTTL(0)
+
+			repos, err := fetchOrganizationRepositories(config.Config{Organization: "o", Visibility: tt.visibility, Silent: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(repos) != 102 || repos[0] != "r1" || repos[101] != "r102" {
+				t.Errorf("listed %d repositories, want r1 to r102", len(repos))
+			}
+			calls := ghCalls(t, logPath)
+			if len(calls) != 2 {
+				t.Fatalf("made %d calls, want one per page: %q", len(calls), calls)
+			}
+			for _, call := range calls {
+				if !strings.Contains(call, "orgs/o/repos?"+tt.wantType) {
+					t.Errorf("call %q does not request %s", call, tt.wantType)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepositoriesStrict(t *testing.T) {
+	// broken fails to analyze between two repositories that succeed
+	script := `case "$*" in
+*rate_limit*) echo '{"resources":{"core":{"limit":5000,"remaining":5000,"reset":1748736000}}}';;
+*orgs/o/repos*) printf 'good\nbroken\nlater\n';;
+*commits*) echo ` + testNow.Format(time.RFC3339) + `;;
+"api repos/o/broken") echo 'gh: Server Error (HTTP 500)' >&2; exit 1;;
+"api repos/o/"*) echo '{}';;
+*) echo "unexpected call: $*" >&2; exit 1;;
+esac`
+	cfg := config.Config{Organization: "o", Metrics: []string{MetricCommits}, MaxCommitAgeInDays: 180, Silent: true}
+
+	tests := []struct {
+		name        string
+		strict      bool
+		wantRepos   []string
+		wantSkipped int
+		wantErr     bool
+		wantLater   bool
+	}{
+		{"skipped with a warning", false, []string{"o/good", "o/later"}, 1, false, true},
+		{"strict stops at the first failure", true, nil, 0, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pinNow(t)
+			logPath := fakeGH(t, script)
+			SetCacheTTL(0)
+
+			analysis, err := AnalyzeRepositories(withConfig(cfg, func(c *config.Config) { c.Strict = tt.strict }))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AnalyzeRepositories error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "failed to analyze o/broken") {
+				t.Errorf("error = %v, want the failed repository named", err)
+			}
+			var names []string
+			for _, repo := range analysis.Repositories {
+				names = append(names, repo.Name)
+			}
+			if !reflect.DeepEqual(names, tt.wantRepos) || analysis.Skipped != tt.wantSkipped {
+				t.Errorf("analyzed %q and skipped %d, want %q and %d", names, analysis.Skipped, tt.wantRepos, tt.wantSkipped)
+			}
+			var failures []string
+			for _, w := range analysis.Warnings {
+				if w.Severity == SeverityError {
+					failures = append(failures, w.Repository)
+				}
+			}
+			if len(failures) != tt.wantSkipped || (tt.wantSkipped > 0 && failures[0] != "o/broken") {
+				t.Errorf("returned failure warnings for %q, want one per skipped repository", failures)
+			}
+			var later bool
+			for _, call := range ghCalls(t, logPath) {
+				later = later || strings.Contains(call, "repos/o/later")
+			}
+			if later != tt.wantLater {
+				t.Errorf("analyzed the repository after the failure = %v, want %v", later, tt.wantLater)
+			}
+		})
+	}
+}
+
+func TestValidateGitHubCLI(t *testing.T) {
+	const scopes = `*"auth status"*) echo '  - Token scopes: '\''read:org'\'', '\''repo'\''';;`
+	tests := []struct {
+		name         string
+		script       string
+		missing      bool
+		strict       bool
+		wantErr      string
+		wantWarnings int
+	}{
+		{"configured binary", `case "$*" in ` + scopes + ` esac`, false, false, "", 0},
+		{"configured binary missing", "", true, false, "cannot be run", 0},
+		{"not logged in", `case "$*" in *"auth status"*) echo 'You are not logged in to any GitHub hosts.' >&2; exit 1;; esac`, false, false, "not authenticated", 0},
+		{"missing scope warned", `case "$*" in *"auth status"*) echo '  - Token scopes: repo';; esac`, false, false, "", 1},
+		{"missing scope in strict mode", `case "$*" in *"auth status"*) echo '  - Token scopes: repo';; esac`, false, true, "missing the read:org scope", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			path := ghPath
+			if tt.missing {
+				path = filepath.Join(t.TempDir(), "no-such-gh")
+				SetGHPath(path)
+			}
+			warnings, err := ValidateGitHubCLI(config.Config{GHPath: path, Silent: true, Strict: tt.strict})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ValidateGitHubCLI error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(warnings); got != tt.wantWarnings {
+				t.Errorf("returned %d warnings, want %d", got, tt.wantWarnings)
+			}
+			// The stub at the configured path is the binary run
+			if calls := ghCalls(t, logPath); !tt.missing && (len(calls) == 0 || calls[0] != "--version") {
+				t.Errorf("stub calls %q, want the version checked first", calls)
+			}
+		})
+	}
+}
@@ -1,9 +1,7 @@
 package analyzer
 
 import (
-	"bytes"
 	"fmt"
-	"os/exec"
 	"strings"
 )
 
@@ -15,18 +13,13 @@ func isRepositoryArchived(repoFullName string) (bool, error) {
 
 // IsRepositoryArchived checks if a repository is archived in GitHub
 func IsRepositoryArchived(repoFullName string) (bool, error) {
-	cmd := exec.Command("gh", "api",
+	out, err := runGH("api",
 		fmt.Sprintf("repos/%s", repoFullName),
 		"--jq", ".archived")
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
-
-	err := cmd.Run()
 	if err != nil {
 		return false, fmt.Errorf("failed to check if repository is archived: %w", err)
 	}
 
-	result := strings.TrimSpace(out.String())
+	result := strings.TrimSpace(string(out))
 	return result == "true", nil
 }
@@ -0,0 +1,73 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tempFiles returns the temporary files left in a directory by writeFileAtomic
+func tempFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, ".*.tmp-*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return matches
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string // previous content, or empty for none
+		perm     os.FileMode
+	}{
+		{"new file", "", 0644},
+		{"replaced file", "previous report", 0644},
+		{"private file", "", 0600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "report.json")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := writeFileAtomic(path, []byte("new report"), tt.perm); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil || string(data) != "new report" {
+				t.Errorf("file holds %q (%v), want the new report", data, err)
+			}
+			if info, err := os.Stat(path); err != nil || info.Mode().Perm() != tt.perm {
+				t.Errorf("file mode = %v (%v), want %v", info.Mode().Perm(), err, tt.perm)
+			}
+			if left := tempFiles(t, dir); len(left) > 0 {
+				t.Errorf("temporary files left behind: %q", left)
+			}
+		})
+	}
+}
+
+func TestWriteFileAtomicFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "missing", "report.json")
+	if err := writeFileAtomic(path, []byte("report"), 0644); err == nil {
+		t.Fatal("writeFileAtomic succeeded in a missing directory")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("target exists after a failed write: %v", err)
+	}
+}
+
+func TestWriteFileAtomicNonRegular(t *testing.T) {
+	// Device files cannot be renamed over, so they are written in place
+	if err := writeFileAtomic(os.DevNull, []byte("report"), 0644); err != nil {
+		t.Errorf("writeFileAtomic(%s) = %v", os.DevNull, err)
+	}
+}
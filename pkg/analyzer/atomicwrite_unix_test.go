@@ -0,0 +1,63 @@
+//go:build unix
+
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// limitFileSize caps the size of files the process may write for the duration of a test,
+// so a larger write fails partway through with EFBIG (Go ignores the accompanying SIGXFSZ)
+func limitFileSize(t *testing.T, size uint64) {
+	t.Helper()
+	var previous syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_FSIZE, &previous); err != nil {
+		t.Skipf("cannot read the file size limit: %v", err)
+	}
+	limit := syscall.Rlimit{Cur: size, Max: previous.Max}
+	if err := syscall.Setrlimit(syscall.RLIMIT_FSIZE, &limit); err != nil {
+		t.Skipf("cannot set the file size limit: %v", err)
+	}
+	t.Cleanup(func() { syscall.Setrlimit(syscall.RLIMIT_FSIZE, &previous) })
+}
+
+func TestWriteFileAtomicMidWriteFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string // previous content, or empty for none
+	}{
+		{"new file", ""},
+		{"replaced file", "previous report"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "report.json")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			limitFileSize(t, 1024)
+			err := writeFileAtomic(path, make([]byte, 64*1024), 0644)
+			if err == nil {
+				t.Fatal("writeFileAtomic succeeded past the file size limit")
+			}
+
+			data, readErr := os.ReadFile(path)
+			switch {
+			case tt.existing == "" && !os.IsNotExist(readErr):
+				t.Errorf("a partial file was left in place (%d bytes)", len(data))
+			case tt.existing != "" && string(data) != tt.existing:
+				t.Errorf("previous file was replaced by %d bytes, want it untouched", len(data))
+			}
+			if left := tempFiles(t, dir); len(left) > 0 {
+				t.Errorf("temporary files left behind: %q", left)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// authAttempts is how many times gh auth status is tried before authentication is reported as failed
+const authAttempts = 3
+
+// authRetryDelay is the base delay between gh auth status retries
+var authRetryDelay = time.Second
+
+// tokenScopesPattern matches the scope line of gh auth status, e.g. "- Token scopes: 'gist', 'read:org', 'repo'"
+var tokenScopesPattern = regexp.MustCompile(`(?m)Token scopes:\s*(.*)$`)
+
+// scopeImplications lists the OAuth scopes that include another one
+var scopeImplications = map[string][]string{
+	"read:org":    {"write:org", "admin:org"},
+	"public_repo": {"repo"},
+}
+
+// getAuthStatus runs gh auth status and returns its output, retrying transient failures
+// gh reports that no account is logged in without retrying
+func getAuthStatus() (string, error) {
+	var lastErr error
+	for attempt := 1; attempt <= authAttempts; attempt++ {
+		cmd := ghCommand("auth", "status")
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+
+		err := cmd.Run()
+		if err == nil {
+			return out.String(), nil
+		}
+
+		lastErr = err
+		if strings.Contains(out.String(), "not logged in") {
+			break
+		}
+		if attempt < authAttempts {
+			time.Sleep(authRetryDelay * time.Duration(attempt))
+		}
+	}
+	return "", fmt.Errorf("GitHub CLI is not authenticated: %w", lastErr)
+}
+
+// parseTokenScopes returns the token scopes listed by gh auth status
+// ok is false when the output lists no scopes, as for fine-grained tokens, whose permissions cannot be inspected
+func parseTokenScopes(status string) (scopes []string, ok bool) {
+	match := tokenScopesPattern.FindStringSubmatch(status)
+	if match == nil {
+		return nil, false
+	}
+
+	for _, scope := range strings.Split(match[1], ",") {
+		scope = strings.Trim(strings.TrimSpace(scope), "'\"")
+		if scope != "" && scope != "none" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, true
+}
+
+// hasScope reports whether the scopes grant a scope, directly or through a broader one
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+		for _, broader := range scopeImplications[scope] {
+			if s == broader {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// missingScopes returns the token scopes a scan needs but the token lacks
+// read:org is needed to see private organization membership, repo to read private repositories,
+// security_events to read Dependabot alerts, and public_repo to update an issue comment
+func missingScopes(scopes []string, cfg config.Config) []string {
+	var missing []string
+	if cfg.ContributorScope != ContributorScopeOrg && !hasScope(scopes, "read:org") {
+		missing = append(missing, "read:org")
+	}
+	if cfg.Visibility != "public" && !hasScope(scopes, "repo") {
+		missing = append(missing, "repo")
+	}
+	if collects(cfg, MetricSecurity) && !hasScope(scopes, "repo") && !hasScope(scopes, "security_events") {
+		missing = append(missing, "security_events")
+	}
+	if cfg.UpdateComment != "" && !hasScope(scopes, "public_repo") {
+		missing = append(missing, "public_repo")
+	}
+	return missing
+}
@@ -0,0 +1,117 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestParseTokenScopes(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		want   []string
+		wantOK bool
+	}{
+		{
+			name: "classic token",
+			status: `github.com
+  ✓ Logged in to github.com account ann (keyring)
+  - Active account: true
+  - Git operations protocol: https
+  - Token: gho_************************************
+  - Token scopes: 'gist', 'read:org', 'repo', 'workflow'`,
+			want:   []string{"gist", "read:org", "repo", "workflow"},
+			wantOK: true,
+		},
+		{
+			name: "older gh",
+			status: `github.com
+  ✓ Logged in to github.com as ann (oauth_token)
+  ✓ Token: *******************
+  ✓ Token scopes: gist, repo`,
+			want:   []string{"gist", "repo"},
+			wantOK: true,
+		},
+		{"no scopes", "  - Token scopes: none", nil, true},
+		{
+			name: "fine-grained token",
+			status: `github.com
+  ✓ Logged in to github.com account ann (GH_TOKEN)
+  - Token: github_pat_****`,
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseTokenScopes(tt.status)
+			if !reflect.DeepEqual(got, tt.want) || ok != tt.wantOK {
+				t.Errorf("parseTokenScopes = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestMissingScopes(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		cfg    config.Config
+		want   []string
+	}{
+		{"all granted", []string{"read:org", "repo"}, config.Config{}, nil},
+		{"none granted", nil, config.Config{}, []string{"read:org", "repo"}},
+		{"implied by admin:org", []string{"admin:org", "repo"}, config.Config{}, nil},
+		{"public repositories only", []string{"read:org"}, config.Config{Visibility: "public"}, nil},
+		{"org contributor scope", []string{"repo"}, config.Config{ContributorScope: ContributorScopeOrg}, nil},
+		{"security alerts", []string{"read:org"}, config.Config{Visibility: "public", Security: true}, []string{"security_events"}},
+		{"security alerts through repo", []string{"read:org", "repo"}, config.Config{Security: true}, nil},
+		{"comment update", []string{"read:org", "repo"}, config.Config{UpdateComment: "o/r#1"}, nil},
+		{"comment update without repo", []string{"read:org"}, config.Config{Visibility: "public", UpdateComment: "o/r#1"}, []string{"public_repo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := missingScopes(tt.scopes, tt.cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("missingScopes(%q) = %q, want %q", tt.scopes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAuthStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		script    string
+		wantErr   bool
+		wantCalls int
+	}{
+		{"authenticated", `echo '  - Token scopes: repo'`, false, 1},
+		{"transient failure retried", `if [ $(wc -l < "$GH_LOG") -lt 2 ]; then echo 'connection reset' >&2; exit 1; fi; echo ok`, false, 2},
+		{"not logged in", `echo 'You are not logged in to any GitHub hosts.' >&2; exit 1`, true, 1},
+		{"persistent failure", `echo 'connection reset' >&2; exit 1`, true, authAttempts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			previous := authRetryDelay
+			authRetryDelay = 0
+			t.Cleanup(func() { authRetryDelay = previous })
+
+			status, err := getAuthStatus()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getAuthStatus error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "not authenticated") {
+				t.Errorf("error = %v, want authentication reported as failed", err)
+			}
+			if err == nil && status == "" {
+				t.Error("getAuthStatus returned no output")
+			}
+			if calls := ghCalls(t, logPath); len(calls) != tt.wantCalls {
+				t.Errorf("ran gh auth status %d times, want %d", len(calls), tt.wantCalls)
+			}
+		})
+	}
+}
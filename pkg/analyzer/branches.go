@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// estimatedBranchesPerRepo is the assumed branch count used to estimate per-branch calls
+const estimatedBranchesPerRepo = 10
+
+// branchCommit is the newest commit found on a branch other than the analyzed one
+type branchCommit struct {
+	Branch string
+	Date   time.Time
+}
+
+// GetNewestBranchCommit returns the branch, other than the analyzed one, whose latest commit is the newest,
+// or nil when the repository has no other branch
+// Branches are listed across every page, so repositories with more than 100 branches are fully covered,
+// and each branch costs one call for its latest commit
+func GetNewestBranchCommit(repoFullName, analyzedBranch string) (*branchCommit, error) {
+	out, err := runGH("api",
+		fmt.Sprintf("repos/%s/branches?per_page=100", repoFullName),
+		"--paginate", "--jq", ".[].name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	var newest *branchCommit
+	for _, branch := range parseBranchNames(out) {
+		if branch == analyzedBranch {
+			continue
+		}
+
+		date, err := runGHParsed(parseBranchCommitDate, "api",
+			withAsOfUntil(commitsEndpoint(repoFullName, branch))+"&per_page=1",
+			"--jq", ".[0].commit.committer.date // empty")
+		if err != nil {
+			// A branch without commits up to the as-of time has nothing to contribute
+			if StatusCode(err) == http.StatusNotFound || StatusCode(err) == http.StatusConflict {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get the latest commit of branch %s: %w", branch, err)
+		}
+		newest = newerBranchCommit(newest, branch, date)
+	}
+	return newest, nil
+}
+
+// newerBranchCommit returns whichever of the current newest branch commit and the given one is newer
+// A zero date means the branch has no commit and never replaces the current one
+func newerBranchCommit(current *branchCommit, branch string, date time.Time) *branchCommit {
+	if date.IsZero() || (current != nil && !date.After(current.Date)) {
+		return current
+	}
+	return &branchCommit{Branch: branch, Date: date}
+}
+
+// parseBranchNames splits the branch names printed one per line
+func parseBranchNames(data []byte) []string {
+	var names []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// parseBranchCommitDate parses the latest commit date of a branch, or returns a zero time when it has none
+func parseBranchCommitDate(data []byte) (time.Time, error) {
+	dateStr := strings.TrimSpace(string(data))
+	if dateStr == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse(time.RFC3339, dateStr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse branch commit date: %w", err)
+	}
+	return date, nil
+}
+
+// hasActiveBranch reports whether another branch has a commit within maxAgeDays, suggesting work
+// continues away from the analyzed branch
+func hasActiveBranch(r Repository, maxAgeDays int) bool {
+	return r.NewestBranchCommitDate != nil && Now().Sub(*r.NewestBranchCommitDate) <= time.Duration(maxAgeDays)*24*time.Hour
+}
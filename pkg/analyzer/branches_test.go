@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetNewestBranchCommit(t *testing.T) {
+	tests := []struct {
+		name       string
+		branches   string
+		wantBranch string
+		wantDate   string
+		wantErr    bool
+	}{
+		{"branch newer than the default", `printf 'main\nold\nfeature\n'`, "feature", "2025-05-20T00:00:00Z", false},
+		{"analyzed branch skipped", `printf 'main\nold\n'`, "old", "2024-01-01T00:00:00Z", false},
+		{"only the analyzed branch", `echo main`, "", "", false},
+		{"branch without commits", `printf 'empty\nold\n'`, "old", "2024-01-01T00:00:00Z", false},
+		{"empty repository branch", `printf 'conflict\n'`, "", "", false},
+		{"latest commit unreadable", `printf 'feature\nbroken\n'`, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, `case "$*" in
+*branches*) `+tt.branches+`;;
+*sha=main*) echo 2025-05-30T00:00:00Z;;
+*sha=feature*) echo 2025-05-20T00:00:00Z;;
+*sha=old*) echo 2024-01-01T00:00:00Z;;
+*sha=empty*) echo 'gh: Not Found (HTTP 404)' >&2; exit 1;;
+*sha=conflict*) echo 'gh: Git Repository is empty. (HTTP 409)' >&2; exit 1;;
+*) echo 'gh: Server Error (HTTP 500)' >&2; exit 1;;
+esac`)
+			SetCacheTTL(0)
+
+			newest, err := GetNewestBranchCommit("o/r", "main")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetNewestBranchCommit error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "branch broken") {
+				t.Errorf("error = %v, want the failed branch named", err)
+			}
+			switch {
+			case tt.wantBranch == "" && newest != nil:
+				t.Errorf("newest branch commit = %+v, want none", newest)
+			case tt.wantBranch != "" && (newest == nil || newest.Branch != tt.wantBranch || newest.Date.Format(time.RFC3339) != tt.wantDate):
+				t.Errorf("newest branch commit = %+v, want %s at %s", newest, tt.wantBranch, tt.wantDate)
+			}
+			for _, call := range ghCalls(t, logPath) {
+				if strings.Contains(call, "sha=main") {
+					t.Errorf("looked up the analyzed branch: %q", call)
+				}
+			}
+		})
+	}
+}
+
+func TestParseBranchNames(t *testing.T) {
+	// Pages of a paginated listing are printed back to back
+	got := parseBranchNames([]byte("main\nfeature\n\nrelease/1.x\n  \n"))
+	if want := []string{"main", "feature", "release/1.x"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseBranchNames = %q, want %q", got, want)
+	}
+}
+
+func TestHasActiveBranch(t *testing.T) {
+	pinNow(t)
+	tests := []struct {
+		name   string
+		newest *time.Time
+		want   bool
+	}{
+		{"no other branch", nil, false},
+		{"recent branch commit", testDaysAgo(10), true},
+		{"stale branches", testDaysAgo(200), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasActiveBranch(Repository{NewestBranchCommitDate: tt.newest}, 180); got != tt.want {
+				t.Errorf("hasActiveBranch = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
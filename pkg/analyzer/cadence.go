@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// medianCommitGapDays returns the median gap between consecutive commits among the recent commits, in days
+// It reports false when fewer than two commits leave no gap to measure.
+func medianCommitGapDays(commits []commitInfo) (float64, bool) {
+	if len(commits) < 2 {
+		return 0, false
+	}
+
+	// Rebased and cherry-picked commits can be listed out of date order
+	dates := make([]time.Time, len(commits))
+	for i, c := range commits {
+		dates[i] = c.Commit.Committer.Date
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].After(dates[j]) })
+
+	gaps := make([]time.Duration, len(dates)-1)
+	for i := range gaps {
+		gaps[i] = dates[i].Sub(dates[i+1])
+	}
+	return medianHours(gaps) / 24, true
+}
+
+// cadenceAgeThreshold is the age in days past which a repository counts as old: the age threshold, raised
+// to the cadence multiplier times the repository's median commit gap when flagging on cadence, so a
+// repository that commits in bursts is not condemned by one of its usual quiet spells
+func cadenceAgeThreshold(r Repository, cfg config.Config) int {
+	if !cfg.FlagOnCadence || r.MedianCommitGapDays == nil {
+		return cfg.MaxCommitAgeInDays
+	}
+	return max(cfg.MaxCommitAgeInDays, int(math.Ceil(cfg.CadenceMultiplier**r.MedianCommitGapDays)))
+}
+
+// cadenceSummary renders the median commit gap for human-readable output, with the age past which
+// the repository counts as old when flagging on cadence
+func cadenceSummary(repo Repository, cfg config.Config) string {
+	summary := fmt.Sprintf("median %.1f days between commits", *repo.MedianCommitGapDays)
+	if cfg.FlagOnCadence {
+		summary += fmt.Sprintf(", old after %d days", cadenceAgeThreshold(repo, cfg))
+	}
+	return summary
+}
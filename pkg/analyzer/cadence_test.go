@@ -0,0 +1,91 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// commitHistory parses a commits list response with one commit for each of the given days before testNow
+func commitHistory(t *testing.T, daysAgo ...int) []commitInfo {
+	t.Helper()
+	var commits []string
+	for _, days := range daysAgo {
+		commits = append(commits, commitJSON(days, "Change", "Ann", "ann", "User", 1))
+	}
+	parsed, err := parseCommits([]byte("[" + strings.Join(commits, ",") + "]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestFlagRepositoryCadence(t *testing.T) {
+	tests := []struct {
+		name          string
+		history       []int
+		flagOnCadence bool
+		wantGap       float64
+		wantThreshold int
+		wantFlagged   bool
+	}{
+		{"steady", []int{200, 202, 204, 206, 208, 210}, true, 2, 180, true},
+		{"seasonal", []int{200, 300, 400, 500}, true, 100, 300, false},
+		{"seasonal without cadence", []int{200, 300, 400, 500}, false, 100, 180, true},
+		{"one burst then quiet", []int{200, 201, 202, 203, 400}, true, 1, 180, true},
+		{"listed out of order", []int{400, 200, 300, 500}, true, 100, 300, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gap, ok := medianCommitGapDays(commitHistory(t, tt.history...))
+			if !ok || gap != tt.wantGap {
+				t.Fatalf("median commit gap = %v (%v), want %v days", gap, ok, tt.wantGap)
+			}
+
+			cfg := withConfig(flaggingConfig, func(c *config.Config) { c.FlagOnCadence = tt.flagOnCadence; c.CadenceMultiplier = 3 })
+			r := Repository{DaysSinceLastCommit: 200, ContributorDataComplete: true, MedianCommitGapDays: &gap}
+			if got := cadenceAgeThreshold(r, cfg); got != tt.wantThreshold {
+				t.Errorf("cadenceAgeThreshold = %d, want %d", got, tt.wantThreshold)
+			}
+			FlagRepository(&r, cfg)
+			if r.Flagged != tt.wantFlagged {
+				t.Errorf("flagged = %v (%s), want %v", r.Flagged, r.FlagReason, tt.wantFlagged)
+			}
+		})
+	}
+}
+
+func TestMedianCommitGapDaysTooFewCommits(t *testing.T) {
+	for _, history := range [][]int{nil, {10}} {
+		if gap, ok := medianCommitGapDays(commitHistory(t, history...)); ok {
+			t.Errorf("medianCommitGapDays(%v) = %v, want no gap to measure", history, gap)
+		}
+	}
+
+	// Without a measured cadence the age threshold applies as is
+	cfg := withConfig(flaggingConfig, func(c *config.Config) { c.FlagOnCadence = true; c.CadenceMultiplier = 3 })
+	if got := cadenceAgeThreshold(Repository{}, cfg); got != cfg.MaxCommitAgeInDays {
+		t.Errorf("cadenceAgeThreshold = %d, want %d", got, cfg.MaxCommitAgeInDays)
+	}
+}
+
+func TestCadenceSummary(t *testing.T) {
+	repo := Repository{MedianCommitGapDays: floatPtr(72.5)}
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want string
+	}{
+		{"measured only", flaggingConfig, "median 72.5 days between commits"},
+		{"flagging on cadence", withConfig(flaggingConfig, func(c *config.Config) { c.FlagOnCadence = true; c.CadenceMultiplier = 3 }),
+			"median 72.5 days between commits, old after 218 days"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cadenceSummary(repo, tt.cfg); got != tt.want {
+				t.Errorf("cadenceSummary = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
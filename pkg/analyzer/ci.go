@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// CIStatusNone is the CI status recorded for repositories without any workflow runs
+const CIStatusNone = "none"
+
+// ciRun is the latest workflow run of a repository
+type ciRun struct {
+	Status string
+	Date   time.Time
+}
+
+// GetLastCIRun returns the status and date of the latest GitHub Actions workflow run on the branch
+// (empty means any branch). Repositories without runs, or with Actions disabled, have status CIStatusNone.
+func GetLastCIRun(repoFullName, branch string) (ciRun, error) {
+	endpoint := fmt.Sprintf("repos/%s/actions/runs?per_page=1", repoFullName)
+	if branch != "" {
+		endpoint += "&branch=" + url.QueryEscape(branch)
+	}
+
+	out, err := runGH("api", endpoint)
+	if err != nil {
+		if StatusCode(err) == http.StatusNotFound {
+			return ciRun{Status: CIStatusNone}, nil
+		}
+		return ciRun{}, fmt.Errorf("failed to get workflow runs: %w", err)
+	}
+
+	return parseLastCIRun(out)
+}
+
+// parseLastCIRun decodes the latest run of a workflow runs response
+// Completed runs report their conclusion (success, failure, cancelled, ...), others their status (queued, in_progress, ...)
+func parseLastCIRun(data []byte) (ciRun, error) {
+	var resp struct {
+		WorkflowRuns []struct {
+			Status     string    `json:"status"`
+			Conclusion string    `json:"conclusion"`
+			CreatedAt  time.Time `json:"created_at"`
+		} `json:"workflow_runs"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return ciRun{}, fmt.Errorf("failed to parse workflow runs: %w", err)
+	}
+
+	if len(resp.WorkflowRuns) == 0 {
+		return ciRun{Status: CIStatusNone}, nil
+	}
+
+	run := resp.WorkflowRuns[0]
+	status := run.Status
+	if status == "completed" && run.Conclusion != "" {
+		status = run.Conclusion
+	}
+	return ciRun{Status: status, Date: run.CreatedAt}, nil
+}
+
+// isCIBroken reports whether a repository's CI is absent, failing, or has not run within maxAgeDays
+func isCIBroken(r Repository, maxAgeDays int) bool {
+	switch r.LastCIStatus {
+	case "":
+		return false
+	case CIStatusNone, "failure", "timed_out", "startup_failure":
+		return true
+	}
+	return r.LastCIDate != nil && Now().Sub(*r.LastCIDate) > time.Duration(maxAgeDays)*24*time.Hour
+}
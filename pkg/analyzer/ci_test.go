@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetLastCIRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		branch     string
+		script     string
+		wantStatus string
+		wantDate   bool
+		wantCall   string
+		wantErr    bool
+	}{
+		{"succeeded", "", `echo '{"workflow_runs":[{"status":"completed","conclusion":"success","created_at":"2025-05-01T00:00:00Z"}]}'`,
+			"success", true, "repos/o/r/actions/runs?per_page=1", false},
+		{"in progress", "", `echo '{"workflow_runs":[{"status":"in_progress","conclusion":null,"created_at":"2025-05-01T00:00:00Z"}]}'`,
+			"in_progress", true, "", false},
+		{"on a branch", "release/1.x", `echo '{"workflow_runs":[{"status":"completed","conclusion":"failure","created_at":"2025-05-01T00:00:00Z"}]}'`,
+			"failure", true, "per_page=1&branch=release%2F1.x", false},
+		{"no runs", "", `echo '{"workflow_runs":[]}'`, CIStatusNone, false, "", false},
+		{"actions disabled", "", `echo 'gh: Not Found (HTTP 404)' >&2; exit 1`, CIStatusNone, false, "", false},
+		{"failure", "", `echo 'gh: Server Error (HTTP 500)' >&2; exit 1`, "", false, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			SetCacheTTL(0)
+
+			run, err := GetLastCIRun("o/r", tt.branch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetLastCIRun error = %v, want error %v", err, tt.wantErr)
+			}
+			if run.Status != tt.wantStatus || run.Date.IsZero() == tt.wantDate {
+				t.Errorf("run = %+v, want status %q with a date %v", run, tt.wantStatus, tt.wantDate)
+			}
+			if calls := ghCalls(t, logPath); !strings.Contains(calls[0], tt.wantCall) {
+				t.Errorf("gh call %q, want %s", calls[0], tt.wantCall)
+			}
+		})
+	}
+}
+
+func TestIsCIBroken(t *testing.T) {
+	pinNow(t)
+	tests := []struct {
+		name   string
+		status string
+		date   int
+		want   bool
+	}{
+		{"not checked", "", 0, false},
+		{"no CI", CIStatusNone, 0, true},
+		{"failing", "failure", 1, true},
+		{"timed out", "timed_out", 1, true},
+		{"recent success", "success", 30, false},
+		{"stale success", "success", 200, true},
+		{"queued recently", "queued", 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := Repository{LastCIStatus: tt.status}
+			if tt.status != "" && tt.status != CIStatusNone {
+				repo.LastCIDate = testDaysAgo(tt.date)
+			}
+			if got := isCIBroken(repo, 180); got != tt.want {
+				t.Errorf("isCIBroken = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
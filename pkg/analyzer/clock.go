@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// asOf pins the analysis time, or is zero to analyze as of the current time
+var asOf time.Time
+
+// SetAsOf pins the time the analysis is made at, so ages are computed relative to it and commits
+// made after it are ignored; a zero time restores the current time
+func SetAsOf(t time.Time) {
+	asOf = t
+}
+
+// ParseAsOf parses an as-of time given as an RFC 3339 timestamp or a date, which means midnight UTC
+func ParseAsOf(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid as-of time %q, expected a date (2006-01-02) or an RFC 3339 timestamp", value)
+}
+
+// Now returns the time the analysis is made at: the pinned as-of time, or the current time
+func Now() time.Time {
+	if !asOf.IsZero() {
+		return asOf
+	}
+	return time.Now()
+}
+
+// asOfKey identifies the pinned as-of time in cache fingerprints, or is empty for the current time
+func asOfKey() string {
+	if asOf.IsZero() {
+		return ""
+	}
+	return asOf.UTC().Format(time.RFC3339)
+}
+
+// withAsOfUntil restricts a commits endpoint to commits made up to the pinned as-of time, if any
+func withAsOfUntil(endpoint string) string {
+	if asOf.IsZero() {
+		return endpoint
+	}
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+	return endpoint + separator + "until=" + url.QueryEscape(asOf.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestParseAsOf(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2025-06-01", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2025-06-01T12:30:00Z", time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC), false},
+		{"2025-06-01T12:30:00+02:00", time.Date(2025, 6, 1, 10, 30, 0, 0, time.UTC), false},
+		{"06/01/2025", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseAsOf(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAsOf error = %v, want error %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseAsOf = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithAsOfUntil(t *testing.T) {
+	tests := []struct {
+		name     string
+		asOf     time.Time
+		endpoint string
+		want     string
+	}{
+		{"current time", time.Time{}, "repos/o/r/commits?per_page=1", "repos/o/r/commits?per_page=1"},
+		{"pinned", testNow, "repos/o/r/commits?per_page=1", "repos/o/r/commits?per_page=1&until=2025-06-01T00%3A00%3A00Z"},
+		{"pinned without a query", testNow, "repos/o/r/commits", "repos/o/r/commits?until=2025-06-01T00%3A00%3A00Z"},
+		{"pinned in another zone", testNow.In(time.FixedZone("CEST", 2*60*60)), "repos/o/r/commits", "repos/o/r/commits?until=2025-06-01T00%3A00%3A00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetAsOf(tt.asOf)
+			t.Cleanup(func() { SetAsOf(time.Time{}) })
+			if got := withAsOfUntil(tt.endpoint); got != tt.want {
+				t.Errorf("withAsOfUntil = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepositoryAsOf(t *testing.T) {
+	// The last commit was made 50 days before testNow
+	tests := []struct {
+		name        string
+		asOfDaysAgo int
+		wantDays    int
+		wantUntil   string
+	}{
+		{"as of testNow", 0, 50, "until=2025-06-01T00%3A00%3A00Z"},
+		{"backdated", 10, 40, "until=2025-05-22T00%3A00%3A00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetAsOf(*testDaysAgo(tt.asOfDaysAgo))
+			t.Cleanup(func() { SetAsOf(time.Time{}) })
+			logPath := fakeGH(t, analyzeScript(`{}`, 50))
+			SetCacheTTL(0)
+
+			cfg := config.Config{MaxCommitAgeInDays: 180, Metrics: []string{MetricCommits}, Silent: true}
+			repo, _, err := AnalyzeRepository("o/r", cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if repo.DaysSinceLastCommit != tt.wantDays {
+				t.Errorf("days since the last commit = %d, want %d", repo.DaysSinceLastCommit, tt.wantDays)
+			}
+			var commitsCall string
+			for _, call := range ghCalls(t, logPath) {
+				if strings.Contains(call, "commits") {
+					commitsCall = call
+				}
+			}
+			if !strings.Contains(commitsCall, tt.wantUntil) {
+				t.Errorf("commits call %q, want %s", commitsCall, tt.wantUntil)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Repository visibilities reported by the repos endpoint
+const (
+	VisibilityPublic   = "public"
+	VisibilityPrivate  = "private"
+	VisibilityInternal = "internal"
+)
+
+// GetOutsideCollaboratorCount counts the collaborators of a repository who are not members of its organization,
+// across every page; nil means the caller may not list the collaborators, which takes push access
+func GetOutsideCollaboratorCount(repoFullName string) (*int, error) {
+	out, err := runGH("api",
+		fmt.Sprintf("repos/%s/collaborators?affiliation=outside&per_page=100", repoFullName),
+		"--paginate", "--jq", "length")
+	if err != nil {
+		switch StatusCode(err) {
+		case http.StatusForbidden, http.StatusNotFound:
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get outside collaborators: %w", err)
+	}
+
+	count, err := parseCollaboratorCount(out)
+	if err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
+
+// parseCollaboratorCount sums the per-page collaborator counts printed by a paginated length query
+func parseCollaboratorCount(data []byte) (int, error) {
+	total := 0
+	for _, line := range bytes.Fields(data) {
+		n, err := strconv.Atoi(string(line))
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse outside collaborator count: %w", err)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// exposedToOutsiders reports whether a repository is public and shared with outside collaborators
+func exposedToOutsiders(r Repository) bool {
+	return r.Visibility == VisibilityPublic && r.OutsideCollaborators != nil && *r.OutsideCollaborators > 0
+}
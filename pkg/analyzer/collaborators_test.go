@@ -0,0 +1,137 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestParseCollaboratorCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    int
+		wantErr bool
+	}{
+		{"no collaborators", "", 0, false},
+		{"one page", "3\n", 3, false},
+		{"several pages", "100\n100\n7\n", 207, false},
+		{"malformed", "3\nnull\n", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCollaboratorCount([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCollaboratorCount error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseCollaboratorCount = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetOutsideCollaboratorCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		want    *int
+		wantErr bool
+	}{
+		{"paginated", `printf '100\n5\n'`, intPtr(105), false},
+		{"none", `echo 0`, intPtr(0), false},
+		{"push access required", `echo 'gh: Forbidden (HTTP 403)' >&2; exit 1`, nil, false},
+		{"not visible", `echo 'gh: Not Found (HTTP 404)' >&2; exit 1`, nil, false},
+		{"other failure", `echo 'gh: Server Error (HTTP 500)' >&2; exit 1`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			SetCacheTTL(0)
+
+			got, err := GetOutsideCollaboratorCount("o/r")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetOutsideCollaboratorCount error = %v, want error %v", err, tt.wantErr)
+			}
+			if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+				t.Errorf("count = %v, want %v", derefInt(got), derefInt(tt.want))
+			}
+			call := ghCalls(t, logPath)[0]
+			if !strings.Contains(call, "collaborators?affiliation=outside") || !strings.Contains(call, "--paginate") {
+				t.Errorf("call %q does not page through the outside collaborators", call)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepositoryGovernance(t *testing.T) {
+	// The repository is archived so it stays flagged with or without the governance checks
+	script := func(visibility, collaborators string) string {
+		return fmt.Sprintf(`case "$*" in
+*collaborators*) %s;;
+*commits*) echo %s;;
+"api repos/o/r") echo '{"archived":true,"visibility":%q}';;
+*) echo "unexpected call: $*" >&2; exit 1;;
+esac`, collaborators, testDaysAgo(400).Format(time.RFC3339), visibility)
+	}
+
+	tests := []struct {
+		name             string
+		visibility       string
+		collaborators    string
+		governance       bool
+		wantVisibility   string
+		wantOutside      *int
+		wantHighPriority bool
+	}{
+		{"public with outside collaborators", VisibilityPublic, `printf '100\n2\n'`, true, VisibilityPublic, intPtr(102), true},
+		{"public without outside collaborators", VisibilityPublic, `echo 0`, true, VisibilityPublic, intPtr(0), false},
+		{"private with outside collaborators", VisibilityPrivate, `echo 4`, true, VisibilityPrivate, intPtr(4), false},
+		{"internal", VisibilityInternal, `echo 1`, true, VisibilityInternal, intPtr(1), false},
+		{"collaborators unknown", VisibilityPublic, `echo 'gh: Forbidden (HTTP 403)' >&2; exit 1`, true, VisibilityPublic, nil, false},
+		{"governance not requested", VisibilityPublic, `echo 2`, false, "", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pinNow(t)
+			fakeGH(t, script(tt.visibility, tt.collaborators))
+			SetCacheTTL(0)
+
+			cfg := withConfig(flaggingConfig, func(c *config.Config) {
+				c.Metrics = []string{MetricCommits}
+				c.Governance = tt.governance
+				c.Silent = true
+			})
+			repo, _, err := AnalyzeRepository("o/r", cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if repo.Visibility != tt.wantVisibility {
+				t.Errorf("visibility = %q, want %q", repo.Visibility, tt.wantVisibility)
+			}
+			if got := derefInt(repo.OutsideCollaborators); got != derefInt(tt.wantOutside) {
+				t.Errorf("outside collaborators = %v, want %v", got, derefInt(tt.wantOutside))
+			}
+			if !repo.Flagged {
+				t.Fatalf("repository not flagged: %+v", repo)
+			}
+			if repo.HighPriority != tt.wantHighPriority || repo.ExposedToOutsiders != tt.wantHighPriority {
+				t.Errorf("high priority %v and exposed %v, want %v", repo.HighPriority, repo.ExposedToOutsiders, tt.wantHighPriority)
+			}
+			if marked := strings.Contains(priorityMarker(repo), "outside collaborators"); marked != tt.wantHighPriority {
+				t.Errorf("priority marker %q, want outside collaborators named %v", priorityMarker(repo), tt.wantHighPriority)
+			}
+		})
+	}
+}
+
+// derefInt renders an optional count for failure messages
+func derefInt(n *int) string {
+	if n == nil {
+		return "nil"
+	}
+	return fmt.Sprint(*n)
+}
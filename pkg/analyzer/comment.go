@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// maxCommentLength is the longest issue comment body GitHub accepts, in characters
+const maxCommentLength = 65536
+
+// commentTargetPattern matches an issue comment URL, https://github.com/org/repo/issues/12#issuecomment-345,
+// or its short form org/repo#issuecomment-345
+var commentTargetPattern = regexp.MustCompile(`^(?:https?://github\.com/)?([^/\s#]+/[^/\s#]+)(?:/(?:issues|pull)/\d+)?#issuecomment-(\d+)$`)
+
+// ParseCommentTarget returns the repository and ID of the issue comment given by its URL or short form
+func ParseCommentTarget(target string) (repoFullName string, commentID int64, err error) {
+	match := commentTargetPattern.FindStringSubmatch(target)
+	if match == nil {
+		return "", 0, fmt.Errorf("invalid comment %q, expected its URL (https://github.com/org/repo/issues/12#issuecomment-345) or org/repo#issuecomment-345", target)
+	}
+	commentID, err = strconv.ParseInt(match[2], 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid comment ID in %q: %w", target, err)
+	}
+	return match[1], commentID, nil
+}
+
+// UpdateIssueComment replaces the body of an issue comment with the Markdown report, so an ongoing
+// tracking issue keeps a single, current flagged list instead of gaining a comment per run
+// With dry run enabled, the update is only logged.
+func UpdateIssueComment(analysis Analysis, cfg config.Config) error {
+	repoFullName, commentID, err := ParseCommentTarget(cfg.UpdateComment)
+	if err != nil {
+		return err
+	}
+
+	body, err := renderCommentBody(analysis, cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.DryRun {
+		Logf("🧪 Dry run: would update comment %d in %s with a %d-character report\n", commentID, repoFullName, len([]rune(body)))
+		return nil
+	}
+
+	_, err = runGH("api", "--method", "PATCH",
+		fmt.Sprintf("repos/%s/issues/comments/%d", repoFullName, commentID),
+		"-f", "body="+body)
+	if err != nil {
+		if StatusCode(err) == http.StatusNotFound {
+			return fmt.Errorf("comment %d not found in %s: it may have been deleted, or the token cannot see the repository", commentID, repoFullName)
+		}
+		return fmt.Errorf("failed to update comment %d in %s: %w", commentID, repoFullName, err)
+	}
+	Logf("💬 Updated comment %d in %s\n", commentID, repoFullName)
+	return nil
+}
+
+// renderCommentBody renders the Markdown report for an issue comment, refusing one too long for GitHub to accept
+func renderCommentBody(analysis Analysis, cfg config.Config) (string, error) {
+	markdownCfg := cfg
+	markdownCfg.OutputFormat = "markdown"
+	report, err := RenderReport(analysis, markdownCfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to render report for comment: %w", err)
+	}
+
+	body := string(report)
+	if length := len([]rune(body)); length > maxCommentLength {
+		return "", fmt.Errorf("report is too long for a comment (%d characters, GitHub accepts %d), shorten it with -top", length, maxCommentLength)
+	}
+	return body, nil
+}
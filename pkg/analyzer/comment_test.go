@@ -0,0 +1,136 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestParseCommentTarget(t *testing.T) {
+	tests := []struct {
+		target   string
+		wantRepo string
+		wantID   int64
+		wantErr  bool
+	}{
+		{"https://github.com/o/tracker/issues/12#issuecomment-345", "o/tracker", 345, false},
+		{"https://github.com/o/tracker/pull/3#issuecomment-678", "o/tracker", 678, false},
+		{"o/tracker#issuecomment-345", "o/tracker", 345, false},
+		{"o/tracker#12", "", 0, true},
+		{"https://github.com/o/tracker/issues/12", "", 0, true},
+		{"345", "", 0, true},
+		{"o/tracker#issuecomment-99999999999999999999", "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			repo, id, err := ParseCommentTarget(tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCommentTarget error = %v, want error %v", err, tt.wantErr)
+			}
+			if repo != tt.wantRepo || id != tt.wantID {
+				t.Errorf("ParseCommentTarget = %s, %d, want %s, %d", repo, id, tt.wantRepo, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestUpdateIssueComment(t *testing.T) {
+	pinNow(t)
+	repos := []Repository{
+		{Name: "o/old", Flagged: true, FlagReason: FlagReasonOldInactiveContributors, LastCommitDate: *testDaysAgo(400), DaysSinceLastCommit: 400},
+		{Name: "o/new", LastCommitDate: *testDaysAgo(5), DaysSinceLastCommit: 5},
+	}
+	cfg := config.Config{Organization: "o", MaxCommitAgeInDays: 180, OutputFormat: "json", Silent: true,
+		UpdateComment: "https://github.com/o/tracker/issues/12#issuecomment-345"}
+	// The fake gh keeps the last argument, the comment body, which spans several lines
+	const keepBody = `for arg; do body=$arg; done; printf '%s' "$body" > "$GH_LOG.body"; `
+
+	tests := []struct {
+		name      string
+		script    string
+		dryRun    bool
+		wantErr   string
+		wantLog   string
+		wantCalls int
+	}{
+		{"updated", keepBody + `echo '{}'`, false, "", "Updated comment 345 in o/tracker", 1},
+		{"dry run", keepBody, true, "", "would update comment 345 in o/tracker", 0},
+		{"deleted", keepBody + `echo 'gh: Not Found (HTTP 404)' >&2; exit 1`, false, "comment 345 not found in o/tracker", "", 1},
+		{"other failure", keepBody + `echo 'gh: Server Error (HTTP 500)' >&2; exit 1`, false, "failed to update comment 345 in o/tracker", "", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			log := captureLog(t)
+
+			err := UpdateIssueComment(Analysis{Repositories: repos}, withConfig(cfg, func(c *config.Config) { c.DryRun = tt.dryRun }))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("UpdateIssueComment error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(log.String(), tt.wantLog) {
+				t.Errorf("log %q does not mention %q", log.String(), tt.wantLog)
+			}
+
+			// The multi-line body spreads each call over several lines of the call log
+			calls := strings.Join(ghCalls(t, logPath), "\n")
+			if got := strings.Count(calls, "api --method PATCH repos/o/tracker/issues/comments/345 -f body="); got != tt.wantCalls {
+				t.Fatalf("updated the comment %d times, want %d: %q", got, tt.wantCalls, calls)
+			}
+			if tt.wantCalls == 0 {
+				return
+			}
+			// The body is the Markdown report, whatever the output format of the run
+			body, err := os.ReadFile(logPath + ".body")
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := RenderReport(Analysis{Repositories: repos}, withConfig(cfg, func(c *config.Config) { c.OutputFormat = "markdown" }))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != "body="+string(want) {
+				t.Errorf("comment body =\n%s\nwant\n%s", body, want)
+			}
+		})
+	}
+}
+
+func TestUpdateIssueCommentRejected(t *testing.T) {
+	pinNow(t)
+	var long []Repository
+	for i := range 2000 {
+		long = append(long, Repository{Name: fmt.Sprintf("o/repository-with-a-long-name-%d", i), Flagged: true,
+			FlagReason: FlagReasonOldInactiveContributors, LastCommitDate: *testDaysAgo(400), DaysSinceLastCommit: 400})
+	}
+	cfg := config.Config{Organization: "o", MaxCommitAgeInDays: 180, Silent: true}
+
+	tests := []struct {
+		name    string
+		repos   []Repository
+		target  string
+		wantErr string
+	}{
+		{"invalid target", nil, "o/tracker#12", "invalid comment"},
+		{"too long", long, "o/tracker#issuecomment-345", "report is too long for a comment"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, `exit 0`)
+
+			err := UpdateIssueComment(Analysis{Repositories: tt.repos}, withConfig(cfg, func(c *config.Config) { c.UpdateComment = tt.target }))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("UpdateIssueComment error = %v, want %q", err, tt.wantErr)
+			}
+			if calls := ghCalls(t, logPath); len(calls) != 0 {
+				t.Errorf("made gh calls %q for a rejected comment", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// commitPath restricts every commit query to a file path or subdirectory, or is empty for the whole repository
+var commitPath string
+
+// pathContributors counts only the authors of commits under commitPath as contributors
+var pathContributors bool
+
+// SetCommitPath scopes the analysis of each repository to the commits touching a path, so a dead
+// component of a monorepo is not hidden by activity elsewhere; with contributors set, only the authors
+// of those commits count as contributors. An empty path restores whole-repository analysis.
+func SetCommitPath(path string, contributors bool) {
+	commitPath = strings.Trim(path, "/")
+	pathContributors = contributors && commitPath != ""
+}
+
+// withCommitPath restricts a commits endpoint to the commits touching the configured path, if any
+func withCommitPath(endpoint string) string {
+	if commitPath == "" {
+		return endpoint
+	}
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+	return endpoint + separator + "path=" + url.QueryEscape(commitPath)
+}
+
+// getPathContributors returns the logins of the authors of the commits under the configured path on the
+// default branch, in order of their most recent commit; commits whose author has no GitHub account are skipped
+// The commits are listed across every page, one call per 100 commits.
+func getPathContributors(repoFullName string) ([]string, error) {
+	out, err := runGH("api",
+		withAsOfUntil(commitsEndpoint(repoFullName, ""))+"&per_page=100",
+		"--paginate", "--jq", ".[].author.login // empty")
+	if err != nil {
+		switch StatusCode(err) {
+		case http.StatusConflict:
+			return nil, nil
+		case http.StatusForbidden, http.StatusUnavailableForLegalReasons:
+			return nil, fmt.Errorf("%w: %v", ErrContributorDataUnavailable, err)
+		}
+		return nil, fmt.Errorf("failed to get the authors under %s: %w", commitPath, err)
+	}
+	return parseAuthorLogins(out), nil
+}
+
+// parseAuthorLogins returns the distinct logins printed one per line, keeping the order of the first occurrence
+func parseAuthorLogins(data []byte) []string {
+	seen := make(map[string]bool)
+	var logins []string
+	for _, line := range strings.Split(string(data), "\n") {
+		login := strings.TrimSpace(line)
+		if login == "" || seen[login] {
+			continue
+		}
+		seen[login] = true
+		logins = append(logins, login)
+	}
+	return logins
+}
@@ -0,0 +1,134 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useCommitPath scopes the analysis to a path for the duration of a test
+func useCommitPath(t *testing.T, path string, contributors bool) {
+	t.Helper()
+	SetCommitPath(path, contributors)
+	t.Cleanup(func() { SetCommitPath("", false) })
+}
+
+func TestCommitsEndpointPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		branch string
+		want   string
+	}{
+		{"whole repository", "", "", "repos/o/r/commits"},
+		{"subdirectory", "services/api", "", "repos/o/r/commits?path=services%2Fapi"},
+		{"slashes trimmed", "/services/api/", "", "repos/o/r/commits?path=services%2Fapi"},
+		{"file with spaces", "docs/read me.md", "", "repos/o/r/commits?path=docs%2Fread+me.md"},
+		{"on a branch", "services/api", "dev", "repos/o/r/commits?sha=dev&path=services%2Fapi"},
+		{"only slashes", "/", "dev", "repos/o/r/commits?sha=dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCommitPath(t, tt.path, false)
+			if got := commitsEndpoint("o/r", tt.branch); got != tt.want {
+				t.Errorf("commitsEndpoint = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetLastCommitDatePath(t *testing.T) {
+	date := testNow.AddDate(0, 0, -30).Format(time.RFC3339)
+
+	tests := []struct {
+		name     string
+		path     string
+		script   string
+		wantCall string
+		wantErr  string
+	}{
+		{"scoped", "services/api", "echo " + date, "api repos/o/r/commits?path=services%2Fapi ", ""},
+		{"whole repository", "", "echo " + date, "api repos/o/r/commits ", ""},
+		{"no commits under the path", "services/api", "exit 0", "", "no commits found under services/api"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			SetCacheTTL(0)
+			useCommitPath(t, tt.path, false)
+
+			got, err := GetLastCommitDate("o/r", "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("GetLastCommitDate error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Format(time.RFC3339) != date {
+				t.Errorf("last commit = %v, want %s", got, date)
+			}
+			if calls := ghCalls(t, logPath); len(calls) != 1 || !strings.HasPrefix(calls[0], tt.wantCall) {
+				t.Errorf("gh calls %q, want %q", calls, tt.wantCall)
+			}
+		})
+	}
+}
+
+func TestGetContributorsPath(t *testing.T) {
+	// The authors of the commits under the path, newest first and one per commit
+	const script = `case "$*" in
+*"commits?path=services%2Fapi&per_page=100"*) printf 'bob\nann\nbob\n\ncy\n';;
+*contributors*) printf 'ann\nbob\ncy\ndee\n';;
+*) echo "unexpected call: $*" >&2; exit 1;;
+esac`
+
+	tests := []struct {
+		name         string
+		path         string
+		contributors bool
+		want         []string
+		wantCall     string
+	}{
+		{"authors under the path", "services/api", true, []string{"bob", "ann", "cy"}, "--paginate"},
+		{"contributors not scoped", "services/api", false, []string{"ann", "bob", "cy", "dee"}, "repos/o/r/contributors"},
+		{"no path", "", true, []string{"ann", "bob", "cy", "dee"}, "repos/o/r/contributors"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, script)
+			SetCacheTTL(0)
+			useCommitPath(t, tt.path, tt.contributors)
+
+			got, err := GetContributors("o/r")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("contributors = %q, want %q", got, tt.want)
+			}
+			if calls := ghCalls(t, logPath); len(calls) != 1 || !strings.Contains(calls[0], tt.wantCall) {
+				t.Errorf("gh calls %q, want one with %s", calls, tt.wantCall)
+			}
+		})
+	}
+}
+
+func TestDisplayNamePath(t *testing.T) {
+	tests := []struct {
+		repo Repository
+		want string
+	}{
+		{Repository{Name: "o/r"}, "o/r"},
+		{Repository{Name: "o/r", Path: "services/api"}, "o/r:services/api"},
+		{Repository{Name: "o/r", Branch: "dev", Path: "services/api"}, "o/r@dev:services/api"},
+	}
+	for _, tt := range tests {
+		if got := displayName(tt.repo); got != tt.want {
+			t.Errorf("displayName(%+v) = %q, want %q", tt.repo, got, tt.want)
+		}
+	}
+}
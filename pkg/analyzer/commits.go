@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// recentCommitLookback is how many recent commits are inspected for a substantive one or for signing
+const recentCommitLookback = 100
+
+// commitInfo holds the commit attributes used to tell automated commits from substantive ones
+// and signed commits from unsigned ones
+type commitInfo struct {
+	Commit struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string `json:"name"`
+		} `json:"author"`
+		Committer struct {
+			Date time.Time `json:"date"`
+		} `json:"committer"`
+		Verification struct {
+			Verified bool `json:"verified"`
+		} `json:"verification"`
+	} `json:"commit"`
+	Author *struct {
+		Login string `json:"login"`
+		Type  string `json:"type"`
+	} `json:"author"`
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+}
+
+// getRecentCommits lists the latest commits on the branch (empty means default), newest first
+// Only a single page is fetched, so the signals derived from it cover at most recentCommitLookback commits
+func getRecentCommits(repoFullName, branch string) ([]commitInfo, error) {
+	endpoint := withAsOfUntil(commitsEndpoint(repoFullName, branch))
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+
+	commits, err := runGHParsed(parseCommits, "api", fmt.Sprintf("%s%sper_page=%d", endpoint, separator, recentCommitLookback))
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return nil, fmt.Errorf("failed to get recent commits: %w", err)
+	}
+	return commits, err
+}
+
+// parseCommits decodes a commits list response
+func parseCommits(data []byte) ([]commitInfo, error) {
+	var commits []commitInfo
+	if err := json.Unmarshal(data, &commits); err != nil {
+		return nil, fmt.Errorf("failed to parse commits: %w", err)
+	}
+	return commits, nil
+}
+
+// GetLastSubstantiveCommitDate returns the date of the most recent commit that is neither a merge
+// nor made by a bot, looking back through the latest commits on the branch (empty means default)
+// When every inspected commit is automated, the date of the oldest one is returned as an upper bound
+func GetLastSubstantiveCommitDate(repoFullName, branch string) (time.Time, error) {
+	commits, err := getRecentCommits(repoFullName, branch)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return lastSubstantiveCommitDate(commits)
+}
+
+// lastSubstantiveCommitDate finds the newest substantive commit in a list of recent commits
+func lastSubstantiveCommitDate(commits []commitInfo) (time.Time, error) {
+	if len(commits) == 0 {
+		return time.Time{}, fmt.Errorf("no commits found")
+	}
+
+	for _, c := range commits {
+		if !isAutomatedCommit(c) {
+			return c.Commit.Committer.Date, nil
+		}
+	}
+
+	return commits[len(commits)-1].Commit.Committer.Date, nil
+}
+
+// signedCommitRatio returns the share of recent commits whose signature GitHub verified
+func signedCommitRatio(commits []commitInfo) (float64, error) {
+	if len(commits) == 0 {
+		return 0, fmt.Errorf("no commits found")
+	}
+
+	signed := 0
+	for _, c := range commits {
+		if c.Commit.Verification.Verified {
+			signed++
+		}
+	}
+	return float64(signed) / float64(len(commits)), nil
+}
+
+// isAutomatedCommit reports whether a commit is a merge or was made by a bot
+func isAutomatedCommit(c commitInfo) bool {
+	// Merge commits have more than one parent, and are also recognizable by the default merge message
+	if len(c.Parents) > 1 {
+		return true
+	}
+	message := c.Commit.Message
+	if strings.HasPrefix(message, "Merge pull request ") || strings.HasPrefix(message, "Merge branch ") {
+		return true
+	}
+
+	if c.Author != nil && isBotAccount(c.Author.Login, c.Author.Type) {
+		return true
+	}
+	return strings.HasSuffix(c.Commit.Author.Name, "[bot]")
+}
+
+// isBotAccount reports whether an account is a bot, marked by its account type or the [bot] suffix
+// of GitHub App logins
+func isBotAccount(login, accountType string) bool {
+	return accountType == "Bot" || strings.HasSuffix(login, "[bot]")
+}
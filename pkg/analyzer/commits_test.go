@@ -0,0 +1,122 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// commitJSON renders a commit of a commits list response, committed the given number of days before testNow
+func commitJSON(daysAgo int, message, authorName, login, accountType string, parents int) string {
+	author := "null"
+	if login != "" {
+		author = fmt.Sprintf(`{"login":%q,"type":%q}`, login, accountType)
+	}
+	shas := make([]string, parents)
+	for i := range shas {
+		shas[i] = fmt.Sprintf(`{"sha":"p%d"}`, i)
+	}
+	return fmt.Sprintf(`{"commit":{"message":%q,"author":{"name":%q},"committer":{"date":%q}},"author":%s,"parents":[%s]}`,
+		message, authorName, testDaysAgo(daysAgo).Format(time.RFC3339), author, strings.Join(shas, ","))
+}
+
+func TestLastSubstantiveCommitDate(t *testing.T) {
+	merge := commitJSON(1, "Merge pull request #12 from o/feature", "Ann", "ann", "User", 2)
+	squashedMerge := commitJSON(2, "Merge branch 'main' into dev", "Ann", "ann", "User", 1)
+	release := commitJSON(3, "chore(release): 1.2.3", "release-bot[bot]", "release-bot[bot]", "Bot", 1)
+	appCommit := commitJSON(4, "Bump lodash", "dependabot[bot]", "", "", 1)
+	fix := commitJSON(40, "Fix the parser", "Bob", "bob", "User", 1)
+	oldMerge := commitJSON(90, "Merge pull request #1 from o/init", "Ann", "ann", "User", 2)
+
+	tests := []struct {
+		name    string
+		commits []string
+		want    int
+		wantErr bool
+	}{
+		{"substantive first", []string{fix, oldMerge}, 40, false},
+		{"behind merges and bots", []string{merge, squashedMerge, release, appCommit, fix, oldMerge}, 40, false},
+		{"merge-only history", []string{merge, release, oldMerge}, 90, false},
+		{"no commits", nil, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commits, err := parseCommits([]byte("[" + strings.Join(tt.commits, ",") + "]"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := lastSubstantiveCommitDate(commits)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("lastSubstantiveCommitDate error = %v, want error %v", err, tt.wantErr)
+			}
+			if want := *testDaysAgo(tt.want); !tt.wantErr && !got.Equal(want) {
+				t.Errorf("last substantive commit = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSignedCommitRatio(t *testing.T) {
+	const signed, unsigned = `{"commit":{"verification":{"verified":true}}}`, `{"commit":{"verification":{"verified":false}}}`
+
+	tests := []struct {
+		name    string
+		commits []string
+		want    float64
+		wantErr bool
+	}{
+		{"all signed", []string{signed, signed}, 1, false},
+		{"weakly signed", []string{unsigned, signed, unsigned, unsigned}, 0.25, false},
+		{"no commits", nil, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commits, err := parseCommits([]byte("[" + strings.Join(tt.commits, ",") + "]"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := signedCommitRatio(commits)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("signedCommitRatio error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("signed commit ratio = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetLastSubstantiveCommitDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		branch   string
+		wantCall string
+	}{
+		{"default branch", "", "repos/o/r/commits?per_page=100"},
+		{"branch", "dev", "repos/o/r/commits?sha=dev&per_page=100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, "echo '["+commitJSON(1, "Merge pull request #2", "Ann", "ann", "User", 2)+","+commitJSON(5, "Add docs", "Bob", "bob", "User", 1)+"]'")
+			SetCacheTTL(0)
+
+			got, err := GetLastSubstantiveCommitDate("o/r", tt.branch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(*testDaysAgo(5)) {
+				t.Errorf("last substantive commit = %v, want %v", got, *testDaysAgo(5))
+			}
+			if calls := ghCalls(t, logPath); len(calls) != 1 || !strings.Contains(calls[0], tt.wantCall) {
+				t.Errorf("gh calls = %q, want %s", calls, tt.wantCall)
+			}
+		})
+	}
+
+	fakeGH(t, `echo 'gh: Server Error (HTTP 500)' >&2; exit 1`)
+	SetCacheTTL(0)
+	if _, err := GetLastSubstantiveCommitDate("o/r", ""); err == nil || !strings.Contains(err.Error(), "failed to get recent commits") {
+		t.Errorf("GetLastSubstantiveCommitDate error = %v, want the commits lookup failure", err)
+	}
+}
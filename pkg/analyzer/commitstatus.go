@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// Commit statuses recorded for repositories whose commits cannot be listed
+const (
+	CommitStatusEmpty      = "empty"      // The repository has no commits yet (409)
+	CommitStatusRestricted = "restricted" // Access to the repository is blocked, e.g. on legal grounds (451)
+)
+
+// ErrRepositoryEmpty is returned when the commits endpoint reports that the repository is empty
+var ErrRepositoryEmpty = errors.New("repository is empty")
+
+// ErrRepositoryRestricted is returned when the commits endpoint is unavailable for legal reasons
+var ErrRepositoryRestricted = errors.New("repository access is restricted")
+
+// commitStatusError maps the commits endpoint statuses that describe the repository rather than a
+// failure to their sentinel errors, keeping the API error wrapped; other errors are returned unchanged
+func commitStatusError(err error) error {
+	switch StatusCode(err) {
+	case http.StatusConflict:
+		return fmt.Errorf("%w: %w", ErrRepositoryEmpty, err)
+	case http.StatusUnavailableForLegalReasons:
+		return fmt.Errorf("%w: %w", ErrRepositoryRestricted, err)
+	}
+	return err
+}
+
+// commitStatusOf returns the commit status an error from the commits endpoint stands for, or "" for other errors
+func commitStatusOf(err error) string {
+	switch {
+	case errors.Is(err, ErrRepositoryEmpty):
+		return CommitStatusEmpty
+	case errors.Is(err, ErrRepositoryRestricted):
+		return CommitStatusRestricted
+	}
+	return ""
+}
+
+// lastCommitDateText renders the last commit date for tables, or the commit status when there is none
+func lastCommitDateText(repo Repository) string {
+	if repo.CommitStatus != "" {
+		return repo.CommitStatus
+	}
+	return repo.LastCommitDate.Format("2006-01-02")
+}
+
+// lastCommitSummary renders the last commit for human-readable output
+func lastCommitSummary(repo Repository, cfg config.Config) string {
+	switch repo.CommitStatus {
+	case CommitStatusEmpty:
+		return "none (empty repository)"
+	case CommitStatusRestricted:
+		return "unavailable (access restricted)"
+	}
+	return fmt.Sprintf("%s (%s%s)",
+		repo.LastCommitDate.Format("2006-01-02"), daysAgo(repo.DaysSinceLastCommit, cfg), sinceLastRunSuffix(repo))
+}
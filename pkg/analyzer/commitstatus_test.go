@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestGetLastCommitDateStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		stderr     string
+		wantErr    error
+		wantStatus string
+	}{
+		{"empty repository", "gh: Git Repository is empty. (HTTP 409)", ErrRepositoryEmpty, CommitStatusEmpty},
+		{"unavailable for legal reasons", "gh: Repository access blocked (HTTP 451)", ErrRepositoryRestricted, CommitStatusRestricted},
+		{"other failure", "gh: Server Error (HTTP 500)", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeGH(t, "echo '"+tt.stderr+"' >&2; exit 1")
+			SetCacheTTL(0)
+
+			_, err := GetLastCommitDate("o/r", "")
+			if err == nil || !strings.Contains(err.Error(), "failed to get commits") {
+				t.Fatalf("GetLastCommitDate error = %v, want the failed call reported", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error %v is not %v", err, tt.wantErr)
+			}
+			if StatusCode(err) == 0 {
+				t.Errorf("error %v lost the API status", err)
+			}
+			if got := commitStatusOf(err); got != tt.wantStatus {
+				t.Errorf("commit status = %q, want %q", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepositoryCommitStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		stderr      string
+		wantStatus  string
+		wantSummary string
+		wantErr     bool
+	}{
+		{"empty repository kept", "gh: Git Repository is empty. (HTTP 409)", CommitStatusEmpty, "none (empty repository)", false},
+		{"restricted repository kept", "gh: Repository access blocked (HTTP 451)", CommitStatusRestricted, "unavailable (access restricted)", false},
+		{"other failure skipped", "gh: Server Error (HTTP 500)", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pinNow(t)
+			fakeGH(t, `case "$*" in
+*commits*) echo '`+tt.stderr+`' >&2; exit 1;;
+"api repos/o/r") echo '{}';;
+*) echo "unexpected call: $*" >&2; exit 1;;
+esac`)
+			SetCacheTTL(0)
+
+			cfg := withConfig(flaggingConfig, func(c *config.Config) { c.Metrics = []string{MetricCommits}; c.Silent = true })
+			repo, _, err := AnalyzeRepository("o/r", cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AnalyzeRepository error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if repo.CommitStatus != tt.wantStatus || lastCommitDateText(repo) != tt.wantStatus {
+				t.Errorf("commit status = %q shown as %q, want %q", repo.CommitStatus, lastCommitDateText(repo), tt.wantStatus)
+			}
+			if got := lastCommitSummary(repo, cfg); got != tt.wantSummary {
+				t.Errorf("last commit summary = %q, want %q", got, tt.wantSummary)
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	RegisterFormatter("console", consoleFormatter{})
+}
+
+// consoleFormatter renders the human-readable report, with emoji on the terminal and as plain text in files
+// When the report goes to a file, the terminal still shows the console rendering
+type consoleFormatter struct{}
+
+// Format writes the console rendering to the terminal or the plain text report to a file
+func (consoleFormatter) Format(w io.Writer, repos []Repository, summary Summary) error {
+	if summary.Terminal {
+		return writeConsole(w, repos, summary)
+	}
+
+	var report []byte
+	if summary.Single && len(repos) == 1 {
+		report = renderSingleTextReport(repos[0], summary)
+	} else {
+		report = renderTextReport(repos, summary)
+		report = append(report, renderRemovedSection(summary.Removed)...)
+		report = append(report, renderDuplicatesSection(summary.Duplicates)...)
+		report = append(report, renderWarningsSection(summary.Warnings)...)
+	}
+	_, err := w.Write(report)
+	return err
+}
+
+// WriteNotes shows the console rendering on the terminal when the report went to a file
+func (consoleFormatter) WriteNotes(w io.Writer, repos []Repository, summary Summary) error {
+	if summary.Terminal {
+		return nil
+	}
+	return writeConsole(w, repos, summary)
+}
+
+// writeConsole writes the human-readable terminal output
+func writeConsole(w io.Writer, repos []Repository, summary Summary) error {
+	if summary.Single && len(repos) == 1 {
+		writeSingleConsole(w, repos[0], summary)
+		return nil
+	}
+
+	cfg := summary.Config
+	writeSummary(w, repos, summary)
+	fmt.Fprintln(w)
+
+	if summary.Flagged > 0 {
+		fmt.Fprintln(w, "🚩 Flagged Repositories:")
+		fmt.Fprintln(w, "---------------------")
+		for _, repo := range repos {
+			if repo.Flagged {
+				fmt.Fprintf(w, "- %s (reason: %s)%s\n", displayName(repo), repo.FlagReason, priorityMarker(repo))
+				fmt.Fprintf(w, "  Last commit: %s\n", lastCommitSummary(repo, cfg))
+				if repo.Lifecycle != "" {
+					fmt.Fprintf(w, "  🌱 Lifecycle: %s\n", repo.Lifecycle)
+				}
+				if repo.LastSubstantiveCommitDate != nil {
+					fmt.Fprintf(w, "  Last substantive commit: %s\n", substantiveCommitSummary(repo, cfg))
+				}
+				if repo.SignedCommitRatio != nil {
+					fmt.Fprintf(w, "  Signed commits: %s\n", signedCommitSummary(repo))
+				}
+				fmt.Fprintf(w, "  Contributors: %s\n", contributorSummary(repo))
+				if len(repo.InactiveContributorDetails) > 0 && cfg.GroupContributors {
+					writeContributorGroups(w, "  ", repo.InactiveContributorDetails)
+				} else if len(repo.InactiveContributorDetails) > 0 {
+					fmt.Fprintf(w, "  Inactive contributors: %s\n", inactiveContributorSummary(repo.InactiveContributorDetails))
+				}
+				if !repo.CreatedAt.IsZero() {
+					fmt.Fprintf(w, "  🗓️ Created: %s\n", repoAgeSummary(repo, cfg))
+				}
+				fmt.Fprintf(w, "  📜 License: %s\n", repo.License)
+				if repo.HasReadme != nil {
+					fmt.Fprintf(w, "  📖 README: %s\n", readmeSummary(repo))
+				}
+				if repo.LastCIStatus != "" {
+					fmt.Fprintf(w, "  ⚙️ CI: %s\n", ciSummary(repo))
+				}
+				if repo.OpenSecurityAlerts != nil || repo.SecurityAlertsUnknown {
+					fmt.Fprintf(w, "  🛡️ Open security alerts: %s\n", securityAlertSummary(repo))
+				}
+				if collects(cfg, MetricReviews) {
+					fmt.Fprintf(w, "  🔀 Last merged PR: %s\n", mergedPRSummary(repo, cfg))
+				}
+				if collects(cfg, MetricEngagement) {
+					fmt.Fprintf(w, "  💬 Issue engagement: %s\n", engagementSummary(repo))
+				}
+				if collects(cfg, MetricTags) {
+					fmt.Fprintf(w, "  🏷️ Last tag: %s\n", tagSummary(repo, cfg))
+				}
+				if collects(cfg, MetricBranches) {
+					fmt.Fprintf(w, "  🌿 Newest other branch: %s\n", newestBranchSummary(repo, cfg))
+				}
+				if repo.CommitMomentum != nil {
+					fmt.Fprintf(w, "  📉 Momentum: %s\n", momentumSummary(repo))
+				}
+				if repo.MedianCommitGapDays != nil {
+					fmt.Fprintf(w, "  ⏱️ Cadence: %s\n", cadenceSummary(repo, cfg))
+				}
+				if repo.StaleBotPRCount != nil {
+					fmt.Fprintf(w, "  🤖 Stale bot PRs: %s\n", staleBotPRSummary(repo, cfg))
+				}
+				if len(repo.Extra) > 0 {
+					fmt.Fprintf(w, "  🏷️ %s\n", extraSummary(repo))
+				}
+				if cfg.Governance {
+					fmt.Fprintf(w, "  🏛️ Governance: %s\n", governanceSummary(repo))
+				}
+				if len(repo.Admins) > 0 {
+					fmt.Fprintf(w, "  👤 Admins: %s\n", strings.Join(repo.Admins, ", "))
+				}
+				if repo.Contact != "" {
+					fmt.Fprintf(w, "  📇 Contact: %s\n", repo.Contact)
+				}
+				if repo.Archived {
+					fmt.Fprintf(w, "  📦 Repository Status: Archived\n\n")
+				} else {
+					fmt.Fprintf(w, "  📦 Repository Status: Not Archived\n\n")
+				}
+			}
+		}
+	}
+
+	if len(summary.Removed) > 0 {
+		fmt.Fprintln(w, "🗑️ Removed Since Last Run:")
+		fmt.Fprintln(w, "---------------------")
+		for _, name := range summary.Removed {
+			fmt.Fprintf(w, "- %s\n", name)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(summary.Duplicates) > 0 {
+		fmt.Fprintln(w, "👯 Likely Duplicates:")
+		fmt.Fprintln(w, "---------------------")
+		for _, c := range summary.Duplicates {
+			fmt.Fprintf(w, "- %s\n", duplicateClusterSummary(c))
+		}
+		fmt.Fprintln(w)
+	}
+
+	writeWarnings(w, summary.Warnings)
+
+	if steps := nextSteps(summary.Flagged, cfg); len(steps) > 0 {
+		fmt.Fprintln(w, "💡 Next Steps:")
+		fmt.Fprintln(w, "---------------------")
+		for _, step := range steps {
+			fmt.Fprintf(w, "- %s\n", step)
+		}
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
+
+// writeSingleConsole writes the human-readable terminal output for a single repository
+func writeSingleConsole(w io.Writer, repo Repository, summary Summary) {
+	cfg := summary.Config
+
+	fmt.Fprintf(w, "\n📊 Analysis Results for %s\n", displayName(repo))
+	fmt.Fprintf(w, "Last commit: %s\n", lastCommitSummary(repo, cfg))
+	if repo.Lifecycle != "" {
+		fmt.Fprintf(w, "🌱 Lifecycle: %s\n", repo.Lifecycle)
+	}
+	if repo.LastSubstantiveCommitDate != nil {
+		fmt.Fprintf(w, "Last substantive commit: %s\n", substantiveCommitSummary(repo, cfg))
+	}
+	if repo.SignedCommitRatio != nil {
+		fmt.Fprintf(w, "Signed commits: %s\n", signedCommitSummary(repo))
+	}
+	fmt.Fprintf(w, "Contributors: %s\n", contributorSummary(repo))
+	if len(repo.InactiveContributorDetails) > 0 && cfg.GroupContributors {
+		writeContributorGroups(w, "", repo.InactiveContributorDetails)
+	} else if len(repo.InactiveContributorDetails) > 0 {
+		fmt.Fprintf(w, "Inactive contributors: %s\n", inactiveContributorSummary(repo.InactiveContributorDetails))
+	}
+	if !repo.CreatedAt.IsZero() {
+		fmt.Fprintf(w, "🗓️ Created: %s\n", repoAgeSummary(repo, cfg))
+	}
+	fmt.Fprintf(w, "📜 License: %s\n", repo.License)
+	if repo.HasReadme != nil {
+		fmt.Fprintf(w, "📖 README: %s\n", readmeSummary(repo))
+	}
+	if repo.LastCIStatus != "" {
+		fmt.Fprintf(w, "⚙️ CI: %s\n", ciSummary(repo))
+	}
+	if repo.OpenSecurityAlerts != nil || repo.SecurityAlertsUnknown {
+		fmt.Fprintf(w, "🛡️ Open security alerts: %s\n", securityAlertSummary(repo))
+	}
+	if collects(cfg, MetricReviews) {
+		fmt.Fprintf(w, "🔀 Last merged PR: %s\n", mergedPRSummary(repo, cfg))
+	}
+	if collects(cfg, MetricEngagement) {
+		fmt.Fprintf(w, "💬 Issue engagement: %s\n", engagementSummary(repo))
+	}
+	if collects(cfg, MetricTags) {
+		fmt.Fprintf(w, "🏷️ Last tag: %s\n", tagSummary(repo, cfg))
+	}
+	if collects(cfg, MetricBranches) {
+		fmt.Fprintf(w, "🌿 Newest other branch: %s\n", newestBranchSummary(repo, cfg))
+	}
+	if repo.CommitMomentum != nil {
+		fmt.Fprintf(w, "📉 Momentum: %s\n", momentumSummary(repo))
+	}
+	if repo.MedianCommitGapDays != nil {
+		fmt.Fprintf(w, "⏱️ Cadence: %s\n", cadenceSummary(repo, cfg))
+	}
+	if repo.StaleBotPRCount != nil {
+		fmt.Fprintf(w, "🤖 Stale bot PRs: %s\n", staleBotPRSummary(repo, cfg))
+	}
+	if len(repo.Extra) > 0 {
+		fmt.Fprintf(w, "🏷️ %s\n", extraSummary(repo))
+	}
+
+	if cfg.Governance {
+		fmt.Fprintf(w, "🏛️ Governance: %s\n", governanceSummary(repo))
+	}
+
+	if len(repo.Admins) > 0 {
+		fmt.Fprintf(w, "👤 Admins: %s\n", strings.Join(repo.Admins, ", "))
+	}
+	if repo.Contact != "" {
+		fmt.Fprintf(w, "📇 Contact: %s\n", repo.Contact)
+	}
+
+	if repo.Archived {
+		fmt.Fprintln(w, "📦 Repository Status: Archived")
+	} else {
+		fmt.Fprintln(w, "📦 Repository Status: Active (Not Archived)")
+	}
+
+	if repo.Flagged {
+		fmt.Fprintf(w, "🚩 Status: Flagged as inactive (reason: %s)%s\n", repo.FlagReason, priorityMarker(repo))
+	} else {
+		fmt.Fprintln(w, "✅ Status: Active")
+	}
+	if !cfg.Silent {
+		fmt.Fprintf(w, "ℹ️ %s\n", flagLegend(cfg))
+	}
+
+	if len(summary.Warnings) > 0 {
+		fmt.Fprintln(w)
+		writeWarnings(w, summary.Warnings)
+	}
+}
+
+// renderSingleTextReport builds the plain text report for a single repository
+func renderSingleTextReport(repo Repository, summary Summary) []byte {
+	cfg := summary.Config
+
+	var reportBuf bytes.Buffer
+	reportBuf.WriteString(fmt.Sprintf("Analysis Results for %s\n", displayName(repo)))
+	reportBuf.WriteString(fmt.Sprintf("Date: %s\n", Now().Format("2006-01-02")))
+	reportBuf.WriteString(fmt.Sprintf("Last commit: %s\n", lastCommitSummary(repo, cfg)))
+	if repo.Lifecycle != "" {
+		reportBuf.WriteString(fmt.Sprintf("Lifecycle: %s\n", repo.Lifecycle))
+	}
+	if repo.LastSubstantiveCommitDate != nil {
+		reportBuf.WriteString(fmt.Sprintf("Last substantive commit: %s\n", substantiveCommitSummary(repo, cfg)))
+	}
+	if repo.SignedCommitRatio != nil {
+		reportBuf.WriteString(fmt.Sprintf("Signed commits: %s\n", signedCommitSummary(repo)))
+	}
+	reportBuf.WriteString(fmt.Sprintf("Contributors: %s\n", contributorSummary(repo)))
+	if len(repo.InactiveContributorDetails) > 0 && cfg.GroupContributors {
+		writeContributorGroups(&reportBuf, "", repo.InactiveContributorDetails)
+	} else if len(repo.InactiveContributorDetails) > 0 {
+		reportBuf.WriteString(fmt.Sprintf("Inactive contributors: %s\n", inactiveContributorSummary(repo.InactiveContributorDetails)))
+	}
+	if !repo.CreatedAt.IsZero() {
+		reportBuf.WriteString(fmt.Sprintf("Created: %s\n", repoAgeSummary(repo, cfg)))
+	}
+	reportBuf.WriteString(fmt.Sprintf("License: %s\n", repo.License))
+	if repo.HasReadme != nil {
+		reportBuf.WriteString(fmt.Sprintf("README: %s\n", readmeSummary(repo)))
+	}
+	if repo.LastCIStatus != "" {
+		reportBuf.WriteString(fmt.Sprintf("CI: %s\n", ciSummary(repo)))
+	}
+	if repo.OpenSecurityAlerts != nil || repo.SecurityAlertsUnknown {
+		reportBuf.WriteString(fmt.Sprintf("Open security alerts: %s\n", securityAlertSummary(repo)))
+	}
+	if collects(cfg, MetricReviews) {
+		reportBuf.WriteString(fmt.Sprintf("Last merged PR: %s\n", mergedPRSummary(repo, cfg)))
+	}
+	if collects(cfg, MetricEngagement) {
+		reportBuf.WriteString(fmt.Sprintf("Issue engagement: %s\n", engagementSummary(repo)))
+	}
+	if collects(cfg, MetricTags) {
+		reportBuf.WriteString(fmt.Sprintf("Last tag: %s\n", tagSummary(repo, cfg)))
+	}
+	if collects(cfg, MetricBranches) {
+		reportBuf.WriteString(fmt.Sprintf("Newest other branch: %s\n", newestBranchSummary(repo, cfg)))
+	}
+	if repo.CommitMomentum != nil {
+		reportBuf.WriteString(fmt.Sprintf("Momentum: %s\n", momentumSummary(repo)))
+	}
+	if repo.MedianCommitGapDays != nil {
+		reportBuf.WriteString(fmt.Sprintf("Cadence: %s\n", cadenceSummary(repo, cfg)))
+	}
+	if repo.StaleBotPRCount != nil {
+		reportBuf.WriteString(fmt.Sprintf("Stale bot PRs: %s\n", staleBotPRSummary(repo, cfg)))
+	}
+	if len(repo.Extra) > 0 {
+		reportBuf.WriteString(fmt.Sprintf("%s\n", extraSummary(repo)))
+	}
+
+	if cfg.Governance {
+		reportBuf.WriteString(fmt.Sprintf("Governance: %s\n", governanceSummary(repo)))
+	}
+
+	if len(repo.Admins) > 0 {
+		reportBuf.WriteString(fmt.Sprintf("Admins: %s\n", strings.Join(repo.Admins, ", ")))
+	}
+	if repo.Contact != "" {
+		reportBuf.WriteString(fmt.Sprintf("Contact: %s\n", repo.Contact))
+	}
+
+	if repo.Archived {
+		reportBuf.WriteString("Repository Status: Archived\n")
+	} else {
+		reportBuf.WriteString("Repository Status: Not Archived\n")
+	}
+
+	if repo.Flagged {
+		reportBuf.WriteString(fmt.Sprintf("Status: Flagged as inactive (reason: %s)%s\n", repo.FlagReason, priorityMarker(repo)))
+	} else {
+		reportBuf.WriteString("Status: Active\n")
+	}
+	if !cfg.Silent {
+		reportBuf.WriteString(flagLegend(cfg) + "\n")
+	}
+
+	return reportBuf.Bytes()
+}
@@ -0,0 +1,73 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// contributorGroup is a set of inactive contributors whose last commit is similarly stale
+type contributorGroup struct {
+	Label        string
+	Contributors []InactiveContributor
+}
+
+// Staleness buckets of the grouped inactive contributors, by age of their last commit to the repository
+const (
+	recentDepartureDays = 180
+	pastDepartureDays   = 365
+)
+
+// groupInactiveContributors buckets inactive contributors by how long ago they last committed,
+// from the most recent departures to those without any commit found; empty groups are left out
+func groupInactiveContributors(details []InactiveContributor, now time.Time) []contributorGroup {
+	groups := []contributorGroup{
+		{Label: "Last commit under 6 months ago"},
+		{Label: "Last commit 6-12 months ago"},
+		{Label: "Last commit over a year ago"},
+		{Label: "No commits found"},
+	}
+
+	for _, c := range details {
+		i := 3
+		if c.LastCommitDate != nil {
+			switch days := int(now.Sub(*c.LastCommitDate).Hours() / 24); {
+			case days < recentDepartureDays:
+				i = 0
+			case days < pastDepartureDays:
+				i = 1
+			default:
+				i = 2
+			}
+		}
+		groups[i].Contributors = append(groups[i].Contributors, c)
+	}
+
+	nonEmpty := groups[:0]
+	for _, g := range groups {
+		if len(g.Contributors) > 0 {
+			nonEmpty = append(nonEmpty, g)
+		}
+	}
+	return nonEmpty
+}
+
+// writeContributorGroups writes the inactive contributors grouped by departure, one group per line under a heading
+func writeContributorGroups(w io.Writer, indent string, details []InactiveContributor) {
+	fmt.Fprintf(w, "%sInactive contributors:\n", indent)
+	for _, g := range groupInactiveContributors(details, Now()) {
+		names := make([]string, 0, len(g.Contributors))
+		for _, c := range g.Contributors {
+			name := c.Login
+			switch {
+			case c.Reason == InactiveReasonSuspended:
+				name += " (suspended)"
+			case c.LastCommitDate != nil:
+				name += " (" + c.LastCommitDate.Format("2006-01-02") + ")"
+			}
+			names = append(names, name)
+		}
+		fmt.Fprintf(w, "%s  %s: %s\n", indent, g.Label, strings.Join(names, ", "))
+	}
+}
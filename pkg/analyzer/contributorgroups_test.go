@@ -0,0 +1,65 @@
+package analyzer
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestGroupInactiveContributors(t *testing.T) {
+	recent := InactiveContributor{Login: "ann", LastCommitDate: testDaysAgo(30)}
+	boundary := InactiveContributor{Login: "bob", LastCommitDate: testDaysAgo(recentDepartureDays)}
+	past := InactiveContributor{Login: "cy", LastCommitDate: testDaysAgo(300)}
+	longAgo := InactiveContributor{Login: "dee", LastCommitDate: testDaysAgo(800)}
+	none := InactiveContributor{Login: "eve"}
+
+	tests := []struct {
+		name    string
+		details []InactiveContributor
+		want    []contributorGroup
+	}{
+		{"no contributors", nil, []contributorGroup{}},
+		{
+			name:    "every bucket",
+			details: []InactiveContributor{none, longAgo, past, recent},
+			want: []contributorGroup{
+				{Label: "Last commit under 6 months ago", Contributors: []InactiveContributor{recent}},
+				{Label: "Last commit 6-12 months ago", Contributors: []InactiveContributor{past}},
+				{Label: "Last commit over a year ago", Contributors: []InactiveContributor{longAgo}},
+				{Label: "No commits found", Contributors: []InactiveContributor{none}},
+			},
+		},
+		{
+			name:    "empty buckets left out",
+			details: []InactiveContributor{boundary, past, none},
+			want: []contributorGroup{
+				{Label: "Last commit 6-12 months ago", Contributors: []InactiveContributor{boundary, past}},
+				{Label: "No commits found", Contributors: []InactiveContributor{none}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := groupInactiveContributors(tt.details, testNow); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groupInactiveContributors = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteContributorGroups(t *testing.T) {
+	pinNow(t)
+	var buf bytes.Buffer
+	writeContributorGroups(&buf, "  ", []InactiveContributor{
+		{Login: "ann", LastCommitDate: testDaysAgo(30)},
+		{Login: "bob", LastCommitDate: testDaysAgo(40), Reason: InactiveReasonSuspended},
+		{Login: "eve"},
+	})
+
+	want := "  Inactive contributors:\n" +
+		"    Last commit under 6 months ago: ann (2025-05-02), bob (suspended)\n" +
+		"    No commits found: eve\n"
+	if buf.String() != want {
+		t.Errorf("grouped contributors =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,122 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ContributorMap merges contributor logins that belong to one identity and drops accounts that should not count
+// Each line of a contributor map file is either a canonical login followed by its aliases, or a login to drop
+// prefixed with "!", in the spirit of a .mailmap file:
+//
+//	# personal and work accounts of the same person
+//	octocat octocat-work <octo-old>
+//	# service accounts and vendored imports
+//	!release-bot
+//
+// Logins are matched case-insensitively, and angle brackets around them are optional.
+type ContributorMap struct {
+	aliases map[string]string // alias (lower case) to canonical login
+	dropped map[string]bool   // dropped logins (lower case)
+	digest  string            // digest of the rules, so cached data collected with other rules is not reused
+}
+
+// LoadContributorMap reads a contributor map file
+func LoadContributorMap(path string) (*ContributorMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contributor map: %w", err)
+	}
+	return parseContributorMap(data)
+}
+
+// parseContributorMap parses contributor map rules, skipping blank lines and # comments
+func parseContributorMap(data []byte) (*ContributorMap, error) {
+	m := &ContributorMap{
+		aliases: make(map[string]string),
+		dropped: make(map[string]bool),
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		for i, field := range fields {
+			fields[i] = strings.TrimSuffix(strings.TrimPrefix(field, "<"), ">")
+		}
+
+		if login, ok := strings.CutPrefix(fields[0], "!"); ok {
+			if login == "" || len(fields) != 1 {
+				return nil, fmt.Errorf("contributor map line %d: expected a single login after !", lineNo)
+			}
+			m.dropped[strings.ToLower(login)] = true
+			continue
+		}
+
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("contributor map line %d: expected a canonical login followed by its aliases", lineNo)
+		}
+		for _, alias := range fields[1:] {
+			key := strings.ToLower(alias)
+			if canonical, ok := m.aliases[key]; ok && !strings.EqualFold(canonical, fields[0]) {
+				return nil, fmt.Errorf("contributor map line %d: %s is already an alias of %s", lineNo, alias, canonical)
+			}
+			m.aliases[key] = fields[0]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read contributor map: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	m.digest = hex.EncodeToString(sum[:8])
+	return m, nil
+}
+
+// Apply replaces aliases with their canonical login, drops excluded accounts, and removes the duplicates
+// merging creates, keeping the order of the first occurrence; a nil map returns the logins unchanged
+func (m *ContributorMap) Apply(logins []string) []string {
+	if m == nil {
+		return logins
+	}
+
+	seen := make(map[string]bool)
+	var mapped []string
+	for _, login := range logins {
+		if canonical, ok := m.aliases[strings.ToLower(login)]; ok {
+			login = canonical
+		}
+		key := strings.ToLower(login)
+		if m.dropped[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		mapped = append(mapped, login)
+	}
+	return mapped
+}
+
+// Digest identifies the rules of the map, or is empty for a nil map
+func (m *ContributorMap) Digest() string {
+	if m == nil {
+		return ""
+	}
+	return m.digest
+}
+
+// contributorMap is applied to every contributor list before activity is classified, when configured
+var contributorMap *ContributorMap
+
+// SetContributorMap configures the contributor map applied to every contributor list
+func SetContributorMap(m *ContributorMap) {
+	contributorMap = m
+}
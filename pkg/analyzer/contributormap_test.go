@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testContributorMap is a contributor map file exercising aliases, drops, comments, and brackets
+const testContributorMap = `
+# personal and work accounts of the same person
+octocat octocat-work <Octo-Old>
+
+!release-bot
+<ann> ann-laptop
+`
+
+func TestContributorMapApply(t *testing.T) {
+	m, err := parseContributorMap([]byte(testContributorMap))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		m      *ContributorMap
+		logins []string
+		want   []string
+	}{
+		{"nil map", nil, []string{"release-bot", "octo-old"}, []string{"release-bot", "octo-old"}},
+		{"unmapped", m, []string{"bob", "carol"}, []string{"bob", "carol"}},
+		{"aliases merged", m, []string{"octocat-work", "bob", "octo-old", "octocat"}, []string{"octocat", "bob"}},
+		{"case-insensitive", m, []string{"OCTOCAT-WORK", "Ann-Laptop"}, []string{"octocat", "ann"}},
+		{"dropped", m, []string{"Release-Bot", "bob"}, []string{"bob"}},
+		{"repeated login", m, []string{"bob", "Bob"}, []string{"bob"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.Apply(tt.logins); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply(%q) = %q, want %q", tt.logins, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseContributorMapInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"canonical only", "octocat\n", "line 1: expected a canonical login followed by its aliases"},
+		{"drop with aliases", "# drops\n!bot other\n", "line 2: expected a single login after !"},
+		{"empty drop", "!\n", "line 1: expected a single login after !"},
+		{"alias of two logins", "ann shared\nbob Shared\n", "line 2: Shared is already an alias of ann"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseContributorMap([]byte(tt.data)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseContributorMap error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContributorMapDigest(t *testing.T) {
+	a, err := parseContributorMap([]byte("ann ann-work\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := parseContributorMap([]byte("ann ann-laptop\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Digest() == "" || a.Digest() == b.Digest() {
+		t.Errorf("digests %q and %q, want distinct non-empty digests for different rules", a.Digest(), b.Digest())
+	}
+	if (*ContributorMap)(nil).Digest() != "" {
+		t.Error("nil map has a digest")
+	}
+
+	if _, err := LoadContributorMap(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("LoadContributorMap succeeded with a missing file")
+	}
+}
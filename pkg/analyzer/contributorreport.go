@@ -0,0 +1,136 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// ContributorDeparture is one contributor's activity across every repository of a scan, for correlating
+// departures with offboarding records
+type ContributorDeparture struct {
+	Login        string   `json:"login"`
+	Repositories []string `json:"repositories"`         // every repository the contributor contributed to
+	InactiveIn   []string `json:"inactiveIn,omitempty"` // the repositories where the contributor is inactive
+	Left         bool     `json:"left"`                 // inactive in every repository they contributed to
+	Reason       string   `json:"reason,omitempty"`     // why the contributor is inactive, from their latest inactive repository
+	// LastCommitDate is the contributor's last commit to any repository where they are inactive,
+	// which approximates when they left
+	LastCommitDate *time.Time `json:"lastCommitDate,omitempty"`
+}
+
+// reportsContributors reports whether each repository retains its contributors for the contributor report
+func reportsContributors(cfg config.Config) bool {
+	return cfg.ContributorReport != ""
+}
+
+// aggregateContributors merges the contributors of every repository into one row per login, sorted by login
+// Logins are matched case-insensitively, as GitHub logins are; repositories without complete
+// contributor data are left out.
+func aggregateContributors(repos []Repository) []ContributorDeparture {
+	byLogin := make(map[string]*ContributorDeparture)
+	row := func(login string) *ContributorDeparture {
+		key := strings.ToLower(login)
+		d, ok := byLogin[key]
+		if !ok {
+			d = &ContributorDeparture{Login: login}
+			byLogin[key] = d
+		}
+		return d
+	}
+
+	for _, repo := range repos {
+		if !repo.ContributorDataComplete {
+			continue
+		}
+		for _, login := range repo.ActiveContributorLogins {
+			d := row(login)
+			d.Repositories = append(d.Repositories, repo.Name)
+		}
+		for _, departed := range repo.DepartedContributors {
+			d := row(departed.Login)
+			d.Repositories = append(d.Repositories, repo.Name)
+			d.InactiveIn = append(d.InactiveIn, repo.Name)
+			if departed.LastCommitDate != nil && (d.LastCommitDate == nil || departed.LastCommitDate.After(*d.LastCommitDate)) {
+				d.LastCommitDate = departed.LastCommitDate
+				d.Reason = departed.Reason
+			} else if d.Reason == "" {
+				d.Reason = departed.Reason
+			}
+		}
+	}
+
+	departures := make([]ContributorDeparture, 0, len(byLogin))
+	for _, d := range byLogin {
+		sort.Strings(d.Repositories)
+		sort.Strings(d.InactiveIn)
+		d.Left = len(d.InactiveIn) == len(d.Repositories)
+		departures = append(departures, *d)
+	}
+	sort.Slice(departures, func(i, j int) bool {
+		return strings.ToLower(departures[i].Login) < strings.ToLower(departures[j].Login)
+	})
+	return departures
+}
+
+// WriteContributorReport writes the contributors of every analyzed repository, one row per contributor,
+// to the configured file: as JSON when the file name ends in .json, as CSV otherwise
+func WriteContributorReport(repos []Repository, cfg config.Config) error {
+	departures := aggregateContributors(repos)
+	if redactionMap != nil {
+		departures = redactionMap.redactDepartures(departures)
+	}
+
+	var data []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(cfg.ContributorReport), ".json") {
+		data, err = json.MarshalIndent(departures, "", "  ")
+	} else {
+		data, err = contributorReportCSV(departures)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to render contributor report: %w", err)
+	}
+
+	if err := writeFileAtomic(cfg.ContributorReport, data, 0644); err != nil {
+		return fmt.Errorf("failed to write contributor report: %w", err)
+	}
+	Logf("👥 Contributor report saved to %s\n", cfg.ContributorReport)
+	return nil
+}
+
+// contributorReportCSV renders the contributor report as CSV, with repository lists separated by semicolons
+func contributorReportCSV(departures []ContributorDeparture) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"Login", "Repositories", "Inactive In", "Left", "Reason", "Last Commit Date"}); err != nil {
+		return nil, err
+	}
+	for _, d := range departures {
+		lastCommit := ""
+		if d.LastCommitDate != nil {
+			lastCommit = d.LastCommitDate.Format("2006-01-02")
+		}
+		record := []string{
+			d.Login,
+			strings.Join(d.Repositories, ";"),
+			strings.Join(d.InactiveIn, ";"),
+			strconv.FormatBool(d.Left),
+			d.Reason,
+			lastCommit,
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
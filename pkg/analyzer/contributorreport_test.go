@@ -0,0 +1,108 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestAggregateContributors(t *testing.T) {
+	repos := []Repository{
+		{
+			Name:                    "o/api",
+			ContributorDataComplete: true,
+			ActiveContributorLogins: []string{"ann"},
+			DepartedContributors: []InactiveContributor{
+				{Login: "bob", Reason: InactiveReasonLeftOrg, LastCommitDate: testDaysAgo(300)},
+				{Login: "cy", Reason: InactiveReasonNoRecentOrgCommit, LastCommitDate: testDaysAgo(200)},
+			},
+		},
+		{
+			Name:                    "o/web",
+			ContributorDataComplete: true,
+			// Logins differ only in case between repositories
+			ActiveContributorLogins: []string{"CY"},
+			DepartedContributors: []InactiveContributor{
+				{Login: "Bob", Reason: InactiveReasonSuspended, LastCommitDate: testDaysAgo(100)},
+				{Login: "dee", Reason: InactiveReasonLeftOrg},
+			},
+		},
+		{
+			Name:                    "o/docs",
+			ContributorDataComplete: false,
+			ActiveContributorLogins: []string{"eve"},
+			DepartedContributors:    []InactiveContributor{{Login: "ann", Reason: InactiveReasonLeftOrg}},
+		},
+	}
+
+	want := []ContributorDeparture{
+		{Login: "ann", Repositories: []string{"o/api"}},
+		{Login: "bob", Repositories: []string{"o/api", "o/web"}, InactiveIn: []string{"o/api", "o/web"}, Left: true,
+			Reason: InactiveReasonSuspended, LastCommitDate: testDaysAgo(100)},
+		{Login: "cy", Repositories: []string{"o/api", "o/web"}, InactiveIn: []string{"o/api"},
+			Reason: InactiveReasonNoRecentOrgCommit, LastCommitDate: testDaysAgo(200)},
+		{Login: "dee", Repositories: []string{"o/web"}, InactiveIn: []string{"o/web"}, Left: true, Reason: InactiveReasonLeftOrg},
+	}
+	if got := aggregateContributors(repos); !reflect.DeepEqual(got, want) {
+		t.Errorf("aggregateContributors =\n%+v\nwant\n%+v", got, want)
+	}
+	if got := aggregateContributors(nil); len(got) != 0 {
+		t.Errorf("aggregateContributors(nil) = %+v, want no rows", got)
+	}
+}
+
+func TestWriteContributorReport(t *testing.T) {
+	repos := []Repository{
+		{Name: "o/api", ContributorDataComplete: true, ActiveContributorLogins: []string{"ann"},
+			DepartedContributors: []InactiveContributor{{Login: "bob", Reason: InactiveReasonLeftOrg, LastCommitDate: testDaysAgo(300)}}},
+		{Name: "o/web", ContributorDataComplete: true,
+			DepartedContributors: []InactiveContributor{{Login: "bob", Reason: InactiveReasonLeftOrg}}},
+	}
+
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{
+			name: "csv",
+			file: "contributors.csv",
+			want: "Login,Repositories,Inactive In,Left,Reason,Last Commit Date\n" +
+				"ann,o/api,,false,,\n" +
+				"bob,o/api;o/web,o/api;o/web,true,left-org," + testDaysAgo(300).Format("2006-01-02") + "\n",
+		},
+		{name: "json", file: "contributors.JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captureLog(t)
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := WriteContributorReport(repos, config.Config{ContributorReport: path}); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want != "" {
+				if string(data) != tt.want {
+					t.Errorf("contributor report =\n%s\nwant\n%s", data, tt.want)
+				}
+				return
+			}
+			var departures []ContributorDeparture
+			if err := json.Unmarshal(data, &departures); err != nil {
+				t.Fatalf("contributor report is not JSON: %v\n%s", err, data)
+			}
+			if len(departures) != 2 || departures[1].Login != "bob" || !departures[1].Left ||
+				!strings.Contains(string(data), `"inactiveIn": [`) {
+				t.Errorf("contributor report = %s, want one row each for ann and bob", data)
+			}
+		})
+	}
+}
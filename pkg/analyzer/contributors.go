@@ -0,0 +1,117 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// Reasons a contributor is classified as inactive
+const (
+	InactiveReasonLeftOrg           = "left-org"
+	InactiveReasonNoRecentOrgCommit = "no-recent-org-commits"
+	InactiveReasonSuspended         = "suspended"
+	InactiveReasonNoRecentCommit    = "no-recent-commits" // in a local clone, which has no organization to check
+)
+
+// InactiveContributor describes a contributor classified as inactive and when they last committed to the repository
+type InactiveContributor struct {
+	Login  string `json:"login"`
+	Reason string `json:"reason"`
+
+	// LastCommitDate is nil when no commit by the contributor could be found
+	LastCommitDate *time.Time `json:"lastCommitDate,omitempty"`
+}
+
+// GetLastAuthorCommitDate returns the date of the contributor's most recent commit to the repository
+// A zero time means no commit authored by the contributor was found
+func GetLastAuthorCommitDate(repoFullName, login string) (time.Time, error) {
+	out, err := runGH("api",
+		withCommitPath(fmt.Sprintf("repos/%s/commits?author=%s&per_page=1", repoFullName, url.QueryEscape(login))),
+		"--jq", ".[0].commit.author.date // empty")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get commits by %s: %w", login, err)
+	}
+
+	return parseAuthorCommitDate(out)
+}
+
+// parseAuthorCommitDate parses the author date extracted from the commits endpoint
+func parseAuthorCommitDate(data []byte) (time.Time, error) {
+	dateStr := strings.TrimSpace(string(data))
+	if dateStr == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, dateStr)
+}
+
+// describesContributors reports whether inactive contributors are looked up individually,
+// either to list them or to group them by departure
+func describesContributors(cfg config.Config) bool {
+	return cfg.ContributorDetails || cfg.GroupContributors
+}
+
+// describeInactiveContributors looks up when each inactive contributor last committed to the repository
+// Contributors in suspended are inactive because their account is suspended
+// Lookup failures are warnings unless strict mode is enabled
+func describeInactiveContributors(repoFullName string, logins, suspended []string, cfg config.Config, warnings *warningLog) ([]InactiveContributor, error) {
+	reason := InactiveReasonLeftOrg
+	if cfg.ContributorScope == ContributorScopeOrg {
+		reason = InactiveReasonNoRecentOrgCommit
+	}
+
+	isSuspended := make(map[string]bool, len(suspended))
+	for _, login := range suspended {
+		isSuspended[login] = true
+	}
+
+	details := make([]InactiveContributor, 0, len(logins))
+	for _, login := range logins {
+		detail := InactiveContributor{Login: login, Reason: reason}
+		if isSuspended[login] {
+			detail.Reason = InactiveReasonSuspended
+		}
+
+		date, err := GetLastAuthorCommitDate(repoFullName, login)
+		if err != nil {
+			if cfg.Strict {
+				return nil, err
+			}
+			warnings.warn(cfg, repoFullName, "Failed to get last commit of %s in %s: %v", login, repoFullName, err)
+		} else if !date.IsZero() {
+			detail.LastCommitDate = &date
+		}
+
+		details = append(details, detail)
+	}
+
+	return details, nil
+}
+
+// inactiveContributorSummary renders the inactive contributors for human-readable output,
+// e.g. "alice (left org; last commit 2023-02-11)"
+func inactiveContributorSummary(details []InactiveContributor) string {
+	parts := make([]string, 0, len(details))
+	for _, c := range details {
+		reason := "left org"
+		switch c.Reason {
+		case InactiveReasonNoRecentOrgCommit:
+			reason = "no recent org commits"
+		case InactiveReasonSuspended:
+			reason = "suspended"
+		case InactiveReasonNoRecentCommit:
+			reason = "no recent commits"
+		}
+
+		lastCommit := "no commits found"
+		if c.LastCommitDate != nil {
+			lastCommit = "last commit " + c.LastCommitDate.Format("2006-01-02")
+		}
+
+		parts = append(parts, fmt.Sprintf("%s (%s; %s)", c.Login, reason, lastCommit))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestDescribeInactiveContributors(t *testing.T) {
+	// ann last committed in 2024, bob has no commits found, and cy's lookup fails
+	const script = `case "$*" in
+*author=ann*) echo 2024-03-01T12:00:00Z;;
+*author=bob*) exit 0;;
+*) echo 'gh: Server Error (HTTP 500)' >&2; exit 1;;
+esac`
+	annDate := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		cfg          config.Config
+		suspended    []string
+		want         []InactiveContributor
+		wantErr      bool
+		wantWarnings int
+	}{
+		{
+			name: "left the organization",
+			cfg:  config.Config{Silent: true},
+			want: []InactiveContributor{
+				{Login: "ann", Reason: InactiveReasonLeftOrg, LastCommitDate: &annDate},
+				{Login: "bob", Reason: InactiveReasonLeftOrg},
+				{Login: "cy", Reason: InactiveReasonLeftOrg},
+			},
+			wantWarnings: 1,
+		},
+		{
+			name:      "org scope and suspended",
+			cfg:       config.Config{ContributorScope: ContributorScopeOrg, Silent: true},
+			suspended: []string{"bob"},
+			want: []InactiveContributor{
+				{Login: "ann", Reason: InactiveReasonNoRecentOrgCommit, LastCommitDate: &annDate},
+				{Login: "bob", Reason: InactiveReasonSuspended},
+				{Login: "cy", Reason: InactiveReasonNoRecentOrgCommit},
+			},
+			wantWarnings: 1,
+		},
+		{
+			name:    "strict",
+			cfg:     config.Config{Strict: true, Silent: true},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeGH(t, script)
+			SetCacheTTL(0)
+			warnings := &warningLog{}
+
+			got, err := describeInactiveContributors("o/r", []string{"ann", "bob", "cy"}, tt.suspended, tt.cfg, warnings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("describeInactiveContributors error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("details = %+v, want %+v", got, tt.want)
+			}
+			if len(warnings.list()) != tt.wantWarnings {
+				t.Errorf("recorded %d warnings, want %d", len(warnings.list()), tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestInactiveContributorSummary(t *testing.T) {
+	details := []InactiveContributor{
+		{Login: "ann", Reason: InactiveReasonLeftOrg, LastCommitDate: testDaysAgo(365)},
+		{Login: "bob", Reason: InactiveReasonNoRecentOrgCommit},
+		{Login: "cy", Reason: InactiveReasonSuspended},
+		{Login: "dee", Reason: InactiveReasonNoRecentCommit, LastCommitDate: testDaysAgo(0)},
+	}
+	want := "ann (left org; last commit 2024-06-01), bob (no recent org commits; no commits found), " +
+		"cy (suspended; no commits found), dee (no recent commits; last commit 2025-06-01)"
+	if got := inactiveContributorSummary(details); got != want {
+		t.Errorf("inactiveContributorSummary = %q, want %q", got, want)
+	}
+}
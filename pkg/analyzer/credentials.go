@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// credentialWildcard is the owner of a credential used for owners without their own
+const credentialWildcard = "*"
+
+// ErrNoCredential is returned for repositories whose owner no configured credential covers
+var ErrNoCredential = errors.New("no credential configured for the repository owner")
+
+// Credential is a token used for the repositories of one owner (user or organization)
+type Credential struct {
+	Owner    string `json:"owner"`              // Owner the token is used for, or * for any other owner
+	Host     string `json:"host,omitempty"`     // GitHub host, for GitHub Enterprise Server (optional)
+	Token    string `json:"token,omitempty"`    // Token value
+	TokenEnv string `json:"tokenEnv,omitempty"` // Environment variable holding the token, instead of Token
+}
+
+// Credentials selects the credential for each repository owner
+type Credentials []Credential
+
+// LoadCredentials reads a JSON array of credentials, resolving tokens given by environment variable
+func LoadCredentials(path string) (Credentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var creds Credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file: %w", err)
+	}
+
+	for i := range creds {
+		c := &creds[i]
+		if c.Owner == "" {
+			return nil, fmt.Errorf("credential %d has no owner", i+1)
+		}
+		if c.Token == "" && c.TokenEnv != "" {
+			c.Token = os.Getenv(c.TokenEnv)
+			if c.Token == "" {
+				return nil, fmt.Errorf("credential for %s: environment variable %s is not set", c.Owner, c.TokenEnv)
+			}
+		}
+		if c.Token == "" {
+			return nil, fmt.Errorf("credential for %s has no token or tokenEnv", c.Owner)
+		}
+	}
+
+	return creds, nil
+}
+
+// For returns the credential for an owner, matched case-insensitively, falling back to the wildcard credential
+func (c Credentials) For(owner string) (Credential, bool) {
+	var wildcard *Credential
+	for i := range c {
+		if strings.EqualFold(c[i].Owner, owner) {
+			return c[i], true
+		}
+		if c[i].Owner == credentialWildcard && wildcard == nil {
+			wildcard = &c[i]
+		}
+	}
+	if wildcard != nil {
+		return *wildcard, true
+	}
+	return Credential{}, false
+}
+
+// credentials selects the token of each gh api call by the owner it addresses, when configured
+var credentials Credentials
+
+// SetCredentials configures per-owner credentials used for every gh api call
+func SetCredentials(creds Credentials) {
+	credentials = creds
+}
+
+// CheckCredential verifies that a credential covers the owner of a repository, when credentials are configured
+func CheckCredential(repoFullName string) error {
+	if credentials == nil {
+		return nil
+	}
+	owner := strings.Split(repoFullName, "/")[0]
+	if _, ok := credentials.For(owner); !ok {
+		return fmt.Errorf("%w: %s", ErrNoCredential, owner)
+	}
+	return nil
+}
+
+// searchOwnerPattern matches the owner qualifier of a search query
+var searchOwnerPattern = regexp.MustCompile(`\b(?:org|user|repo):([A-Za-z0-9-]+)`)
+
+// ghValueFlags are the gh api flags followed by a value
+var ghValueFlags = map[string]bool{
+	"--method": true, "-X": true, "--jq": true, "-q": true, "--template": true, "-t": true,
+	"--field": true, "-F": true, "--raw-field": true, "-f": true, "--header": true, "-H": true,
+	"--cache": true, "--input": true, "--hostname": true,
+}
+
+// apiOwner returns the repository owner or organization a gh api call addresses, or "" if none
+func apiOwner(args []string) string {
+	if len(args) < 2 || args[0] != "api" {
+		return ""
+	}
+
+	// The endpoint is the first argument that is not a flag or a flag value
+	var endpoint string
+	for i := 1; i < len(args); i++ {
+		if strings.HasPrefix(args[i], "-") {
+			if ghValueFlags[args[i]] {
+				i++
+			}
+			continue
+		}
+		endpoint = strings.TrimPrefix(args[i], "/")
+		break
+	}
+
+	path, query, _ := strings.Cut(endpoint, "?")
+	parts := strings.Split(path, "/")
+	switch {
+	case len(parts) >= 2 && (parts[0] == "repos" || parts[0] == "orgs" || parts[0] == "users"):
+		return parts[1]
+	case len(parts) >= 4 && parts[0] == "user" && parts[1] == "memberships" && parts[2] == "orgs":
+		return parts[3]
+	case parts[0] == "search":
+		if q, err := url.ParseQuery(query); err == nil {
+			if match := searchOwnerPattern.FindStringSubmatch(q.Get("q")); match != nil {
+				return match[1]
+			}
+		}
+	}
+	return ""
+}
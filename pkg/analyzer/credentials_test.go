@@ -0,0 +1,138 @@
+package analyzer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadCredentials(t *testing.T) {
+	t.Setenv("ACME_TOKEN", "ghp_env")
+
+	tests := []struct {
+		name    string
+		content string
+		want    Credentials
+		wantErr string
+	}{
+		{
+			name:    "tokens",
+			content: `[{"owner":"acme","token":"ghp_1"},{"owner":"*","host":"ghe.example.com","tokenEnv":"ACME_TOKEN"}]`,
+			want:    Credentials{{Owner: "acme", Token: "ghp_1"}, {Owner: "*", Host: "ghe.example.com", Token: "ghp_env", TokenEnv: "ACME_TOKEN"}},
+		},
+		{"token before environment", `[{"owner":"acme","token":"ghp_1","tokenEnv":"ACME_TOKEN"}]`, Credentials{{Owner: "acme", Token: "ghp_1", TokenEnv: "ACME_TOKEN"}}, ""},
+		{"unset environment variable", `[{"owner":"acme","tokenEnv":"UNSET_TOKEN"}]`, nil, "credential for acme: environment variable UNSET_TOKEN is not set"},
+		{"no owner", `[{"owner":"acme","token":"ghp_1"},{"token":"ghp_2"}]`, nil, "credential 2 has no owner"},
+		{"no token", `[{"owner":"acme"}]`, nil, "credential for acme has no token or tokenEnv"},
+		{"not json", `{"owner":"acme"}`, nil, "failed to parse credentials file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "credentials.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadCredentials(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadCredentials error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadCredentials = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredentialsFor(t *testing.T) {
+	creds := Credentials{{Owner: "*", Token: "wild-1"}, {Owner: "Acme", Token: "acme"}, {Owner: "*", Token: "wild-2"}}
+
+	tests := []struct {
+		name      string
+		creds     Credentials
+		owner     string
+		wantToken string
+		wantOK    bool
+	}{
+		{"exact owner over an earlier wildcard", creds, "Acme", "acme", true},
+		{"case-insensitive", creds, "ACME", "acme", true},
+		{"first wildcard", creds, "beta", "wild-1", true},
+		{"not covered", creds[1:2], "beta", "", false},
+		{"none configured", nil, "acme", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.creds.For(tt.owner)
+			if got.Token != tt.wantToken || ok != tt.wantOK {
+				t.Errorf("For(%q) = %q, %v, want %q, %v", tt.owner, got.Token, ok, tt.wantToken, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestAPIOwner(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"repository", []string{"api", "repos/acme/api/commits"}, "acme"},
+		{"leading slash", []string{"api", "/orgs/acme/repos"}, "acme"},
+		{"user", []string{"api", "users/jdoe"}, "jdoe"},
+		{"membership", []string{"api", "user/memberships/orgs/acme"}, "acme"},
+		{"after flags", []string{"api", "--method", "GET", "-H", "Accept: x", "--paginate", "repos/acme/api"}, "acme"},
+		{"search", []string{"api", "search/repositories?q=org%3Aacme+archived%3Afalse"}, "acme"},
+		{"search without owner", []string{"api", "search/repositories?q=stars%3A%3E10"}, ""},
+		{"graphql", []string{"api", "graphql", "-f", "query=..."}, ""},
+		{"not api", []string{"auth", "status"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := apiOwner(tt.args); got != tt.want {
+				t.Errorf("apiOwner(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredentialsSelectToken(t *testing.T) {
+	fakeGH(t, `echo "$GH_TOKEN $GH_HOST"`)
+	SetCacheTTL(0)
+	SetCredentials(Credentials{{Owner: "acme", Token: "acme-token"}, {Owner: "beta", Host: "ghe.example.com", Token: "beta-token"}})
+	t.Cleanup(func() { SetCredentials(nil) })
+	t.Setenv("GH_TOKEN", "default-token")
+	t.Setenv("GH_HOST", "")
+
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{"repos/acme/api", "acme-token \n"},
+		{"orgs/beta/repos", "beta-token ghe.example.com\n"},
+		{"repos/other/api", "default-token \n"},
+	}
+	for _, tt := range tests {
+		out, err := runGH("api", tt.endpoint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != tt.want {
+			t.Errorf("call to %s ran with %q, want %q", tt.endpoint, out, tt.want)
+		}
+	}
+
+	if err := CheckCredential("other/api"); !errors.Is(err, ErrNoCredential) {
+		t.Errorf("CheckCredential error = %v, want ErrNoCredential", err)
+	}
+	if err := CheckCredential("Acme/api"); err != nil {
+		t.Errorf("CheckCredential error = %v, want nil", err)
+	}
+}
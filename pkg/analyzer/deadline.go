@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// deadlineGrace is how long the repositories being analyzed when the deadline passes may take to finish
+// before their remaining gh calls are cut off
+const deadlineGrace = 30 * time.Second
+
+// ErrDeadlineExceeded is returned by gh calls cut off after the deadline and its grace period
+var ErrDeadlineExceeded = errors.New("run deadline reached")
+
+var (
+	// runDeadline is the configured total run time, or 0 when the run is unbounded
+	runDeadline time.Duration
+
+	// scanContext is done once the deadline passes, after which no more repositories are started
+	scanContext = context.Background()
+
+	// callContext is done once the grace period after the deadline ends, cancelling the gh calls still running
+	callContext = context.Background()
+
+	// cancelDeadline releases the timers of the contexts above
+	cancelDeadline context.CancelFunc = func() {}
+
+	// notStarted counts the repositories left unanalyzed because the deadline passed
+	notStarted atomic.Int64
+
+	// deadlineLogged makes sure the deadline is announced only once
+	deadlineLogged sync.Once
+)
+
+// SetDeadline bounds the total run time from now: once it passes, no more repositories are started and
+// those in progress get a short grace period before their gh calls are cut off; 0 removes the bound
+func SetDeadline(d time.Duration) {
+	cancelDeadline()
+	runDeadline = d
+	notStarted.Store(0)
+	deadlineLogged = sync.Once{}
+	if d <= 0 {
+		scanContext, callContext, cancelDeadline = context.Background(), context.Background(), func() {}
+		return
+	}
+
+	var cancelScan, cancelCalls context.CancelFunc
+	scanContext, cancelScan = context.WithTimeout(context.Background(), d)
+	callContext, cancelCalls = context.WithTimeout(context.Background(), d+deadlineGrace)
+	cancelDeadline = func() {
+		cancelScan()
+		cancelCalls()
+	}
+}
+
+// StartRepository reports whether another repository may be analyzed, which is no longer the case
+// once the deadline has passed; repositories turned away are counted as not analyzed
+func StartRepository(cfg config.Config) bool {
+	if scanContext.Err() == nil {
+		return true
+	}
+	notStarted.Add(1)
+	deadlineLogged.Do(func() {
+		if !cfg.Silent {
+			Logf("⏰ Deadline of %s reached, finishing the repositories in progress\n", formatDuration(runDeadline))
+		}
+	})
+	return false
+}
+
+// NotStarted returns how many repositories were not analyzed because the deadline passed
+func NotStarted() int {
+	return int(notStarted.Load())
+}
+
+// deadlineNote explains that a report is partial because the deadline passed, or is empty for a complete report
+func deadlineNote(summary Summary) string {
+	if summary.NotAnalyzed == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Deadline of %s reached, results partial: %d repositories were not analyzed",
+		formatDuration(summary.Config.Deadline), summary.NotAnalyzed)
+}
@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// useDeadline bounds the run time from now for the duration of a test
+func useDeadline(t *testing.T, d time.Duration) {
+	t.Helper()
+	SetDeadline(d)
+	t.Cleanup(func() { SetDeadline(0) })
+}
+
+func TestAnalyzeRepositoriesDeadline(t *testing.T) {
+	// Analyzing slow outlasts a short deadline, so the repositories after it are never started
+	script := `case "$*" in
+*rate_limit*) echo '{"resources":{"core":{"limit":5000,"remaining":5000,"reset":1748736000}}}';;
+*orgs/o/repos*) printf 'slow\nnext\nlast\n';;
+*repos/o/slow/commits*) sleep 0.5; echo ` + testNow.Format(time.RFC3339) + `;;
+*commits*) echo ` + testNow.Format(time.RFC3339) + `;;
+"api repos/o/"*) echo '{}';;
+*) echo "unexpected call: $*" >&2; exit 1;;
+esac`
+	const note = "results partial: 2 repositories were not analyzed"
+
+	tests := []struct {
+		name            string
+		deadline        time.Duration
+		wantRepos       int
+		wantNotAnalyzed int
+	}{
+		{"deadline reached", 200 * time.Millisecond, 1, 2},
+		{"unbounded", 0, 3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pinNow(t)
+			fakeGH(t, script)
+			SetCacheTTL(0)
+			captureLog(t)
+			useDeadline(t, tt.deadline)
+
+			cfg := config.Config{Organization: "o", Metrics: []string{MetricCommits}, MaxCommitAgeInDays: 180,
+				Concurrency: 1, Deadline: tt.deadline, Silent: true}
+			analysis, err := AnalyzeRepositories(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			repos, skipped := analysis.Repositories, analysis.Skipped
+			if len(repos) != tt.wantRepos || skipped != 0 || NotStarted() != tt.wantNotAnalyzed {
+				t.Fatalf("analyzed %d, skipped %d and left %d, want %d analyzed and %d left",
+					len(repos), skipped, NotStarted(), tt.wantRepos, tt.wantNotAnalyzed)
+			}
+			if tt.wantNotAnalyzed > 0 && repos[0].Name != "o/slow" {
+				t.Errorf("analyzed %s, want the repository in progress at the deadline finished", repos[0].Name)
+			}
+
+			summary := newSummary(repos, skipped, nil, nil, false, cfg)
+			for _, format := range []string{"console", "markdown", "json"} {
+				f, _ := LookupFormatter(format)
+				var buf bytes.Buffer
+				if err := f.Format(&buf, repos, summary); err != nil {
+					t.Fatal(err)
+				}
+				wantNote := tt.wantNotAnalyzed > 0
+				if format == "json" {
+					if got := strings.Contains(buf.String(), `"partial": true`); got != wantNote {
+						t.Errorf("json report marked partial = %v, want %v", got, wantNote)
+					}
+				} else if got := strings.Contains(buf.String(), note); got != wantNote {
+					t.Errorf("deadline note in %s output = %v, want %v:\n%s", format, got, wantNote, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestDeadlineNote(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary Summary
+		want    string
+	}{
+		{"complete", Summary{Config: config.Config{Deadline: time.Minute}}, ""},
+		{"partial", Summary{NotAnalyzed: 4, Config: config.Config{Deadline: 20 * time.Minute}},
+			"Deadline of " + formatDuration(20*time.Minute) + " reached, results partial: 4 repositories were not analyzed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deadlineNote(tt.summary); got != tt.want {
+				t.Errorf("deadlineNote = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// duplicateNameSimilarity is the lowest name similarity (1 minus the edit distance over the longer name)
+// at which two repositories are considered likely duplicates
+const duplicateNameSimilarity = 0.8
+
+// minDuplicateNameLength is the shortest name compared by edit distance, as short names such as api
+// and app are too close by nature to say anything
+const minDuplicateNameLength = 5
+
+// copySuffixPattern matches the name parts left by copying a repository, such as project-old or project_v2
+var copySuffixPattern = regexp.MustCompile(`^(old|new|copy|backup|bak|archive|archived|legacy|deprecated|orig|original|tmp|temp|fork|clone|mirror|v?\d+)$`)
+
+// DuplicateCluster is a set of flagged repositories whose names suggest copies of the same project
+type DuplicateCluster struct {
+	Repositories []string `json:"repositories"`
+	SameTip      bool     `json:"sameTip"` // every repository's latest commit is the same commit
+}
+
+// findDuplicateClusters groups the flagged repositories whose names are alike, in the order
+// each cluster's first repository was listed; repositories without a likely duplicate are left out
+func findDuplicateClusters(repos []Repository) []DuplicateCluster {
+	var names []string
+	seen := make(map[string]bool)
+	for _, repo := range repos {
+		if repo.Flagged && !seen[repo.Name] {
+			seen[repo.Name] = true
+			names = append(names, repo.Name)
+		}
+	}
+
+	// Union-find over the names, linking every alike pair
+	parent := make([]int, len(names))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range names {
+		for j := i + 1; j < len(names); j++ {
+			if namesAlike(names[i], names[j]) {
+				parent[root(j)] = root(i)
+			}
+		}
+	}
+
+	var clusters []DuplicateCluster
+	index := make(map[int]int)
+	for i, name := range names {
+		r := root(i)
+		c, ok := index[r]
+		if !ok {
+			c = len(clusters)
+			index[r] = c
+			clusters = append(clusters, DuplicateCluster{})
+		}
+		clusters[c].Repositories = append(clusters[c].Repositories, name)
+	}
+
+	var duplicates []DuplicateCluster
+	for _, c := range clusters {
+		if len(c.Repositories) > 1 {
+			duplicates = append(duplicates, c)
+		}
+	}
+	return duplicates
+}
+
+// namesAlike reports whether two repository full names look like copies of the same project:
+// they are the same once copy suffixes are dropped, or their names are within the edit distance threshold
+func namesAlike(a, b string) bool {
+	a, b = strings.ToLower(repoShortName(a)), strings.ToLower(repoShortName(b))
+	if baseName(a) == baseName(b) {
+		return true
+	}
+	if min(len(a), len(b)) < minDuplicateNameLength {
+		return false
+	}
+	return nameSimilarity(a, b) >= duplicateNameSimilarity
+}
+
+// repoShortName returns the repository part of a full name
+func repoShortName(fullName string) string {
+	return fullName[strings.LastIndex(fullName, "/")+1:]
+}
+
+// baseName drops the copy suffixes from a lower-case repository name, so project-old-2 becomes project
+func baseName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	for len(parts) > 1 && copySuffixPattern.MatchString(parts[len(parts)-1]) {
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, "-")
+}
+
+// nameSimilarity is 1 minus the edit distance between the names over the length of the longer one
+func nameSimilarity(a, b string) float64 {
+	longer := max(len(a), len(b))
+	if longer == 0 {
+		return 1
+	}
+	return 1 - float64(editDistance(a, b))/float64(longer)
+}
+
+// editDistance is the Levenshtein distance between two strings, counting bytes
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// FindDuplicates reports the likely duplicate clusters among the flagged repositories, and whether
+// each cluster's repositories all point at the same latest commit, which makes them exact copies
+// Only the repositories in a cluster cost a call, for their latest commit.
+func FindDuplicates(repos []Repository, cfg config.Config) []DuplicateCluster {
+	return findDuplicates(repos, cfg, nil)
+}
+
+// findDuplicates finds the duplicate clusters like FindDuplicates, recording the failed lookups in a log
+func findDuplicates(repos []Repository, cfg config.Config, warnings *warningLog) []DuplicateCluster {
+	branches := make(map[string]string)
+	for _, repo := range repos {
+		branches[repo.Name] = repo.Branch
+	}
+
+	clusters := findDuplicateClusters(repos)
+	for i := range clusters {
+		clusters[i].SameTip = sameTip(clusters[i].Repositories, branches, cfg, warnings)
+	}
+	return clusters
+}
+
+// sameTip reports whether the repositories' latest commits are all the same commit
+// A repository whose latest commit cannot be looked up never matches
+func sameTip(names []string, branches map[string]string, cfg config.Config, warnings *warningLog) bool {
+	first := ""
+	for _, name := range names {
+		sha, err := getTipSHA(name, branches[name])
+		if err != nil {
+			warnings.warn(cfg, name, "%v", err)
+			return false
+		}
+		if sha == "" || (first != "" && sha != first) {
+			return false
+		}
+		first = sha
+	}
+	return true
+}
+
+// getTipSHA returns the SHA of the latest commit on the branch (empty means default), or an empty
+// string when the repository has no commits
+func getTipSHA(repoFullName, branch string) (string, error) {
+	endpoint := withAsOfUntil(commitsEndpoint(repoFullName, branch))
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+
+	out, err := runGH("api", endpoint+separator+"per_page=1", "--jq", ".[0].sha // empty")
+	if err != nil {
+		if StatusCode(err) == http.StatusConflict {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get the latest commit: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// duplicateClusterSummary renders a duplicate cluster for human-readable output
+func duplicateClusterSummary(c DuplicateCluster) string {
+	summary := strings.Join(c.Repositories, ", ")
+	if c.SameTip {
+		summary += " (same latest commit)"
+	}
+	return summary
+}
@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestNamesAlike(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"o/project", "o/project-old", true},
+		{"o/project", "o/Project_v2", true},
+		{"o/project-old-2", "o/project.backup", true},
+		{"o/service", "p/service-copy", true},
+		{"o/billing-api", "o/biling-api", true},
+		{"o/payments", "o/payment", true},
+		{"o/api", "o/app", false},
+		{"o/frontend", "o/backend", false},
+		{"o/old", "o/new", false},
+		{"o/v2", "o/v2-old", true},
+	}
+	for _, tt := range tests {
+		if got := namesAlike(tt.a, tt.b); got != tt.want {
+			t.Errorf("namesAlike(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFindDuplicateClusters(t *testing.T) {
+	flagged := func(names ...string) []Repository {
+		repos := make([]Repository, len(names))
+		for i, name := range names {
+			repos[i] = Repository{Name: name, Flagged: true}
+		}
+		return repos
+	}
+
+	tests := []struct {
+		name  string
+		repos []Repository
+		want  []DuplicateCluster
+	}{
+		{"none", flagged("o/api", "o/web"), nil},
+		{"pair", flagged("o/api", "o/billing", "o/billing-old"), []DuplicateCluster{{Repositories: []string{"o/billing", "o/billing-old"}}}},
+		{
+			name:  "linked through a middle name",
+			repos: flagged("o/reporting-v1", "o/web", "o/reportin", "o/reporting", "o/web-copy"),
+			want: []DuplicateCluster{
+				{Repositories: []string{"o/reporting-v1", "o/reportin", "o/reporting"}},
+				{Repositories: []string{"o/web", "o/web-copy"}},
+			},
+		},
+		{"unflagged left out", append(flagged("o/billing"), Repository{Name: "o/billing-old"}), nil},
+		{"repeated name counted once", flagged("o/billing", "o/billing"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findDuplicateClusters(tt.repos); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findDuplicateClusters = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindDuplicatesSameTip(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   bool
+	}{
+		{"same commit", `echo abc123`, true},
+		{"different commits", `case "$*" in *billing-old*) echo def456;; *) echo abc123;; esac`, false},
+		{"empty repository", `case "$*" in *billing-old*) echo 'gh: Git Repository is empty. (HTTP 409)' >&2; exit 1;; *) echo abc123;; esac`, false},
+		{"lookup failure", `echo 'gh: Server Error (HTTP 500)' >&2; exit 1`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			SetCacheTTL(0)
+
+			repos := []Repository{
+				{Name: "o/billing", Flagged: true},
+				{Name: "o/billing-old", Branch: "main", Flagged: true},
+				{Name: "o/web"},
+			}
+			got := FindDuplicates(repos, config.Config{Silent: true})
+			if len(got) != 1 || got[0].SameTip != tt.want {
+				t.Fatalf("FindDuplicates = %+v, want one cluster with same tip %v", got, tt.want)
+			}
+			if calls := ghCalls(t, logPath); len(calls) > 2 {
+				t.Errorf("made %d gh calls, want at most one per clustered repository: %q", len(calls), calls)
+			}
+		})
+	}
+}
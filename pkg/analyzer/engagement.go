@@ -0,0 +1,167 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// engagementIssueSample is how many of the most recently opened issues are checked for a maintainer response
+const engagementIssueSample = 30
+
+// engagementGracePeriod is how long a new issue may go without a response before it counts as unanswered
+const engagementGracePeriod = 7 * 24 * time.Hour
+
+// minUnansweredSample is the fewest issues past the grace period needed to judge the unanswered share
+const minUnansweredSample = 3
+
+// maintainerAssociations are the author associations of comments counted as a maintainer response
+var maintainerAssociations = map[string]bool{
+	"OWNER":        true,
+	"MEMBER":       true,
+	"COLLABORATOR": true,
+}
+
+// issueEngagement summarizes how quickly maintainers respond to recent issues
+type issueEngagement struct {
+	MedianResponseHours *float64 // nil when no sampled issue was answered
+	Unanswered          int      // issues past the grace period without a maintainer response
+	Sampled             int      // issues past the grace period or answered
+}
+
+// engagementIssue is an issue and the comments on it
+type engagementIssue struct {
+	Number    int       `json:"number"`
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+	Comments  int       `json:"comments"`
+}
+
+// engagementComment is a comment on an issue
+type engagementComment struct {
+	Author      string    `json:"author"`
+	Association string    `json:"author_association"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// GetIssueEngagement measures the time to the first maintainer response on the most recently opened issues
+// It lists the issues once and their comments once per commented issue, so it is kept opt-in
+func GetIssueEngagement(repoFullName string, now time.Time) (issueEngagement, error) {
+	issues, err := runGHParsed(parseEngagementIssues, "api",
+		fmt.Sprintf("repos/%s/issues?state=all&sort=created&direction=desc&per_page=%d", repoFullName, engagementIssueSample),
+		"--jq", ".[] | select(.pull_request == null) | {number, author: .user.login, created_at, comments}")
+	if err != nil {
+		return issueEngagement{}, fmt.Errorf("failed to get issues: %w", err)
+	}
+
+	responses := make(map[int]*time.Time, len(issues))
+	for _, issue := range issues {
+		if issue.Comments == 0 {
+			continue
+		}
+		comments, err := runGHParsed(parseEngagementComments, "api",
+			fmt.Sprintf("repos/%s/issues/%d/comments?per_page=100", repoFullName, issue.Number),
+			"--jq", ".[] | {author: .user.login, author_association, created_at}")
+		if err != nil {
+			return issueEngagement{}, fmt.Errorf("failed to get comments of issue #%d: %w", issue.Number, err)
+		}
+		responses[issue.Number] = firstMaintainerResponse(issue, comments)
+	}
+
+	return summarizeEngagement(issues, responses, now), nil
+}
+
+// firstMaintainerResponse returns when a maintainer other than the issue author first commented, or nil if none did
+func firstMaintainerResponse(issue engagementIssue, comments []engagementComment) *time.Time {
+	var first *time.Time
+	for i := range comments {
+		c := comments[i]
+		if c.Author == issue.Author || !maintainerAssociations[c.Association] {
+			continue
+		}
+		if first == nil || c.CreatedAt.Before(*first) {
+			first = &comments[i].CreatedAt
+		}
+	}
+	return first
+}
+
+// summarizeEngagement computes the median response time and the unanswered count from each issue's first response
+// Issues still within the grace period and without a response are left out, as they may yet be answered
+func summarizeEngagement(issues []engagementIssue, responses map[int]*time.Time, now time.Time) issueEngagement {
+	var engagement issueEngagement
+	var responseTimes []time.Duration
+	for _, issue := range issues {
+		if response := responses[issue.Number]; response != nil {
+			responseTimes = append(responseTimes, response.Sub(issue.CreatedAt))
+			engagement.Sampled++
+			continue
+		}
+		if now.Sub(issue.CreatedAt) > engagementGracePeriod {
+			engagement.Unanswered++
+			engagement.Sampled++
+		}
+	}
+
+	if len(responseTimes) > 0 {
+		median := medianHours(responseTimes)
+		engagement.MedianResponseHours = &median
+	}
+	return engagement
+}
+
+// medianHours returns the median of the durations in hours, averaging the middle two of an even count
+func medianHours(durations []time.Duration) float64 {
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid].Hours()
+	}
+	return (sorted[mid-1] + sorted[mid]).Hours() / 2
+}
+
+// parseEngagementIssues decodes the issues printed one JSON object per line
+func parseEngagementIssues(data []byte) ([]engagementIssue, error) {
+	var issues []engagementIssue
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var issue engagementIssue
+		if err := json.Unmarshal(line, &issue); err != nil {
+			return nil, fmt.Errorf("failed to parse issues: %w", err)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// parseEngagementComments decodes the issue comments printed one JSON object per line
+func parseEngagementComments(data []byte) ([]engagementComment, error) {
+	var comments []engagementComment
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var comment engagementComment
+		if err := json.Unmarshal(line, &comment); err != nil {
+			return nil, fmt.Errorf("failed to parse issue comments: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
+
+// isUnresponsive reports whether maintainers have stopped answering issues: the median first response
+// takes longer than maxResponseHours, or at least half of the sampled issues went unanswered
+// Repositories whose engagement was not measured, or with too few issues to judge, are never unresponsive
+func isUnresponsive(r Repository, maxResponseHours float64) bool {
+	if r.MedianIssueResponseHours != nil && *r.MedianIssueResponseHours > maxResponseHours {
+		return true
+	}
+	return r.IssuesSampled >= minUnansweredSample && r.UnansweredIssues*2 >= r.IssuesSampled
+}
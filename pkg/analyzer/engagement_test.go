@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// issueTime formats the time hours after the given number of days before testNow
+func issueTime(daysAgo int, hours int) string {
+	return testDaysAgo(daysAgo).Add(time.Duration(hours) * time.Hour).Format(time.RFC3339)
+}
+
+func TestGetIssueEngagement(t *testing.T) {
+	issue := func(number int, author string, daysAgo, comments int) string {
+		return fmt.Sprintf(`{"number":%d,"author":%q,"created_at":%q,"comments":%d}`, number, author, issueTime(daysAgo, 0), comments)
+	}
+	comment := func(author, association string, daysAgo, hours int) string {
+		return fmt.Sprintf(`{"author":%q,"author_association":%q,"created_at":%q}`, author, association, issueTime(daysAgo, hours))
+	}
+	issues := []string{
+		issue(1, "ann", 20, 2), // answered by bob after 4 hours; ann's own comment does not count
+		issue(2, "cy", 15, 1),  // only a contributor commented
+		issue(3, "cy", 10, 2),  // ann answered first, after 2 hours
+		issue(4, "eve", 2, 0),  // still within the grace period
+		issue(5, "eve", 30, 0), // never answered
+		issue(6, "eve", 12, 1), // answered after 12 hours
+	}
+	script := fmt.Sprintf(`case "$*" in
+*issues/1/comments*) printf '%%s\n' '%s' '%s';;
+*issues/2/comments*) echo '%s';;
+*issues/3/comments*) printf '%%s\n' '%s' '%s';;
+*issues/6/comments*) echo '%s';;
+*issues/*) echo "unexpected call: $*" >&2; exit 1;;
+*issues*) printf '%%s\n' '%s';;
+esac`,
+		comment("ann", "OWNER", 20, 1), comment("bob", "MEMBER", 20, 4),
+		comment("dee", "CONTRIBUTOR", 15, 1),
+		comment("bob", "COLLABORATOR", 10, 10), comment("ann", "OWNER", 10, 2),
+		comment("bob", "MEMBER", 12, 12),
+		strings.Join(issues, "' '"))
+
+	logPath := fakeGH(t, script)
+	SetCacheTTL(0)
+
+	engagement, err := GetIssueEngagement("o/r", testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if engagement.MedianResponseHours == nil || *engagement.MedianResponseHours != 4 {
+		t.Errorf("median response = %v hours, want 4", engagement.MedianResponseHours)
+	}
+	if engagement.Unanswered != 2 || engagement.Sampled != 5 {
+		t.Errorf("%d of %d sampled issues unanswered, want 2 of 5", engagement.Unanswered, engagement.Sampled)
+	}
+	if calls := ghCalls(t, logPath); len(calls) != 5 {
+		t.Errorf("made %d calls, want the issue list and the comments of the 4 commented issues: %q", len(calls), calls)
+	}
+}
+
+func TestSummarizeEngagement(t *testing.T) {
+	answered := func(hours int) *time.Time {
+		at := testDaysAgo(20).Add(time.Duration(hours) * time.Hour)
+		return &at
+	}
+	old := engagementIssue{Number: 1, CreatedAt: *testDaysAgo(20)}
+	recent := engagementIssue{Number: 2, CreatedAt: *testDaysAgo(1)}
+
+	tests := []struct {
+		name           string
+		issues         []engagementIssue
+		responses      map[int]*time.Time
+		wantMedian     *float64
+		wantUnanswered int
+		wantSampled    int
+	}{
+		{"no issues", nil, nil, nil, 0, 0},
+		{"answered", []engagementIssue{old}, map[int]*time.Time{1: answered(6)}, floatPtr(6), 0, 1},
+		{"unanswered", []engagementIssue{old}, nil, nil, 1, 1},
+		{"new issue not judged yet", []engagementIssue{recent}, nil, nil, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summarizeEngagement(tt.issues, tt.responses, testNow)
+			if (got.MedianResponseHours == nil) != (tt.wantMedian == nil) ||
+				got.MedianResponseHours != nil && *got.MedianResponseHours != *tt.wantMedian {
+				t.Errorf("median = %v, want %v", got.MedianResponseHours, tt.wantMedian)
+			}
+			if got.Unanswered != tt.wantUnanswered || got.Sampled != tt.wantSampled {
+				t.Errorf("%d of %d unanswered, want %d of %d", got.Unanswered, got.Sampled, tt.wantUnanswered, tt.wantSampled)
+			}
+		})
+	}
+}
+
+func TestMedianHours(t *testing.T) {
+	tests := []struct {
+		name  string
+		hours []int
+		want  float64
+	}{
+		{"single", []int{5}, 5},
+		{"odd count unsorted", []int{30, 2, 7}, 7},
+		{"even count averaged", []int{10, 1, 4, 100}, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var durations []time.Duration
+			for _, h := range tt.hours {
+				durations = append(durations, time.Duration(h)*time.Hour)
+			}
+			if got := medianHours(durations); got != tt.want {
+				t.Errorf("medianHours(%v) = %v, want %v", tt.hours, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsUnresponsive(t *testing.T) {
+	tests := []struct {
+		name string
+		repo Repository
+		want bool
+	}{
+		{"not measured", Repository{}, false},
+		{"quick responses", Repository{MedianIssueResponseHours: floatPtr(10), IssuesSampled: 10, UnansweredIssues: 1}, false},
+		{"slow responses", Repository{MedianIssueResponseHours: floatPtr(100)}, true},
+		{"half unanswered", Repository{IssuesSampled: 4, UnansweredIssues: 2}, true},
+		{"too few issues to judge", Repository{IssuesSampled: 2, UnansweredIssues: 2}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isUnresponsive(tt.repo, 72); got != tt.want {
+				t.Errorf("isUnresponsive = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
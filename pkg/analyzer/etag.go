@@ -0,0 +1,110 @@
+package analyzer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// etagCacheDir holds the ETag and output of earlier read-only calls, revalidated with conditional requests
+// once gh's own response cache has expired (empty disables conditional requests)
+var etagCacheDir string
+
+// SetETagCacheDir configures the directory of the conditional request cache; an empty directory disables it
+func SetETagCacheDir(dir string) {
+	etagCacheDir = dir
+}
+
+// usesETag reports whether a call is revalidated with a conditional request
+// Paginated calls are left out, as every page carries its own ETag, and so are silent calls, whose output
+// is not needed.
+func usesETag(args []string) bool {
+	if etagCacheDir == "" || cacheTTL <= 0 || !isReadOnlyAPICall(args) {
+		return false
+	}
+	for _, arg := range args {
+		if arg == "--paginate" || arg == "--silent" || arg == "--include" || arg == "-i" {
+			return false
+		}
+	}
+	return true
+}
+
+// etagCachePath returns the cache file of a call, named after a hash of its arguments
+func etagCachePath(args []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(args, "\x00")))
+	return filepath.Join(etagCacheDir, hex.EncodeToString(sum[:]))
+}
+
+// loadETag returns the ETag and output cached for a call, or false when there is none
+// A cache file holds the ETag on its first line and the output after it.
+func loadETag(args []string) (etag string, output []byte, ok bool) {
+	data, err := os.ReadFile(etagCachePath(args))
+	if err != nil {
+		return "", nil, false
+	}
+	line, output, found := bytes.Cut(data, []byte("\n"))
+	if !found || len(line) == 0 {
+		return "", nil, false
+	}
+	return string(line), output, true
+}
+
+// saveETag caches the ETag and output of a call; failures only cost the next call its revalidation
+func saveETag(args []string, etag string, output []byte) {
+	if err := os.MkdirAll(etagCacheDir, 0700); err != nil {
+		return
+	}
+	data := append([]byte(etag+"\n"), output...)
+	writeFileAtomic(etagCachePath(args), data, 0600)
+}
+
+// forgetETag drops the cached output of a call, so that it is fetched in full next time
+func forgetETag(args []string) {
+	os.Remove(etagCachePath(args))
+}
+
+// splitIncludedResponse splits the output of a gh api --include call into its ETag header and body
+func splitIncludedResponse(out []byte) (etag string, body []byte) {
+	head, body, found := bytes.Cut(out, []byte("\r\n\r\n"))
+	if !found {
+		return "", out
+	}
+	for _, line := range strings.Split(string(head), "\r\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if ok && http.CanonicalHeaderKey(strings.TrimSpace(name)) == "Etag" {
+			etag = strings.TrimSpace(value)
+		}
+	}
+	return etag, body
+}
+
+// runGHConditional runs a read-only gh api call as a conditional request when its output is cached,
+// returning the cached output when GitHub answers 304 Not Modified, which does not count against the rate limit
+func runGHConditional(args ...string) ([]byte, error) {
+	request := append(append([]string(nil), args...), "--include")
+	etag, cached, ok := loadETag(args)
+	if ok {
+		request = append(request, "--header", "If-None-Match: "+etag)
+	}
+
+	out, err := runGHRetrying(request...)
+	if ok && StatusCode(err) == http.StatusNotModified {
+		return cached, nil
+	}
+
+	newETag, body := splitIncludedResponse(out)
+	if err != nil {
+		return body, err
+	}
+	if newETag != "" {
+		saveETag(args, newETag, body)
+	} else if ok {
+		forgetETag(args)
+	}
+	return body, nil
+}
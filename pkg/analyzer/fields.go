@@ -0,0 +1,117 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RepositoryFieldNames returns the JSON field names of Repository in declaration order
+func RepositoryFieldNames() []string {
+	var names []string
+	t := reflect.TypeOf(Repository{})
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		names = append(names, tag)
+	}
+	return names
+}
+
+// ParseFields splits and validates a comma-separated field list against the Repository JSON tags
+func ParseFields(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return nil, nil
+	}
+
+	valid := make(map[string]bool)
+	for _, name := range RepositoryFieldNames() {
+		valid[name] = true
+	}
+
+	var fields, unknown []string
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !valid[field] {
+			unknown = append(unknown, field)
+			continue
+		}
+		fields = append(fields, field)
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown field(s) %s, valid fields are: %s",
+			strings.Join(unknown, ", "), strings.Join(RepositoryFieldNames(), ", "))
+	}
+
+	return fields, nil
+}
+
+// fieldSelection is a repository restricted to a subset of its JSON fields, in the requested order
+type fieldSelection struct {
+	fields []string
+	values map[string]json.RawMessage
+}
+
+// selectFields extracts the requested fields from a repository
+func selectFields(repo Repository, fields []string) (fieldSelection, error) {
+	data, err := json.Marshal(repo)
+	if err != nil {
+		return fieldSelection{}, fmt.Errorf("failed to marshal repository: %w", err)
+	}
+
+	values := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fieldSelection{}, fmt.Errorf("failed to select fields: %w", err)
+	}
+
+	return fieldSelection{fields: fields, values: values}, nil
+}
+
+// MarshalJSON writes the selected fields in the requested order, using null for omitted values
+func (f fieldSelection) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range f.fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(field)
+		buf.Write(name)
+		buf.WriteByte(':')
+		if value, ok := f.values[field]; ok {
+			buf.Write(value)
+		} else {
+			buf.WriteString("null")
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// csvValue renders a selected JSON value as a CSV cell
+func (f fieldSelection) csvValue(field string) string {
+	value, ok := f.values[field]
+	if !ok {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return s
+	}
+
+	var list []string
+	if err := json.Unmarshal(value, &list); err == nil {
+		return strings.Join(list, ";")
+	}
+
+	return string(value)
+}
@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRepositoryFieldNames(t *testing.T) {
+	names := RepositoryFieldNames()
+	if len(names) < 4 || !reflect.DeepEqual(names[:4], []string{"name", "branch", "path", "parent"}) {
+		t.Errorf("field names start %q, want the declaration order name, branch, path, parent", names)
+	}
+	for _, name := range names {
+		if name == "" || name == "-" || strings.Contains(name, ",") {
+			t.Errorf("invalid field name %q", name)
+		}
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		list    string
+		want    []string
+		wantErr string
+	}{
+		{"empty", " ", nil, ""},
+		{"in the given order", "flagged, name,daysSinceLastCommit", []string{"flagged", "name", "daysSinceLastCommit"}, ""},
+		{"empty items skipped", "name,,admins,", []string{"name", "admins"}, ""},
+		{"unknown", "name,stars,Forks", nil, "unknown field(s) stars, Forks, valid fields are: name, branch"},
+		{"case-sensitive", "Name", nil, "unknown field(s) Name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFields(tt.list)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseFields error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFields(%q) = %q, want %q", tt.list, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	repo := Repository{
+		Name:                "o/r",
+		DaysSinceLastCommit: 400,
+		Flagged:             true,
+		Admins:              []string{"ann", "bob"},
+		InactivePercentage:  66.5,
+	}
+
+	tests := []struct {
+		name     string
+		fields   []string
+		wantJSON string
+		wantCSV  []string
+	}{
+		{"requested order", []string{"flagged", "name"}, `{"flagged":true,"name":"o/r"}`, []string{"true", "o/r"}},
+		{"numbers", []string{"daysSinceLastCommit", "inactivePercentage"}, `{"daysSinceLastCommit":400,"inactivePercentage":66.5}`, []string{"400", "66.5"}},
+		{"list", []string{"admins"}, `{"admins":["ann","bob"]}`, []string{"ann;bob"}},
+		{"omitted value", []string{"name", "branch"}, `{"name":"o/r","branch":null}`, []string{"o/r", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selection, err := selectFields(repo, tt.fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := json.Marshal(selection)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.wantJSON {
+				t.Errorf("JSON = %s, want %s", data, tt.wantJSON)
+			}
+			for i, field := range tt.fields {
+				if got := selection.csvValue(field); got != tt.wantCSV[i] {
+					t.Errorf("CSV value of %s = %q, want %q", field, got, tt.wantCSV[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package analyzer
+
+import (
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// Flag reasons recorded on a flagged repository
+// A repository counts as old when its last commit is older than the age threshold, which cadence flagging
+// raises to a multiple of its median commit gap, and a recent tag or commit on another branch keeps it from
+// counting as old when those metrics are enabled. Repositories below the minimum contributor count or age
+// are never flagged, archived ones excepted.
+const (
+	FlagReasonArchived                = "archived"
+	FlagReasonOldInactiveContributors = "old+inactive-contributors" // inactive share and, when set, count at or above the minimums
+	FlagReasonOldNoContributors       = "old+no-contributors"
+	FlagReasonOldIssuesDisabled       = "old+issues-disabled" // with governance checks
+	FlagReasonOldBrokenCI             = "old+broken-ci"       // CI absent, failing, or stale
+	FlagReasonStaleReviews            = "stale-reviews"       // last merged pull request old, however recent the commits
+	FlagReasonStaleBotPRs             = "stale-bot-prs"       // too many old bot pull requests open, however recent the commits
+	FlagReasonDeclining               = "declining"           // commit momentum dropped sharply, however recent the commits
+	FlagReasonUnresponsive            = "unresponsive"        // maintainers stopped answering issues, however recent the commits
+)
+
+// FlagRepository applies the flagging criteria to a repository and marks its priority and lifecycle stage
+func FlagRepository(r *Repository, cfg config.Config) {
+	flagRepository(r, cfg)
+
+	if r.Flagged && BelowMinContributors(*r, cfg) && (!r.Archived || cfg.MinContributorsIncludeArchived) {
+		r.Flagged = false
+		r.FlagReason = ""
+	}
+
+	if r.Flagged && BelowMinRepoAge(*r, cfg) && !r.Archived {
+		r.Flagged = false
+		r.FlagReason = ""
+	}
+
+	r.ExposedToOutsiders = r.Flagged && exposedToOutsiders(*r)
+	r.Undocumented = r.Flagged && cfg.PrioritizeUndocumented && lacksReadme(*r)
+	r.Unlicensed = r.Flagged && cfg.PrioritizeUnlicensed && r.License == LicenseNone
+	r.HighPriority = r.Unlicensed || r.Undocumented || r.ExposedToOutsiders
+	r.SecurityReview = r.Flagged && cfg.MinSignedRatio > 0 &&
+		r.SignedCommitRatio != nil && *r.SignedCommitRatio < cfg.MinSignedRatio
+	r.UrgentSecurity = r.Flagged && r.OpenSecurityAlerts != nil && *r.OpenSecurityAlerts > 0
+
+	if cfg.Lifecycle {
+		r.Lifecycle = lifecycleStage(*r, cfg)
+	}
+}
+
+// BelowMinContributors reports whether a repository has fewer contributors than the configured minimum
+// Repositories with unavailable contributor data are never considered below the minimum
+func BelowMinContributors(r Repository, cfg config.Config) bool {
+	return cfg.MinContributors > 0 && r.ContributorDataComplete && r.TotalContributors < cfg.MinContributors
+}
+
+// BelowMinRepoAge reports whether a repository is younger than the configured minimum age
+// Repositories with an unknown creation date are never considered too young
+func BelowMinRepoAge(r Repository, cfg config.Config) bool {
+	return cfg.MinRepoAgeDays > 0 && !r.CreatedAt.IsZero() && r.RepoAgeDays < cfg.MinRepoAgeDays
+}
+
+// FilterRepositories drops repositories below the minimum contributor count when configured to
+func FilterRepositories(repos []Repository, cfg config.Config) []Repository {
+	if !cfg.DropSmallRepos || cfg.MinContributors <= 0 {
+		return repos
+	}
+
+	var filtered []Repository
+	for _, r := range repos {
+		if BelowMinContributors(r, cfg) && (!r.Archived || cfg.MinContributorsIncludeArchived) {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
+
+// flagRepository applies the flagging rules without the minimum contributor filter
+func flagRepository(r *Repository, cfg config.Config) {
+	r.Flagged = false
+	r.FlagReason = ""
+
+	// Always flag archived repositories
+	if r.Archived {
+		r.Flagged = true
+		r.FlagReason = FlagReasonArchived
+		return
+	}
+
+	flagOldRepository(r, cfg)
+
+	// Review going quiet is a signal of its own, even while direct commits continue
+	if !r.Flagged && cfg.FlagStaleReviews && isReviewStale(*r, cfg.MaxCommitAgeInDays) {
+		r.Flagged = true
+		r.FlagReason = FlagReasonStaleReviews
+	}
+
+	// A repository winding down is worth a look before it goes past the staleness threshold
+	if !r.Flagged && cfg.FlagDeclining && isDeclining(*r, cfg.DecliningMomentum) {
+		r.Flagged = true
+		r.FlagReason = FlagReasonDeclining
+	}
+
+	// Issues nobody answers show a repository is not maintained, whatever its commits say
+	if !r.Flagged && cfg.FlagUnresponsive && isUnresponsive(*r, cfg.MaxResponseHours) {
+		r.Flagged = true
+		r.FlagReason = FlagReasonUnresponsive
+	}
+
+	// Dependency updates nobody merges pile up in a repository nobody maintains
+	if !r.Flagged && cfg.FlagStaleBotPRs && hasStaleBotPRPileUp(*r, cfg.MaxStaleBotPRs) {
+		r.Flagged = true
+		r.FlagReason = FlagReasonStaleBotPRs
+	}
+}
+
+// flagAge returns the days since the last commit a repository is judged on, which come from the last
+// substantive commit instead when configured to
+func flagAge(r Repository, cfg config.Config) int {
+	if cfg.FlagOnSubstantiveCommit && r.LastSubstantiveCommitDate != nil {
+		return r.DaysSinceSubstantiveCommit
+	}
+	return r.DaysSinceLastCommit
+}
+
+// flagOldRepository applies the rules for repositories whose last commit is older than the threshold
+func flagOldRepository(r *Repository, cfg config.Config) {
+	// For non-archived repos, check age and contributor criteria
+	isOld := flagAge(*r, cfg) > cadenceAgeThreshold(*r, cfg)
+	// A recent tag shows a release-driven repository is still shipping from another branch
+	if !isOld || (cfg.RecentTags && hasRecentTag(*r, cfg.MaxCommitAgeInDays)) {
+		return
+	}
+	// Recent commits on another branch suggest work moved off the analyzed branch, e.g. during a reorganization
+	if cfg.ActiveBranches && hasActiveBranch(*r, cfg.MaxCommitAgeInDays) {
+		return
+	}
+
+	if r.TotalContributors > 0 {
+		// If there are contributors, flag if the inactive percentage meets the threshold
+		// and enough contributors are inactive, so one departure from a tiny team is not enough
+		if inactiveRatio(*r, cfg) >= cfg.InactiveContribThreshold && r.InactiveContributors >= cfg.MinInactiveCount {
+			r.Flagged = true
+			r.FlagReason = FlagReasonOldInactiveContributors
+		}
+	} else if r.ContributorDataComplete {
+		// If there are no contributors, flag it simply for being old
+		// Repositories whose contributor data was unavailable are not flagged on this rule
+		r.Flagged = true
+		r.FlagReason = FlagReasonOldNoContributors
+	}
+
+	// Old repositories with issues disabled are often read-only mirrors or dumping grounds
+	if !r.Flagged && cfg.Governance && !r.IssuesEnabled {
+		r.Flagged = true
+		r.FlagReason = FlagReasonOldIssuesDisabled
+	}
+
+	// A repository nobody keeps green is unlikely to be maintained
+	if !r.Flagged && cfg.FlagBrokenCI && isCIBroken(*r, cfg.MaxCommitAgeInDays) {
+		r.Flagged = true
+		r.FlagReason = FlagReasonOldBrokenCI
+	}
+}
@@ -0,0 +1,331 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// flaggingConfig is the default configuration flagging is tested against
+var flaggingConfig = config.Config{MaxCommitAgeInDays: 180, InactiveContribThreshold: 0.5}
+
+func TestFlagRepositoryReason(t *testing.T) {
+	pinNow(t)
+
+	tests := []struct {
+		name   string
+		repo   Repository
+		cfg    config.Config
+		reason string
+	}{
+		{
+			name:   "archived",
+			repo:   Repository{Archived: true, DaysSinceLastCommit: 1},
+			cfg:    flaggingConfig,
+			reason: FlagReasonArchived,
+		},
+		{
+			name:   "recent",
+			repo:   Repository{DaysSinceLastCommit: 30, TotalContributors: 2, InactiveContributors: 2, InactivePercentage: 1, ContributorDataComplete: true},
+			cfg:    flaggingConfig,
+			reason: "",
+		},
+		{
+			name:   "old with inactive contributors",
+			repo:   Repository{DaysSinceLastCommit: 200, TotalContributors: 4, InactiveContributors: 2, InactivePercentage: 0.5, ContributorDataComplete: true},
+			cfg:    flaggingConfig,
+			reason: FlagReasonOldInactiveContributors,
+		},
+		{
+			name:   "old with active contributors",
+			repo:   Repository{DaysSinceLastCommit: 200, TotalContributors: 4, InactiveContributors: 1, InactivePercentage: 0.25, ContributorDataComplete: true},
+			cfg:    flaggingConfig,
+			reason: "",
+		},
+		{
+			name:   "old without contributors",
+			repo:   Repository{DaysSinceLastCommit: 200, ContributorDataComplete: true},
+			cfg:    flaggingConfig,
+			reason: FlagReasonOldNoContributors,
+		},
+		{
+			name:   "old with unavailable contributor data",
+			repo:   Repository{DaysSinceLastCommit: 200},
+			cfg:    flaggingConfig,
+			reason: "",
+		},
+		{
+			name:   "below minimum inactive count",
+			repo:   Repository{DaysSinceLastCommit: 200, TotalContributors: 2, InactiveContributors: 1, InactivePercentage: 0.5, ContributorDataComplete: true},
+			cfg:    withConfig(flaggingConfig, func(c *config.Config) { c.MinInactiveCount = 2 }),
+			reason: "",
+		},
+		{
+			name:   "weighted ratio below threshold",
+			repo:   Repository{DaysSinceLastCommit: 200, TotalContributors: 2, InactiveContributors: 1, InactivePercentage: 0.5, WeightedInactivePercentage: floatPtr(0.1), ContributorDataComplete: true},
+			cfg:    withConfig(flaggingConfig, func(c *config.Config) { c.WeightedContributors = true }),
+			reason: "",
+		},
+		{
+			name:   "old with issues disabled",
+			repo:   Repository{DaysSinceLastCommit: 200, TotalContributors: 2, ContributorDataComplete: true},
+			cfg:    withConfig(flaggingConfig, func(c *config.Config) { c.Governance = true }),
+			reason: FlagReasonOldIssuesDisabled,
+		},
+		{
+			name:   "old with failing CI",
+			repo:   Repository{DaysSinceLastCommit: 200, TotalContributors: 2, IssuesEnabled: true, ContributorDataComplete: true, LastCIStatus: "failure", LastCIDate: testDaysAgo(1)},
+			cfg:    withConfig(flaggingConfig, func(c *config.Config) { c.FlagBrokenCI = true }),
+			reason: FlagReasonOldBrokenCI,
+		},
+		{
+			name:   "old with recent tag",
+			repo:   Repository{DaysSinceLastCommit: 200, ContributorDataComplete: true, LastTagDate: testDaysAgo(10)},
+			cfg:    withConfig(flaggingConfig, func(c *config.Config) { c.RecentTags = true }),
+			reason: "",
+		},
+		{
+			name:   "old with active branch",
+			repo:   Repository{DaysSinceLastCommit: 200, ContributorDataComplete: true, NewestBranchCommitDate: testDaysAgo(10)},
+			cfg:    withConfig(flaggingConfig, func(c *config.Config) { c.ActiveBranches = true }),
+			reason: "",
+		},
+		{
+			name:   "old within its cadence",
+			repo:   Repository{DaysSinceLastCommit: 200, ContributorDataComplete: true, MedianCommitGapDays: floatPtr(100)},
+			cfg:    withConfig(flaggingConfig, func(c *config.Config) { c.FlagOnCadence = true; c.CadenceMultiplier = 3 }),
+			reason: "",
+		},
+		{
+			name:   "recent with stale reviews",
+			repo:   Repository{DaysSinceLastCommit: 5, LastMergedPRDate: testDaysAgo(400)},
+			cfg:    withConfig(flaggingConfig, func(c *config.Config) { c.FlagStaleReviews = true }),
+			reason: FlagReasonStaleReviews,
+		},
+		{
+			name:   "recent and declining",
+			repo:   Repository{DaysSinceLastCommit: 5, CommitMomentum: intPtr(-40)},
+			cfg:    withConfig(flaggingConfig, func(c *config.Config) { c.FlagDeclining = true; c.DecliningMomentum = 20 }),
+			reason: FlagReasonDeclining,
+		},
+		{
+			name:   "recent and unresponsive",
+			repo:   Repository{DaysSinceLastCommit: 5, MedianIssueResponseHours: floatPtr(500)},
+			cfg:    withConfig(flaggingConfig, func(c *config.Config) { c.FlagUnresponsive = true; c.MaxResponseHours = 72 }),
+			reason: FlagReasonUnresponsive,
+		},
+		{
+			name:   "recent with stale bot pull requests",
+			repo:   Repository{DaysSinceLastCommit: 5, StaleBotPRCount: intPtr(12)},
+			cfg:    withConfig(flaggingConfig, func(c *config.Config) { c.FlagStaleBotPRs = true; c.MaxStaleBotPRs = 10 }),
+			reason: FlagReasonStaleBotPRs,
+		},
+		{
+			name:   "below minimum contributors",
+			repo:   Repository{DaysSinceLastCommit: 200, TotalContributors: 1, InactiveContributors: 1, InactivePercentage: 1, ContributorDataComplete: true},
+			cfg:    withConfig(flaggingConfig, func(c *config.Config) { c.MinContributors = 2 }),
+			reason: "",
+		},
+		{
+			name:   "archived below minimum contributors",
+			repo:   Repository{Archived: true, TotalContributors: 1, ContributorDataComplete: true},
+			cfg:    withConfig(flaggingConfig, func(c *config.Config) { c.MinContributors = 2 }),
+			reason: FlagReasonArchived,
+		},
+		{
+			name:   "younger than minimum age",
+			repo:   Repository{DaysSinceLastCommit: 200, ContributorDataComplete: true, CreatedAt: *testDaysAgo(250), RepoAgeDays: 250},
+			cfg:    withConfig(flaggingConfig, func(c *config.Config) { c.MinRepoAgeDays = 365 }),
+			reason: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := tt.repo
+			FlagRepository(&repo, tt.cfg)
+			if repo.FlagReason != tt.reason || repo.Flagged != (tt.reason != "") {
+				t.Errorf("flagged %v with reason %q, want reason %q", repo.Flagged, repo.FlagReason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestFlagRepositoryMarkers(t *testing.T) {
+	pinNow(t)
+	old := Repository{DaysSinceLastCommit: 200, ContributorDataComplete: true}
+
+	tests := []struct {
+		name  string
+		set   func(*Repository)
+		cfg   config.Config
+		check func(Repository) bool
+	}{
+		{
+			name:  "unlicensed high priority",
+			set:   func(r *Repository) { r.License = LicenseNone },
+			cfg:   withConfig(flaggingConfig, func(c *config.Config) { c.PrioritizeUnlicensed = true }),
+			check: func(r Repository) bool { return r.Unlicensed && r.HighPriority },
+		},
+		{
+			name:  "undocumented high priority",
+			set:   func(r *Repository) { r.HasReadme = boolPtr(false) },
+			cfg:   withConfig(flaggingConfig, func(c *config.Config) { c.PrioritizeUndocumented = true }),
+			check: func(r Repository) bool { return r.Undocumented && r.HighPriority },
+		},
+		{
+			name:  "exposed to outsiders",
+			set:   func(r *Repository) { r.Visibility = VisibilityPublic; r.OutsideCollaborators = intPtr(2) },
+			cfg:   flaggingConfig,
+			check: func(r Repository) bool { return r.ExposedToOutsiders && r.HighPriority },
+		},
+		{
+			name:  "security review",
+			set:   func(r *Repository) { r.SignedCommitRatio = floatPtr(0.2) },
+			cfg:   withConfig(flaggingConfig, func(c *config.Config) { c.MinSignedRatio = 0.8 }),
+			check: func(r Repository) bool { return r.SecurityReview },
+		},
+		{
+			name:  "urgent security",
+			set:   func(r *Repository) { r.OpenSecurityAlerts = intPtr(3) },
+			cfg:   flaggingConfig,
+			check: func(r Repository) bool { return r.UrgentSecurity },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flagged := old
+			tt.set(&flagged)
+			FlagRepository(&flagged, tt.cfg)
+			if !tt.check(flagged) {
+				t.Errorf("flagged repository %+v is missing the marker", flagged)
+			}
+
+			// Markers only apply to flagged repositories
+			recent := flagged
+			recent.DaysSinceLastCommit = 1
+			FlagRepository(&recent, tt.cfg)
+			if recent.Flagged || recent.HighPriority || recent.Unlicensed || recent.Undocumented || recent.ExposedToOutsiders || recent.SecurityReview || recent.UrgentSecurity {
+				t.Errorf("unflagged repository %+v carries a marker", recent)
+			}
+		})
+	}
+}
+
+func TestPriorityMarkerNamesEveryReason(t *testing.T) {
+	pinNow(t)
+	repo := Repository{
+		DaysSinceLastCommit:     200,
+		ContributorDataComplete: true,
+		License:                 LicenseNone,
+		HasReadme:               boolPtr(false),
+		Visibility:              VisibilityPublic,
+		OutsideCollaborators:    intPtr(2),
+	}
+	FlagRepository(&repo, withConfig(flaggingConfig, func(c *config.Config) {
+		c.PrioritizeUnlicensed = true
+		c.PrioritizeUndocumented = true
+	}))
+
+	want := " [high priority: public with 2 outside collaborators] [high priority: no README] [high priority: no license]"
+	if got := priorityMarker(repo); got != want {
+		t.Errorf("priority marker %q, want %q", got, want)
+	}
+}
+
+func TestLifecycleStage(t *testing.T) {
+	cfg := withConfig(flaggingConfig, func(c *config.Config) {
+		c.Lifecycle = true
+		c.LifecycleActiveDays = 30
+		c.LifecycleAbandonedDays = 730
+	})
+
+	tests := []struct {
+		name string
+		repo Repository
+		want string
+	}{
+		{"archived", Repository{Archived: true}, LifecycleArchived},
+		{"active", Repository{DaysSinceLastCommit: 10}, LifecycleActive},
+		{"maintenance", Repository{DaysSinceLastCommit: 100}, LifecycleMaintenance},
+		{"stale", Repository{DaysSinceLastCommit: 300, TotalContributors: 4, InactivePercentage: 0.25, ContributorDataComplete: true}, LifecycleStale},
+		{"abandoned by contributors", Repository{DaysSinceLastCommit: 300, TotalContributors: 4, InactivePercentage: 0.75, ContributorDataComplete: true}, LifecycleAbandoned},
+		{"abandoned by age", Repository{DaysSinceLastCommit: 800, TotalContributors: 4, ContributorDataComplete: true}, LifecycleAbandoned},
+		{"unknown age", Repository{CommitStatus: "empty"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := tt.repo
+			FlagRepository(&repo, cfg)
+			if repo.Lifecycle != tt.want {
+				t.Errorf("lifecycle = %q, want %q", repo.Lifecycle, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterRepositories(t *testing.T) {
+	repos := []Repository{
+		{Name: "o/small", TotalContributors: 1, ContributorDataComplete: true},
+		{Name: "o/big", TotalContributors: 5, ContributorDataComplete: true},
+		{Name: "o/unknown", TotalContributors: 0},
+		{Name: "o/archived", Archived: true, TotalContributors: 1, ContributorDataComplete: true},
+	}
+
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want []string
+	}{
+		{"not dropping", config.Config{MinContributors: 2}, []string{"o/small", "o/big", "o/unknown", "o/archived"}},
+		{"dropping", config.Config{MinContributors: 2, DropSmallRepos: true}, []string{"o/big", "o/unknown", "o/archived"}},
+		{"dropping archived too", config.Config{MinContributors: 2, DropSmallRepos: true, MinContributorsIncludeArchived: true}, []string{"o/big", "o/unknown"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, repo := range FilterRepositories(repos, tt.cfg) {
+				got = append(got, repo.Name)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("kept %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("kept %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFlagRepositoryMinInactiveCount(t *testing.T) {
+	pinNow(t)
+	team := func(total, inactive int) Repository {
+		return Repository{DaysSinceLastCommit: 200, TotalContributors: total, InactiveContributors: inactive,
+			InactivePercentage: float64(inactive) / float64(total), ContributorDataComplete: true}
+	}
+
+	tests := []struct {
+		name     string
+		repo     Repository
+		minCount int
+		want     bool
+	}{
+		{"one of two without a minimum", team(2, 1), 0, true},
+		{"one of two gated", team(2, 1), 2, false},
+		{"both of two gated through", team(2, 2), 2, true},
+		{"one of one gated", team(1, 1), 2, false},
+		{"large team meets both", team(10, 6), 3, true},
+		{"count met but ratio not", team(10, 4), 3, false},
+		{"no contributors still flagged", Repository{DaysSinceLastCommit: 200, ContributorDataComplete: true}, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := tt.repo
+			FlagRepository(&repo, withConfig(flaggingConfig, func(c *config.Config) { c.MinInactiveCount = tt.minCount }))
+			if repo.Flagged != tt.want {
+				t.Errorf("flagged = %v (%s), want %v", repo.Flagged, repo.FlagReason, tt.want)
+			}
+		})
+	}
+}
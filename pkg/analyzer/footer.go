@@ -0,0 +1,32 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// nextSteps suggests what to do with the flagged repositories, pointing at options that were not enabled
+// Nothing is suggested when no repository was flagged or in silent mode
+func nextSteps(flagged int, cfg config.Config) []string {
+	if flagged == 0 || cfg.Silent {
+		return nil
+	}
+
+	steps := []string{
+		fmt.Sprintf("Review the %d flagged repositories: consider archiving, transferring, or contacting their owners", flagged),
+	}
+	if !cfg.ShowAdmins {
+		steps = append(steps, "Rerun with -show-admins to find who can act on each flagged repository")
+	}
+	if !describesContributors(cfg) {
+		steps = append(steps, "Rerun with -contributor-details to see when inactive contributors last committed")
+	}
+	if cfg.StateFile == "" {
+		steps = append(steps, "Use -state <file> to track what changed since the previous run")
+	}
+	if !cfg.PublishStatus {
+		steps = append(steps, "Use -publish-status to record the result on each repository as a check")
+	}
+	return steps
+}
@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestNextSteps(t *testing.T) {
+	const review = "Review the 2 flagged repositories: consider archiving, transferring, or contacting their owners"
+	tests := []struct {
+		name    string
+		flagged int
+		cfg     config.Config
+		want    []string
+	}{
+		{"nothing flagged", 0, config.Config{}, nil},
+		{"silent", 2, config.Config{Silent: true}, nil},
+		{
+			name:    "every option suggested",
+			flagged: 2,
+			want: []string{
+				review,
+				"Rerun with -show-admins to find who can act on each flagged repository",
+				"Rerun with -contributor-details to see when inactive contributors last committed",
+				"Use -state <file> to track what changed since the previous run",
+				"Use -publish-status to record the result on each repository as a check",
+			},
+		},
+		{
+			name:    "enabled options not suggested",
+			flagged: 2,
+			cfg:     config.Config{ShowAdmins: true, ContributorDetails: true, StateFile: "state.json", PublishStatus: true},
+			want:    []string{review},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextSteps(tt.flagged, tt.cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nextSteps = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextStepsFormats(t *testing.T) {
+	pinNow(t)
+	repos := []Repository{
+		{Name: "o/old", Flagged: true, FlagReason: FlagReasonOldInactiveContributors, LastCommitDate: *testDaysAgo(400), DaysSinceLastCommit: 400},
+		{Name: "o/new", LastCommitDate: *testDaysAgo(5), DaysSinceLastCommit: 5},
+	}
+	summary := Summary{Total: 2, Flagged: 1, Inactive: 1, Config: config.Config{Organization: "o", MaxCommitAgeInDays: 180}}
+
+	tests := []struct {
+		name       string
+		format     string
+		terminal   bool
+		wantFooter bool
+	}{
+		{"console", "console", true, true},
+		{"markdown", "markdown", false, true},
+		{"plain text file", "console", false, false},
+		{"json", "json", false, false},
+		{"csv", "csv", false, false},
+		{"ndjson", "ndjson", false, false},
+		{"table", "table", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, ok := LookupFormatter(tt.format)
+			if !ok {
+				t.Fatalf("format %s not registered", tt.format)
+			}
+			var buf bytes.Buffer
+			summary := summary
+			summary.Terminal = tt.terminal
+			if err := f.Format(&buf, repos, summary); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(buf.String(), "-show-admins"); got != tt.wantFooter {
+				t.Errorf("next steps in %s output = %v, want %v:\n%s", tt.format, got, tt.wantFooter, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// ListForks returns the full names of the direct forks of a repository, across every page
+// Forks of forks are not listed, as each fork has a network of its own under the same parent
+func ListForks(repoFullName string) ([]string, error) {
+	out, err := runGH("api",
+		fmt.Sprintf("repos/%s/forks?sort=oldest&per_page=100", repoFullName),
+		"--paginate", "--jq", ".[].full_name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list forks of %s: %w", repoFullName, err)
+	}
+	return parseForkNames(out), nil
+}
+
+// parseForkNames splits the fork names printed one per line
+func parseForkNames(data []byte) []string {
+	var names []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// reportSubject names what a report covers in its title: the forks of a repository or an organization,
+// or nothing for a list of repositories
+func reportSubject(cfg config.Config) string {
+	if cfg.ForksOf != "" {
+		return "forks of " + cfg.ForksOf
+	}
+	return cfg.Organization
+}
+
+// getAccountContributorsStatus classifies the contributors of a repository owned by a personal account,
+// which has no organization membership to check: contributors whose account still exists are active,
+// and those whose account was deleted are inactive
+func getAccountContributorsStatus(repoFullName string, cfg config.Config) (active, inactive []string, err error) {
+	contributors, err := GetContributors(repoFullName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, contributor := range contributors {
+		account, err := lookupUserAccount(contributor)
+		if err != nil {
+			if cfg.Strict {
+				return nil, nil, err
+			}
+			// Existence could not be determined, so leave the contributor out of the ratio
+			continue
+		}
+
+		if account.Exists {
+			active = append(active, contributor)
+		} else {
+			inactive = append(inactive, contributor)
+		}
+	}
+	return active, inactive, nil
+}
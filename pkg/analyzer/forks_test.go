@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestListForks(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		want    []string
+		wantErr bool
+	}{
+		{"pages of forks", `printf 'ann/lib\nbob/lib\n\ncy/lib-fork\n'`, []string{"ann/lib", "bob/lib", "cy/lib-fork"}, false},
+		{"no forks", `exit 0`, nil, false},
+		{"failure", `echo 'gh: Not Found (HTTP 404)' >&2; exit 1`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			SetCacheTTL(0)
+
+			got, err := ListForks("o/lib")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListForks error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "failed to list forks of o/lib") {
+				t.Errorf("error = %v, want the parent named", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("forks = %q, want %q", got, tt.want)
+			}
+			if call := ghCalls(t, logPath)[0]; !strings.Contains(call, "repos/o/lib/forks?") || !strings.Contains(call, "--paginate") {
+				t.Errorf("gh call %q does not page through the forks of o/lib", call)
+			}
+		})
+	}
+}
+
+func TestReportSubject(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want string
+	}{
+		{"organization", config.Config{Organization: "o"}, "o"},
+		{"forks", config.Config{ForksOf: "o/lib"}, "forks of o/lib"},
+		{"repository list", config.Config{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reportSubject(tt.cfg); got != tt.want {
+				t.Errorf("reportSubject = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAccountContributorsStatus(t *testing.T) {
+	// cy's account exists, dee's was deleted, and looking up eve fails
+	const script = `case "$*" in
+*contributors*) printf 'cy\ndee\neve\n';;
+*users/cy*) echo;;
+*users/dee*) echo 'gh: Not Found (HTTP 404)' >&2; exit 1;;
+*) echo 'gh: Server Error (HTTP 500)' >&2; exit 1;;
+esac`
+
+	tests := []struct {
+		name         string
+		strict       bool
+		wantActive   []string
+		wantInactive []string
+		wantErr      bool
+	}{
+		{"lookup failure left out", false, []string{"cy"}, []string{"dee"}, false},
+		{"strict", true, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, script)
+			SetCacheTTL(0)
+			resetUserAccounts(t)
+
+			active, inactive, err := getAccountContributorsStatus("ann/lib", config.Config{Strict: tt.strict, Silent: true})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getAccountContributorsStatus error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(active, tt.wantActive) || !reflect.DeepEqual(inactive, tt.wantInactive) {
+				t.Errorf("active %q and inactive %q, want %q and %q", active, inactive, tt.wantActive, tt.wantInactive)
+			}
+			for _, call := range ghCalls(t, logPath) {
+				if strings.Contains(call, "members") {
+					t.Errorf("checked organization membership for a personal fork: %q", call)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepositoryPersonalFork(t *testing.T) {
+	pinNow(t)
+	fakeGH(t, fmt.Sprintf(`case "$*" in
+*contributors*) printf 'cy\ndee\n';;
+*users/cy*) echo;;
+*users/dee*) echo 'gh: Not Found (HTTP 404)' >&2; exit 1;;
+*commits*) echo %s;;
+"api repos/ann/lib") echo '{"fork":true,"owner":{"type":"User"},"parent":{"full_name":"o/lib"}}';;
+*) echo "unexpected call: $*" >&2; exit 1;;
+esac`, testDaysAgo(400).Format(time.RFC3339)))
+	SetCacheTTL(0)
+	resetUserAccounts(t)
+
+	repo, _, err := AnalyzeRepository("ann/lib", withConfig(flaggingConfig, func(c *config.Config) { c.ForksOf = "o/lib"; c.Silent = true }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.Parent != "o/lib" {
+		t.Errorf("parent = %q, want o/lib", repo.Parent)
+	}
+	if repo.TotalContributors != 2 || repo.InactiveContributors != 1 || !repo.Flagged {
+		t.Errorf("%d of %d contributors inactive and flagged %v, want 1 of 2 flagged", repo.InactiveContributors, repo.TotalContributors, repo.Flagged)
+	}
+}
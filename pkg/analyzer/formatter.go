@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// Summary carries the context of a run that formatters render alongside the repositories
+type Summary struct {
+	Config      config.Config      // Configuration of the run
+	Total       int                // Repositories in the report
+	Flagged     int                // Flagged repositories in the report
+	Inactive    int                // Repositories flagged for inactivity rather than for being archived
+	Archived    int                // Repositories flagged for being archived
+	Skipped     int                // Repositories whose analysis failed
+	NotAnalyzed int                // Repositories not analyzed because the deadline passed
+	Warnings    []Warning          // Non-fatal issues met during the run
+	Removed     []string           // Repositories that disappeared since the previous run
+	Duplicates  []DuplicateCluster // Likely duplicates among the flagged repositories, when detected
+	Lifecycle   map[string]int     // Repositories per lifecycle stage, when repositories are classified
+	Single      bool               // Whether the report is for the single repository command
+	Terminal    bool               // Whether the report is written to the terminal rather than a file
+}
+
+// Formatter renders repositories in an output format
+type Formatter interface {
+	Format(w io.Writer, repos []Repository, summary Summary) error
+}
+
+// TerminalNotes is implemented by formatters that print something to the terminal after the report,
+// such as the analysis summary next to CSV output
+type TerminalNotes interface {
+	WriteNotes(w io.Writer, repos []Repository, summary Summary) error
+}
+
+// formatters maps output format names to their formatter
+var formatters = make(map[string]Formatter)
+
+// defaultFormat is used for format names without a registered formatter
+const defaultFormat = "console"
+
+// RegisterFormatter makes a formatter available under a format name
+func RegisterFormatter(name string, f Formatter) {
+	formatters[name] = f
+}
+
+// LookupFormatter returns the formatter registered under a format name
+func LookupFormatter(name string) (Formatter, bool) {
+	f, ok := formatters[name]
+	return f, ok
+}
+
+// FormatNames returns the registered format names in alphabetical order
+func FormatNames() []string {
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatterFor returns the formatter for a format name, falling back to the console formatter
+func formatterFor(name string) Formatter {
+	if f, ok := formatters[name]; ok {
+		return f
+	}
+	return formatters[defaultFormat]
+}
+
+// newSummary builds the summary of a report
+func newSummary(repos []Repository, skipped int, removed []string, warnings []Warning, single bool, cfg config.Config) Summary {
+	summary := Summary{
+		Config:      cfg,
+		Total:       len(repos),
+		Skipped:     skipped,
+		NotAnalyzed: NotStarted(),
+		Removed:     removed,
+		Warnings:    warnings,
+		Single:      single,
+	}
+	for _, repo := range repos {
+		if repo.Flagged {
+			summary.Flagged++
+		}
+	}
+	summary.Inactive, summary.Archived = countFlagged(repos)
+	if cfg.Lifecycle {
+		summary.Lifecycle = countLifecycleStages(repos)
+	}
+	return summary
+}
+
+// writeReport renders the report with the configured formatter to the output file or the terminal,
+// followed by any terminal notes of the format
+func writeReport(repos []Repository, summary Summary) error {
+	repos, summary = redactReport(repos, summary)
+	cfg := summary.Config
+	f := formatterFor(cfg.OutputFormat)
+	if cfg.EmitScript != "" {
+		f = scriptFormatter{action: cfg.EmitScript}
+	}
+
+	if cfg.OutputFile == "" {
+		summary.Terminal = true
+		if err := f.Format(os.Stdout, repos, summary); err != nil {
+			return err
+		}
+	} else if !outputStreamed() {
+		// A streamed output file already holds every repository
+		var buf bytes.Buffer
+		if err := f.Format(&buf, repos, summary); err != nil {
+			return err
+		}
+		if err := writeOutputFile(cfg, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	if notes, ok := f.(TerminalNotes); ok {
+		if err := notes.WriteNotes(os.Stdout, repos, summary); err != nil {
+			return err
+		}
+	}
+
+	if cfg.OutputFile != "" {
+		Logf("💾 Results saved to %s\n", cfg.OutputFile)
+	}
+	return nil
+}
+
+// writeSummary writes the analysis summary shared by the multi-repository terminal outputs
+func writeSummary(w io.Writer, repos []Repository, summary Summary) {
+	cfg := summary.Config
+	fmt.Fprintf(w, "\n📊 Analysis Results for %s\n", reportSubject(cfg))
+	if cfg.Organization != "" {
+		fmt.Fprintf(w, "Visibility: %s\n", cfg.Visibility)
+	}
+	fmt.Fprintf(w, "Total repositories analyzed: %d\n", summary.Total)
+	fmt.Fprintf(w, "🚩 Flagged repositories: %d (%d inactive, %d archived)\n", summary.Flagged, summary.Inactive, summary.Archived)
+	if summary.Lifecycle != nil {
+		fmt.Fprintf(w, "🌱 Lifecycle: %s\n", lifecycleSummary(summary.Lifecycle))
+	}
+	fmt.Fprintf(w, "🔑 Report ID: %s\n", reportIDSummary(repos, cfg))
+	if note := topNote(repos, summary); note != "" {
+		fmt.Fprintf(w, "🔝 %s\n", note)
+	}
+	if note := deadlineNote(summary); note != "" {
+		fmt.Fprintf(w, "⏰ %s\n", note)
+	}
+	if !cfg.Silent {
+		fmt.Fprintf(w, "ℹ️ %s\n", flagLegend(cfg))
+	}
+}
@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// countingFormatter writes how many repositories and flagged repositories it was given
+type countingFormatter struct{}
+
+func (countingFormatter) Format(w io.Writer, repos []Repository, summary Summary) error {
+	_, err := fmt.Fprintf(w, "%d repositories, %d flagged\n", summary.Total, summary.Flagged)
+	return err
+}
+
+// registerTestFormatter registers a formatter for the duration of a test
+func registerTestFormatter(t *testing.T, name string, f Formatter) {
+	t.Helper()
+	previous, existed := formatters[name]
+	RegisterFormatter(name, f)
+	t.Cleanup(func() {
+		if existed {
+			formatters[name] = previous
+		} else {
+			delete(formatters, name)
+		}
+	})
+}
+
+func TestLookupFormatter(t *testing.T) {
+	tests := []struct {
+		name string
+		want Formatter
+	}{
+		{"console", consoleFormatter{}},
+		{"json", jsonFormatter{}},
+		{"csv", csvFormatter{}},
+		{"ndjson", ndjsonFormatter{}},
+		{"markdown", markdownFormatter{}},
+		{"md", markdownFormatter{}},
+		{"table", tableFormatter{}},
+		{"ascii-table", tableFormatter{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, ok := LookupFormatter(tt.name)
+			if !ok || !reflect.DeepEqual(f, tt.want) {
+				t.Errorf("LookupFormatter(%q) = %T, %v, want %T", tt.name, f, ok, tt.want)
+			}
+		})
+	}
+
+	if _, ok := LookupFormatter("yaml"); ok {
+		t.Error("LookupFormatter found an unregistered format")
+	}
+	if f := formatterFor("yaml"); !reflect.DeepEqual(f, consoleFormatter{}) {
+		t.Errorf("formatterFor an unknown format = %T, want the console formatter", f)
+	}
+}
+
+func TestFormatNames(t *testing.T) {
+	registerTestFormatter(t, "counting", countingFormatter{})
+
+	names := FormatNames()
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("FormatNames = %q, want them sorted", names)
+	}
+	found := false
+	for _, name := range names {
+		found = found || name == "counting"
+	}
+	if !found {
+		t.Errorf("FormatNames = %q, want the registered counting format", names)
+	}
+}
+
+func TestWriteReportRegisteredFormatter(t *testing.T) {
+	registerTestFormatter(t, "counting", countingFormatter{})
+	captureLog(t)
+	path := filepath.Join(t.TempDir(), "report.txt")
+
+	repos := []Repository{{Name: "o/a"}, {Name: "o/b", Flagged: true}}
+	cfg := config.Config{OutputFormat: "counting", OutputFile: path}
+	if err := writeReport(repos, newSummary(repos, 0, nil, nil, false, cfg)); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2 repositories, 1 flagged\n"; string(data) != want {
+		t.Errorf("report = %q, want %q", data, want)
+	}
+}
+
+func TestNewSummaryFlaggedSplit(t *testing.T) {
+	archived := Repository{Name: "o/archived", Archived: true, Flagged: true, FlagReason: FlagReasonArchived}
+	inactive := Repository{Name: "o/inactive", Flagged: true, FlagReason: FlagReasonOldInactiveContributors}
+	active := Repository{Name: "o/active"}
+	// An archived repository flagged for another reason still counts as inactive
+	archivedInactive := Repository{Name: "o/archived-inactive", Archived: true, Flagged: true, FlagReason: FlagReasonOldInactiveContributors}
+
+	tests := []struct {
+		name         string
+		repos        []Repository
+		wantFlagged  int
+		wantInactive int
+		wantArchived int
+	}{
+		{"none", nil, 0, 0, 0},
+		{"active only", []Repository{active}, 0, 0, 0},
+		{"inactive and archived", []Repository{archived, inactive, active}, 2, 1, 1},
+		{"archived only", []Repository{archived, archived}, 2, 0, 2},
+		{"archived for another reason", []Repository{archivedInactive}, 1, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSummary(tt.repos, 0, nil, nil, false, config.Config{})
+			if s.Flagged != tt.wantFlagged || s.Inactive != tt.wantInactive || s.Archived != tt.wantArchived {
+				t.Errorf("flagged %d (%d inactive, %d archived), want %d (%d inactive, %d archived)",
+					s.Flagged, s.Inactive, s.Archived, tt.wantFlagged, tt.wantInactive, tt.wantArchived)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package analyzer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// httpStatusPattern matches the status gh reports on failed API calls, e.g. "gh: Not Found (HTTP 404)",
+// or "gh: HTTP 304" for a response without a message
+var httpStatusPattern = regexp.MustCompile(`\(?HTTP (\d{3})\)?`)
+
+// APIError describes a failed gh api call and the HTTP status it reported, if any
+type APIError struct {
+	StatusCode int
+	Message    string
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Err.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns the HTTP status reported by a failed gh api call, or 0 if unknown
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// TokenSource supplies the token used to authenticate gh invocations
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// tokenSource overrides the gh authentication when set
+var tokenSource TokenSource
+
+// cacheTTL is how long gh caches read-only API responses (0 disables caching)
+var cacheTTL = time.Hour
+
+// ghPath is the GitHub CLI binary every gh command runs
+var ghPath = "gh"
+
+// SetGHPath runs every gh command with the binary at path instead of the gh found in PATH;
+// an empty path restores the default
+func SetGHPath(path string) {
+	if path == "" {
+		path = "gh"
+	}
+	ghPath = path
+}
+
+// SetCacheTTL configures the cache duration applied to every read-only gh api call
+func SetCacheTTL(ttl time.Duration) {
+	cacheTTL = ttl
+}
+
+// SetTokenSource configures a token source used for every gh invocation
+func SetTokenSource(ts TokenSource) {
+	tokenSource = ts
+}
+
+// ghCommand builds a gh command, injecting the configured token if any
+// Read-only api calls get the configured --cache duration, and api calls wait out any secondary rate limit pause
+func ghCommand(args ...string) *exec.Cmd {
+	if len(args) > 0 && args[0] == "api" {
+		waitForAPIPause()
+	}
+
+	if cacheTTL > 0 && isReadOnlyAPICall(args) {
+		args = append(args, "--cache", cacheTTL.String())
+	}
+
+	// Calls still running once the deadline's grace period ends are cut off
+	cmd := exec.CommandContext(callContext, ghPath, args...)
+
+	// Per-owner credentials take precedence for the calls they cover
+	if credentials != nil {
+		if cred, ok := credentials.For(apiOwner(args)); ok {
+			cmd.Env = append(os.Environ(), "GH_TOKEN="+cred.Token)
+			if cred.Host != "" {
+				cmd.Env = append(cmd.Env, "GH_HOST="+cred.Host)
+			}
+			return cmd
+		}
+	}
+
+	if tokenSource != nil {
+		token, err := tokenSource.Token()
+		if err != nil {
+			// Surface the token failure when the command is run
+			cmd.Err = fmt.Errorf("failed to obtain authentication token: %w", err)
+			return cmd
+		}
+		cmd.Env = append(os.Environ(), "GH_TOKEN="+token)
+	}
+
+	return cmd
+}
+
+// isReadOnlyAPICall reports whether the arguments describe a gh api GET request
+func isReadOnlyAPICall(args []string) bool {
+	if len(args) == 0 || args[0] != "api" {
+		return false
+	}
+
+	// The remaining quota must always be read live
+	if len(args) > 1 && args[1] == "rate_limit" {
+		return false
+	}
+
+	for i, arg := range args {
+		if (arg == "--method" || arg == "-X") && i+1 < len(args) && !strings.EqualFold(args[i+1], "GET") {
+			return false
+		}
+		// Fields turn a request into a POST unless the method is given explicitly
+		if arg == "-f" || arg == "-F" || arg == "--field" || arg == "--raw-field" || arg == "--input" {
+			return false
+		}
+		if arg == "--cache" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// runGH runs a gh command and returns its stdout, reporting failures as *APIError
+// Read-only calls whose response cache has expired are revalidated with their ETag when one is cached.
+func runGH(args ...string) ([]byte, error) {
+	if usesETag(args) {
+		return runGHConditional(args...)
+	}
+	return runGHRetrying(args...)
+}
+
+// runGHRetrying runs a gh command and returns its stdout, reporting failures as *APIError
+// A call rejected by a secondary rate limit pauses every API call for the wait the response asks for,
+// then is retried
+func runGHRetrying(args ...string) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		out, err := runGHOnce(args...)
+		var apiErr *APIError
+		if err == nil || attempt == secondaryRateLimitAttempts || !errors.As(err, &apiErr) || !isSecondaryRateLimit(apiErr.Message) {
+			return out, err
+		}
+
+		wait := secondaryRateLimitWait(apiErr.Message)
+		if pauseAPICalls(wait) {
+			Logf("⏸️ Secondary rate limit hit, pausing all API calls for %s\n", wait)
+		}
+	}
+}
+
+// runGHOnce runs a gh command once and returns its stdout, reporting failures as *APIError
+func runGHOnce(args ...string) ([]byte, error) {
+	cmd := ghCommand(args...)
+
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if callContext.Err() != nil {
+			return out.Bytes(), ErrDeadlineExceeded
+		}
+		message := strings.TrimSpace(stderr.String())
+		apiErr := &APIError{Message: message, Err: err}
+		if match := httpStatusPattern.FindStringSubmatch(message); match != nil {
+			apiErr.StatusCode, _ = strconv.Atoi(match[1])
+		}
+		return out.Bytes(), apiErr
+	}
+
+	return out.Bytes(), nil
+}
+
+// runGHParsed runs a gh command and parses its output
+// A cached response that fails to parse (stale, truncated, or empty) is retried once with the cache bypassed
+func runGHParsed[T any](parse func([]byte) (T, error), args ...string) (T, error) {
+	var zero T
+
+	out, err := runGH(args...)
+	if err != nil {
+		return zero, err
+	}
+
+	v, err := parse(out)
+	if err == nil || cacheTTL <= 0 || !isReadOnlyAPICall(args) {
+		return v, err
+	}
+
+	if usesETag(args) {
+		forgetETag(args)
+	}
+	fresh := append(append([]string(nil), args...), "--cache", "0s")
+	out, err = runGH(fresh...)
+	if err != nil {
+		return zero, err
+	}
+	return parse(out)
+}
+
+// CheckRepositoryAccess verifies that a repository exists and is accessible
+func CheckRepositoryAccess(repoFullName string) error {
+	_, err := runGH("api",
+		fmt.Sprintf("repos/%s", repoFullName),
+		"--silent")
+
+	return err
+}
@@ -0,0 +1,258 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeGH replaces gh with a shell script for the duration of a test and returns the file logging the
+// arguments of each call, one call per line
+// The script sees its arguments as "$*" and the log path as $GH_LOG.
+func fakeGH(t *testing.T, script string) string {
+	t.Helper()
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "calls.log")
+	path := filepath.Join(dir, "gh")
+	body := "#!/bin/sh\nGH_LOG=" + logPath + "\necho \"$*\" >> \"$GH_LOG\"\n" + script + "\n"
+	if err := os.WriteFile(path, []byte(body), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	SetGHPath(path)
+	previousTTL, previousDir := cacheTTL, etagCacheDir
+	t.Cleanup(func() {
+		SetGHPath("")
+		SetCacheTTL(previousTTL)
+		SetETagCacheDir(previousDir)
+	})
+	return logPath
+}
+
+// ghCalls returns the arguments of each call logged by a fake gh
+func ghCalls(t *testing.T, logPath string) []string {
+	t.Helper()
+	data, err := os.ReadFile(logPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func TestIsReadOnlyAPICall(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{"get", []string{"api", "repos/o/r"}, true},
+		{"explicit get", []string{"api", "--method", "GET", "repos/o/r"}, true},
+		{"patch", []string{"api", "--method", "PATCH", "repos/o/r/issues/1"}, false},
+		{"fields post", []string{"api", "repos/o/r/issues", "-f", "title=x"}, false},
+		{"rate limit", []string{"api", "rate_limit"}, false},
+		{"cache given", []string{"api", "repos/o/r", "--cache", "0s"}, false},
+		{"not api", []string{"auth", "status"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isReadOnlyAPICall(tt.args); got != tt.want {
+				t.Errorf("isReadOnlyAPICall(%q) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGHCommandCacheFlag(t *testing.T) {
+	previous := cacheTTL
+	defer SetCacheTTL(previous)
+
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		args []string
+		want []string
+	}{
+		{"read-only call cached", time.Hour, []string{"api", "repos/o/r"}, []string{"api", "repos/o/r", "--cache", "1h0m0s"}},
+		{"write not cached", time.Hour, []string{"api", "--method", "POST", "repos/o/r/issues"}, []string{"api", "--method", "POST", "repos/o/r/issues"}},
+		{"caching disabled", 0, []string{"api", "repos/o/r"}, []string{"api", "repos/o/r"}},
+		{"rate limit live", time.Hour, []string{"api", "rate_limit"}, []string{"api", "rate_limit"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetCacheTTL(tt.ttl)
+			cmd := ghCommand(tt.args...)
+			if got := cmd.Args[1:]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ghCommand(%q) args = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunGHStatusCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		stderr string
+		want   int
+	}{
+		{"message and status", "gh: Not Found (HTTP 404)", 404},
+		{"bare status", "gh: HTTP 304", 304},
+		{"no status", "connection refused", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeGH(t, "echo '"+tt.stderr+"' >&2; exit 1")
+			SetCacheTTL(0)
+			_, err := runGH("api", "repos/o/r")
+			if err == nil {
+				t.Fatal("runGH succeeded, want an error")
+			}
+			if got := StatusCode(err); got != tt.want {
+				t.Errorf("StatusCode = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// etagScript answers like gh api --include: 304 when the request carries the ETag "abc",
+// and otherwise the output "fresh" with that ETag
+const etagScript = `case "$*" in
+  *'If-None-Match: "abc"'*) printf 'HTTP/2.0 304 Not Modified\r\nEtag: "abc"\r\n\r\n'; echo 'gh: HTTP 304' >&2; exit 1 ;;
+  *--include*) printf 'HTTP/2.0 200 OK\r\nEtag: "abc"\r\n\r\nfresh\n' ;;
+  *) echo plain ;;
+esac`
+
+func TestRunGHConditional(t *testing.T) {
+	logPath := fakeGH(t, etagScript)
+	SetCacheTTL(time.Hour)
+	SetETagCacheDir(t.TempDir())
+
+	// The first call is unconditional and caches the ETag, the second revalidates it
+	for i, want := range []string{"fresh\n", "fresh\n"} {
+		out, err := runGH("api", "repos/o/r", "--jq", ".name")
+		if err != nil {
+			t.Fatalf("call %d: %v", i+1, err)
+		}
+		if string(out) != want {
+			t.Errorf("call %d output = %q, want %q", i+1, out, want)
+		}
+	}
+
+	calls := ghCalls(t, logPath)
+	if len(calls) != 2 {
+		t.Fatalf("got %d calls, want 2: %q", len(calls), calls)
+	}
+	if strings.Contains(calls[0], "If-None-Match") {
+		t.Errorf("first call %q is conditional, want unconditional", calls[0])
+	}
+	if !strings.Contains(calls[1], `--header If-None-Match: "abc"`) {
+		t.Errorf("second call %q does not revalidate the cached ETag", calls[1])
+	}
+}
+
+func TestUsesETag(t *testing.T) {
+	previousTTL, previousDir := cacheTTL, etagCacheDir
+	defer func() {
+		SetCacheTTL(previousTTL)
+		SetETagCacheDir(previousDir)
+	}()
+
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		dir  string
+		args []string
+		want bool
+	}{
+		{"single page", time.Hour, "cache", []string{"api", "repos/o/r"}, true},
+		{"paginated", time.Hour, "cache", []string{"api", "repos/o/r/forks", "--paginate"}, false},
+		{"silent", time.Hour, "cache", []string{"api", "repos/o/r", "--silent"}, false},
+		{"write", time.Hour, "cache", []string{"api", "--method", "PATCH", "repos/o/r"}, false},
+		{"caching disabled", 0, "cache", []string{"api", "repos/o/r"}, false},
+		{"no cache directory", time.Hour, "", []string{"api", "repos/o/r"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetCacheTTL(tt.ttl)
+			SetETagCacheDir(tt.dir)
+			if got := usesETag(tt.args); got != tt.want {
+				t.Errorf("usesETag(%q) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitIncludedResponse(t *testing.T) {
+	etag, body := splitIncludedResponse([]byte("HTTP/2.0 200 OK\r\nContent-Type: application/json\r\nEtag: W/\"x1\"\r\n\r\n{\"a\":1}\n"))
+	if etag != `W/"x1"` {
+		t.Errorf("etag = %q, want %q", etag, `W/"x1"`)
+	}
+	if string(body) != "{\"a\":1}\n" {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestGetLastCommitDateRetriesFresh(t *testing.T) {
+	const date = "2025-05-01T00:00:00Z"
+	tests := []struct {
+		name      string
+		ttl       time.Duration
+		cached    string
+		want      string
+		wantErr   bool
+		wantCalls int
+	}{
+		{"cached response parses", time.Hour, "echo " + date, date, false, 1},
+		{"corrupt cached response", time.Hour, "echo garbage", date, false, 2},
+		{"empty cached response", time.Hour, "exit 0", date, false, 2},
+		{"caching disabled", 0, "echo garbage", "", true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The fresh request, with the cache bypassed, always succeeds
+			logPath := fakeGH(t, `case "$*" in
+*'--cache 0s'*) echo `+date+`;;
+*) `+tt.cached+`;;
+esac`)
+			SetCacheTTL(tt.ttl)
+
+			got, err := GetLastCommitDate("o/r", "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetLastCommitDate error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && got.Format(time.RFC3339) != tt.want {
+				t.Errorf("date = %s, want %s", got.Format(time.RFC3339), tt.want)
+			}
+			if calls := ghCalls(t, logPath); len(calls) != tt.wantCalls {
+				t.Errorf("made %d calls, want %d: %q", len(calls), tt.wantCalls, calls)
+			}
+		})
+	}
+}
+
+func TestSetGHPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"configured", "/opt/tools/gh", "/opt/tools/gh"},
+		{"default", "", "gh"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetGHPath(tt.path)
+			t.Cleanup(func() { SetGHPath("") })
+			for _, args := range [][]string{{"--version"}, {"auth", "status"}, {"api", "repos/o/r"}} {
+				if got := ghCommand(args...).Args[0]; got != tt.want {
+					t.Errorf("ghCommand(%q) runs %q, want %q", args, got, tt.want)
+				}
+			}
+		})
+	}
+}
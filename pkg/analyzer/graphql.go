@@ -0,0 +1,156 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// APIs used for the membership checks of the repo contributor scope
+const (
+	APIREST    = "rest"
+	APIGraphQL = "graphql"
+	APIMembers = "members"
+)
+
+// graphQLMembershipBatch is how many contributors are checked per GraphQL query, keeping each query
+// well under the node limit
+const graphQLMembershipBatch = 50
+
+// graphQLFallback remembers that a GraphQL query failed, so the rest of the run uses REST without retrying
+var graphQLFallback = struct {
+	mu       sync.Mutex
+	disabled bool
+}{}
+
+// graphQLDisabled reports whether GraphQL membership checks were given up on for this run
+func graphQLDisabled() bool {
+	graphQLFallback.mu.Lock()
+	defer graphQLFallback.mu.Unlock()
+	return graphQLFallback.disabled
+}
+
+// disableGraphQL switches the rest of the run to REST membership checks, warning once
+func disableGraphQL(err error, cfg config.Config, warnings *warningLog) {
+	graphQLFallback.mu.Lock()
+	first := !graphQLFallback.disabled
+	graphQLFallback.disabled = true
+	graphQLFallback.mu.Unlock()
+
+	if first {
+		warnings.warn(cfg, "", "GraphQL membership checks failed, falling back to REST: %v", err)
+	}
+}
+
+// checkOrgMembershipsGraphQL reports which of the users are members of the organization, checking
+// a batch of users per `gh api graphql` query instead of one REST call per user
+// A user's organization field resolves only when the caller can see the membership, so a caller
+// outside the organization sees public membership only, as with the public_members endpoint.
+// Deleted accounts resolve to no user and count as non-members, as the REST 404 does.
+func checkOrgMembershipsGraphQL(orgName string, logins []string) (map[string]bool, error) {
+	members := make(map[string]bool, len(logins))
+	for start := 0; start < len(logins); start += graphQLMembershipBatch {
+		end := min(start+graphQLMembershipBatch, len(logins))
+		batch := logins[start:end]
+
+		args := []string{"api", "graphql", "-f", "query=" + membershipQuery(len(batch)), "-f", "org=" + orgName}
+		for i, login := range batch {
+			args = append(args, "-f", fmt.Sprintf("u%d=%s", i, login))
+		}
+
+		// gh exits with an error when any user cannot be resolved, but still prints the response
+		out, runErr := runGH(args...)
+		found, err := parseMembershipResponse(out, len(batch))
+		if err != nil {
+			if runErr != nil {
+				return nil, fmt.Errorf("failed to check membership in %s: %w", orgName, runErr)
+			}
+			return nil, fmt.Errorf("failed to check membership in %s: %w", orgName, err)
+		}
+		for i, login := range batch {
+			members[login] = found[i]
+		}
+	}
+	return members, nil
+}
+
+// membershipQuery builds a query looking up n users, aliased u0 to un-1, and their membership of $org
+func membershipQuery(n int) string {
+	var b strings.Builder
+	b.WriteString("query($org: String!")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, ", $u%d: String!", i)
+	}
+	b.WriteString(") {")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, " u%d: user(login: $u%d) { organization(login: $org) { id } }", i, i)
+	}
+	b.WriteString(" }")
+	return b.String()
+}
+
+// graphQLError is an error reported in a GraphQL response
+type graphQLError struct {
+	Type    string `json:"type"`
+	Path    []any  `json:"path"`
+	Message string `json:"message"`
+}
+
+// parseMembershipResponse returns whether each of the n queried users is a member
+// Users that could not be found are non-members; any other error fails the whole batch
+func parseMembershipResponse(data []byte, n int) ([]bool, error) {
+	var response struct {
+		Data map[string]*struct {
+			Organization *struct {
+				ID string `json:"id"`
+			} `json:"organization"`
+		} `json:"data"`
+		Errors []graphQLError `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL response: %w", err)
+	}
+
+	for _, e := range response.Errors {
+		if e.Type != "NOT_FOUND" || len(e.Path) != 1 {
+			return nil, fmt.Errorf("GraphQL error: %s", e.Message)
+		}
+	}
+	if response.Data == nil {
+		return nil, fmt.Errorf("GraphQL response has no data")
+	}
+
+	members := make([]bool, n)
+	for i := range members {
+		user := response.Data[fmt.Sprintf("u%d", i)]
+		members[i] = user != nil && user.Organization != nil
+	}
+	return members, nil
+}
+
+// classifyByMembershipGraphQL splits the contributors into members and non-members with GraphQL queries,
+// returning false when GraphQL is unavailable so the caller checks them over REST instead
+func classifyByMembershipGraphQL(orgName string, contributors []string, cfg config.Config, warnings *warningLog) (active, inactive []string, ok bool) {
+	if graphQLDisabled() {
+		return nil, nil, false
+	}
+
+	members, err := checkOrgMembershipsGraphQL(orgName, contributors)
+	if err != nil {
+		disableGraphQL(err, cfg, warnings)
+		return nil, nil, false
+	}
+
+	for _, contributor := range contributors {
+		if members[contributor] {
+			active = append(active, contributor)
+		} else {
+			// User is not in the organization anymore
+			inactive = append(inactive, contributor)
+		}
+	}
+	return active, inactive, true
+}
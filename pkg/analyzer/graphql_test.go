@@ -0,0 +1,144 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// resetGraphQLFallback re-enables GraphQL membership checks given up on by earlier tests
+func resetGraphQLFallback(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		graphQLFallback.mu.Lock()
+		graphQLFallback.disabled = false
+		graphQLFallback.mu.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestParseMembershipResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     []bool
+		wantErr  string
+	}{
+		{"members and non-members", `{"data":{"u0":{"organization":{"id":"O_1"}},"u1":{"organization":null},"u2":{"organization":{"id":"O_1"}}}}`,
+			[]bool{true, false, true}, ""},
+		{"deleted account", `{"data":{"u0":null,"u1":{"organization":{"id":"O_1"}},"u2":null},"errors":[{"type":"NOT_FOUND","path":["u0"],"message":"Could not resolve to a User"}]}`,
+			[]bool{false, true, false}, ""},
+		{"other error", `{"data":null,"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`, nil, "API rate limit exceeded"},
+		{"nested not found", `{"data":{},"errors":[{"type":"NOT_FOUND","path":["u0","organization"],"message":"gone"}]}`, nil, "GraphQL error: gone"},
+		{"no data", `{}`, nil, "no data"},
+		{"malformed", `not json`, nil, "failed to parse GraphQL response"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMembershipResponse([]byte(tt.response), 3)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parseMembershipResponse error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("members = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMembershipQuery(t *testing.T) {
+	want := "query($org: String!, $u0: String!, $u1: String!) {" +
+		" u0: user(login: $u0) { organization(login: $org) { id } }" +
+		" u1: user(login: $u1) { organization(login: $org) { id } } }"
+	if got := membershipQuery(2); got != want {
+		t.Errorf("membershipQuery(2) = %q, want %q", got, want)
+	}
+}
+
+func TestGetContributorsStatusGraphQL(t *testing.T) {
+	// ann and cy are members and bob left; the GraphQL query answers in the order the logins were given
+	const members = `{"data":{"u0":{"organization":{"id":"O_1"}},"u1":{"organization":null},"u2":{"organization":{"id":"O_1"}}}}`
+
+	tests := []struct {
+		name         string
+		api          string
+		graphql      string
+		wantGraphQL  bool
+		wantREST     bool
+		wantWarnings int
+	}{
+		{"REST", APIREST, "", false, true, 0},
+		{"GraphQL", APIGraphQL, "echo '" + members + "'", true, false, 0},
+		{"GraphQL unavailable", APIGraphQL, `echo 'gh: Bad credentials (HTTP 401)' >&2; exit 1`, true, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, `case "$*" in
+*graphql*) `+tt.graphql+`;;
+*user/memberships*) echo '{"state":"active"}';;
+*contributors*) printf 'ann\nbob\ncy\n';;
+*members/ann*|*members/cy*) exit 0;;
+*) echo 'gh: Not Found (HTTP 404)' >&2; exit 1;;
+esac`)
+			SetCacheTTL(0)
+			fastMembershipRetries(t)
+			resetGraphQLFallback(t)
+			warnings := &warningLog{}
+
+			active, inactive, err := getContributorsStatus("o/r", "o", config.Config{API: tt.api, Silent: true}, warnings)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Both paths give the same classification
+			if !reflect.DeepEqual(active, []string{"ann", "cy"}) || !reflect.DeepEqual(inactive, []string{"bob"}) {
+				t.Errorf("active %q and inactive %q, want ann, cy and bob", active, inactive)
+			}
+
+			var graphQL, rest bool
+			for _, call := range ghCalls(t, logPath) {
+				graphQL = graphQL || strings.Contains(call, "graphql")
+				rest = rest || strings.Contains(call, "orgs/o/members/")
+			}
+			if graphQL != tt.wantGraphQL || rest != tt.wantREST {
+				t.Errorf("checked over GraphQL %v and REST %v, want %v and %v", graphQL, rest, tt.wantGraphQL, tt.wantREST)
+			}
+			if got := len(warnings.list()); got != tt.wantWarnings {
+				t.Errorf("recorded %d warnings, want %d", got, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestCheckOrgMembershipsGraphQLBatches(t *testing.T) {
+	logPath := fakeGH(t, `echo '{"data":{}}'`)
+	SetCacheTTL(0)
+
+	logins := make([]string, 2*graphQLMembershipBatch+1)
+	for i := range logins {
+		logins[i] = fmt.Sprintf("user%d", i)
+	}
+	members, err := checkOrgMembershipsGraphQL("o", logins)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != len(logins) {
+		t.Errorf("checked %d of %d users", len(members), len(logins))
+	}
+	calls := ghCalls(t, logPath)
+	if len(calls) != 3 {
+		t.Fatalf("made %d queries, want 3 batches", len(calls))
+	}
+	if !strings.Contains(calls[2], "u0=user100") || strings.Contains(calls[2], "u1=") {
+		t.Errorf("last batch %q, want only user100", calls[2])
+	}
+}
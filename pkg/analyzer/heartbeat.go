@@ -0,0 +1,101 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+	"golang.org/x/term"
+)
+
+// Heartbeat periodically logs how far a scan has got when the log output is not a terminal, such as
+// in CI logs, where the animated progress bar would only add noise
+type Heartbeat struct {
+	mu    sync.Mutex
+	w     io.Writer
+	total int
+	done  int
+	start time.Time
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewHeartbeat starts logging the progress of a scan of total repositories every configured interval
+// It returns nil when silent, when the interval is 0, or when the log output is a terminal, and all
+// methods are no-ops on a nil heartbeat
+func NewHeartbeat(total int, cfg config.Config) *Heartbeat {
+	if cfg.Silent || cfg.Heartbeat <= 0 || isTerminal(LogOutput()) {
+		return nil
+	}
+
+	// Heartbeats go to stderr rather than stdout, where they would end up in a piped report
+	w := LogOutput()
+	if w == os.Stdout {
+		w = os.Stderr
+	}
+
+	h := &Heartbeat{
+		w:     w,
+		total: total,
+		start: time.Now(),
+		stop:  make(chan struct{}),
+	}
+	ticker := time.NewTicker(cfg.Heartbeat)
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		defer ticker.Stop()
+		h.run(ticker.C)
+	}()
+	return h
+}
+
+// run logs a heartbeat on every tick until the heartbeat is stopped
+func (h *Heartbeat) run(ticks <-chan time.Time) {
+	for {
+		select {
+		case now := <-ticks:
+			h.beat(now)
+		case <-h.stop:
+			return
+		}
+	}
+}
+
+// beat logs the repositories analyzed so far and the time elapsed at now
+func (h *Heartbeat) beat(now time.Time) {
+	h.mu.Lock()
+	done := h.done
+	h.mu.Unlock()
+
+	fmt.Fprintf(h.w, "💓 Analyzed %d/%d repositories, elapsed %s\n", done, h.total, formatDuration(now.Sub(h.start)))
+}
+
+// RepoCompleted records that a repository has been processed; it is safe for concurrent use
+func (h *Heartbeat) RepoCompleted() {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.done++
+}
+
+// Stop stops logging heartbeats
+func (h *Heartbeat) Stop() {
+	if h == nil {
+		return
+	}
+	close(h.stop)
+	h.wg.Wait()
+}
+
+// isTerminal reports whether the writer is a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// lineWriter sends each write to a channel, so a test can wait for a heartbeat to be logged
+type lineWriter chan string
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
+
+func TestHeartbeatCadence(t *testing.T) {
+	out := make(lineWriter)
+	h := &Heartbeat{w: out, total: 3, start: testNow, stop: make(chan struct{})}
+	ticks := make(chan time.Time)
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		h.run(ticks)
+	}()
+	defer h.Stop()
+
+	// Each tick of the fake clock logs one line with the progress at that point
+	steps := []struct {
+		completed int
+		tick      time.Duration
+		want      string
+	}{
+		{0, 30 * time.Second, "💓 Analyzed 0/3 repositories, elapsed 30s\n"},
+		{2, 60 * time.Second, "💓 Analyzed 2/3 repositories, elapsed 1m 0s\n"},
+		{1, 90 * time.Second, "💓 Analyzed 3/3 repositories, elapsed 1m 30s\n"},
+	}
+	for _, step := range steps {
+		for i := 0; i < step.completed; i++ {
+			h.RepoCompleted()
+		}
+		ticks <- testNow.Add(step.tick)
+		if got := <-out; got != step.want {
+			t.Errorf("heartbeat at %s = %q, want %q", step.tick, got, step.want)
+		}
+	}
+
+	// Nothing is logged without a tick
+	select {
+	case line := <-out:
+		t.Errorf("logged %q between ticks", line)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestNewHeartbeat(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want bool
+	}{
+		{"piped log", config.Config{Heartbeat: time.Hour}, true},
+		{"silent", config.Config{Heartbeat: time.Hour, Silent: true}, false},
+		{"disabled", config.Config{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captureLog(t)
+			h := NewHeartbeat(10, tt.cfg)
+			if (h != nil) != tt.want {
+				t.Errorf("heartbeat started = %v, want %v", h != nil, tt.want)
+			}
+			// Calls work whether or not a heartbeat was started
+			h.RepoCompleted()
+			h.Stop()
+		})
+	}
+}
@@ -1,19 +1,229 @@
 package analyzer
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
 )
 
 // getLastCommitDate retrieves the date of the last commit for a repository (unexported version for internal use)
-func getLastCommitDate(repoFullName string) (time.Time, error) {
+func getLastCommitDate(repoFullName, branch string) (time.Time, error) {
 	// Delegate to the exported version
-	return GetLastCommitDate(repoFullName)
+	return GetLastCommitDate(repoFullName, branch)
 }
 
-// getContributorsStatus checks how many contributors are still active in the organization (unexported version for internal use)
-func getContributorsStatus(repoFullName, orgName string) (active, inactive int, err error) {
-	// Delegate to the exported version
-	return GetContributorsStatus(repoFullName, orgName)
+// displayName renders the repository name with its branch and path, if they were analyzed
+func displayName(repo Repository) string {
+	name := repo.Name
+	if repo.Branch != "" {
+		name += "@" + repo.Branch
+	}
+	if repo.Path != "" {
+		name += ":" + repo.Path
+	}
+	return name
+}
+
+// substantiveCommitSummary renders the last substantive commit for human-readable output
+func substantiveCommitSummary(repo Repository, cfg config.Config) string {
+	return fmt.Sprintf("%s (%s)",
+		repo.LastSubstantiveCommitDate.Format("2006-01-02"), daysAgo(repo.DaysSinceSubstantiveCommit, cfg))
+}
+
+// contributorSummary renders the contributor counts for human-readable output
+func contributorSummary(repo Repository) string {
+	if !repo.ContributorDataComplete {
+		return "data unavailable"
+	}
+	summary := fmt.Sprintf("%d total, %d inactive (%.1f%%)",
+		repo.TotalContributors, repo.InactiveContributors,
+		repo.InactivePercentage*100)
+	if repo.WeightedInactivePercentage != nil {
+		summary += fmt.Sprintf(", %.1f%% by commits in the last year", *repo.WeightedInactivePercentage*100)
+	}
+	if repo.SuspendedContributors > 0 {
+		summary += fmt.Sprintf(", %d suspended", repo.SuspendedContributors)
+	}
+	return summary
+}
+
+// extraColumnNames returns the extra column names present on any repository, in alphabetical order
+func extraColumnNames(repos []Repository) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, repo := range repos {
+		for name := range repo.Extra {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// extraSummary renders the extra columns of a repository for human-readable output
+func extraSummary(repo Repository) string {
+	names := extraColumnNames([]Repository{repo})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s: %s", name, repo.Extra[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// csvQuoteAll quotes the values that contain CSV separators, quotes, or line breaks
+func csvQuoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		if strings.ContainsAny(v, ",\"\r\n") {
+			v = `"` + strings.ReplaceAll(v, `"`, `""`) + `"`
+		}
+		quoted[i] = v
+	}
+	return quoted
+}
+
+// isFlaggedArchived reports whether a repository was flagged for being archived rather than for inactivity
+func isFlaggedArchived(repo Repository) bool {
+	return repo.Flagged && repo.FlagReason == FlagReasonArchived
+}
+
+// countFlagged splits the flagged repositories into those flagged for inactivity and those flagged as archived,
+// so that intentional archiving is not reported as neglect
+func countFlagged(repos []Repository) (inactive, archived int) {
+	for _, repo := range repos {
+		switch {
+		case isFlaggedArchived(repo):
+			archived++
+		case repo.Flagged:
+			inactive++
+		}
+	}
+	return inactive, archived
+}
+
+// enabledString renders a repository feature toggle for human-readable output
+func enabledString(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// signedCommitSummary renders the signed commit ratio for human-readable output
+func signedCommitSummary(repo Repository) string {
+	return fmt.Sprintf("%.1f%% of recent commits", *repo.SignedCommitRatio*100)
+}
+
+// repoAgeSummary renders the repository creation date and age for human-readable output
+func repoAgeSummary(repo Repository, cfg config.Config) string {
+	return fmt.Sprintf("%s (%s)", repo.CreatedAt.Format("2006-01-02"), daysAgo(repo.RepoAgeDays, cfg))
+}
+
+// securityAlertSummary renders the open Dependabot alert count for human-readable output
+func securityAlertSummary(repo Repository) string {
+	if repo.OpenSecurityAlerts == nil {
+		return "unknown"
+	}
+	return strconv.Itoa(*repo.OpenSecurityAlerts)
+}
+
+// engagementSummary renders the issue engagement for human-readable output
+func engagementSummary(repo Repository) string {
+	if repo.IssuesSampled == 0 {
+		return "no recent issues"
+	}
+	median := "no responses"
+	if repo.MedianIssueResponseHours != nil {
+		median = fmt.Sprintf("median first response %.1fh", *repo.MedianIssueResponseHours)
+	}
+	return fmt.Sprintf("%s, %d of %d recent issues unanswered", median, repo.UnansweredIssues, repo.IssuesSampled)
+}
+
+// tagSummary renders the newest tag for human-readable output
+func tagSummary(repo Repository, cfg config.Config) string {
+	if repo.LastTagDate == nil {
+		return "none"
+	}
+	days := int(Now().Sub(*repo.LastTagDate).Hours() / 24)
+	return fmt.Sprintf("%s, %s (%s)", repo.LastTag, repo.LastTagDate.Format("2006-01-02"), daysAgo(days, cfg))
+}
+
+// newestBranchSummary renders the newest commit on another branch for human-readable output
+func newestBranchSummary(repo Repository, cfg config.Config) string {
+	if repo.NewestBranchCommitDate == nil {
+		return "none"
+	}
+	days := int(Now().Sub(*repo.NewestBranchCommitDate).Hours() / 24)
+	return fmt.Sprintf("%s, %s (%s)", repo.NewestBranch, repo.NewestBranchCommitDate.Format("2006-01-02"), daysAgo(days, cfg))
+}
+
+// momentumSummary renders the commit momentum for human-readable output
+func momentumSummary(repo Repository) string {
+	return fmt.Sprintf("%+d commits (last %d days vs the %d before)", *repo.CommitMomentum, momentumWindowDays, momentumWindowDays)
+}
+
+// staleBotPRSummary renders the stale open bot pull requests for human-readable output
+func staleBotPRSummary(repo Repository, cfg config.Config) string {
+	return fmt.Sprintf("%d open over %d days", *repo.StaleBotPRCount, cfg.MaxCommitAgeInDays)
+}
+
+// mergedPRSummary renders the last merged pull request for human-readable output
+func mergedPRSummary(repo Repository, cfg config.Config) string {
+	if repo.LastMergedPRDate == nil {
+		return "none"
+	}
+	days := int(Now().Sub(*repo.LastMergedPRDate).Hours() / 24)
+	return fmt.Sprintf("%s (%s)", repo.LastMergedPRDate.Format("2006-01-02"), daysAgo(days, cfg))
+}
+
+// ciSummary renders the latest CI run for human-readable output
+func ciSummary(repo Repository) string {
+	if repo.LastCIDate == nil {
+		return repo.LastCIStatus
+	}
+	return fmt.Sprintf("%s (%s)", repo.LastCIStatus, repo.LastCIDate.Format("2006-01-02"))
+}
+
+// governanceSummary renders the governance settings for human-readable output
+func governanceSummary(repo Repository) string {
+	summary := fmt.Sprintf("issues %s, discussions %s", enabledString(repo.IssuesEnabled), enabledString(repo.HasDiscussions))
+	if repo.Visibility != "" {
+		summary += ", " + repo.Visibility
+	}
+	if repo.OutsideCollaborators != nil {
+		summary += fmt.Sprintf(", %d outside collaborators", *repo.OutsideCollaborators)
+	} else {
+		summary += ", outside collaborators unknown"
+	}
+	return summary
+}
+
+// priorityMarker renders the high priority and security review notes appended to a flagged repository's reason, one per reason that applies
+func priorityMarker(repo Repository) string {
+	marker := ""
+	if repo.ExposedToOutsiders {
+		marker += fmt.Sprintf(" [high priority: public with %d outside collaborators]", *repo.OutsideCollaborators)
+	}
+	if repo.Undocumented {
+		marker += " [high priority: no README]"
+	}
+	if repo.Unlicensed {
+		marker += " [high priority: no license]"
+	}
+	if repo.SecurityReview {
+		marker += " [security review: low signed commit ratio]"
+	}
+	if repo.UrgentSecurity {
+		marker += fmt.Sprintf(" [urgent: %d open security alerts]", *repo.OpenSecurityAlerts)
+	}
+	return marker
 }
 
 // isRepositoryArchived is defined in archive.go
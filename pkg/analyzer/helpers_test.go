@@ -0,0 +1,35 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// testNow is the analysis time pinned by tests depending on the current time
+var testNow = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// pinNow pins the analysis time to testNow for the duration of a test
+func pinNow(t *testing.T) {
+	t.Helper()
+	SetAsOf(testNow)
+	t.Cleanup(func() { SetAsOf(time.Time{}) })
+}
+
+// testDaysAgo returns the time the given number of days before testNow
+func testDaysAgo(days int) *time.Time {
+	t := testNow.AddDate(0, 0, -days)
+	return &t
+}
+
+func intPtr(v int) *int           { return &v }
+func floatPtr(v float64) *float64 { return &v }
+func boolPtr(v bool) *bool        { return &v }
+func strPtr(v string) *string     { return &v }
+
+// withConfig returns a copy of a configuration changed by set
+func withConfig(cfg config.Config, set func(*config.Config)) config.Config {
+	set(&cfg)
+	return cfg
+}
@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// HookError is returned when a post-processing hook exits with a non-zero status
+type HookError struct {
+	Command  string
+	ExitCode int
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("hook %q exited with status %d", e.Command, e.ExitCode)
+}
+
+// RunHook runs a shell command with the report on its stdin, logging each line of its combined output
+// A non-zero exit is returned as a *HookError carrying the exit code
+func RunHook(command string, report []byte) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Stdin = bytes.NewReader(report)
+
+	out, err := cmd.CombinedOutput()
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		Logf("🪝 %s\n", scanner.Text())
+	}
+
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &HookError{Command: command, ExitCode: exitErr.ExitCode()}
+		}
+		return fmt.Errorf("failed to run hook %q: %w", command, err)
+	}
+	return nil
+}
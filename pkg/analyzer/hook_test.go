@@ -0,0 +1,52 @@
+package analyzer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunHook(t *testing.T) {
+	report := []byte(`{"organization":"o","repositories":[{"name":"o/r","flagged":true}]}`)
+	received := filepath.Join(t.TempDir(), "received.json")
+
+	tests := []struct {
+		name     string
+		command  string
+		wantLog  string
+		wantExit int
+	}{
+		{"report on stdin", "cat > " + received, "", 0},
+		{"output logged", "cat", `🪝 {"organization":"o"`, 0},
+		{"stderr logged", "echo pushed >&2", "🪝 pushed", 0},
+		{"exit code surfaced", "echo rejected; exit 3", "🪝 rejected", 3},
+		{"missing command", "no-such-hook-command", "", 127},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureLog(t)
+
+			err := RunHook(tt.command, report)
+			var hookErr *HookError
+			if tt.wantExit == 0 && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantExit != 0 && (!errors.As(err, &hookErr) || hookErr.ExitCode != tt.wantExit) {
+				t.Fatalf("RunHook error = %v, want a hook error with status %d", err, tt.wantExit)
+			}
+			if !strings.Contains(logs.String(), tt.wantLog) {
+				t.Errorf("log = %q, want %q", logs.String(), tt.wantLog)
+			}
+		})
+	}
+
+	data, err := os.ReadFile(received)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(report) {
+		t.Errorf("hook received %q, want the report %q", data, report)
+	}
+}
@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// daysPerMonth and daysPerYear are the average calendar lengths used to humanize ages
+const (
+	daysPerMonth = 30.44
+	daysPerYear  = 365.25
+)
+
+// daysAgo renders an age in days for human-readable output, humanized if configured
+func daysAgo(days int, cfg config.Config) string {
+	if cfg.Humanize {
+		return humanizeDays(days)
+	}
+	return fmt.Sprintf("%d days ago", days)
+}
+
+// humanizeDays renders an age in days as a relative time, e.g. "3 weeks ago" or "over a year ago"
+// Days are counted up to a week, weeks up to a month, and months up to a year; years are
+// qualified as about, over, or almost depending on how far past a whole year the age is
+func humanizeDays(days int) string {
+	switch {
+	case days <= 0:
+		return "today"
+	case days == 1:
+		return "yesterday"
+	case days < 7:
+		return fmt.Sprintf("%d days ago", days)
+	case days < 30:
+		return plural(int(math.Round(float64(days)/7)), "a week", "weeks") + " ago"
+	case float64(days) < daysPerYear-daysPerMonth/2:
+		return "about " + plural(int(math.Round(float64(days)/daysPerMonth)), "a month", "months") + " ago"
+	}
+
+	years := float64(days) / daysPerYear
+	whole := int(years)
+	switch months := (years - float64(whole)) * 12; {
+	case months < 3:
+		return "about " + plural(whole, "a year", "years") + " ago"
+	case months < 9:
+		return "over " + plural(whole, "a year", "years") + " ago"
+	case months < 11:
+		return "almost " + plural(whole+1, "a year", "years") + " ago"
+	default:
+		return "about " + plural(whole+1, "a year", "years") + " ago"
+	}
+}
+
+// plural renders a count with the singular phrase for one and the plural unit otherwise
+func plural(n int, one, unit string) string {
+	if n == 1 {
+		return one
+	}
+	return fmt.Sprintf("%d %s", n, unit)
+}
@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestHumanizeDays(t *testing.T) {
+	tests := []struct {
+		days int
+		want string
+	}{
+		{-1, "today"},
+		{0, "today"},
+		{1, "yesterday"},
+		{3, "3 days ago"},
+		{7, "a week ago"},
+		{20, "3 weeks ago"},
+		{29, "4 weeks ago"},
+		{30, "about a month ago"},
+		{100, "about 3 months ago"},
+		{350, "about 11 months ago"},
+		{351, "about a year ago"},
+		{400, "about a year ago"},
+		{500, "over a year ago"},
+		{700, "almost 2 years ago"},
+		{730, "about 2 years ago"},
+		{1000, "over 2 years ago"},
+	}
+	for _, tt := range tests {
+		if got := humanizeDays(tt.days); got != tt.want {
+			t.Errorf("humanizeDays(%d) = %q, want %q", tt.days, got, tt.want)
+		}
+	}
+}
+
+func TestDaysAgo(t *testing.T) {
+	tests := []struct {
+		name     string
+		humanize bool
+		days     int
+		want     string
+	}{
+		{"plain", false, 400, "400 days ago"},
+		{"plain today", false, 0, "0 days ago"},
+		{"humanized", true, 400, "about a year ago"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := daysAgo(tt.days, config.Config{Humanize: tt.humanize}); got != tt.want {
+				t.Errorf("daysAgo(%d) = %q, want %q", tt.days, got, tt.want)
+			}
+		})
+	}
+}
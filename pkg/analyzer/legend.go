@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// flagLegend states the effective flagging criteria in one sentence, so readers of a human-readable
+// report know what flagged means without the documentation
+func flagLegend(cfg config.Config) string {
+	commit := "last commit"
+	if cfg.FlagOnSubstantiveCommit {
+		commit = "last substantive commit"
+	}
+
+	inactive := fmt.Sprintf("inactive contributors ≥ %.0f%%", cfg.InactiveContribThreshold*100)
+	if cfg.WeightedContributors {
+		inactive = fmt.Sprintf("inactive contributors' share of the last year's commits ≥ %.0f%%", cfg.InactiveContribThreshold*100)
+	}
+	if cfg.MinInactiveCount > 0 {
+		inactive += fmt.Sprintf(" (at least %d)", cfg.MinInactiveCount)
+	}
+	stale := []string{inactive, "no contributors"}
+	if cfg.Governance {
+		stale = append(stale, "issues disabled")
+	}
+	if cfg.FlagBrokenCI {
+		stale = append(stale, "CI broken")
+	}
+	age := fmt.Sprintf("%d days", cfg.MaxCommitAgeInDays)
+	if cfg.FlagOnCadence {
+		age = fmt.Sprintf("max(%s, %g× its median commit gap)", age, cfg.CadenceMultiplier)
+	}
+	old := fmt.Sprintf("%s > %s AND (%s)", commit, age, strings.Join(stale, " OR "))
+
+	var kept []string
+	if cfg.RecentTags {
+		kept = append(kept, "a tag")
+	}
+	if cfg.ActiveBranches {
+		kept = append(kept, "another branch")
+	}
+	if len(kept) > 0 {
+		old += fmt.Sprintf(" unless %s has a commit within %d days", strings.Join(kept, " or "), cfg.MaxCommitAgeInDays)
+	}
+
+	criteria := []string{"archived", old}
+	if cfg.FlagStaleReviews {
+		criteria = append(criteria, fmt.Sprintf("last merged PR > %d days", cfg.MaxCommitAgeInDays))
+	}
+	if cfg.FlagDeclining {
+		criteria = append(criteria, fmt.Sprintf("commits in the last 90 days down by ≥ %d", cfg.DecliningMomentum))
+	}
+	if cfg.FlagUnresponsive {
+		criteria = append(criteria, fmt.Sprintf("median issue response > %.0f hours OR half of recent issues unanswered", cfg.MaxResponseHours))
+	}
+	if cfg.FlagStaleBotPRs {
+		criteria = append(criteria, fmt.Sprintf("more than %d bot PRs open > %d days", cfg.MaxStaleBotPRs, cfg.MaxCommitAgeInDays))
+	}
+	legend := "Flagged if " + strings.Join(criteria, ", OR ")
+
+	var exempt []string
+	if cfg.MinContributors > 0 {
+		small := fmt.Sprintf("fewer than %d contributors", cfg.MinContributors)
+		if !cfg.MinContributorsIncludeArchived {
+			small += " unless archived"
+		}
+		exempt = append(exempt, small)
+	}
+	if cfg.MinRepoAgeDays > 0 {
+		exempt = append(exempt, fmt.Sprintf("created under %d days ago unless archived", cfg.MinRepoAgeDays))
+	}
+	if len(exempt) > 0 {
+		legend += "; never if " + strings.Join(exempt, ", OR ")
+	}
+	return legend
+}
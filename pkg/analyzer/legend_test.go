@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestFlagLegend(t *testing.T) {
+	base := config.Config{MaxCommitAgeInDays: 180, InactiveContribThreshold: 0.5}
+
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want string
+	}{
+		{
+			name: "defaults",
+			cfg:  base,
+			want: "Flagged if archived, OR last commit > 180 days AND (inactive contributors ≥ 50% OR no contributors)",
+		},
+		{
+			name: "configured thresholds",
+			cfg: withConfig(base, func(c *config.Config) {
+				c.MaxCommitAgeInDays = 365
+				c.InactiveContribThreshold = 0.8
+				c.MinInactiveCount = 2
+			}),
+			want: "Flagged if archived, OR last commit > 365 days AND (inactive contributors ≥ 80% (at least 2) OR no contributors)",
+		},
+		{
+			name: "weighted substantive commits on a cadence",
+			cfg: withConfig(base, func(c *config.Config) {
+				c.WeightedContributors = true
+				c.FlagOnSubstantiveCommit = true
+				c.FlagOnCadence = true
+				c.CadenceMultiplier = 3
+			}),
+			want: "Flagged if archived, OR last substantive commit > max(180 days, 3× its median commit gap) AND " +
+				"(inactive contributors' share of the last year's commits ≥ 50% OR no contributors)",
+		},
+		{
+			name: "extra rules and exemptions",
+			cfg: withConfig(base, func(c *config.Config) {
+				c.Governance = true
+				c.FlagBrokenCI = true
+				c.RecentTags = true
+				c.ActiveBranches = true
+				c.FlagStaleReviews = true
+				c.FlagDeclining = true
+				c.DecliningMomentum = 10
+				c.FlagUnresponsive = true
+				c.MaxResponseHours = 72
+				c.FlagStaleBotPRs = true
+				c.MaxStaleBotPRs = 5
+				c.MinContributors = 3
+				c.MinRepoAgeDays = 90
+			}),
+			want: "Flagged if archived, OR last commit > 180 days AND (inactive contributors ≥ 50% OR no contributors OR issues disabled OR CI broken)" +
+				" unless a tag or another branch has a commit within 180 days" +
+				", OR last merged PR > 180 days" +
+				", OR commits in the last 90 days down by ≥ 10" +
+				", OR median issue response > 72 hours OR half of recent issues unanswered" +
+				", OR more than 5 bot PRs open > 180 days" +
+				"; never if fewer than 3 contributors unless archived, OR created under 90 days ago unless archived",
+		},
+		{
+			name: "small archived repositories flagged",
+			cfg:  withConfig(base, func(c *config.Config) { c.MinContributors = 3; c.MinContributorsIncludeArchived = true }),
+			want: "Flagged if archived, OR last commit > 180 days AND (inactive contributors ≥ 50% OR no contributors); never if fewer than 3 contributors",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := flagLegend(tt.cfg); got != tt.want {
+				t.Errorf("flagLegend =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlagLegendFormats(t *testing.T) {
+	pinNow(t)
+	repos := []Repository{
+		{Name: "o/old", Flagged: true, FlagReason: FlagReasonOldInactiveContributors, LastCommitDate: *testDaysAgo(400), DaysSinceLastCommit: 400},
+		{Name: "o/new", LastCommitDate: *testDaysAgo(5), DaysSinceLastCommit: 5},
+	}
+	cfg := config.Config{Organization: "o", MaxCommitAgeInDays: 270, InactiveContribThreshold: 0.75}
+	const legend = "270 days AND (inactive contributors ≥ 75%"
+
+	tests := []struct {
+		name       string
+		format     string
+		terminal   bool
+		silent     bool
+		wantLegend bool
+	}{
+		{"console", "console", true, false, true},
+		{"plain text file", "console", false, false, true},
+		{"markdown", "markdown", false, false, true},
+		{"silent console", "console", true, true, false},
+		{"silent markdown", "markdown", false, true, false},
+		{"json", "json", false, false, false},
+		{"csv", "csv", false, false, false},
+		{"ndjson", "ndjson", false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, ok := LookupFormatter(tt.format)
+			if !ok {
+				t.Fatalf("format %s not registered", tt.format)
+			}
+			summary := Summary{Total: 2, Flagged: 1, Inactive: 1, Terminal: tt.terminal,
+				Config: withConfig(cfg, func(c *config.Config) { c.Silent = tt.silent })}
+			var buf bytes.Buffer
+			if err := f.Format(&buf, repos, summary); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(buf.String(), legend); got != tt.wantLegend {
+				t.Errorf("legend in %s output = %v, want %v:\n%s", tt.name, got, tt.wantLegend, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// Lifecycle stages assigned with -lifecycle, from the most to the least alive
+const (
+	LifecycleActive      = "active"      // Committed to within the active threshold
+	LifecycleMaintenance = "maintenance" // Committed to within the stale threshold, but not recently
+	LifecycleStale       = "stale"       // Past the stale threshold, with its contributors still around
+	LifecycleAbandoned   = "abandoned"   // Past the stale threshold with its contributors gone, or past the abandoned threshold
+	LifecycleArchived    = "archived"    // Archived on GitHub
+)
+
+// LifecycleStages lists the lifecycle stages in the order summaries show them
+var LifecycleStages = []string{LifecycleActive, LifecycleMaintenance, LifecycleStale, LifecycleAbandoned, LifecycleArchived}
+
+// lifecycleStage returns the lifecycle stage of a repository, or an empty string when the age of its last
+// commit is unknown, because commits were not collected or could not be listed
+// The age is the one flagging uses, so it comes from the last substantive commit when configured to.
+func lifecycleStage(r Repository, cfg config.Config) string {
+	if r.Archived {
+		return LifecycleArchived
+	}
+	if !collects(cfg, MetricCommits) || r.CommitStatus != "" {
+		return ""
+	}
+
+	age := flagAge(r, cfg)
+	switch {
+	case age <= cfg.LifecycleActiveDays:
+		return LifecycleActive
+	case age <= cfg.LifecycleStaleAfterDays():
+		return LifecycleMaintenance
+	case age > cfg.LifecycleAbandonedDays || contributorsGone(r, cfg):
+		return LifecycleAbandoned
+	default:
+		return LifecycleStale
+	}
+}
+
+// contributorsGone reports whether a repository has no contributors left to pick it up: none at all, or
+// at least the inactive contributor threshold of them inactive
+// Repositories with unavailable contributor data keep the benefit of the doubt.
+func contributorsGone(r Repository, cfg config.Config) bool {
+	if !r.ContributorDataComplete {
+		return false
+	}
+	return r.TotalContributors == 0 || inactiveRatio(r, cfg) >= cfg.InactiveContribThreshold
+}
+
+// countLifecycleStages counts the repositories in each lifecycle stage, leaving out those without one
+func countLifecycleStages(repos []Repository) map[string]int {
+	counts := make(map[string]int, len(LifecycleStages))
+	for _, stage := range LifecycleStages {
+		counts[stage] = 0
+	}
+	for _, repo := range repos {
+		if repo.Lifecycle != "" {
+			counts[repo.Lifecycle]++
+		}
+	}
+	return counts
+}
+
+// lifecycleSummary renders the repository count of each lifecycle stage for human-readable output,
+// such as "12 active, 4 maintenance, 6 stale, 3 abandoned, 2 archived"
+func lifecycleSummary(counts map[string]int) string {
+	parts := make([]string, len(LifecycleStages))
+	for i, stage := range LifecycleStages {
+		parts[i] = fmt.Sprintf("%d %s", counts[stage], stage)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestLifecycleStageBoundaries(t *testing.T) {
+	// Active up to 30 days, maintenance up to the 180-day age threshold, abandoned past 730 days
+	cfg := withConfig(flaggingConfig, func(c *config.Config) {
+		c.Lifecycle = true
+		c.LifecycleActiveDays = 30
+		c.LifecycleAbandonedDays = 730
+	})
+	// Half of four contributors inactive meets the 50% threshold
+	around := func(days int) Repository {
+		return Repository{DaysSinceLastCommit: days, ContributorDataComplete: true, TotalContributors: 4, InactiveContributors: 1, InactivePercentage: 0.25}
+	}
+	gone := func(days int) Repository {
+		return Repository{DaysSinceLastCommit: days, ContributorDataComplete: true, TotalContributors: 4, InactiveContributors: 2, InactivePercentage: 0.5}
+	}
+
+	tests := []struct {
+		name string
+		repo Repository
+		cfg  config.Config
+		want string
+	}{
+		{"last active day", around(30), cfg, LifecycleActive},
+		{"first maintenance day", around(31), cfg, LifecycleMaintenance},
+		{"last maintenance day", around(180), cfg, LifecycleMaintenance},
+		{"first stale day", around(181), cfg, LifecycleStale},
+		{"last stale day", around(730), cfg, LifecycleStale},
+		{"first abandoned day", around(731), cfg, LifecycleAbandoned},
+		{"contributors gone at the threshold", gone(181), cfg, LifecycleAbandoned},
+		{"contributors gone while maintained", gone(180), cfg, LifecycleMaintenance},
+		{"no contributors", Repository{DaysSinceLastCommit: 181, ContributorDataComplete: true}, cfg, LifecycleAbandoned},
+		{"contributor data unavailable", Repository{DaysSinceLastCommit: 181}, cfg, LifecycleStale},
+		{"configured stale threshold", around(91), withConfig(cfg, func(c *config.Config) { c.LifecycleStaleDays = 90 }), LifecycleStale},
+		{"configured active threshold", around(31), withConfig(cfg, func(c *config.Config) { c.LifecycleActiveDays = 60 }), LifecycleActive},
+		{
+			name: "substantive commit age",
+			repo: Repository{DaysSinceLastCommit: 5, LastSubstantiveCommitDate: testDaysAgo(200), DaysSinceSubstantiveCommit: 200,
+				ContributorDataComplete: true, TotalContributors: 4, InactivePercentage: 0.25},
+			cfg:  withConfig(cfg, func(c *config.Config) { c.FlagOnSubstantiveCommit = true }),
+			want: LifecycleStale,
+		},
+		{"archived whatever its age", Repository{Archived: true, DaysSinceLastCommit: 1}, cfg, LifecycleArchived},
+		{"restricted", Repository{CommitStatus: CommitStatusRestricted}, cfg, ""},
+		{"commits not collected", around(10), withConfig(cfg, func(c *config.Config) { c.Metrics = []string{MetricContributors} }), ""},
+		{"not classified", around(10), withConfig(cfg, func(c *config.Config) { c.Lifecycle = false }), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := tt.repo
+			FlagRepository(&repo, tt.cfg)
+			if repo.Lifecycle != tt.want {
+				t.Errorf("lifecycle = %q, want %q", repo.Lifecycle, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountLifecycleStages(t *testing.T) {
+	repos := []Repository{
+		{Lifecycle: LifecycleActive},
+		{Lifecycle: LifecycleActive},
+		{Lifecycle: LifecycleAbandoned},
+		{Lifecycle: LifecycleArchived},
+		{},
+	}
+	counts := countLifecycleStages(repos)
+	want := map[string]int{LifecycleActive: 2, LifecycleMaintenance: 0, LifecycleStale: 0, LifecycleAbandoned: 1, LifecycleArchived: 1}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("countLifecycleStages = %v, want %v", counts, want)
+	}
+	if got := lifecycleSummary(counts); got != "2 active, 0 maintenance, 0 stale, 1 abandoned, 1 archived" {
+		t.Errorf("lifecycleSummary = %q", got)
+	}
+}
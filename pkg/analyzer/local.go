@@ -0,0 +1,211 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// localAuthor is one commit author of a local clone, identified by e-mail address
+type localAuthor struct {
+	email         string
+	lastCommit    time.Time
+	recentCommits int // Commits in the weighting window, for weighted contributors
+}
+
+// localHistory summarizes the commit log of a local clone
+type localHistory struct {
+	authors     []localAuthor // Commit authors other than bots, most recently active first
+	firstCommit time.Time     // Date of the oldest commit
+	lastCommit  time.Time     // Date of the newest commit
+}
+
+// runGit runs git in a local clone and returns its standard output, with git's message in the error
+func runGit(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// AnalyzeLocalRepository collects the commit and contributor metrics of a local git clone with git log
+// instead of the GitHub API, and flags it like AnalyzeRepository does
+// A clone has no archived status or organization to check membership against, so a contributor counts as
+// inactive when their last commit is older than the contributor window, and the first commit stands in
+// for the creation date. The checked-out branch is analyzed, and with a path only the commits touching it count.
+func AnalyzeLocalRepository(dir string, cfg config.Config) (Repository, []Warning, error) {
+	now := Now()
+	warnings := &warningLog{}
+
+	top, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return Repository{}, nil, fmt.Errorf("%s is not a git clone: %w", dir, err)
+	}
+	top = bytes.TrimSpace(top)
+
+	r := Repository{
+		Name: localRepositoryName(string(top)),
+		Path: cfg.Path,
+	}
+
+	// Other metrics come from the GitHub API, which a local analysis does without
+	for _, metric := range MetricNames {
+		if metric != MetricCommits && metric != MetricContributors && collects(cfg, metric) {
+			warnings.warn(cfg, r.Name, "The %s metric needs the GitHub API and is not collected for a local clone", metric)
+		}
+	}
+
+	history, err := readLocalHistory(string(top), now.AddDate(0, 0, -weightWindowDays), cfg)
+	if err != nil {
+		return r, warnings.list(), err
+	}
+
+	if history.lastCommit.IsZero() {
+		// A clone without commits is reported like an empty repository on GitHub
+		r.CommitStatus = CommitStatusEmpty
+	} else {
+		if collects(cfg, MetricCommits) {
+			r.LastCommitDate = history.lastCommit
+			r.DaysSinceLastCommit = int(now.Sub(history.lastCommit).Hours() / 24)
+		}
+		r.CreatedAt = history.firstCommit
+		r.RepoAgeDays = int(now.Sub(history.firstCommit).Hours() / 24)
+	}
+
+	if collects(cfg, MetricContributors) {
+		classifyLocalAuthors(&r, history.authors, now, cfg)
+	}
+
+	for _, note := range r.Validate() {
+		warnings.warn(cfg, r.Name, "Corrected impossible metric for %s: %s", r.Name, note)
+	}
+	FlagRepository(&r, cfg)
+	return r, warnings.list(), nil
+}
+
+// localRepositoryName names a clone after the owner/repo of its origin remote, or after its directory
+// when it has no origin
+func localRepositoryName(top string) string {
+	out, err := runGit(top, "remote", "get-url", "origin")
+	if err == nil {
+		remote := strings.TrimSuffix(strings.TrimSpace(string(out)), ".git")
+		// Both https://host/owner/repo and git@host:owner/repo end with owner/repo
+		parts := strings.FieldsFunc(remote, func(c rune) bool { return c == '/' || c == ':' })
+		if len(parts) >= 2 {
+			return parts[len(parts)-2] + "/" + parts[len(parts)-1]
+		}
+	}
+	return filepath.Base(top)
+}
+
+// readLocalHistory reads the commit log of the checked-out branch, limited to the commits touching the path if any
+// Bots are left out of the authors, as they are not people who can leave. Each author's commits after
+// weightSince are counted for weighted contributors.
+func readLocalHistory(top string, weightSince time.Time, cfg config.Config) (localHistory, error) {
+	var history localHistory
+
+	// Without any commit there is no HEAD to log
+	if _, err := runGit(top, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return history, nil
+	}
+
+	args := []string{"log", "--format=%aE%x09%aN%x09%cI", "HEAD"}
+	if cfg.Path != "" {
+		args = append(args, "--", cfg.Path)
+	}
+	out, err := runGit(top, args...)
+	if err != nil {
+		return history, fmt.Errorf("failed to read the commit log: %w", err)
+	}
+
+	byEmail := make(map[string]*localAuthor)
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, fields[2])
+		if err != nil {
+			return history, fmt.Errorf("failed to parse commit date %q: %w", fields[2], err)
+		}
+		if history.firstCommit.IsZero() || date.Before(history.firstCommit) {
+			history.firstCommit = date
+		}
+		if date.After(history.lastCommit) {
+			history.lastCommit = date
+		}
+
+		email, name := strings.ToLower(fields[0]), fields[1]
+		if strings.HasSuffix(name, "[bot]") || strings.HasSuffix(email, "[bot]@users.noreply.github.com") {
+			continue
+		}
+		a, ok := byEmail[email]
+		if !ok {
+			a = &localAuthor{email: email, lastCommit: date}
+			byEmail[email] = a
+		} else if date.After(a.lastCommit) {
+			a.lastCommit = date
+		}
+		if date.After(weightSince) {
+			a.recentCommits++
+		}
+	}
+
+	for _, a := range byEmail {
+		history.authors = append(history.authors, *a)
+	}
+	sort.Slice(history.authors, func(i, j int) bool { return history.authors[i].lastCommit.After(history.authors[j].lastCommit) })
+	return history, nil
+}
+
+// classifyLocalAuthors records the contributor counts of a clone, counting the authors without a commit
+// in the contributor window as inactive
+func classifyLocalAuthors(r *Repository, authors []localAuthor, now time.Time, cfg config.Config) {
+	since := now.AddDate(0, 0, -cfg.ContributorWindowDays())
+
+	var active, inactive []string
+	var details []InactiveContributor
+	counts := make(map[string]int, len(authors))
+	for _, a := range authors {
+		counts[a.email] = a.recentCommits
+		if a.lastCommit.After(since) {
+			active = append(active, a.email)
+			continue
+		}
+		lastCommit := a.lastCommit
+		inactive = append(inactive, a.email)
+		details = append(details, InactiveContributor{Login: a.email, Reason: InactiveReasonNoRecentCommit, LastCommitDate: &lastCommit})
+	}
+
+	r.ContributorDataComplete = true
+	r.TotalContributors = len(authors)
+	r.InactiveContributors = len(details)
+	if r.TotalContributors > 0 {
+		r.InactivePercentage = float64(r.InactiveContributors) / float64(r.TotalContributors)
+	}
+	if cfg.WeightedContributors {
+		if ratio, ok := weightedInactiveRatio(counts, active, inactive); ok {
+			r.WeightedInactivePercentage = &ratio
+		}
+	}
+	if describesContributors(cfg) {
+		r.InactiveContributorDetails = details
+	}
+	if reportsContributors(cfg) {
+		r.ActiveContributorLogins = active
+		r.DepartedContributors = details
+	}
+}
@@ -0,0 +1,184 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// fixtureCommit is a commit made in a fixture clone
+type fixtureCommit struct {
+	author  string // "Name <email>"
+	daysAgo int    // before testNow
+	file    string // file the commit touches
+}
+
+// gitFixture creates a git clone in a temporary directory with the given commits, oldest first,
+// and an origin remote when one is given
+func gitFixture(t *testing.T, origin string, commits ...fixtureCommit) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := filepath.Join(t.TempDir(), "clone")
+
+	git := func(env []string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1")
+		cmd.Env = append(cmd.Env, env...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	git(nil, "init", "--quiet", "--initial-branch=main")
+	if origin != "" {
+		git(nil, "remote", "add", "origin", origin)
+	}
+	for i, c := range commits {
+		path := filepath.Join(dir, c.file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString("change\n")
+		f.Close()
+
+		date := testNow.AddDate(0, 0, -c.daysAgo).Format(time.RFC3339)
+		env := []string{"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date,
+			"GIT_COMMITTER_NAME=Committer", "GIT_COMMITTER_EMAIL=committer@example.com"}
+		git(env, "add", c.file)
+		git(env, "commit", "--quiet", "--no-gpg-sign", "--author", c.author, "-m", fmt.Sprintf("commit %d", i+1))
+	}
+	return dir
+}
+
+func TestAnalyzeLocalRepository(t *testing.T) {
+	pinNow(t)
+	cfg := config.Config{MaxCommitAgeInDays: 180, InactiveContribThreshold: 0.5}
+
+	tests := []struct {
+		name         string
+		origin       string
+		commits      []fixtureCommit
+		cfg          config.Config
+		wantName     string
+		wantDays     int
+		wantTotal    int
+		wantInactive int
+		wantReason   string
+		wantAgeDays  int
+		wantStatus   string
+	}{
+		{
+			name:   "abandoned",
+			origin: "git@github.com:acme/legacy.git",
+			commits: []fixtureCommit{
+				{"Ann <ann@example.com>", 900, "main.go"},
+				{"Bob <bob@example.com>", 400, "main.go"},
+			},
+			cfg:          cfg,
+			wantName:     "acme/legacy",
+			wantDays:     400,
+			wantTotal:    2,
+			wantInactive: 2,
+			wantReason:   FlagReasonOldInactiveContributors,
+			wantAgeDays:  900,
+		},
+		{
+			name:   "active",
+			origin: "https://github.com/acme/api.git",
+			commits: []fixtureCommit{
+				{"Ann <ann@example.com>", 400, "main.go"},
+				{"Bob <bob@example.com>", 10, "main.go"},
+			},
+			cfg:          cfg,
+			wantName:     "acme/api",
+			wantDays:     10,
+			wantTotal:    2,
+			wantInactive: 1,
+			wantAgeDays:  400,
+		},
+		{
+			name: "bots left out",
+			commits: []fixtureCommit{
+				{"Ann <ann@example.com>", 300, "main.go"},
+				{"dependabot[bot] <49699333+dependabot[bot]@users.noreply.github.com>", 5, "go.mod"},
+			},
+			cfg:          cfg,
+			wantName:     "clone",
+			wantDays:     5,
+			wantTotal:    1,
+			wantInactive: 1,
+			wantAgeDays:  300,
+		},
+		{
+			name: "path scoped",
+			commits: []fixtureCommit{
+				{"Ann <ann@example.com>", 500, "docs/guide.md"},
+				{"Bob <bob@example.com>", 3, "main.go"},
+			},
+			cfg:          withConfig(cfg, func(c *config.Config) { c.Path = "docs" }),
+			wantName:     "clone",
+			wantDays:     500,
+			wantTotal:    1,
+			wantInactive: 1,
+			wantReason:   FlagReasonOldInactiveContributors,
+			wantAgeDays:  500,
+		},
+		{
+			name:       "empty",
+			cfg:        cfg,
+			wantName:   "clone",
+			wantStatus: CommitStatusEmpty,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := gitFixture(t, tt.origin, tt.commits...)
+
+			repo, _, err := AnalyzeLocalRepository(dir, tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if repo.Name != tt.wantName {
+				t.Errorf("name = %q, want %q", repo.Name, tt.wantName)
+			}
+			if repo.CommitStatus != tt.wantStatus {
+				t.Errorf("commit status = %q, want %q", repo.CommitStatus, tt.wantStatus)
+			}
+			if repo.DaysSinceLastCommit != tt.wantDays || repo.RepoAgeDays != tt.wantAgeDays {
+				t.Errorf("days since last commit %d and age %d, want %d and %d", repo.DaysSinceLastCommit, repo.RepoAgeDays, tt.wantDays, tt.wantAgeDays)
+			}
+			if repo.TotalContributors != tt.wantTotal || repo.InactiveContributors != tt.wantInactive || !repo.ContributorDataComplete {
+				t.Errorf("contributors %d, inactive %d (complete %v), want %d and %d", repo.TotalContributors, repo.InactiveContributors, repo.ContributorDataComplete, tt.wantTotal, tt.wantInactive)
+			}
+			if repo.FlagReason != tt.wantReason {
+				t.Errorf("flag reason = %q, want %q", repo.FlagReason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestAnalyzeLocalRepositoryNotAClone(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CEILING_DIRECTORIES", os.TempDir())
+
+	if _, _, err := AnalyzeLocalRepository(t.TempDir(), config.Config{MaxCommitAgeInDays: 180}); err == nil {
+		t.Error("AnalyzeLocalRepository succeeded outside a git clone")
+	}
+}
@@ -0,0 +1,25 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// logOutput receives diagnostic output such as progress, warnings, and saved-file notices
+var logOutput io.Writer = os.Stdout
+
+// SetLogOutput redirects diagnostic output, keeping reports written to stdout clean
+func SetLogOutput(w io.Writer) {
+	logOutput = w
+}
+
+// LogOutput returns the writer receiving diagnostic output
+func LogOutput() io.Writer {
+	return logOutput
+}
+
+// Logf writes a diagnostic message to the configured log output
+func Logf(format string, args ...interface{}) {
+	fmt.Fprintf(logOutput, format, args...)
+}
@@ -0,0 +1,44 @@
+package analyzer
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// captureLog redirects diagnostic output to a buffer for the duration of a test
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	SetLogOutput(&buf)
+	t.Cleanup(func() { SetLogOutput(os.Stdout) })
+	return &buf
+}
+
+func TestLogOutput(t *testing.T) {
+	if LogOutput() != os.Stdout {
+		t.Errorf("diagnostics go to %v by default, want stdout", LogOutput())
+	}
+
+	tests := []struct {
+		name string
+		log  func()
+		want string
+	}{
+		{"message", func() { Logf("📄 Fetching page %d of repositories...\n", 2) }, "📄 Fetching page 2 of repositories...\n"},
+		{"warning", func() { (&warningLog{}).warn(config.Config{}, "o/r", "Failed to get admins for %s", "o/r") }, "⚠️ Warning: Failed to get admins for o/r\n"},
+		{"silent warning", func() { (&warningLog{}).warn(config.Config{Silent: true}, "o/r", "Failed to get admins") }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := captureLog(t)
+
+			tt.log()
+			if got := buf.String(); got != tt.want {
+				t.Errorf("logged %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
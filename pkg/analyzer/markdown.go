@@ -0,0 +1,257 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func init() {
+	RegisterFormatter("markdown", markdownFormatter{})
+	RegisterFormatter("md", markdownFormatter{})
+}
+
+// Markdown report styles
+const (
+	MarkdownStyleTable   = "table"
+	MarkdownStyleDetails = "details"
+)
+
+// IsMarkdownFormat reports whether the output format is the Markdown report
+func IsMarkdownFormat(format string) bool {
+	return format == "markdown" || format == "md"
+}
+
+// renderMarkdown builds the Markdown report, either as a table of all repositories
+// or as one collapsible details block per flagged repository, optionally sectioned by flag reason
+// The totals come from the summary, so they stay true when the listed repositories are limited
+func renderMarkdown(repos []Repository, summary Summary) []byte {
+	cfg := summary.Config
+
+	var buf bytes.Buffer
+	if subject := reportSubject(cfg); subject != "" {
+		buf.WriteString(fmt.Sprintf("## Repository Inactivity Report for %s\n\n", markdownEscape(subject)))
+	} else {
+		buf.WriteString("## Repository Inactivity Report\n\n")
+	}
+	buf.WriteString(fmt.Sprintf("- **Date:** %s\n", Now().Format("2006-01-02")))
+	if cfg.Organization != "" {
+		buf.WriteString(fmt.Sprintf("- **Visibility:** %s\n", cfg.Visibility))
+	}
+	buf.WriteString(fmt.Sprintf("- **Total repositories analyzed:** %d\n", summary.Total))
+	buf.WriteString(fmt.Sprintf("- **Flagged repositories:** %d (%d inactive, %d archived)\n", summary.Flagged, summary.Inactive, summary.Archived))
+	if summary.Lifecycle != nil {
+		buf.WriteString(fmt.Sprintf("- **Lifecycle:** %s\n", lifecycleSummary(summary.Lifecycle)))
+	}
+	buf.WriteString(fmt.Sprintf("- **Report ID:** `%s`\n", reportIDSummary(repos, cfg)))
+	if note := topNote(repos, summary); note != "" {
+		buf.WriteString(fmt.Sprintf("- **Note:** %s\n", note))
+	}
+	if note := deadlineNote(summary); note != "" {
+		buf.WriteString(fmt.Sprintf("- **Note:** %s\n", note))
+	}
+	if !cfg.Silent {
+		buf.WriteString(fmt.Sprintf("- **Legend:** %s\n", markdownEscape(flagLegend(cfg))))
+	}
+	buf.WriteString("\n")
+
+	if cfg.GroupByReason {
+		for _, group := range groupByFlagReason(repos) {
+			buf.WriteString(fmt.Sprintf("### %s (%d)\n\n", markdownEscape(flagReasonHeading(group.Reason)), len(group.Repos)))
+			if cfg.MarkdownStyle == MarkdownStyleDetails {
+				for _, repo := range group.Repos {
+					writeMarkdownDetails(&buf, repo, cfg)
+				}
+			} else {
+				writeMarkdownTable(&buf, group.Repos)
+				buf.WriteString("\n")
+			}
+		}
+		writeMarkdownDuplicates(&buf, summary.Duplicates)
+		writeMarkdownWarnings(&buf, summary.Warnings)
+		writeMarkdownNextSteps(&buf, summary.Flagged, cfg)
+		return buf.Bytes()
+	}
+
+	if cfg.MarkdownStyle == MarkdownStyleDetails {
+		for _, repo := range repos {
+			if repo.Flagged {
+				writeMarkdownDetails(&buf, repo, cfg)
+			}
+		}
+		writeMarkdownDuplicates(&buf, summary.Duplicates)
+		writeMarkdownWarnings(&buf, summary.Warnings)
+		writeMarkdownNextSteps(&buf, summary.Flagged, cfg)
+		return buf.Bytes()
+	}
+
+	writeMarkdownTable(&buf, repos)
+
+	if len(summary.Duplicates) > 0 {
+		buf.WriteString("\n")
+		writeMarkdownDuplicates(&buf, summary.Duplicates)
+	}
+
+	if len(summary.Warnings) > 0 {
+		buf.WriteString("\n")
+		writeMarkdownWarnings(&buf, summary.Warnings)
+	}
+
+	if steps := nextSteps(summary.Flagged, cfg); len(steps) > 0 {
+		buf.WriteString("\n")
+		writeMarkdownNextSteps(&buf, summary.Flagged, cfg)
+	}
+
+	return buf.Bytes()
+}
+
+// writeMarkdownTable writes the repositories as a table, marking the flagged ones
+func writeMarkdownTable(buf *bytes.Buffer, repos []Repository) {
+	buf.WriteString("| | Repository | Last Commit | Days | Contributors | License | Reason |\n")
+	buf.WriteString("|---|---|---|---:|---|---|---|\n")
+	for _, repo := range repos {
+		marker := ""
+		if repo.Flagged {
+			marker = "🚩"
+		}
+		buf.WriteString(fmt.Sprintf("| %s | %s | %s | %d | %s | %s | %s |\n",
+			marker,
+			markdownEscape(displayName(repo)),
+			lastCommitDateText(repo),
+			repo.DaysSinceLastCommit,
+			contributorSummary(repo),
+			markdownEscape(repo.License),
+			markdownEscape(repo.FlagReason+priorityMarker(repo))))
+	}
+}
+
+// writeMarkdownDuplicates writes the likely duplicate clusters among the flagged repositories, if any
+func writeMarkdownDuplicates(buf *bytes.Buffer, duplicates []DuplicateCluster) {
+	if len(duplicates) == 0 {
+		return
+	}
+
+	buf.WriteString("### Likely duplicates\n\n")
+	for _, c := range duplicates {
+		buf.WriteString(fmt.Sprintf("- %s\n", markdownEscape(duplicateClusterSummary(c))))
+	}
+	buf.WriteString("\n")
+}
+
+// writeMarkdownWarnings writes the warnings met during the run, if any
+func writeMarkdownWarnings(buf *bytes.Buffer, warnings []Warning) {
+	if len(warnings) == 0 {
+		return
+	}
+
+	buf.WriteString("### Warnings\n\n")
+	for _, warning := range warnings {
+		buf.WriteString(fmt.Sprintf("- %s\n", markdownEscape(warningSummary(warning))))
+	}
+	buf.WriteString("\n")
+}
+
+// writeMarkdownNextSteps writes the suggested next steps for the flagged repositories, if any
+func writeMarkdownNextSteps(buf *bytes.Buffer, flagged int, cfg config.Config) {
+	steps := nextSteps(flagged, cfg)
+	if len(steps) == 0 {
+		return
+	}
+
+	buf.WriteString("### Next Steps\n\n")
+	for _, step := range steps {
+		buf.WriteString(fmt.Sprintf("- %s\n", markdownEscape(step)))
+	}
+}
+
+// writeMarkdownDetails writes a flagged repository as a collapsible details block
+// whose summary line names the repository and reason, with the metrics as the body
+func writeMarkdownDetails(buf *bytes.Buffer, repo Repository, cfg config.Config) {
+	buf.WriteString("<details>\n")
+	buf.WriteString(fmt.Sprintf("<summary><strong>%s</strong>: %s (%d days since last commit)</summary>\n\n",
+		html.EscapeString(displayName(repo)), html.EscapeString(repo.FlagReason+priorityMarker(repo)), repo.DaysSinceLastCommit))
+
+	buf.WriteString(fmt.Sprintf("- **Last commit:** %s\n", lastCommitSummary(repo, cfg)))
+	if repo.Lifecycle != "" {
+		buf.WriteString(fmt.Sprintf("- **Lifecycle:** %s\n", repo.Lifecycle))
+	}
+	if repo.LastSubstantiveCommitDate != nil {
+		buf.WriteString(fmt.Sprintf("- **Last substantive commit:** %s\n", substantiveCommitSummary(repo, cfg)))
+	}
+	if repo.SignedCommitRatio != nil {
+		buf.WriteString(fmt.Sprintf("- **Signed commits:** %s\n", signedCommitSummary(repo)))
+	}
+	buf.WriteString(fmt.Sprintf("- **Contributors:** %s\n", contributorSummary(repo)))
+	if len(repo.InactiveContributorDetails) > 0 {
+		buf.WriteString(fmt.Sprintf("- **Inactive contributors:** %s\n",
+			markdownEscape(inactiveContributorSummary(repo.InactiveContributorDetails))))
+	}
+	if !repo.CreatedAt.IsZero() {
+		buf.WriteString(fmt.Sprintf("- **Created:** %s\n", repoAgeSummary(repo, cfg)))
+	}
+	buf.WriteString(fmt.Sprintf("- **License:** %s\n", markdownEscape(repo.License)))
+	if repo.HasReadme != nil {
+		buf.WriteString(fmt.Sprintf("- **README:** %s\n", readmeSummary(repo)))
+	}
+	if repo.LastCIStatus != "" {
+		buf.WriteString(fmt.Sprintf("- **CI:** %s\n", markdownEscape(ciSummary(repo))))
+	}
+	if repo.OpenSecurityAlerts != nil || repo.SecurityAlertsUnknown {
+		buf.WriteString(fmt.Sprintf("- **Open security alerts:** %s\n", securityAlertSummary(repo)))
+	}
+	if collects(cfg, MetricReviews) {
+		buf.WriteString(fmt.Sprintf("- **Last merged PR:** %s\n", mergedPRSummary(repo, cfg)))
+	}
+	if collects(cfg, MetricEngagement) {
+		buf.WriteString(fmt.Sprintf("- **Issue engagement:** %s\n", engagementSummary(repo)))
+	}
+	if collects(cfg, MetricTags) {
+		buf.WriteString(fmt.Sprintf("- **Last tag:** %s\n", markdownEscape(tagSummary(repo, cfg))))
+	}
+	if collects(cfg, MetricBranches) {
+		buf.WriteString(fmt.Sprintf("- **Newest other branch:** %s\n", markdownEscape(newestBranchSummary(repo, cfg))))
+	}
+	if repo.CommitMomentum != nil {
+		buf.WriteString(fmt.Sprintf("- **Momentum:** %s\n", momentumSummary(repo)))
+	}
+	if repo.MedianCommitGapDays != nil {
+		buf.WriteString(fmt.Sprintf("- **Cadence:** %s\n", cadenceSummary(repo, cfg)))
+	}
+	if repo.StaleBotPRCount != nil {
+		buf.WriteString(fmt.Sprintf("- **Stale bot PRs:** %s\n", staleBotPRSummary(repo, cfg)))
+	}
+	if cfg.Governance {
+		buf.WriteString(fmt.Sprintf("- **Governance:** %s\n", markdownEscape(governanceSummary(repo))))
+	}
+	if len(repo.Admins) > 0 {
+		buf.WriteString(fmt.Sprintf("- **Admins:** %s\n", markdownEscape(strings.Join(repo.Admins, ", "))))
+	}
+	if repo.Contact != "" {
+		buf.WriteString(fmt.Sprintf("- **Contact:** %s\n", markdownEscape(repo.Contact)))
+	}
+	if repo.Archived {
+		buf.WriteString("- **Repository Status:** Archived\n")
+	} else {
+		buf.WriteString("- **Repository Status:** Not Archived\n")
+	}
+
+	buf.WriteString("\n</details>\n\n")
+}
+
+// markdownEscape escapes text so that embedded HTML and table separators render literally
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(html.EscapeString(s), "|", "\\|")
+}
+
+// markdownFormatter renders the Markdown report
+type markdownFormatter struct{}
+
+// Format writes the Markdown report
+func (markdownFormatter) Format(w io.Writer, repos []Repository, summary Summary) error {
+	_, err := w.Write(renderMarkdown(repos, summary))
+	return err
+}
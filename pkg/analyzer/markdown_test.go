@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	repos := []Repository{
+		{Name: "o/active", LastCommitDate: testNow, License: "MIT", ContributorDataComplete: true, TotalContributors: 2},
+		{Name: "o/<old>", LastCommitDate: testNow.AddDate(-1, 0, 0), DaysSinceLastCommit: 365, License: LicenseNone,
+			Flagged: true, FlagReason: FlagReasonOldInactiveContributors, Admins: []string{"ann"}},
+	}
+	cfg := config.Config{Organization: "o", Visibility: "all", Silent: true}
+
+	tests := []struct {
+		name    string
+		style   string
+		want    []string
+		wantNot []string
+	}{
+		{
+			name:  "table",
+			style: MarkdownStyleTable,
+			want: []string{
+				"## Repository Inactivity Report for o\n",
+				"- **Date:** 2025-06-01\n",
+				"- **Flagged repositories:** 1 (1 inactive, 0 archived)\n",
+				"| | Repository | Last Commit | Days | Contributors | License | Reason |\n",
+				"|  | o/active | 2025-06-01 | 0 | 2 total, 0 inactive (0.0%) | MIT |  |\n",
+				"| 🚩 | o/&lt;old&gt; | 2024-06-01 | 365 | data unavailable | none | old+inactive-contributors |\n",
+			},
+			wantNot: []string{"<details>"},
+		},
+		{
+			name:  "details",
+			style: MarkdownStyleDetails,
+			want: []string{
+				"<details>\n<summary><strong>o/&lt;old&gt;</strong>: old+inactive-contributors (365 days since last commit)</summary>\n\n",
+				"- **License:** none\n",
+				"- **Admins:** ann\n",
+				"- **Repository Status:** Not Archived\n\n</details>\n",
+			},
+			wantNot: []string{"o/active", "| Repository |"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pinNow(t)
+			cfg := withConfig(cfg, func(c *config.Config) { c.MarkdownStyle = tt.style })
+			got := string(renderMarkdown(repos, newSummary(repos, 0, nil, nil, false, cfg)))
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("report does not contain %q:\n%s", want, got)
+				}
+			}
+			for _, unwanted := range tt.wantNot {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("report contains %q:\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkdownEscape(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"o/r", "o/r"},
+		{"a|b", `a\|b`},
+		{"<script>&", "&lt;script&gt;&amp;"},
+	}
+	for _, tt := range tests {
+		if got := markdownEscape(tt.s); got != tt.want {
+			t.Errorf("markdownEscape(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// MemberSet holds the logins of an organization's members, in lower case, for classifying contributors
+// without one membership call each
+type MemberSet map[string]bool
+
+// NewMemberSet builds a member set from member logins
+func NewMemberSet(logins []string) MemberSet {
+	members := make(MemberSet, len(logins))
+	for _, login := range logins {
+		members[strings.ToLower(login)] = true
+	}
+	return members
+}
+
+// Contains reports whether a login belongs to a member; logins are matched case-insensitively, as GitHub logins are
+func (s MemberSet) Contains(login string) bool {
+	return s[strings.ToLower(login)]
+}
+
+// orgMemberList is the member list of one organization, fetched once per run
+type orgMemberList struct {
+	once    sync.Once
+	members MemberSet
+	err     error
+}
+
+// orgMemberLists holds the member lists fetched or injected during the run, keyed by lower-case organization
+// and whether only public members were listed
+var orgMemberLists = struct {
+	mu    sync.Mutex
+	lists map[string]*orgMemberList
+}{lists: make(map[string]*orgMemberList)}
+
+// orgMemberListKey identifies the member list of an organization, or of its public members only
+func orgMemberListKey(orgName string, publicOnly bool) string {
+	return fmt.Sprintf("%s public=%t", strings.ToLower(orgName), publicOnly)
+}
+
+// SetOrgMembers injects the member set of an organization, which the members API then classifies
+// contributors with instead of listing the members itself
+func SetOrgMembers(orgName string, members MemberSet) {
+	list := &orgMemberList{members: members}
+	list.once.Do(func() {})
+
+	orgMemberLists.mu.Lock()
+	defer orgMemberLists.mu.Unlock()
+	orgMemberLists.lists[orgMemberListKey(orgName, false)] = list
+	orgMemberLists.lists[orgMemberListKey(orgName, true)] = list
+}
+
+// GetOrgMembers lists every page of an organization's members, or of its public members only when publicOnly is set
+func GetOrgMembers(orgName string, publicOnly bool) (MemberSet, error) {
+	endpoint := "members"
+	if publicOnly {
+		endpoint = "public_members"
+	}
+
+	out, err := runGH("api",
+		fmt.Sprintf("orgs/%s/%s?per_page=100", orgName, endpoint),
+		"--paginate", "--jq", ".[].login")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members of %s: %w", orgName, err)
+	}
+	return NewMemberSet(parseLogins(string(out))), nil
+}
+
+// orgMemberSet returns the member set of an organization, listing the members on first use, or false
+// when they cannot be listed, in which case the caller checks contributors one by one instead
+func orgMemberSet(orgName string, publicOnly bool, cfg config.Config, warnings *warningLog) (MemberSet, bool) {
+	key := orgMemberListKey(orgName, publicOnly)
+	orgMemberLists.mu.Lock()
+	list, ok := orgMemberLists.lists[key]
+	if !ok {
+		list = &orgMemberList{}
+		orgMemberLists.lists[key] = list
+	}
+	orgMemberLists.mu.Unlock()
+
+	list.once.Do(func() {
+		list.members, list.err = GetOrgMembers(orgName, publicOnly)
+		if list.err != nil {
+			warnings.warn(cfg, "", "Cannot list the members of %s, checking each contributor instead: %v", orgName, list.err)
+		}
+	})
+	return list.members, list.err == nil
+}
+
+// classifyByMemberSet splits the contributors into members and non-members by looking them up in a member set
+func classifyByMemberSet(members MemberSet, contributors []string) (active, inactive []string) {
+	for _, contributor := range contributors {
+		if members.Contains(contributor) {
+			active = append(active, contributor)
+		} else {
+			// User is not in the organization anymore
+			inactive = append(inactive, contributor)
+		}
+	}
+	return active, inactive
+}
@@ -0,0 +1,135 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// resetOrgMembers forgets the member lists fetched or injected by earlier tests
+func resetOrgMembers(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		orgMemberLists.mu.Lock()
+		orgMemberLists.lists = make(map[string]*orgMemberList)
+		orgMemberLists.mu.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestMemberSet(t *testing.T) {
+	members := NewMemberSet([]string{"Ann", "bob"})
+	tests := []struct {
+		login string
+		want  bool
+	}{
+		{"ann", true},
+		{"ANN", true},
+		{"Bob", true},
+		{"cy", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := members.Contains(tt.login); got != tt.want {
+			t.Errorf("Contains(%q) = %v, want %v", tt.login, got, tt.want)
+		}
+	}
+}
+
+func TestGetContributorsStatusMemberList(t *testing.T) {
+	// ann and Bob are members, Bob with a differently cased login; cy and dee left
+	memberList := `printf 'ann\nbob\n'`
+
+	tests := []struct {
+		name         string
+		api          string
+		memberList   string
+		inject       MemberSet
+		wantListed   int
+		wantPerCall  bool
+		wantWarnings int
+	}{
+		{"per contributor", APIREST, memberList, nil, 0, true, 0},
+		{"member list", APIMembers, memberList, nil, 1, false, 0},
+		{"member list unavailable", APIMembers, `echo 'gh: Forbidden (HTTP 403)' >&2; exit 1`, nil, 1, true, 1},
+		{"injected member set", APIMembers, memberList, NewMemberSet([]string{"ANN", "bob"}), 0, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, `case "$*" in
+*"orgs/o/members?per_page=100"*) `+tt.memberList+`;;
+*user/memberships*) echo '{"state":"active"}';;
+*contributors*) printf 'ann\nBob\ncy\ndee\n';;
+*members/ann*|*members/Bob*) exit 0;;
+*) echo 'gh: Not Found (HTTP 404)' >&2; exit 1;;
+esac`)
+			SetCacheTTL(0)
+			fastMembershipRetries(t)
+			resetOrgMembers(t)
+			warnings := &warningLog{}
+			if tt.inject != nil {
+				SetOrgMembers("o", tt.inject)
+			}
+
+			// Classifying two repositories of the organization lists its members once
+			cfg := config.Config{API: tt.api, Silent: true}
+			for _, repo := range []string{"o/r", "o/s"} {
+				active, inactive, err := getContributorsStatus(repo, "o", cfg, warnings)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// Every path gives the same classification
+				if !reflect.DeepEqual(active, []string{"ann", "Bob"}) || !reflect.DeepEqual(inactive, []string{"cy", "dee"}) {
+					t.Errorf("%s: active %q and inactive %q, want ann, Bob and cy, dee", repo, active, inactive)
+				}
+			}
+
+			var listed int
+			var perCall bool
+			for _, call := range ghCalls(t, logPath) {
+				if strings.Contains(call, "orgs/o/members?per_page=100") {
+					listed++
+					if !strings.Contains(call, "--paginate") {
+						t.Errorf("call %q does not page through the members", call)
+					}
+				}
+				perCall = perCall || strings.Contains(call, "orgs/o/members/")
+			}
+			if listed != tt.wantListed || perCall != tt.wantPerCall {
+				t.Errorf("listed the members %d times and checked per contributor %v, want %d and %v",
+					listed, perCall, tt.wantListed, tt.wantPerCall)
+			}
+			if got := len(warnings.list()); got != tt.wantWarnings {
+				t.Errorf("recorded %d warnings, want %d", got, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestGetContributorsStatusPublicMemberList(t *testing.T) {
+	// Outside the organization only its public members can be listed
+	logPath := fakeGH(t, `case "$*" in
+*"orgs/o/public_members?per_page=100"*) echo ann;;
+*user/memberships*) echo 'gh: Not Found (HTTP 404)' >&2; exit 1;;
+*contributors*) printf 'ann\nbob\n';;
+*) echo "unexpected call: $*" >&2; exit 1;;
+esac`)
+	SetCacheTTL(0)
+	fastMembershipRetries(t)
+	resetOrgMembers(t)
+
+	cfg := config.Config{API: APIMembers, MembershipFallback: MembershipFallbackPublic, Silent: true}
+	active, inactive, err := GetContributorsStatus("o/r", "o", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(active, []string{"ann"}) || !reflect.DeepEqual(inactive, []string{"bob"}) {
+		t.Errorf("active %q and inactive %q, want ann and bob", active, inactive)
+	}
+	if calls := ghCalls(t, logPath); len(calls) != 3 {
+		t.Errorf("made %d calls, want the memberships, contributors and public member list: %q", len(calls), calls)
+	}
+}
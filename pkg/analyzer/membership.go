@@ -0,0 +1,119 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// Fallbacks when the caller cannot see private organization membership
+const (
+	MembershipFallbackUnknown = "unknown"
+	MembershipFallbackPublic  = "public"
+)
+
+// ErrMembershipUnavailable is returned when the caller cannot see private organization membership,
+// so non-members cannot be told apart from private members
+var ErrMembershipUnavailable = errors.New("organization membership not visible to the caller")
+
+// membershipAttempts is how many times a membership check is tried before giving up
+const membershipAttempts = 3
+
+// membershipRetryDelay is the base delay between membership check retries
+var membershipRetryDelay = time.Second
+
+// checkOrgMembership reports whether a user is a member of the organization, or only a public member when publicOnly is set
+// A 404 means the user is not a member, other failures are retried and then returned
+func checkOrgMembership(orgName, login string, publicOnly bool) (bool, error) {
+	var lastErr error
+
+	endpoint := "members"
+	if publicOnly {
+		endpoint = "public_members"
+	}
+
+	for attempt := 1; attempt <= membershipAttempts; attempt++ {
+		_, err := runGH("api",
+			fmt.Sprintf("orgs/%s/%s/%s", orgName, endpoint, login),
+			"--silent")
+		if err == nil {
+			return true, nil
+		}
+
+		if StatusCode(err) == http.StatusNotFound {
+			return false, nil
+		}
+
+		lastErr = err
+		if attempt < membershipAttempts {
+			time.Sleep(membershipRetryDelay * time.Duration(attempt))
+		}
+	}
+
+	return false, fmt.Errorf("failed to check membership of %s in %s: %w", login, orgName, lastErr)
+}
+
+// membershipVisibility caches, per organization, whether the caller can see private membership
+var membershipVisibility = struct {
+	mu      sync.Mutex
+	results map[string]bool
+}{results: make(map[string]bool)}
+
+// canReadOrgMembership reports whether the caller can see the organization's private members
+// Only organization members can, others are redirected to the public member list, where
+// private members look like non-members. GitHub App installations are assumed to have been
+// granted the members permission.
+func canReadOrgMembership(orgName string, cfg config.Config, warnings *warningLog) (bool, error) {
+	if tokenSource != nil {
+		return true, nil
+	}
+
+	key := strings.ToLower(orgName)
+	membershipVisibility.mu.Lock()
+	visible, ok := membershipVisibility.results[key]
+	membershipVisibility.mu.Unlock()
+	if ok {
+		return visible, nil
+	}
+
+	out, err := runGH("api", fmt.Sprintf("user/memberships/orgs/%s", orgName))
+	switch {
+	case err == nil:
+		visible = parseMembershipState(out) == "active"
+	case StatusCode(err) == http.StatusNotFound || StatusCode(err) == http.StatusForbidden:
+		visible = false
+	default:
+		return false, fmt.Errorf("failed to check access to %s membership: %w", orgName, err)
+	}
+
+	membershipVisibility.mu.Lock()
+	membershipVisibility.results[key] = visible
+	membershipVisibility.mu.Unlock()
+
+	if !visible {
+		if cfg.MembershipFallback == MembershipFallbackPublic {
+			warnings.warn(cfg, "", "Not a member of %s, so only public membership can be checked", orgName)
+		} else {
+			warnings.warn(cfg, "", "Not a member of %s, so contributor activity is reported as unknown", orgName)
+		}
+	}
+
+	return visible, nil
+}
+
+// parseMembershipState returns the state (active or pending) of an organization membership response
+func parseMembershipState(data []byte) string {
+	var membership struct {
+		State string `json:"state"`
+	}
+	if err := json.Unmarshal(data, &membership); err != nil {
+		return ""
+	}
+	return membership.State
+}
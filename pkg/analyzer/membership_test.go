@@ -0,0 +1,183 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// fastMembershipRetries removes the delay between membership check retries and forgets the
+// membership visibility cached by earlier tests
+func fastMembershipRetries(t *testing.T) {
+	t.Helper()
+	previous := membershipRetryDelay
+	membershipRetryDelay = 0
+	reset := func() {
+		membershipVisibility.mu.Lock()
+		membershipVisibility.results = make(map[string]bool)
+		membershipVisibility.mu.Unlock()
+	}
+	reset()
+	t.Cleanup(func() {
+		membershipRetryDelay = previous
+		reset()
+	})
+}
+
+// flakyAfter fails the first calls matching pattern, as many as failures, with a server error
+func flakyAfter(pattern string, failures int) string {
+	return fmt.Sprintf(`if [ $(grep -c '%s' "$GH_LOG") -le %d ]; then echo 'gh: Bad Gateway (HTTP 502)' >&2; exit 1; fi`, pattern, failures)
+}
+
+func TestCheckOrgMembership(t *testing.T) {
+	tests := []struct {
+		name       string
+		script     string
+		publicOnly bool
+		want       bool
+		wantErr    bool
+		wantCalls  int
+	}{
+		{"member", `exit 0`, false, true, false, 1},
+		{"not a member", `echo 'gh: Not Found (HTTP 404)' >&2; exit 1`, false, false, false, 1},
+		{"transient failure retried", flakyAfter("members/ann", 2) + `; exit 0`, false, true, false, 3},
+		{"persistent failure", `echo 'gh: Bad Gateway (HTTP 502)' >&2; exit 1`, false, false, true, membershipAttempts},
+		{"public members", `case "$*" in *public_members/ann*) exit 0;; esac; exit 1`, true, true, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			SetCacheTTL(0)
+			fastMembershipRetries(t)
+
+			got, err := checkOrgMembership("o", "ann", tt.publicOnly)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkOrgMembership error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "failed to check membership of ann in o") {
+				t.Errorf("error = %v, want the login and organization", err)
+			}
+			if got != tt.want {
+				t.Errorf("member = %v, want %v", got, tt.want)
+			}
+			if calls := ghCalls(t, logPath); len(calls) != tt.wantCalls {
+				t.Errorf("made %d calls, want %d: %q", len(calls), tt.wantCalls, calls)
+			}
+		})
+	}
+}
+
+func TestGetContributorsStatusUndetermined(t *testing.T) {
+	// ann is a member, bob left, and cy's check keeps failing
+	const script = `case "$*" in
+*user/memberships*) echo '{"state":"active"}';;
+*contributors*) printf 'ann\nbob\ncy\n';;
+*members/ann*) exit 0;;
+*members/bob*) echo 'gh: Not Found (HTTP 404)' >&2; exit 1;;
+*) echo 'gh: Bad Gateway (HTTP 502)' >&2; exit 1;;
+esac`
+
+	tests := []struct {
+		name         string
+		strict       bool
+		wantActive   []string
+		wantInactive []string
+		wantErr      bool
+	}{
+		{"left out of the ratio", false, []string{"ann"}, []string{"bob"}, false},
+		{"strict", true, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeGH(t, script)
+			SetCacheTTL(0)
+			fastMembershipRetries(t)
+
+			active, inactive, err := GetContributorsStatus("o/r", "o", config.Config{Strict: tt.strict, Silent: true})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetContributorsStatus error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(active, tt.wantActive) || !reflect.DeepEqual(inactive, tt.wantInactive) {
+				t.Errorf("active %q and inactive %q, want %q and %q", active, inactive, tt.wantActive, tt.wantInactive)
+			}
+		})
+	}
+}
+
+func TestGetContributorsStatusMembershipHidden(t *testing.T) {
+	// Outside the organization private members look like non-members, so only ann's public membership shows
+	tests := []struct {
+		name         string
+		memberships  string
+		fallback     string
+		wantActive   []string
+		wantInactive []string
+		wantErr      error
+		wantCall     string
+	}{
+		{
+			name:         "member of the organization",
+			memberships:  `echo '{"state":"active"}'`,
+			wantActive:   []string{"ann", "bob"},
+			wantInactive: []string{"cy"},
+			wantCall:     "orgs/o/members/bob",
+		},
+		{
+			name:        "pending invitation",
+			memberships: `echo '{"state":"pending"}'`,
+			wantErr:     ErrMembershipUnavailable,
+		},
+		{
+			name:        "not a member",
+			memberships: `echo 'gh: Not Found (HTTP 404)' >&2; exit 1`,
+			wantErr:     ErrMembershipUnavailable,
+		},
+		{
+			name:        "membership read denied",
+			memberships: `echo 'gh: Forbidden (HTTP 403)' >&2; exit 1`,
+			wantErr:     ErrMembershipUnavailable,
+		},
+		{
+			name:         "public membership fallback",
+			memberships:  `echo 'gh: Not Found (HTTP 404)' >&2; exit 1`,
+			fallback:     MembershipFallbackPublic,
+			wantActive:   []string{"ann"},
+			wantInactive: []string{"bob", "cy"},
+			wantCall:     "orgs/o/public_members/bob",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, `case "$*" in
+*user/memberships*) `+tt.memberships+`;;
+*contributors*) printf 'ann\nbob\ncy\n';;
+*public_members/ann*) exit 0;;
+*public_members/*) echo 'gh: Not Found (HTTP 404)' >&2; exit 1;;
+*members/ann*|*members/bob*) exit 0;;
+*) echo 'gh: Not Found (HTTP 404)' >&2; exit 1;;
+esac`)
+			SetCacheTTL(0)
+			fastMembershipRetries(t)
+
+			cfg := config.Config{MembershipFallback: tt.fallback, Silent: true}
+			active, inactive, err := GetContributorsStatus("o/r", "o", cfg)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetContributorsStatus error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(active, tt.wantActive) || !reflect.DeepEqual(inactive, tt.wantInactive) {
+				t.Errorf("active %q and inactive %q, want %q and %q", active, inactive, tt.wantActive, tt.wantInactive)
+			}
+			calls := strings.Join(ghCalls(t, logPath), "\n")
+			if tt.wantCall != "" && !strings.Contains(calls, tt.wantCall) {
+				t.Errorf("gh calls %q do not include %s", calls, tt.wantCall)
+			}
+			if tt.wantErr != nil && strings.Contains(calls, "members/") {
+				t.Errorf("checked members whose membership is hidden: %q", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// LicenseNone is the license recorded for repositories without a detected license
+const LicenseNone = "none"
+
+// RepositoryMetadata holds the repository attributes returned by the repos endpoint
+type RepositoryMetadata struct {
+	Archived       bool      `json:"archived"`
+	HasIssues      bool      `json:"has_issues"`
+	HasDiscussions bool      `json:"has_discussions"`
+	DefaultBranch  string    `json:"default_branch"`
+	Visibility     string    `json:"visibility"`
+	CreatedAt      time.Time `json:"created_at"`
+	PushedAt       time.Time `json:"pushed_at"`
+	License        *struct {
+		SPDXID string `json:"spdx_id"`
+	} `json:"license"`
+	Owner struct {
+		Type string `json:"type"`
+	} `json:"owner"`
+	Parent *struct {
+		FullName string `json:"full_name"`
+	} `json:"parent"`
+}
+
+// LicenseID returns the SPDX identifier of the repository license, or LicenseNone when there is none
+func (m RepositoryMetadata) LicenseID() string {
+	if m.License == nil || m.License.SPDXID == "" {
+		return LicenseNone
+	}
+	return m.License.SPDXID
+}
+
+// OwnedByUser reports whether the repository belongs to a personal account rather than an organization
+func (m RepositoryMetadata) OwnedByUser() bool {
+	return m.Owner.Type == "User"
+}
+
+// ParentName returns the full name of the repository a fork was made from, or "" when it is not a fork
+func (m RepositoryMetadata) ParentName() string {
+	if m.Parent == nil {
+		return ""
+	}
+	return m.Parent.FullName
+}
+
+// GetRepositoryMetadata retrieves the repository attributes used for flagging in a single call
+func GetRepositoryMetadata(repoFullName string) (RepositoryMetadata, error) {
+	meta, err := runGHParsed(parseRepositoryMetadata, "api",
+		fmt.Sprintf("repos/%s", repoFullName))
+	if err != nil {
+		return RepositoryMetadata{}, fmt.Errorf("failed to get repository metadata: %w", err)
+	}
+	return meta, nil
+}
+
+// parseRepositoryMetadata decodes the repos endpoint response
+func parseRepositoryMetadata(data []byte) (RepositoryMetadata, error) {
+	var meta RepositoryMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return RepositoryMetadata{}, fmt.Errorf("failed to parse repository metadata: %w", err)
+	}
+	return meta, nil
+}
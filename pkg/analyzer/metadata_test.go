@@ -0,0 +1,101 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetRepositoryMetadata(t *testing.T) {
+	tests := []struct {
+		name            string
+		response        string
+		wantIssues      bool
+		wantDiscussions bool
+		wantLicense     string
+		wantUserOwned   bool
+		wantParent      string
+	}{
+		{
+			name:            "organization repository",
+			response:        `{"has_issues":true,"has_discussions":true,"license":{"spdx_id":"MIT"},"owner":{"type":"Organization"}}`,
+			wantIssues:      true,
+			wantDiscussions: true,
+			wantLicense:     "MIT",
+		},
+		{
+			name:        "issues and discussions disabled",
+			response:    `{"has_issues":false,"has_discussions":false,"license":null,"owner":{"type":"Organization"}}`,
+			wantLicense: LicenseNone,
+		},
+		{
+			name:          "personal fork",
+			response:      `{"has_issues":true,"license":{"spdx_id":""},"owner":{"type":"User"},"parent":{"full_name":"up/r"}}`,
+			wantIssues:    true,
+			wantLicense:   LicenseNone,
+			wantUserOwned: true,
+			wantParent:    "up/r",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeGH(t, "echo '"+tt.response+"'")
+			SetCacheTTL(0)
+
+			meta, err := GetRepositoryMetadata("o/r")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if meta.HasIssues != tt.wantIssues || meta.HasDiscussions != tt.wantDiscussions {
+				t.Errorf("issues %v and discussions %v, want %v and %v", meta.HasIssues, meta.HasDiscussions, tt.wantIssues, tt.wantDiscussions)
+			}
+			if got := meta.LicenseID(); got != tt.wantLicense {
+				t.Errorf("LicenseID = %q, want %q", got, tt.wantLicense)
+			}
+			if got := meta.OwnedByUser(); got != tt.wantUserOwned {
+				t.Errorf("OwnedByUser = %v, want %v", got, tt.wantUserOwned)
+			}
+			if got := meta.ParentName(); got != tt.wantParent {
+				t.Errorf("ParentName = %q, want %q", got, tt.wantParent)
+			}
+		})
+	}
+}
+
+func TestGetRepositoryMetadataInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		wantErr string
+	}{
+		{"malformed", `echo 'not json'`, "failed to parse repository metadata"},
+		{"failure", `echo 'gh: Not Found (HTTP 404)' >&2; exit 1`, "failed to get repository metadata"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeGH(t, tt.script)
+			SetCacheTTL(0)
+			if _, err := GetRepositoryMetadata("o/r"); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("GetRepositoryMetadata error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGovernanceSummary(t *testing.T) {
+	tests := []struct {
+		name string
+		repo Repository
+		want string
+	}{
+		{"disabled", Repository{}, "issues disabled, discussions disabled, outside collaborators unknown"},
+		{"enabled", Repository{IssuesEnabled: true, HasDiscussions: true, Visibility: "public", OutsideCollaborators: intPtr(2)},
+			"issues enabled, discussions enabled, public, 2 outside collaborators"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := governanceSummary(tt.repo); got != tt.want {
+				t.Errorf("governanceSummary = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
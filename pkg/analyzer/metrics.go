@@ -0,0 +1,120 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// Per-repository metrics that can be selected with -metrics
+const (
+	MetricCommits      = "commits"
+	MetricContributors = "contributors"
+	MetricSubstantive  = "substantive"
+	MetricSigning      = "signing"
+	MetricCI           = "ci"
+	MetricSecurity     = "security"
+	MetricReviews      = "reviews"
+	MetricMomentum     = "momentum"
+	MetricTags         = "tags"
+	MetricEngagement   = "engagement"
+	MetricBranches     = "branches"
+	MetricBotPRs       = "bot-prs"
+	MetricCadence      = "cadence"
+	MetricReadme       = "readme"
+)
+
+// MetricNames lists the selectable metrics
+var MetricNames = []string{MetricCommits, MetricContributors, MetricSubstantive, MetricSigning, MetricCI, MetricSecurity, MetricReviews, MetricMomentum, MetricTags, MetricEngagement, MetricBranches, MetricBotPRs, MetricCadence, MetricReadme}
+
+// defaultMetrics are collected when no metrics are selected
+var defaultMetrics = []string{MetricCommits, MetricContributors}
+
+// ParseMetrics splits and validates a comma-separated metric list
+func ParseMetrics(list string) ([]string, error) {
+	valid := make(map[string]bool)
+	for _, name := range MetricNames {
+		valid[name] = true
+	}
+
+	var metrics, unknown []string
+	for _, metric := range strings.Split(list, ",") {
+		metric = strings.TrimSpace(metric)
+		if metric == "" {
+			continue
+		}
+		if !valid[metric] {
+			unknown = append(unknown, metric)
+			continue
+		}
+		metrics = append(metrics, metric)
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown metric(s) %s, valid metrics are: %s",
+			strings.Join(unknown, ", "), strings.Join(MetricNames, ", "))
+	}
+
+	return metrics, nil
+}
+
+// MetricsHelp describes the -metrics option with every selectable metric
+func MetricsHelp() string {
+	return fmt.Sprintf("Comma-separated metrics to collect per repository, replacing the default %s: %s; without %s no repository is flagged as old",
+		strings.Join(defaultMetrics, ","), strings.Join(MetricNames, ", "), MetricCommits)
+}
+
+// CheckMetrics returns a warning when the selected metrics leave out the last commit, without which no repository is flagged as old
+func CheckMetrics(cfg config.Config) []Warning {
+	if len(cfg.Metrics) == 0 || collects(cfg, MetricCommits) {
+		return nil
+	}
+	warnings := &warningLog{}
+	warnings.warn(cfg, "", "The selected metrics leave out %s, so no repository is flagged as old (add %s to -metrics)", MetricCommits, MetricCommits)
+	return warnings.list()
+}
+
+// collects reports whether a metric is collected, either selected with -metrics
+// or required by an option that depends on it
+func collects(cfg config.Config, metric string) bool {
+	selected := cfg.Metrics
+	if len(selected) == 0 {
+		selected = defaultMetrics
+	}
+	for _, name := range selected {
+		if name == metric {
+			return true
+		}
+	}
+
+	switch metric {
+	case MetricSubstantive:
+		return cfg.SubstantiveCommits || cfg.FlagOnSubstantiveCommit
+	case MetricSigning:
+		return cfg.SignedCommits || cfg.MinSignedRatio > 0
+	case MetricCI:
+		return cfg.CIStatus || cfg.FlagBrokenCI
+	case MetricSecurity:
+		return cfg.Security
+	case MetricReviews:
+		return cfg.MergedPRs || cfg.FlagStaleReviews
+	case MetricMomentum:
+		return cfg.Momentum || cfg.FlagDeclining
+	case MetricTags:
+		return cfg.RecentTags
+	case MetricEngagement:
+		return cfg.Engagement || cfg.FlagUnresponsive
+	case MetricBranches:
+		return cfg.ActiveBranches
+	case MetricBotPRs:
+		return cfg.BotPRs || cfg.FlagStaleBotPRs
+	case MetricCadence:
+		return cfg.Cadence || cfg.FlagOnCadence
+	case MetricReadme:
+		return cfg.Readme || cfg.PrioritizeUndocumented
+	case MetricContributors:
+		return describesContributors(cfg)
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestParseMetrics(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    []string
+		wantErr string
+	}{
+		{"", nil, ""},
+		{"commits", []string{MetricCommits}, ""},
+		{" commits , ci,,releases", nil, "unknown metric(s) releases"},
+		{"commits,contributors,ci", []string{MetricCommits, MetricContributors, MetricCI}, ""},
+		{"prs,bogus", nil, "unknown metric(s) prs, bogus"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.list, func(t *testing.T) {
+			got, err := ParseMetrics(tt.list)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseMetrics error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMetrics = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollects(t *testing.T) {
+	commitsOnly := config.Config{Metrics: []string{MetricCommits}}
+	tests := []struct {
+		name   string
+		cfg    config.Config
+		metric string
+		want   bool
+	}{
+		{"commits by default", config.Config{}, MetricCommits, true},
+		{"contributors by default", config.Config{}, MetricContributors, true},
+		{"CI not by default", config.Config{}, MetricCI, false},
+		{"selected", config.Config{Metrics: []string{MetricCI}}, MetricCI, true},
+		{"default replaced", commitsOnly, MetricContributors, false},
+		{"required by an option", withConfig(commitsOnly, func(c *config.Config) { c.FlagBrokenCI = true }), MetricCI, true},
+		{"required by a flag", withConfig(commitsOnly, func(c *config.Config) { c.MinSignedRatio = 0.5 }), MetricSigning, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := collects(tt.cfg, tt.metric); got != tt.want {
+				t.Errorf("collects(%s) = %v, want %v", tt.metric, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetricsHelp(t *testing.T) {
+	help := MetricsHelp()
+	for _, name := range MetricNames {
+		if !strings.Contains(help, name) {
+			t.Errorf("help %q does not list the %s metric", help, name)
+		}
+	}
+	if !strings.Contains(help, "replacing the default commits,contributors") {
+		t.Errorf("help %q does not say selected metrics replace the default", help)
+	}
+}
+
+func TestCheckMetrics(t *testing.T) {
+	tests := []struct {
+		name         string
+		metrics      []string
+		wantWarnings int
+	}{
+		{"default", nil, 0},
+		{"commits selected", []string{MetricCommits, MetricCI}, 0},
+		{"commits left out", []string{MetricContributors, MetricCI}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(CheckMetrics(config.Config{Metrics: tt.metrics, Silent: true})); got != tt.wantWarnings {
+				t.Errorf("returned %d warnings, want %d", got, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepositoryMetrics(t *testing.T) {
+	script := fmt.Sprintf(`case "$*" in
+*actions/runs*) echo '{"workflow_runs":[{"status":"completed","conclusion":"success","created_at":"2025-05-01T00:00:00Z"}]}';;
+*user/memberships*) echo '{"state":"active"}';;
+*contributors*) printf 'cy\ndee\n';;
+*members/*) echo 'gh: Not Found (HTTP 404)' >&2; exit 1;;
+*commits*) echo %s;;
+"api repos/o/r") echo '{}';;
+*) echo "unexpected call: $*" >&2; exit 1;;
+esac`, testDaysAgo(10).Format(time.RFC3339))
+
+	tests := []struct {
+		name             string
+		metrics          []string
+		wantContributors bool
+		wantCI           bool
+	}{
+		{"default", nil, true, false},
+		{"commits only", []string{MetricCommits}, false, false},
+		{"commits and CI", []string{MetricCommits, MetricCI}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pinNow(t)
+			logPath := fakeGH(t, script)
+			SetCacheTTL(0)
+			fastMembershipRetries(t)
+
+			cfg := withConfig(flaggingConfig, func(c *config.Config) { c.Metrics = tt.metrics; c.Silent = true })
+			repo, _, err := AnalyzeRepository("o/r", cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if repo.DaysSinceLastCommit != 10 {
+				t.Errorf("days since the last commit = %d, want 10", repo.DaysSinceLastCommit)
+			}
+
+			var contributorCalls, ciCalls bool
+			for _, call := range ghCalls(t, logPath) {
+				contributorCalls = contributorCalls || strings.Contains(call, "contributors")
+				ciCalls = ciCalls || strings.Contains(call, "actions/runs")
+			}
+			if contributorCalls != tt.wantContributors || (repo.TotalContributors > 0) != tt.wantContributors {
+				t.Errorf("listed contributors = %v with %d counted, want %v", contributorCalls, repo.TotalContributors, tt.wantContributors)
+			}
+			if ciCalls != tt.wantCI || (repo.LastCIStatus != "") != tt.wantCI {
+				t.Errorf("read CI runs = %v with status %q, want %v", ciCalls, repo.LastCIStatus, tt.wantCI)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// momentumWindowDays is the length of each of the two windows compared for commit momentum
+const momentumWindowDays = 90
+
+// GetCommitMomentum returns the commits on the branch (empty means default) in the last 90 days
+// minus the commits in the 90 days before, so a negative value means activity is slowing down
+func GetCommitMomentum(repoFullName, branch string, now time.Time) (int, error) {
+	windowStart := now.AddDate(0, 0, -momentumWindowDays)
+
+	recent, err := countCommits(repoFullName, branch, windowStart, now)
+	if err != nil {
+		return 0, err
+	}
+	previous, err := countCommits(repoFullName, branch, windowStart.AddDate(0, 0, -momentumWindowDays), windowStart)
+	if err != nil {
+		return 0, err
+	}
+
+	return commitMomentum(recent, previous), nil
+}
+
+// commitMomentum is the change in commit count from the previous window to the recent one
+func commitMomentum(recent, previous int) int {
+	return recent - previous
+}
+
+// countCommits counts the commits on the branch (empty means default) made between since and until
+func countCommits(repoFullName, branch string, since, until time.Time) (int, error) {
+	endpoint := commitsEndpoint(repoFullName, branch)
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+
+	count, err := runGHParsed(parseCommitCount, "api",
+		fmt.Sprintf("%s%ssince=%s&until=%s&per_page=100", endpoint, separator,
+			url.QueryEscape(since.UTC().Format(time.RFC3339)), url.QueryEscape(until.UTC().Format(time.RFC3339))),
+		"--paginate", "--jq", "length")
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return 0, fmt.Errorf("failed to count commits: %w", err)
+	}
+	return count, err
+}
+
+// parseCommitCount sums the per-page commit counts printed by a paginated length query
+func parseCommitCount(data []byte) (int, error) {
+	total := 0
+	for _, line := range bytes.Fields(data) {
+		n, err := strconv.Atoi(string(line))
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse commit count: %w", err)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// isDeclining reports whether commit momentum has dropped by at least minDrop commits
+// Repositories whose momentum was not measured are never declining
+func isDeclining(r Repository, minDrop int) bool {
+	return r.CommitMomentum != nil && *r.CommitMomentum <= -minDrop
+}
@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetCommitMomentum(t *testing.T) {
+	// The recent window starts 2025-03-03 and the previous one 2024-12-03
+	tests := []struct {
+		name     string
+		recent   string
+		previous string
+		branch   string
+		want     int
+		wantErr  bool
+		wantCall string
+	}{
+		{"declining", "echo 4", `printf '100\n20\n'`, "", -116, false, "repos/o/r/commits?since=2025-03-03T00%3A00%3A00Z&until=2025-06-01T00%3A00%3A00Z"},
+		{"growing", `printf '100\n5\n'`, "echo 30", "", 75, false, ""},
+		{"no commits", "echo 0", "exit 0", "", 0, false, ""},
+		{"on a branch", "echo 3", "echo 1", "dev", 2, false, "sha=dev&since="},
+		{"failure", "echo 4", `echo 'gh: Server Error (HTTP 500)' >&2; exit 1`, "", 0, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, `case "$*" in
+*since=2025-03-03*) `+tt.recent+`;;
+*since=2024-12-03*) `+tt.previous+`;;
+*) echo "unexpected call: $*" >&2; exit 1;;
+esac`)
+			SetCacheTTL(0)
+
+			got, err := GetCommitMomentum("o/r", tt.branch, testNow)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetCommitMomentum error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "failed to count commits") {
+				t.Errorf("error = %v, want the failed count reported", err)
+			}
+			if got != tt.want {
+				t.Errorf("momentum = %d, want %d", got, tt.want)
+			}
+			if calls := ghCalls(t, logPath); !strings.Contains(calls[0], tt.wantCall) {
+				t.Errorf("gh call %q, want %s", calls[0], tt.wantCall)
+			}
+		})
+	}
+}
+
+func TestIsDeclining(t *testing.T) {
+	tests := []struct {
+		name     string
+		momentum *int
+		want     bool
+	}{
+		{"not measured", nil, false},
+		{"growing", intPtr(5), false},
+		{"small drop", intPtr(-19), false},
+		{"drop at the threshold", intPtr(-20), true},
+		{"large drop", intPtr(-40), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDeclining(Repository{CommitMomentum: tt.momentum}, 20); got != tt.want {
+				t.Errorf("isDeclining = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+func init() {
+	RegisterFormatter("ndjson", ndjsonFormatter{})
+}
+
+// ndjsonMeta is the first line of an NDJSON report, announcing how many repositories follow
+type ndjsonMeta struct {
+	Type         string `json:"type"`
+	Total        int    `json:"total"`
+	Organization string `json:"organization"`
+}
+
+// ndjsonRepository is one repository line of an NDJSON report
+type ndjsonRepository struct {
+	Type string `json:"type"`
+	Repository
+}
+
+// ndjsonSummary is the last line of an NDJSON report
+type ndjsonSummary struct {
+	Type            string `json:"type"`
+	Total           int    `json:"total"`
+	Flagged         int    `json:"flagged"`
+	FlaggedInactive int    `json:"flaggedInactive"`
+	Archived        int    `json:"archived"`
+
+	// Lifecycle counts the repositories per lifecycle stage, when repositories are classified
+	Lifecycle map[string]int `json:"lifecycle,omitempty"`
+}
+
+// IsNDJSONFormat reports whether the output format is the NDJSON stream
+func IsNDJSONFormat(format string) bool {
+	return format == "ndjson"
+}
+
+// NDJSONStream writes an NDJSON report line by line through a ResultWriter,
+// so repositories can be streamed as they are analyzed
+type NDJSONStream struct {
+	rw        *ResultWriter
+	mu        sync.Mutex
+	count     int
+	flagged   int
+	archived  int
+	lifecycle map[string]int
+}
+
+// NewNDJSONStream creates a stream writing to the given result writer
+func NewNDJSONStream(rw *ResultWriter) *NDJSONStream {
+	return &NDJSONStream{rw: rw}
+}
+
+// WriteMeta writes the meta line announcing how many repositories follow
+func (s *NDJSONStream) WriteMeta(total int, organization string) error {
+	if err := s.rw.WriteJSON(ndjsonMeta{Type: "meta", Total: total, Organization: organization}); err != nil {
+		return fmt.Errorf("failed to write NDJSON meta: %w", err)
+	}
+	return nil
+}
+
+// WriteRepository writes one repository line; it is safe for concurrent use
+func (s *NDJSONStream) WriteRepository(repo Repository) error {
+	s.mu.Lock()
+	s.count++
+	if repo.Flagged {
+		s.flagged++
+	}
+	if isFlaggedArchived(repo) {
+		s.archived++
+	}
+	if repo.Lifecycle != "" {
+		if s.lifecycle == nil {
+			s.lifecycle = countLifecycleStages(nil)
+		}
+		s.lifecycle[repo.Lifecycle]++
+	}
+	s.mu.Unlock()
+
+	if err := s.rw.WriteJSON(ndjsonRepository{Type: "repo", Repository: repo}); err != nil {
+		return fmt.Errorf("failed to write NDJSON repository: %w", err)
+	}
+	return nil
+}
+
+// WriteSummary writes the final summary line with the totals seen by the stream
+func (s *NDJSONStream) WriteSummary() error {
+	s.mu.Lock()
+	summary := ndjsonSummary{
+		Type:            "summary",
+		Total:           s.count,
+		Flagged:         s.flagged,
+		FlaggedInactive: s.flagged - s.archived,
+		Archived:        s.archived,
+		Lifecycle:       s.lifecycle,
+	}
+	s.mu.Unlock()
+
+	if err := s.rw.WriteJSON(summary); err != nil {
+		return fmt.Errorf("failed to write NDJSON summary: %w", err)
+	}
+	return nil
+}
+
+// WriteNDJSON writes a meta line, one line per repository, and a summary line
+func WriteNDJSON(w io.Writer, repos []Repository, organization string) error {
+	stream := NewNDJSONStream(NewResultWriter(w))
+
+	if err := stream.WriteMeta(len(repos), organization); err != nil {
+		return err
+	}
+	for _, repo := range repos {
+		if err := stream.WriteRepository(repo); err != nil {
+			return err
+		}
+	}
+	return stream.WriteSummary()
+}
+
+// renderNDJSON renders the NDJSON report into memory
+func renderNDJSON(repos []Repository, organization string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteNDJSON(&buf, repos, organization); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ndjsonFormatter renders the NDJSON report
+type ndjsonFormatter struct{}
+
+// Format writes the NDJSON report, taking the organization of a single repository from its name
+func (ndjsonFormatter) Format(w io.Writer, repos []Repository, summary Summary) error {
+	organization := summary.Config.Organization
+	if summary.Single && len(repos) == 1 {
+		organization = strings.SplitN(repos[0].Name, "/", 2)[0]
+	}
+	return WriteNDJSON(w, repos, organization)
+}
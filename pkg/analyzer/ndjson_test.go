@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// ndjsonLines decodes each line of an NDJSON report into a generic object
+func ndjsonLines(t *testing.T, data []byte) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %q is not a JSON object: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		repos       []Repository
+		wantSummary map[string]interface{}
+	}{
+		{
+			name:        "no repositories",
+			wantSummary: map[string]interface{}{"type": "summary", "total": 0.0, "flagged": 0.0, "flaggedInactive": 0.0, "archived": 0.0},
+		},
+		{
+			name: "flagged and archived",
+			repos: []Repository{
+				{Name: "o/a"},
+				{Name: "o/b", Flagged: true, FlagReason: FlagReasonOldInactiveContributors},
+				{Name: "o/c", Flagged: true, FlagReason: FlagReasonArchived},
+			},
+			wantSummary: map[string]interface{}{"type": "summary", "total": 3.0, "flagged": 2.0, "flaggedInactive": 1.0, "archived": 1.0},
+		},
+		{
+			name:  "lifecycle stages",
+			repos: []Repository{{Name: "o/a", Lifecycle: LifecycleActive}, {Name: "o/b", Lifecycle: LifecycleStale}},
+			wantSummary: map[string]interface{}{"type": "summary", "total": 2.0, "flagged": 0.0, "flaggedInactive": 0.0, "archived": 0.0,
+				"lifecycle": map[string]interface{}{"active": 1.0, "maintenance": 0.0, "stale": 1.0, "abandoned": 0.0, "archived": 0.0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := renderNDJSON(tt.repos, "o")
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := ndjsonLines(t, data)
+			if len(lines) != len(tt.repos)+2 {
+				t.Fatalf("report has %d lines, want a meta line, %d repositories, and a summary line", len(lines), len(tt.repos))
+			}
+
+			meta := map[string]interface{}{"type": "meta", "total": float64(len(tt.repos)), "organization": "o"}
+			if !reflect.DeepEqual(lines[0], meta) {
+				t.Errorf("meta line = %v, want %v", lines[0], meta)
+			}
+			for i, repo := range tt.repos {
+				if line := lines[i+1]; line["type"] != "repo" || line["name"] != repo.Name {
+					t.Errorf("line %d = %v, want the %s repository line", i+2, line, repo.Name)
+				}
+			}
+			if summary := lines[len(lines)-1]; !reflect.DeepEqual(summary, tt.wantSummary) {
+				t.Errorf("summary line = %v, want %v", summary, tt.wantSummary)
+			}
+		})
+	}
+}
+
+func TestNDJSONFormatterOrganization(t *testing.T) {
+	tests := []struct {
+		name    string
+		repos   []Repository
+		summary Summary
+		want    string
+	}{
+		{"organization scan", []Repository{{Name: "o/a"}}, Summary{Config: config.Config{Organization: "o"}}, `"organization":"o"`},
+		{"single repository", []Repository{{Name: "p/r"}}, Summary{Single: true}, `"organization":"p"`},
+		{"several repositories", []Repository{{Name: "p/r"}, {Name: "q/r"}}, Summary{}, `"organization":""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := (ndjsonFormatter{}).Format(&b, tt.repos, tt.summary); err != nil {
+				t.Fatal(err)
+			}
+			if meta, _, _ := strings.Cut(b.String(), "\n"); !strings.Contains(meta, tt.want) {
+				t.Errorf("meta line %s does not contain %s", meta, tt.want)
+			}
+		})
+	}
+}
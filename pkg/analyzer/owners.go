@@ -0,0 +1,165 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ContactUnknown is the contact of a flagged repository that no owners map entry covers
+const ContactUnknown = "unknown"
+
+// OwnersMap maps repositories and teams to the contact responsible for them, such as an email address
+// or a Slack handle. Each line of an owners map file is a repository or an @org/team followed by the contact:
+//
+//	# repositories first, then the teams owning them
+//	acme/billing      billing-oncall@acme.com
+//	@acme/platform    #platform-alerts
+//
+// Names are matched case-insensitively. A repository entry takes precedence over its teams.
+type OwnersMap struct {
+	contacts map[string]string // repository full name or @org/team (lower case) to contact
+}
+
+// ownersMap is the owners map of the current run, nil when none is configured
+var ownersMap *OwnersMap
+
+// SetOwnersMap sets the owners map the contacts of flagged repositories are looked up in; nil disables contacts
+func SetOwnersMap(m *OwnersMap) {
+	ownersMap = m
+}
+
+// LoadOwnersMap reads an owners map file
+func LoadOwnersMap(path string) (*OwnersMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read owners map: %w", err)
+	}
+	return parseOwnersMap(data)
+}
+
+// parseOwnersMap parses owners map entries, skipping blank lines and # comments
+func parseOwnersMap(data []byte) (*OwnersMap, error) {
+	m := &OwnersMap{contacts: make(map[string]string)}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("owners map line %d: expected a repository or @org/team followed by a contact", lineNo)
+		}
+		name := strings.TrimPrefix(fields[0], "@")
+		if strings.Count(name, "/") != 1 || strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") {
+			return nil, fmt.Errorf("owners map line %d: %s is not an owner/repo or @org/team name", lineNo, fields[0])
+		}
+
+		key := strings.ToLower(fields[0])
+		if contact, ok := m.contacts[key]; ok && contact != fields[1] {
+			return nil, fmt.Errorf("owners map line %d: %s is already mapped to %s", lineNo, fields[0], contact)
+		}
+		m.contacts[key] = fields[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read owners map: %w", err)
+	}
+
+	return m, nil
+}
+
+// Contact returns the contact of a repository: its own entry if any, otherwise the entry of the first
+// of its teams that has one, or ContactUnknown
+func (m *OwnersMap) Contact(repoFullName string, teams []string) string {
+	if contact, ok := m.contacts[strings.ToLower(repoFullName)]; ok {
+		return contact
+	}
+	owner := repoFullName[:strings.Index(repoFullName, "/")+1]
+	for _, team := range teams {
+		if contact, ok := m.contacts[strings.ToLower("@"+owner+team)]; ok {
+			return contact
+		}
+	}
+	return ContactUnknown
+}
+
+// hasRepository reports whether the repository has an entry of its own, which saves looking up its teams
+func (m *OwnersMap) hasRepository(repoFullName string) bool {
+	_, ok := m.contacts[strings.ToLower(repoFullName)]
+	return ok
+}
+
+// teamPermissionRank orders team permissions from the most to the least ownership they imply
+var teamPermissionRank = map[string]int{"admin": 0, "maintain": 1, "push": 2, "triage": 3, "pull": 4}
+
+// GetRepositoryTeams returns the slugs of the teams with access to a repository, the teams with
+// the most ownership (admin, then maintain, and so on) first; personal repositories have none
+func GetRepositoryTeams(repoFullName string) ([]string, error) {
+	out, err := runGH("api",
+		fmt.Sprintf("repos/%s/teams?per_page=100", repoFullName),
+		"--paginate",
+		"--jq", `.[] | "\(.permission) \(.slug)"`)
+	if err != nil {
+		// Repositories of personal accounts have no teams
+		if StatusCode(err) == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get repository teams: %w", err)
+	}
+	return parseRepositoryTeams(string(out)), nil
+}
+
+// parseRepositoryTeams sorts the "permission slug" lines of the teams of a repository by permission,
+// keeping the listing order among teams with the same permission
+func parseRepositoryTeams(out string) []string {
+	type team struct {
+		slug string
+		rank int
+	}
+	var teams []team
+	for _, line := range parseLogins(out) {
+		permission, slug, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		rank, known := teamPermissionRank[permission]
+		if !known {
+			rank = len(teamPermissionRank)
+		}
+		teams = append(teams, team{slug: slug, rank: rank})
+	}
+	sort.SliceStable(teams, func(i, j int) bool { return teams[i].rank < teams[j].rank })
+
+	slugs := make([]string, len(teams))
+	for i, t := range teams {
+		slugs[i] = t.slug
+	}
+	return slugs
+}
+
+// lookUpContact sets the contact of a flagged repository from the owners map, looking up the teams
+// of the repository only when it has no entry of its own
+func lookUpContact(r *Repository, repoFullName string) error {
+	if ownersMap == nil || !r.Flagged {
+		return nil
+	}
+
+	var teams []string
+	if !ownersMap.hasRepository(repoFullName) {
+		var err error
+		teams, err = GetRepositoryTeams(repoFullName)
+		if err != nil {
+			r.Contact = ContactUnknown
+			return err
+		}
+	}
+	r.Contact = ownersMap.Contact(repoFullName, teams)
+	return nil
+}
@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testOwnersMap is an owners map file with repository and team entries
+const testOwnersMap = `
+# repositories first, then the teams owning them
+acme/billing      billing-oncall@acme.com
+@acme/platform    #platform-alerts
+@acme/web         web@acme.com
+`
+
+func TestOwnersMapContact(t *testing.T) {
+	m, err := parseOwnersMap([]byte(testOwnersMap))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		repo  string
+		teams []string
+		want  string
+	}{
+		{"repository entry", "acme/billing", nil, "billing-oncall@acme.com"},
+		{"repository entry over teams", "Acme/Billing", []string{"platform"}, "billing-oncall@acme.com"},
+		{"first team with an entry", "acme/api", []string{"security", "web", "platform"}, "web@acme.com"},
+		{"team case-insensitive", "acme/api", []string{"Platform"}, "#platform-alerts"},
+		{"team of another organization", "beta/api", []string{"platform"}, ContactUnknown},
+		{"no entry", "acme/api", []string{"security"}, ContactUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Contact(tt.repo, tt.teams); got != tt.want {
+				t.Errorf("Contact(%q, %q) = %q, want %q", tt.repo, tt.teams, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOwnersMapInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"no contact", "acme/billing\n", "line 1: expected a repository or @org/team followed by a contact"},
+		{"extra field", "acme/billing a b\n", "line 1: expected a repository or @org/team"},
+		{"organization only", "# orgs\n@acme a@acme.com\n", "line 2: @acme is not an owner/repo or @org/team name"},
+		{"nested name", "acme/a/b a@acme.com\n", "acme/a/b is not an owner/repo"},
+		{"conflicting contacts", "acme/api a@acme.com\nACME/api b@acme.com\n", "line 2: ACME/api is already mapped to a@acme.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseOwnersMap([]byte(tt.data)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseOwnersMap error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseRepositoryTeams(t *testing.T) {
+	out := "pull readers\nadmin owners\npush devs\ncustom auditors\nmaintain leads\nadmin sre\n"
+	want := []string{"owners", "sre", "leads", "devs", "readers", "auditors"}
+	if got := parseRepositoryTeams(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRepositoryTeams = %q, want %q", got, want)
+	}
+}
+
+func TestLookUpContact(t *testing.T) {
+	m, err := parseOwnersMap([]byte(testOwnersMap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetOwnersMap(m)
+	t.Cleanup(func() { SetOwnersMap(nil) })
+
+	tests := []struct {
+		name        string
+		repo        string
+		flagged     bool
+		script      string
+		wantContact string
+		wantCalls   int
+		wantErr     bool
+	}{
+		{"not flagged", "acme/api", false, `echo "admin web"`, "", 0, false},
+		{"own entry skips teams", "acme/billing", true, `echo "admin web"`, "billing-oncall@acme.com", 0, false},
+		{"from teams", "acme/api", true, `echo "push web"; echo "admin platform"`, "#platform-alerts", 1, false},
+		{"personal repository", "jdoe/api", true, `echo 'gh: Not Found (HTTP 404)' >&2; exit 1`, ContactUnknown, 1, false},
+		{"lookup failure", "acme/api", true, `echo 'gh: Server Error (HTTP 500)' >&2; exit 1`, ContactUnknown, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			SetCacheTTL(0)
+
+			repo := Repository{Name: tt.repo, Flagged: tt.flagged}
+			err := lookUpContact(&repo, tt.repo)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("lookUpContact error = %v, want error %v", err, tt.wantErr)
+			}
+			if repo.Contact != tt.wantContact {
+				t.Errorf("contact = %q, want %q", repo.Contact, tt.wantContact)
+			}
+			if calls := ghCalls(t, logPath); len(calls) != tt.wantCalls {
+				t.Errorf("made %d gh calls, want %d", len(calls), tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// AdminOfMe selects the authenticated user for -admin-of
+const AdminOfMe = "@me"
+
+// GetAuthenticatedLogin returns the login of the user gh is authenticated as
+func GetAuthenticatedLogin() (string, error) {
+	out, err := runGH("api", "user", "--jq", ".login")
+	if err != nil {
+		return "", fmt.Errorf("failed to get the authenticated user: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// HasAdminPermission reports whether a user has admin permission on a repository
+// Users who are not collaborators, or whose permission the caller may not see, are not admins
+func HasAdminPermission(repoFullName, login string) (bool, error) {
+	out, err := runGH("api",
+		fmt.Sprintf("repos/%s/collaborators/%s/permission", repoFullName, login),
+		"--jq", ".permission")
+	if err != nil {
+		switch StatusCode(err) {
+		case http.StatusNotFound, http.StatusForbidden:
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get permission of %s on %s: %w", login, repoFullName, err)
+	}
+	return strings.TrimSpace(string(out)) == "admin", nil
+}
+
+// filterAdminRepositories keeps the organization repositories the -admin-of user administers,
+// so that only repositories they can act on are analyzed; failed checks drop the repository unless strict mode is enabled
+func filterAdminRepositories(names []string, cfg config.Config, warnings *warningLog) ([]string, error) {
+	login := cfg.AdminOf
+	if login == AdminOfMe {
+		var err error
+		if login, err = GetAuthenticatedLogin(); err != nil {
+			return nil, err
+		}
+	}
+
+	var kept []string
+	for _, name := range names {
+		isAdmin, err := HasAdminPermission(fmt.Sprintf("%s/%s", cfg.Organization, name), login)
+		if err != nil {
+			if cfg.Strict {
+				return nil, err
+			}
+			warnings.warn(cfg, fmt.Sprintf("%s/%s", cfg.Organization, name), "%v (skipping)", err)
+			continue
+		}
+		if isAdmin {
+			kept = append(kept, name)
+		}
+	}
+
+	if !cfg.Silent {
+		Logf("🔑 %s administers %d of %d repositories\n", login, len(kept), len(names))
+	}
+	return kept, nil
+}
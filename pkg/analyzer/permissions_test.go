@@ -0,0 +1,91 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestHasAdminPermission(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		want    bool
+		wantErr bool
+	}{
+		{"admin", `echo admin`, true, false},
+		{"write", `echo write`, false, false},
+		{"not a collaborator", `echo 'gh: Not Found (HTTP 404)' >&2; exit 1`, false, false},
+		{"permission hidden", `echo 'gh: Forbidden (HTTP 403)' >&2; exit 1`, false, false},
+		{"failure", `echo 'gh: Server Error (HTTP 500)' >&2; exit 1`, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			SetCacheTTL(0)
+
+			got, err := HasAdminPermission("o/r", "ann")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HasAdminPermission error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("admin = %v, want %v", got, tt.want)
+			}
+			if calls := ghCalls(t, logPath); !strings.Contains(calls[0], "repos/o/r/collaborators/ann/permission") {
+				t.Errorf("gh call %q does not read the permission of ann", calls[0])
+			}
+		})
+	}
+}
+
+func TestFilterAdminRepositories(t *testing.T) {
+	// ann administers a and c, and checking b fails
+	const script = `case "$*" in
+"api user "*) echo ann;;
+*repos/o/a/collaborators/ann/*|*repos/o/c/collaborators/ann/*) echo admin;;
+*repos/o/b/*) echo 'gh: Server Error (HTTP 500)' >&2; exit 1;;
+*) echo read;;
+esac`
+
+	tests := []struct {
+		name         string
+		adminOf      string
+		names        []string
+		strict       bool
+		want         []string
+		wantErr      bool
+		wantUser     bool
+		wantWarnings int
+	}{
+		{"given user", "ann", []string{"a", "c", "d"}, false, []string{"a", "c"}, false, false, 0},
+		{"authenticated user", AdminOfMe, []string{"a", "d"}, false, []string{"a"}, false, true, 0},
+		{"other user", "bob", []string{"a", "c"}, false, nil, false, false, 0},
+		{"failed check skipped", "ann", []string{"a", "b", "c"}, false, []string{"a", "c"}, false, false, 1},
+		{"failed check in strict mode", "ann", []string{"a", "b", "c"}, true, nil, true, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, script)
+			SetCacheTTL(0)
+			warnings := &warningLog{}
+
+			cfg := config.Config{Organization: "o", AdminOf: tt.adminOf, Strict: tt.strict, Silent: true}
+			got, err := filterAdminRepositories(tt.names, cfg, warnings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("filterAdminRepositories error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("kept %q, want %q", got, tt.want)
+			}
+			if got := len(warnings.list()); got != tt.wantWarnings {
+				t.Errorf("recorded %d warnings, want %d", got, tt.wantWarnings)
+			}
+			calls := ghCalls(t, logPath)
+			if asked := strings.HasPrefix(calls[0], "api user "); asked != tt.wantUser {
+				t.Errorf("looked up the authenticated user = %v, want %v", asked, tt.wantUser)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package analyzer
+
+import (
+	"sync"
+)
+
+// ForEach calls fn for every index in [0, n), running up to concurrency calls at a time,
+// and returns once all of the started calls have finished
+// The first error returned by fn stops further calls from starting and is returned.
+// A concurrency of 1 or less calls fn for each index in order on the calling goroutine
+func ForEach(n, concurrency int, fn func(i int) error) error {
+	if concurrency <= 1 {
+		for i := 0; i < n; i++ {
+			if err := fn(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	indexes := make(chan int)
+	stop := make(chan struct{})
+	var stopOnce sync.Once
+	var firstErr error
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := fn(i); err != nil {
+					stopOnce.Do(func() {
+						firstErr = err
+						close(stop)
+					})
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		// A failure already seen wins over a worker ready for the next index
+		select {
+		case <-stop:
+			break feed
+		default:
+		}
+		select {
+		case indexes <- i:
+		case <-stop:
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	return firstErr
+}
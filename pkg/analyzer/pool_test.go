@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEach(t *testing.T) {
+	tests := []struct {
+		name        string
+		n           int
+		concurrency int
+	}{
+		{"nothing", 0, 4},
+		{"sequential", 5, 1},
+		{"unset concurrency", 5, 0},
+		{"concurrent", 20, 4},
+		{"more workers than items", 3, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var running, peak int32
+			seen := make([]int, tt.n)
+			err := ForEach(tt.n, tt.concurrency, func(i int) error {
+				current := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				mu.Lock()
+				seen[i]++
+				if current > peak {
+					peak = current
+				}
+				mu.Unlock()
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, count := range seen {
+				if count != 1 {
+					t.Errorf("item %d ran %d times, want once", i, count)
+				}
+			}
+			limit := int32(tt.concurrency)
+			if limit < 1 {
+				limit = 1
+			}
+			if peak > limit {
+				t.Errorf("%d items ran at once, want at most %d", peak, limit)
+			}
+		})
+	}
+}
+
+func TestForEachStopsAtFirstError(t *testing.T) {
+	failure := errors.New("failed")
+	for _, concurrency := range []int{1, 4} {
+		t.Run(fmt.Sprintf("concurrency %d", concurrency), func(t *testing.T) {
+			var started int32
+			err := ForEach(100, concurrency, func(i int) error {
+				atomic.AddInt32(&started, 1)
+				if i == 2 {
+					return failure
+				}
+				// Keep the other workers busy while the failure is seen
+				time.Sleep(5 * time.Millisecond)
+				return nil
+			})
+			if !errors.Is(err, failure) {
+				t.Errorf("ForEach error = %v, want %v", err, failure)
+			}
+			// Only the calls already running or handed to a worker when the failure was seen may start
+			if got := atomic.LoadInt32(&started); got > int32(3+concurrency) {
+				t.Errorf("started %d calls after a failure at the third, want the rest cancelled", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// ProgressEvent is a machine-readable progress update emitted as a JSON line
+type ProgressEvent struct {
+	Type  string `json:"type"`
+	Done  int    `json:"done"`
+	Total int    `json:"total"`
+	Repo  string `json:"repo"`
+}
+
+// ProgressEmitter writes progress events to a separate file descriptor for integrations
+type ProgressEmitter struct {
+	mu    sync.Mutex
+	w     io.Writer
+	total int
+	done  int
+}
+
+// NewProgressEmitter creates an emitter writing to the given file descriptor
+// It returns nil when fd is 0, and all methods are no-ops on a nil emitter
+func NewProgressEmitter(fd int, total int) *ProgressEmitter {
+	if fd <= 0 {
+		return nil
+	}
+	return &ProgressEmitter{
+		w:     progressFile(fd),
+		total: total,
+	}
+}
+
+// progressFiles holds the file opened on each progress descriptor for the rest of the process
+// A file dropped with its emitter would close the descriptor when garbage collected, under the
+// next emitter or whatever reused the descriptor number.
+var progressFiles = struct {
+	mu    sync.Mutex
+	files map[int]*os.File
+}{files: make(map[int]*os.File)}
+
+// progressFile returns the file writing to a progress descriptor, opening it on first use
+func progressFile(fd int) *os.File {
+	progressFiles.mu.Lock()
+	defer progressFiles.mu.Unlock()
+	f, ok := progressFiles.files[fd]
+	if !ok {
+		f = os.NewFile(uintptr(fd), fmt.Sprintf("progress-fd-%d", fd))
+		progressFiles.files[fd] = f
+	}
+	return f
+}
+
+// RepoCompleted records that a repository has been processed and emits a progress event; it is safe for concurrent use
+func (p *ProgressEmitter) RepoCompleted(repoFullName string) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done++
+	data, err := json.Marshal(ProgressEvent{
+		Type:  "progress",
+		Done:  p.done,
+		Total: p.total,
+		Repo:  repoFullName,
+	})
+	if err != nil {
+		return
+	}
+
+	// Progress events are best effort and never interrupt the analysis
+	_, _ = p.w.Write(append(data, '\n'))
+}
@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewProgressEmitterDisabled(t *testing.T) {
+	for _, fd := range []int{0, -1} {
+		p := NewProgressEmitter(fd, 3)
+		if p != nil {
+			t.Errorf("NewProgressEmitter(%d) = %v, want nil", fd, p)
+		}
+		// A nil emitter ignores progress
+		p.RepoCompleted("o/r")
+	}
+}
+
+func TestProgressEmitterRepoCompleted(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	p := NewProgressEmitter(int(w.Fd()), 2)
+	repos := []string{"o/a", "o/b"}
+	for _, repo := range repos {
+		p.RepoCompleted(repo)
+	}
+
+	scanner := bufio.NewScanner(r)
+	for i, repo := range repos {
+		if !scanner.Scan() {
+			t.Fatalf("missing progress event %d: %v", i+1, scanner.Err())
+		}
+		var event ProgressEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("event %q is not JSON: %v", scanner.Text(), err)
+		}
+		want := ProgressEvent{Type: "progress", Done: i + 1, Total: 2, Repo: repo}
+		if event != want {
+			t.Errorf("event %d = %+v, want %+v", i+1, event, want)
+		}
+	}
+}
+
+func TestProgressEmitterDescriptorOutlivesEmitter(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	fd := int(w.Fd())
+
+	// An emitter dropped after its run must leave the descriptor open for the next one
+	NewProgressEmitter(fd, 1).RepoCompleted("o/a")
+	for range 3 {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	NewProgressEmitter(fd, 1).RepoCompleted("o/b")
+	if err := w.Close(); err != nil {
+		t.Fatalf("progress descriptor closed under the emitters: %v", err)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("read %d progress events, want one from each emitter:\n%s", lines, data)
+	}
+}
@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// statusContext names the commit status and check run published for a repository
+const statusContext = "inactivity"
+
+// Conclusions published for a repository
+const (
+	ConclusionSuccess = "success"
+	ConclusionNeutral = "neutral"
+	ConclusionFailure = "failure"
+)
+
+// StatusConclusion maps a repository's analysis to the conclusion published on it:
+// failure when flagged, neutral when contributor data was incomplete, success otherwise
+func StatusConclusion(r Repository) string {
+	switch {
+	case r.Flagged:
+		return ConclusionFailure
+	case !r.ContributorDataComplete:
+		return ConclusionNeutral
+	default:
+		return ConclusionSuccess
+	}
+}
+
+// statusDescription summarizes the analysis for the published status
+func statusDescription(r Repository) string {
+	switch StatusConclusion(r) {
+	case ConclusionFailure:
+		return fmt.Sprintf("Flagged as inactive (%s)", r.FlagReason)
+	case ConclusionNeutral:
+		return "Active, contributor data incomplete"
+	default:
+		return "Active"
+	}
+}
+
+// PublishStatus publishes the analysis result on the head commit of the analyzed branch
+// GitHub App installations create a check run; other credentials, which cannot create check runs,
+// set a commit status instead, where neutral is published as success since statuses have no neutral state.
+// With dry run enabled, the status is only logged.
+func PublishStatus(r Repository, cfg config.Config) error {
+	sha, err := getHeadSHA(r.Name, r.Branch)
+	if err != nil {
+		return err
+	}
+
+	conclusion := StatusConclusion(r)
+	description := statusDescription(r)
+
+	if cfg.DryRun {
+		Logf("🧪 Dry run: would publish %s (%s) on %s@%.7s\n", conclusion, description, r.Name, sha)
+		return nil
+	}
+
+	if tokenSource != nil {
+		_, err = runGH("api", "--method", "POST",
+			fmt.Sprintf("repos/%s/check-runs", r.Name),
+			"-f", "name="+statusContext,
+			"-f", "head_sha="+sha,
+			"-f", "status=completed",
+			"-f", "conclusion="+conclusion,
+			"-f", "output[title]="+description,
+			"-f", "output[summary]="+description)
+	} else {
+		state := conclusion
+		if state == ConclusionNeutral {
+			state = ConclusionSuccess
+		}
+		_, err = runGH("api", "--method", "POST",
+			fmt.Sprintf("repos/%s/statuses/%s", r.Name, sha),
+			"-f", "state="+state,
+			"-f", "context="+statusContext,
+			"-f", "description="+description)
+	}
+	if err != nil {
+		if code := StatusCode(err); code == http.StatusForbidden || code == http.StatusNotFound {
+			return fmt.Errorf("failed to publish status on %s, write access is required (repo:status scope or checks permission): %w", r.Name, err)
+		}
+		return fmt.Errorf("failed to publish status on %s: %w", r.Name, err)
+	}
+
+	return nil
+}
+
+// getHeadSHA returns the head commit of a branch (empty means the default branch)
+// The response cache is bypassed so that the status lands on the current head
+func getHeadSHA(repoFullName, branch string) (string, error) {
+	ref := "HEAD"
+	if branch != "" {
+		ref = url.PathEscape(branch)
+	}
+
+	out, err := runGH("api",
+		fmt.Sprintf("repos/%s/commits/%s", repoFullName, ref),
+		"--jq", ".sha",
+		"--cache", "0s")
+	if err != nil {
+		return "", fmt.Errorf("failed to get head commit: %w", err)
+	}
+
+	sha := strings.TrimSpace(string(out))
+	if sha == "" {
+		return "", fmt.Errorf("no head commit found")
+	}
+	return sha, nil
+}
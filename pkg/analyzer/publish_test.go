@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// staticToken is a token source standing in for a GitHub App installation
+type staticToken string
+
+func (s staticToken) Token() (string, error) { return string(s), nil }
+
+func TestStatusConclusion(t *testing.T) {
+	tests := []struct {
+		name string
+		repo Repository
+		want string
+	}{
+		{"flagged", Repository{Flagged: true, ContributorDataComplete: true}, ConclusionFailure},
+		{"incomplete contributor data", Repository{}, ConclusionNeutral},
+		{"active", Repository{ContributorDataComplete: true}, ConclusionSuccess},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatusConclusion(tt.repo); got != tt.want {
+				t.Errorf("StatusConclusion = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublishStatus(t *testing.T) {
+	const sha = "0123456789abcdef"
+	flagged := Repository{Name: "o/r", Flagged: true, FlagReason: FlagReasonOldInactiveContributors, ContributorDataComplete: true}
+
+	tests := []struct {
+		name     string
+		repo     Repository
+		app      bool
+		dryRun   bool
+		post     string
+		wantHead string
+		wantCall []string
+		wantErr  string
+	}{
+		{
+			name:     "commit status",
+			repo:     flagged,
+			wantHead: "repos/o/r/commits/HEAD",
+			wantCall: []string{"repos/o/r/statuses/" + sha, "state=failure", "context=inactivity"},
+		},
+		{
+			name:     "neutral commit status published as success",
+			repo:     Repository{Name: "o/r"},
+			wantHead: "repos/o/r/commits/HEAD",
+			wantCall: []string{"repos/o/r/statuses/" + sha, "state=success"},
+		},
+		{
+			name:     "check run",
+			repo:     Repository{Name: "o/r", Branch: "release/1.x"},
+			app:      true,
+			wantHead: "repos/o/r/commits/release%2F1.x",
+			wantCall: []string{"repos/o/r/check-runs", "head_sha=" + sha, "conclusion=neutral", "status=completed"},
+		},
+		{
+			name:     "dry run",
+			repo:     flagged,
+			dryRun:   true,
+			wantHead: "repos/o/r/commits/HEAD",
+		},
+		{
+			name:     "write access denied",
+			repo:     flagged,
+			post:     `echo 'gh: Resource not accessible by integration (HTTP 403)' >&2; exit 1`,
+			wantHead: "repos/o/r/commits/HEAD",
+			wantCall: []string{"repos/o/r/statuses/" + sha},
+			wantErr:  "write access is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post := tt.post
+			if post == "" {
+				post = "exit 0"
+			}
+			logPath := fakeGH(t, `case "$*" in
+*POST*) `+post+`;;
+*commits/*) echo `+sha+`;;
+*) echo "unexpected call: $*" >&2; exit 1;;
+esac`)
+			SetCacheTTL(0)
+			if tt.app {
+				SetTokenSource(staticToken("app-token"))
+				t.Cleanup(func() { SetTokenSource(nil) })
+			}
+
+			err := PublishStatus(tt.repo, config.Config{DryRun: tt.dryRun, Silent: true})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("PublishStatus error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			calls := ghCalls(t, logPath)
+			if !strings.Contains(calls[0], tt.wantHead) || !strings.Contains(calls[0], "--cache 0s") {
+				t.Errorf("head commit call %q, want %s bypassing the cache", calls[0], tt.wantHead)
+			}
+			if tt.wantCall == nil {
+				if len(calls) != 1 {
+					t.Errorf("published in a dry run: %q", calls)
+				}
+				return
+			}
+			if len(calls) != 2 {
+				t.Fatalf("made %d calls, want the head commit then the status: %q", len(calls), calls)
+			}
+			for _, want := range tt.wantCall {
+				if !strings.Contains(calls[1], want) {
+					t.Errorf("status call %q does not include %s", calls[1], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+// pullRequestLookback is how many recently updated closed pull requests are searched for the last merge
+const pullRequestLookback = 100
+
+// GetLastMergedPRDate returns when the most recently merged pull request into the branch (empty means any)
+// was merged, or nil when none of the recently closed pull requests was merged
+func GetLastMergedPRDate(repoFullName, branch string) (*time.Time, error) {
+	endpoint := fmt.Sprintf("repos/%s/pulls?state=closed&sort=updated&direction=desc&per_page=%d", repoFullName, pullRequestLookback)
+	if branch != "" {
+		endpoint += "&base=" + url.QueryEscape(branch)
+	}
+
+	merged, err := runGHParsed(parseLastMergedPRDate, "api", endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merged pull requests: %w", err)
+	}
+	return merged, nil
+}
+
+// parseLastMergedPRDate finds the latest merge date in a closed pull requests response
+// Pull requests closed without being merged have no merge date and are ignored
+func parseLastMergedPRDate(data []byte) (*time.Time, error) {
+	var pulls []struct {
+		MergedAt *time.Time `json:"merged_at"`
+	}
+	if err := json.Unmarshal(data, &pulls); err != nil {
+		return nil, fmt.Errorf("failed to parse pull requests: %w", err)
+	}
+
+	var latest *time.Time
+	for _, pull := range pulls {
+		if pull.MergedAt != nil && (latest == nil || pull.MergedAt.After(*latest)) {
+			latest = pull.MergedAt
+		}
+	}
+	return latest, nil
+}
+
+// isReviewStale reports whether the last merged pull request is more than maxAgeDays old
+// Repositories without any merged pull request give no review signal and are never stale
+func isReviewStale(r Repository, maxAgeDays int) bool {
+	return r.LastMergedPRDate != nil && Now().Sub(*r.LastMergedPRDate) > time.Duration(maxAgeDays)*24*time.Hour
+}
+
+// botPullRequest is an open pull request as listed for the stale bot pull request count
+type botPullRequest struct {
+	Login     string    `json:"login"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GetStaleBotPRCount counts the open pull requests opened by bots, such as dependency update bots,
+// more than maxAgeDays ago, across every page of open pull requests
+func GetStaleBotPRCount(repoFullName string, maxAgeDays int, now time.Time) (int, error) {
+	count, err := runGHParsed(func(data []byte) (int, error) {
+		return parseStaleBotPRCount(data, maxAgeDays, now)
+	}, "api", fmt.Sprintf("repos/%s/pulls?state=open&per_page=100", repoFullName),
+		"--paginate", "--jq", ".[] | {login: .user.login, type: .user.type, created_at: .created_at}")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get open pull requests: %w", err)
+	}
+	return count, nil
+}
+
+// parseStaleBotPRCount counts the bot pull requests older than maxAgeDays among the open pull requests
+// printed one JSON object per line
+func parseStaleBotPRCount(data []byte, maxAgeDays int, now time.Time) (int, error) {
+	cutoff := now.AddDate(0, 0, -maxAgeDays)
+	count := 0
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var pull botPullRequest
+		if err := decoder.Decode(&pull); err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, fmt.Errorf("failed to parse open pull requests: %w", err)
+		}
+		if isBotAccount(pull.Login, pull.Type) && pull.CreatedAt.Before(cutoff) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// hasStaleBotPRPileUp reports whether more than maxPRs bot pull requests have been left open past the age threshold
+func hasStaleBotPRPileUp(r Repository, maxPRs int) bool {
+	return r.StaleBotPRCount != nil && *r.StaleBotPRCount > maxPRs
+}
@@ -0,0 +1,178 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetLastMergedPRDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		branch   string
+		pulls    string
+		want     string
+		wantCall string
+	}{
+		{
+			name:     "latest merge",
+			pulls:    `[{"merged_at":"2025-03-01T00:00:00Z"},{"merged_at":"2025-04-01T00:00:00Z"},{"merged_at":"2025-02-01T00:00:00Z"}]`,
+			want:     "2025-04-01T00:00:00Z",
+			wantCall: "repos/o/r/pulls?state=closed&sort=updated&direction=desc&per_page=100",
+		},
+		{
+			name:  "closed without merging excluded",
+			pulls: `[{"merged_at":null},{"merged_at":"2025-01-01T00:00:00Z"},{"merged_at":null}]`,
+			want:  "2025-01-01T00:00:00Z",
+		},
+		{
+			name:  "none merged",
+			pulls: `[{"merged_at":null},{"merged_at":null}]`,
+		},
+		{
+			name:  "no pull requests",
+			pulls: `[]`,
+		},
+		{
+			name:     "into a branch",
+			branch:   "release/1.x",
+			pulls:    `[{"merged_at":"2025-04-01T00:00:00Z"}]`,
+			want:     "2025-04-01T00:00:00Z",
+			wantCall: "&base=release%2F1.x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, "echo '"+tt.pulls+"'")
+			SetCacheTTL(0)
+
+			got, err := GetLastMergedPRDate("o/r", tt.branch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("last merge = %s, want none", got)
+				}
+			} else if got == nil || got.Format(time.RFC3339) != tt.want {
+				t.Errorf("last merge = %v, want %s", got, tt.want)
+			}
+			if calls := ghCalls(t, logPath); !strings.Contains(calls[0], tt.wantCall) {
+				t.Errorf("gh call %q, want %s", calls[0], tt.wantCall)
+			}
+		})
+	}
+
+	fakeGH(t, `echo 'gh: Server Error (HTTP 500)' >&2; exit 1`)
+	if _, err := GetLastMergedPRDate("o/r", ""); err == nil || !strings.Contains(err.Error(), "failed to get merged pull requests") {
+		t.Errorf("GetLastMergedPRDate error = %v, want the failure reported", err)
+	}
+}
+
+func TestIsReviewStale(t *testing.T) {
+	pinNow(t)
+	tests := []struct {
+		name       string
+		lastMerged *time.Time
+		want       bool
+	}{
+		{"no merged pull requests", nil, false},
+		{"recent merge", testDaysAgo(30), false},
+		{"stale merge", testDaysAgo(200), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isReviewStale(Repository{LastMergedPRDate: tt.lastMerged}, 180); got != tt.want {
+				t.Errorf("isReviewStale = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// pullJSON renders an open pull request as printed by the stale bot pull request query, opened the given number
+// of days before testNow
+func pullJSON(login, accountType string, daysAgo int) string {
+	return fmt.Sprintf(`{"login":%q,"type":%q,"created_at":%q}`, login, accountType, testDaysAgo(daysAgo).Format(time.RFC3339))
+}
+
+func TestParseStaleBotPRCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		pulls   []string
+		want    int
+		wantErr bool
+	}{
+		{"no open pull requests", nil, 0, false},
+		{
+			name: "bots and humans, fresh and stale",
+			pulls: []string{
+				pullJSON("dependabot[bot]", "Bot", 200),
+				pullJSON("dependabot[bot]", "Bot", 10),
+				pullJSON("renovate[bot]", "Bot", 400),
+				pullJSON("ann", "User", 300),
+				pullJSON("bob", "User", 5),
+				// An app account reported without the Bot type is still recognized by its login
+				pullJSON("snyk-bot[bot]", "User", 250),
+			},
+			want: 3,
+		},
+		{"exactly at the threshold", []string{pullJSON("dependabot[bot]", "Bot", 180)}, 0, false},
+		{"malformed", []string{pullJSON("dependabot[bot]", "Bot", 200), "{"}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseStaleBotPRCount([]byte(strings.Join(tt.pulls, "\n")), 180, testNow)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseStaleBotPRCount error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("stale bot pull requests = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetStaleBotPRCount(t *testing.T) {
+	// gh prints the pull requests of every page one after the other
+	page1 := strings.Repeat(pullJSON("dependabot[bot]", "Bot", 200)+"\n", 100)
+	page2 := pullJSON("dependabot[bot]", "Bot", 300) + "\n" + pullJSON("ann", "User", 300)
+	logPath := fakeGH(t, "printf '%s' '"+page1+page2+"'")
+	SetCacheTTL(0)
+
+	got, err := GetStaleBotPRCount("o/r", 180, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 101 {
+		t.Errorf("stale bot pull requests = %d, want 101 across both pages", got)
+	}
+	call := ghCalls(t, logPath)[0]
+	if !strings.Contains(call, "repos/o/r/pulls?state=open&per_page=100") || !strings.Contains(call, "--paginate") {
+		t.Errorf("call %q does not page through the open pull requests", call)
+	}
+
+	fakeGH(t, `echo 'gh: Server Error (HTTP 500)' >&2; exit 1`)
+	if _, err := GetStaleBotPRCount("o/r", 180, testNow); err == nil || !strings.Contains(err.Error(), "failed to get open pull requests") {
+		t.Errorf("GetStaleBotPRCount error = %v, want a failure", err)
+	}
+}
+
+func TestHasStaleBotPRPileUp(t *testing.T) {
+	tests := []struct {
+		name  string
+		count *int
+		want  bool
+	}{
+		{"not counted", nil, false},
+		{"at the threshold", intPtr(10), false},
+		{"above the threshold", intPtr(11), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasStaleBotPRPileUp(Repository{StaleBotPRCount: tt.count}, 10); got != tt.want {
+				t.Errorf("hasStaleBotPRPileUp = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// estimatedContributorsPerRepo is the assumed contributor count used to estimate per-contributor calls
+const estimatedContributorsPerRepo = 10
+
+// ErrInsufficientQuota is returned when the remaining API quota cannot cover the planned scan
+var ErrInsufficientQuota = errors.New("insufficient API quota")
+
+// RateLimit is the core REST API quota reported by the rate_limit endpoint
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+// QuotaEstimate compares the remaining API quota with the calls a scan is expected to make
+type QuotaEstimate struct {
+	RateLimit
+	Repositories int
+	CallsPerRepo int
+	Needed       int
+}
+
+// Sufficient reports whether the remaining quota covers the estimated calls
+func (q QuotaEstimate) Sufficient() bool {
+	return q.Remaining >= q.Needed
+}
+
+// GetRateLimit retrieves the current core API quota
+func GetRateLimit() (RateLimit, error) {
+	out, err := runGH("api", "rate_limit")
+	if err != nil {
+		return RateLimit{}, fmt.Errorf("failed to get rate limit: %w", err)
+	}
+	return parseRateLimit(out)
+}
+
+// parseRateLimit decodes the core quota from a rate_limit response
+func parseRateLimit(data []byte) (RateLimit, error) {
+	var resp struct {
+		Resources struct {
+			Core struct {
+				Limit     int   `json:"limit"`
+				Remaining int   `json:"remaining"`
+				Reset     int64 `json:"reset"`
+			} `json:"core"`
+		} `json:"resources"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return RateLimit{}, fmt.Errorf("failed to parse rate limit: %w", err)
+	}
+
+	core := resp.Resources.Core
+	return RateLimit{
+		Limit:     core.Limit,
+		Remaining: core.Remaining,
+		Reset:     time.Unix(core.Reset, 0),
+	}, nil
+}
+
+// EstimateCallsPerRepo estimates the core API calls made per repository with the enabled metrics
+// Commit search calls in the org contributor scope use the separate search quota and are not counted
+func EstimateCallsPerRepo(cfg config.Config) int {
+	// Repository metadata
+	calls := 1
+
+	// Last commit
+	if collects(cfg, MetricCommits) {
+		calls++
+	}
+
+	// Recent commits are listed once to find the last substantive one, the signing ratio, and the cadence
+	if collects(cfg, MetricSubstantive) || collects(cfg, MetricSigning) || collects(cfg, MetricCadence) {
+		calls++
+	}
+
+	// The latest workflow run is fetched for the CI status
+	if collects(cfg, MetricCI) {
+		calls++
+	}
+
+	// The latest closed pull requests are listed for the last merge
+	if collects(cfg, MetricReviews) {
+		calls++
+	}
+
+	// Open pull requests are listed for the bot ones, usually in one page
+	if collects(cfg, MetricBotPRs) {
+		calls++
+	}
+
+	// Recent issues are listed, and the comments of each commented one
+	if collects(cfg, MetricEngagement) {
+		calls += 1 + engagementIssueSample
+	}
+
+	// The newest tag is listed and its commit looked up
+	if collects(cfg, MetricTags) {
+		calls += 2
+	}
+
+	// The branches are listed, usually in a single page, and the latest commit of each is looked up
+	if collects(cfg, MetricBranches) {
+		calls += 1 + estimatedBranchesPerRepo
+	}
+
+	// Commits are counted in each of the two momentum windows, usually in a single page each
+	if collects(cfg, MetricMomentum) {
+		calls += 2
+	}
+
+	// The last year's commits are listed to weigh contributors, usually in a page or two
+	if cfg.WeightedContributors && collects(cfg, MetricContributors) {
+		calls += 2
+	}
+
+	// Outside collaborators are counted for governance, usually in a single page
+	if cfg.Governance {
+		calls++
+	}
+
+	// The README is looked up
+	if collects(cfg, MetricReadme) {
+		calls++
+	}
+
+	// Open Dependabot alerts are counted, usually in a single page
+	if collects(cfg, MetricSecurity) {
+		calls++
+	}
+
+	// Contributor list, plus one membership check per contributor, or one per batch over GraphQL
+	// The member list is fetched once per run, which the per-repository estimate leaves out
+	if collects(cfg, MetricContributors) {
+		calls++
+		switch {
+		case cfg.ContributorScope == ContributorScopeOrg, cfg.API == APIMembers:
+		case cfg.API == APIGraphQL:
+			calls += (estimatedContributorsPerRepo + graphQLMembershipBatch - 1) / graphQLMembershipBatch
+		default:
+			calls += estimatedContributorsPerRepo
+		}
+	}
+
+	// Each active contributor's account is checked for suspension, once per run
+	if cfg.CheckSuspended {
+		calls += estimatedContributorsPerRepo
+	}
+
+	// Each inactive contributor's last commit is looked up when details or the contributor report are requested
+	if describesContributors(cfg) || reportsContributors(cfg) {
+		calls += estimatedContributorsPerRepo
+	}
+
+	// Admins are only fetched for flagged repositories, but any repository may be flagged
+	if cfg.ShowAdmins {
+		calls++
+	}
+
+	// So are the teams of flagged repositories without an owners map entry of their own
+	if cfg.OwnersMap != "" {
+		calls++
+	}
+
+	// Publishing looks up the head commit and creates the status
+	if cfg.PublishStatus {
+		calls += 2
+	}
+
+	// Repositories given by name are checked for access first
+	if cfg.Organization == "" {
+		calls++
+	}
+
+	return calls
+}
+
+// EstimateQuota combines a rate limit with the calls expected for the given number of repositories
+func EstimateQuota(limit RateLimit, repoCount int, cfg config.Config) QuotaEstimate {
+	perRepo := EstimateCallsPerRepo(cfg)
+	return QuotaEstimate{
+		RateLimit:    limit,
+		Repositories: repoCount,
+		CallsPerRepo: perRepo,
+		Needed:       repoCount * perRepo,
+	}
+}
+
+// CheckQuota returns a warning when the remaining API quota is unlikely to cover the scan,
+// or ErrInsufficientQuota when configured to abort
+func CheckQuota(repoCount int, cfg config.Config) ([]Warning, error) {
+	if repoCount == 0 {
+		return nil, nil
+	}
+
+	warnings := &warningLog{}
+	limit, err := GetRateLimit()
+	if err != nil {
+		if cfg.Strict {
+			return nil, err
+		}
+		// The check is advisory, so an unavailable quota must not block the scan
+		warnings.warn(cfg, "", "Could not check API quota: %v", err)
+		return warnings.list(), nil
+	}
+
+	estimate := EstimateQuota(limit, repoCount, cfg)
+	if estimate.Sufficient() {
+		return nil, nil
+	}
+
+	if cfg.AbortOnInsufficientQuota {
+		return nil, fmt.Errorf("%w: about %d calls needed for %d repositories, %d of %d remaining until %s",
+			ErrInsufficientQuota, estimate.Needed, repoCount, estimate.Remaining, estimate.Limit,
+			estimate.Reset.Format(time.RFC3339))
+	}
+
+	warnings.warn(cfg, "", "About %d API calls are needed for %d repositories but only %d remain (resets at %s)",
+		estimate.Needed, repoCount, estimate.Remaining, estimate.Reset.Format("15:04:05"))
+	return warnings.list(), nil
+}
@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestEstimateCallsPerRepo(t *testing.T) {
+	// The last commit and the repository metadata of an organization scan
+	base := config.Config{Organization: "o", Metrics: []string{MetricCommits}}
+	withContributors := withConfig(base, func(c *config.Config) { c.Metrics = []string{MetricCommits, MetricContributors} })
+
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want int
+	}{
+		{"commits", base, 2},
+		{"repositories by name", withConfig(base, func(c *config.Config) { c.Organization = "" }), 3},
+		{"contributors over REST", withContributors, 3 + estimatedContributorsPerRepo},
+		{"contributors over GraphQL", withConfig(withContributors, func(c *config.Config) { c.API = APIGraphQL }),
+			3 + (estimatedContributorsPerRepo+graphQLMembershipBatch-1)/graphQLMembershipBatch},
+		{"contributors from the member list", withConfig(withContributors, func(c *config.Config) { c.API = APIMembers }), 3},
+		{"contributors in the org scope", withConfig(withContributors, func(c *config.Config) { c.ContributorScope = ContributorScopeOrg }), 3},
+		{"contributor details", withConfig(withContributors, func(c *config.Config) { c.API = APIMembers; c.ContributorDetails = true }), 3 + estimatedContributorsPerRepo},
+		{"engagement", withConfig(base, func(c *config.Config) { c.Metrics = []string{MetricCommits, MetricEngagement} }), 3 + engagementIssueSample},
+		{"implied CI status", withConfig(base, func(c *config.Config) { c.FlagBrokenCI = true }), 3},
+		{"admins, owners, and governance", withConfig(base, func(c *config.Config) { c.ShowAdmins = true; c.OwnersMap = "owners"; c.Governance = true }), 5},
+		{"published status", withConfig(base, func(c *config.Config) { c.PublishStatus = true }), 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateCallsPerRepo(tt.cfg); got != tt.want {
+				t.Errorf("EstimateCallsPerRepo = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckQuota(t *testing.T) {
+	const rateLimit = `echo '{"resources":{"core":{"limit":5000,"remaining":100,"reset":1748736000}}}'`
+	cfg := config.Config{Organization: "o", Metrics: []string{MetricCommits}, Silent: true}
+
+	tests := []struct {
+		name         string
+		script       string
+		repos        int
+		cfg          config.Config
+		wantErr      error
+		wantWarnings int
+		wantCalls    int
+	}{
+		{"nothing to scan", rateLimit, 0, cfg, nil, 0, 0},
+		{"sufficient", rateLimit, 50, cfg, nil, 0, 1},
+		{"insufficient warns", rateLimit, 51, cfg, nil, 1, 1},
+		{"insufficient aborts", rateLimit, 51, withConfig(cfg, func(c *config.Config) { c.AbortOnInsufficientQuota = true }), ErrInsufficientQuota, 0, 1},
+		{"unavailable warns", `exit 1`, 10, cfg, nil, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			warnings, err := CheckQuota(tt.repos, tt.cfg)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckQuota error = %v, want %v", err, tt.wantErr)
+			}
+			if got := len(warnings); got != tt.wantWarnings {
+				t.Errorf("returned %d warnings, want %d", got, tt.wantWarnings)
+			}
+			if calls := ghCalls(t, logPath); len(calls) != tt.wantCalls {
+				t.Errorf("made %d gh calls, want %d", len(calls), tt.wantCalls)
+			}
+		})
+	}
+
+	fakeGH(t, `exit 1`)
+	if _, err := CheckQuota(10, withConfig(cfg, func(c *config.Config) { c.Strict = true })); err == nil {
+		t.Error("CheckQuota ignored an unavailable quota in strict mode")
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	limit, err := parseRateLimit([]byte(`{"resources":{"core":{"limit":5000,"remaining":42,"reset":1748736000},"search":{"limit":30}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := RateLimit{Limit: 5000, Remaining: 42, Reset: time.Unix(1748736000, 0)}
+	if limit != want {
+		t.Errorf("parseRateLimit = %+v, want %+v", limit, want)
+	}
+	if _, err := parseRateLimit([]byte("not json")); err == nil {
+		t.Error("parseRateLimit succeeded on malformed output")
+	}
+}
@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// GetReadmeSize returns the size in bytes of the README GitHub shows for the branch (empty means default),
+// or nil when there is none; with a commit path, the README of that directory is looked up instead
+func GetReadmeSize(repoFullName, branch string) (*int, error) {
+	endpoint := fmt.Sprintf("repos/%s/readme", repoFullName)
+	if commitPath != "" {
+		endpoint += "/" + commitPath
+	}
+	if branch != "" {
+		endpoint += "?ref=" + url.QueryEscape(branch)
+	}
+
+	out, err := runGH("api", endpoint, "--jq", ".size")
+	if err != nil {
+		if StatusCode(err) == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get README: %w", err)
+	}
+
+	size, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse README size: %w", err)
+	}
+	return &size, nil
+}
+
+// lacksReadme reports whether a repository was checked for a README and has none
+func lacksReadme(r Repository) bool {
+	return r.HasReadme != nil && !*r.HasReadme
+}
+
+// readmeSummary renders the README presence for human-readable output
+func readmeSummary(repo Repository) string {
+	if !*repo.HasReadme {
+		return "none"
+	}
+	return fmt.Sprintf("%d bytes", repo.ReadmeSizeBytes)
+}
@@ -0,0 +1,118 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestGetReadmeSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		branch   string
+		path     string
+		want     *int
+		wantErr  bool
+		wantCall string
+	}{
+		{"present", `echo 2048`, "", "", intPtr(2048), false, "api repos/o/r/readme --jq .size"},
+		{"absent", `echo 'gh: Not Found (HTTP 404)' >&2; exit 1`, "", "", nil, false, "api repos/o/r/readme "},
+		{"on a branch", `echo 12`, "release/1.x", "", intPtr(12), false, "api repos/o/r/readme?ref=release%2F1.x "},
+		{"under a path", `echo 12`, "dev", "services/api", intPtr(12), false, "api repos/o/r/readme/services/api?ref=dev "},
+		{"other failure", `echo 'gh: Server Error (HTTP 500)' >&2; exit 1`, "", "", nil, true, "api repos/o/r/readme "},
+		{"malformed", `echo null`, "", "", nil, true, "api repos/o/r/readme "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			SetCacheTTL(0)
+			useCommitPath(t, tt.path, false)
+
+			got, err := GetReadmeSize("o/r", tt.branch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetReadmeSize error = %v, want error %v", err, tt.wantErr)
+			}
+			if derefInt(got) != derefInt(tt.want) {
+				t.Errorf("README size = %s, want %s", derefInt(got), derefInt(tt.want))
+			}
+			if calls := ghCalls(t, logPath); len(calls) != 1 || !strings.HasPrefix(calls[0], tt.wantCall) {
+				t.Errorf("gh calls %q, want %q", calls, tt.wantCall)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepositoryReadme(t *testing.T) {
+	// The repository is archived so it is flagged whatever its README
+	script := func(readme string) string {
+		return fmt.Sprintf(`case "$*" in
+*readme*) %s;;
+*commits*) echo %s;;
+"api repos/o/r") echo '{"archived":true}';;
+*) echo "unexpected call: $*" >&2; exit 1;;
+esac`, readme, testDaysAgo(400).Format(time.RFC3339))
+	}
+
+	tests := []struct {
+		name             string
+		readme           string
+		prioritize       bool
+		wantReadme       *bool
+		wantSize         int
+		wantHighPriority bool
+	}{
+		{"present", `echo 1500`, true, boolPtr(true), 1500, false},
+		{"absent", `echo 'gh: Not Found (HTTP 404)' >&2; exit 1`, true, boolPtr(false), 0, true},
+		{"absent without priority", `echo 'gh: Not Found (HTTP 404)' >&2; exit 1`, false, boolPtr(false), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pinNow(t)
+			fakeGH(t, script(tt.readme))
+			SetCacheTTL(0)
+
+			cfg := withConfig(flaggingConfig, func(c *config.Config) {
+				c.Metrics = []string{MetricCommits}
+				c.Readme = true
+				c.PrioritizeUndocumented = tt.prioritize
+				c.Silent = true
+			})
+			repo, _, err := AnalyzeRepository("o/r", cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if repo.HasReadme == nil || *repo.HasReadme != *tt.wantReadme || repo.ReadmeSizeBytes != tt.wantSize {
+				t.Errorf("README %v of %d bytes, want %v of %d bytes", repo.HasReadme, repo.ReadmeSizeBytes, *tt.wantReadme, tt.wantSize)
+			}
+			if repo.HighPriority != tt.wantHighPriority || repo.Undocumented != tt.wantHighPriority {
+				t.Errorf("high priority %v and undocumented %v, want %v", repo.HighPriority, repo.Undocumented, tt.wantHighPriority)
+			}
+			if got := strings.Contains(priorityMarker(repo), "no README"); got != tt.wantHighPriority {
+				t.Errorf("priority marker %q, want no README named %v", priorityMarker(repo), tt.wantHighPriority)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepositoryReadmeNotRequested(t *testing.T) {
+	pinNow(t)
+	logPath := fakeGH(t, analyzeScript(`{}`, 400))
+	SetCacheTTL(0)
+
+	repo, _, err := AnalyzeRepository("o/r", withConfig(flaggingConfig, func(c *config.Config) { c.Metrics = []string{MetricCommits}; c.Silent = true }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.HasReadme != nil {
+		t.Errorf("README recorded as %v without being requested", *repo.HasReadme)
+	}
+	for _, call := range ghCalls(t, logPath) {
+		if strings.Contains(call, "readme") {
+			t.Errorf("README looked up without being requested: %q", call)
+		}
+	}
+}
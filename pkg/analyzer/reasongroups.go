@@ -0,0 +1,65 @@
+package analyzer
+
+// reasonGroup is a set of flagged repositories sharing a flag reason
+type reasonGroup struct {
+	Reason string
+	Repos  []Repository
+}
+
+// flagReasonHeadings names the report section of each flag reason, in reporting order
+var flagReasonHeadings = []struct {
+	Reason  string
+	Heading string
+}{
+	{FlagReasonArchived, "Archived"},
+	{FlagReasonOldInactiveContributors, "Stale + inactive team"},
+	{FlagReasonOldNoContributors, "Stale + no contributors"},
+	{FlagReasonOldIssuesDisabled, "Stale + issues disabled"},
+	{FlagReasonOldBrokenCI, "Stale + broken CI"},
+	{FlagReasonStaleReviews, "Stale reviews"},
+	{FlagReasonDeclining, "Declining activity"},
+	{FlagReasonUnresponsive, "Unresponsive maintainers"},
+	{FlagReasonStaleBotPRs, "Unmerged bot pull requests"},
+}
+
+// flagReasonHeading returns the section heading of a flag reason, or the reason itself when it has none
+func flagReasonHeading(reason string) string {
+	for _, h := range flagReasonHeadings {
+		if h.Reason == reason {
+			return h.Heading
+		}
+	}
+	return reason
+}
+
+// groupByFlagReason groups the flagged repositories by flag reason, keeping their order within each group
+// Groups follow the order of flagReasonHeadings, with any other reason after them; empty groups are left out
+func groupByFlagReason(repos []Repository) []reasonGroup {
+	var groups []reasonGroup
+	for _, h := range flagReasonHeadings {
+		groups = append(groups, reasonGroup{Reason: h.Reason})
+	}
+
+	for _, repo := range repos {
+		if !repo.Flagged {
+			continue
+		}
+
+		i := 0
+		for i < len(groups) && groups[i].Reason != repo.FlagReason {
+			i++
+		}
+		if i == len(groups) {
+			groups = append(groups, reasonGroup{Reason: repo.FlagReason})
+		}
+		groups[i].Repos = append(groups[i].Repos, repo)
+	}
+
+	var nonEmpty []reasonGroup
+	for _, g := range groups {
+		if len(g.Repos) > 0 {
+			nonEmpty = append(nonEmpty, g)
+		}
+	}
+	return nonEmpty
+}
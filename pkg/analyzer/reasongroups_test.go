@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestGroupByFlagReason(t *testing.T) {
+	archived := Repository{Name: "o/archived", Flagged: true, FlagReason: FlagReasonArchived}
+	team := Repository{Name: "o/team", Flagged: true, FlagReason: FlagReasonOldInactiveContributors}
+	team2 := Repository{Name: "o/team2", Flagged: true, FlagReason: FlagReasonOldInactiveContributors}
+	nobody := Repository{Name: "o/nobody", Flagged: true, FlagReason: FlagReasonOldNoContributors}
+	custom := Repository{Name: "o/custom", Flagged: true, FlagReason: "custom-reason"}
+	active := Repository{Name: "o/active"}
+
+	tests := []struct {
+		name  string
+		repos []Repository
+		want  []reasonGroup
+	}{
+		{"nothing flagged", []Repository{active}, nil},
+		{
+			name:  "heading order",
+			repos: []Repository{team, nobody, active, archived, team2},
+			want: []reasonGroup{
+				{Reason: FlagReasonArchived, Repos: []Repository{archived}},
+				{Reason: FlagReasonOldInactiveContributors, Repos: []Repository{team, team2}},
+				{Reason: FlagReasonOldNoContributors, Repos: []Repository{nobody}},
+			},
+		},
+		{
+			name:  "reason without a heading last",
+			repos: []Repository{custom, nobody},
+			want: []reasonGroup{
+				{Reason: FlagReasonOldNoContributors, Repos: []Repository{nobody}},
+				{Reason: "custom-reason", Repos: []Repository{custom}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := groupByFlagReason(tt.repos); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groupByFlagReason = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if got := flagReasonHeading("custom-reason"); got != "custom-reason" {
+		t.Errorf("heading of a reason without one = %q, want the reason", got)
+	}
+}
+
+func TestRenderGroupedReports(t *testing.T) {
+	pinNow(t)
+	repos := []Repository{
+		{Name: "o/team", Flagged: true, FlagReason: FlagReasonOldInactiveContributors, DaysSinceLastCommit: 400},
+		{Name: "o/archived", Archived: true, Flagged: true, FlagReason: FlagReasonArchived},
+		{Name: "o/nobody", Flagged: true, FlagReason: FlagReasonOldNoContributors, DaysSinceLastCommit: 500},
+		{Name: "o/team2", Flagged: true, FlagReason: FlagReasonOldInactiveContributors, DaysSinceLastCommit: 300},
+		{Name: "o/active"},
+	}
+	cfg := config.Config{Organization: "o", MaxCommitAgeInDays: 180, GroupByReason: true, Silent: true}
+	summary := newSummary(repos, 0, nil, nil, false, cfg)
+
+	tests := []struct {
+		name     string
+		render   func() string
+		headings []string
+	}{
+		{
+			name:     "text",
+			render:   func() string { return string(renderTextReport(repos, summary)) },
+			headings: []string{"🚩 Archived (1):", "🚩 Stale + inactive team (2):", "🚩 Stale + no contributors (1):"},
+		},
+		{
+			name:     "markdown",
+			render:   func() string { return string(renderMarkdown(repos, summary)) },
+			headings: []string{"### Archived (1)", "### Stale + inactive team (2)", "### Stale + no contributors (1)"},
+		},
+	}
+	// Each section lists its repositories before the next heading
+	sections := [][]string{{"o/archived"}, {"o/team", "o/team2"}, {"o/nobody"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := tt.render()
+			if strings.Contains(report, "o/active") {
+				t.Errorf("report lists the active repository:\n%s", report)
+			}
+			for i, heading := range tt.headings {
+				start := strings.Index(report, heading)
+				if start < 0 {
+					t.Fatalf("report has no %q heading:\n%s", heading, report)
+				}
+				section := report[start:]
+				if i+1 < len(tt.headings) {
+					end := strings.Index(section, tt.headings[i+1])
+					if end < 0 {
+						t.Fatalf("heading %q does not follow %q:\n%s", tt.headings[i+1], heading, report)
+					}
+					section = section[:end]
+				}
+				for _, name := range sections[i] {
+					if !strings.Contains(section, name) {
+						t.Errorf("%s is not under %q:\n%s", name, heading, report)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Kinds of names replaced by pseudonyms, which are also the pseudonym prefixes
+const (
+	redactOrganization = "org"
+	redactRepository   = "repo"
+	redactUser         = "user"
+)
+
+// RedactionMap replaces organization, repository, and user names with stable pseudonyms such as org-1/repo-4
+// and user-12, so reports can be shared outside the organization. The pseudonyms are recorded in a local
+// mapping file to de-anonymize a shared report, and reused by later runs sharing the file:
+//
+//	{
+//	  "organizations": {"org-1": "acme"},
+//	  "repositories": {"repo-4": "acme/billing"},
+//	  "users": {"user-12": "jdoe"}
+//	}
+//
+// Names are matched case-insensitively, as GitHub names are. It is safe for concurrent use.
+type RedactionMap struct {
+	mu         sync.Mutex
+	path       string
+	real       map[string]map[string]string // kind to pseudonym to real name, as stored in the file
+	pseudonyms map[string]map[string]string // kind to lower-case real name to pseudonym
+	next       map[string]int               // kind to the number of the next pseudonym
+}
+
+// redactionMap is the redaction map of the current run, nil when reports show real names
+var redactionMap *RedactionMap
+
+// SetRedactionMap sets the redaction map every report is anonymized with; nil shows real names
+func SetRedactionMap(m *RedactionMap) {
+	redactionMap = m
+}
+
+// LoadRedactionMap reads the mapping file at path, starting an empty map when it does not exist yet
+func LoadRedactionMap(path string) (*RedactionMap, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return parseRedactionMap(path, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redaction map: %w", err)
+	}
+	return parseRedactionMap(path, data)
+}
+
+// parseRedactionMap decodes a mapping file, or starts an empty map for nil data
+func parseRedactionMap(path string, data []byte) (*RedactionMap, error) {
+	var file struct {
+		Organizations map[string]string `json:"organizations"`
+		Repositories  map[string]string `json:"repositories"`
+		Users         map[string]string `json:"users"`
+	}
+	if data != nil {
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse redaction map: %w", err)
+		}
+	}
+
+	m := &RedactionMap{
+		path: path,
+		real: map[string]map[string]string{
+			redactOrganization: file.Organizations,
+			redactRepository:   file.Repositories,
+			redactUser:         file.Users,
+		},
+		pseudonyms: make(map[string]map[string]string),
+		next:       make(map[string]int),
+	}
+	for kind, names := range m.real {
+		if names == nil {
+			m.real[kind] = make(map[string]string)
+		}
+		m.pseudonyms[kind] = make(map[string]string)
+		m.next[kind] = 1
+		for pseudonym, name := range m.real[kind] {
+			n, err := strconv.Atoi(strings.TrimPrefix(pseudonym, kind+"-"))
+			if !strings.HasPrefix(pseudonym, kind+"-") || err != nil {
+				return nil, fmt.Errorf("redaction map: %s is not a %s pseudonym", pseudonym, kind)
+			}
+			m.pseudonyms[kind][strings.ToLower(name)] = pseudonym
+			m.next[kind] = max(m.next[kind], n+1)
+		}
+	}
+	return m, nil
+}
+
+// Save writes the mapping file, readable only by its owner as it de-anonymizes every shared report
+func (m *RedactionMap) Save() error {
+	m.mu.Lock()
+	data, err := json.MarshalIndent(map[string]map[string]string{
+		"organizations": m.real[redactOrganization],
+		"repositories":  m.real[redactRepository],
+		"users":         m.real[redactUser],
+	}, "", "  ")
+	m.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal redaction map: %w", err)
+	}
+
+	if err := writeFileAtomic(m.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write redaction map: %w", err)
+	}
+	return nil
+}
+
+// pseudonym returns the pseudonym of a name, assigning the next one on first sight
+func (m *RedactionMap) pseudonym(kind, name string) string {
+	if name == "" {
+		return ""
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := strings.ToLower(name)
+	if pseudonym, ok := m.pseudonyms[kind][key]; ok {
+		return pseudonym
+	}
+	pseudonym := fmt.Sprintf("%s-%d", kind, m.next[kind])
+	m.next[kind]++
+	m.pseudonyms[kind][key] = pseudonym
+	m.real[kind][pseudonym] = name
+	return pseudonym
+}
+
+// Organization returns the pseudonym of an organization or user account owning repositories
+func (m *RedactionMap) Organization(name string) string {
+	return m.pseudonym(redactOrganization, name)
+}
+
+// Repository returns the pseudonym of an owner/repo name, keeping its shape as org-N/repo-M
+func (m *RedactionMap) Repository(fullName string) string {
+	owner, _, ok := strings.Cut(fullName, "/")
+	if !ok {
+		return m.pseudonym(redactRepository, fullName)
+	}
+	return m.Organization(owner) + "/" + m.pseudonym(redactRepository, fullName)
+}
+
+// User returns the pseudonym of a user login
+func (m *RedactionMap) User(login string) string {
+	return m.pseudonym(redactUser, login)
+}
+
+// users returns the pseudonyms of user logins
+func (m *RedactionMap) users(logins []string) []string {
+	if logins == nil {
+		return nil
+	}
+	redacted := make([]string, len(logins))
+	for i, login := range logins {
+		redacted[i] = m.User(login)
+	}
+	return redacted
+}
+
+// redactRepository returns a copy of a repository with its names replaced by pseudonyms and every metric kept
+// The extra CSV columns are dropped, as they can hold anything.
+func (m *RedactionMap) redactRepository(repo Repository) Repository {
+	repo.Name = m.Repository(repo.Name)
+	if repo.Parent != "" {
+		repo.Parent = m.Repository(repo.Parent)
+	}
+	repo.Admins = m.users(repo.Admins)
+	if repo.Contact != "" && repo.Contact != ContactUnknown {
+		repo.Contact = m.User(repo.Contact)
+	}
+	repo.ActiveContributorLogins = m.users(repo.ActiveContributorLogins)
+	repo.InactiveContributorDetails = m.redactContributors(repo.InactiveContributorDetails)
+	repo.DepartedContributors = m.redactContributors(repo.DepartedContributors)
+	repo.Extra = nil
+	return repo
+}
+
+// redactContributors returns a copy of inactive contributors with their logins replaced by pseudonyms
+func (m *RedactionMap) redactContributors(contributors []InactiveContributor) []InactiveContributor {
+	if contributors == nil {
+		return nil
+	}
+	redacted := make([]InactiveContributor, len(contributors))
+	for i, c := range contributors {
+		c.Login = m.User(c.Login)
+		redacted[i] = c
+	}
+	return redacted
+}
+
+// redactReport replaces the names in the repositories and summary of a report by pseudonyms
+// Warning messages have the names known to the map replaced, which covers those of the report.
+func (m *RedactionMap) redactReport(repos []Repository, summary Summary) ([]Repository, Summary) {
+	redacted := make([]Repository, len(repos))
+	for i, repo := range repos {
+		redacted[i] = m.redactRepository(repo)
+	}
+
+	cfg := summary.Config
+	cfg.Organization = m.Organization(cfg.Organization)
+	if cfg.SingleRepository != "" {
+		cfg.SingleRepository = m.Repository(cfg.SingleRepository)
+	}
+	if cfg.ForksOf != "" {
+		cfg.ForksOf = m.Repository(cfg.ForksOf)
+	}
+	if cfg.TrackingIssueRepo != "" {
+		cfg.TrackingIssueRepo = m.Repository(cfg.TrackingIssueRepo)
+	}
+	if cfg.AdminOf != "" && cfg.AdminOf != "@me" {
+		cfg.AdminOf = m.User(cfg.AdminOf)
+	}
+	cfg.Repositories = m.repositories(cfg.Repositories)
+	cfg.EmailTo, cfg.EmailFrom, cfg.SMTPUsername, cfg.UpdateComment, cfg.LocalRepository = "", "", "", "", ""
+	summary.Config = cfg
+
+	summary.Removed = m.repositories(summary.Removed)
+
+	if summary.Duplicates != nil {
+		duplicates := make([]DuplicateCluster, len(summary.Duplicates))
+		for i, cluster := range summary.Duplicates {
+			duplicates[i] = DuplicateCluster{Repositories: m.repositories(cluster.Repositories), SameTip: cluster.SameTip}
+		}
+		summary.Duplicates = duplicates
+	}
+
+	if summary.Warnings != nil {
+		warnings := make([]Warning, len(summary.Warnings))
+		for i, w := range summary.Warnings {
+			if w.Repository != "" {
+				w.Repository = m.Repository(w.Repository)
+			}
+			warnings[i] = w
+		}
+		for i := range warnings {
+			warnings[i].Message = m.redactText(warnings[i].Message)
+		}
+		summary.Warnings = warnings
+	}
+
+	return redacted, summary
+}
+
+// redactNameToken matches the words of free text that can be organization names or user logins
+var redactNameToken = regexp.MustCompile(`[A-Za-z0-9][A-Za-z0-9_.-]*`)
+
+// redactText replaces the repository, organization, and user names known to the map in free text,
+// such as an error message; repository names go first so their owners are replaced with them
+func (m *RedactionMap) redactText(text string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Longer names first, so acme/api-gateway is not replaced as acme/api
+	var repos []string
+	for _, name := range m.real[redactRepository] {
+		repos = append(repos, name)
+	}
+	sort.Slice(repos, func(i, j int) bool { return len(repos[i]) > len(repos[j]) })
+	for _, name := range repos {
+		if strings.Contains(strings.ToLower(text), strings.ToLower(name)) {
+			pseudonym := m.pseudonyms[redactRepository][strings.ToLower(name)]
+			if owner, _, ok := strings.Cut(name, "/"); ok {
+				if org, ok := m.pseudonyms[redactOrganization][strings.ToLower(owner)]; ok {
+					pseudonym = org + "/" + pseudonym
+				}
+			}
+			text = replaceFold(text, name, pseudonym)
+		}
+	}
+
+	return redactNameToken.ReplaceAllStringFunc(text, func(word string) string {
+		key := strings.ToLower(word)
+		if pseudonym, ok := m.pseudonyms[redactOrganization][key]; ok {
+			return pseudonym
+		}
+		if pseudonym, ok := m.pseudonyms[redactUser][key]; ok {
+			return pseudonym
+		}
+		return word
+	})
+}
+
+// replaceFold replaces every case-insensitive occurrence of old in s with new
+func replaceFold(s, old, new string) string {
+	lower, lowerOld := strings.ToLower(s), strings.ToLower(old)
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, lowerOld)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		b.WriteString(s[:i])
+		b.WriteString(new)
+		s, lower = s[i+len(old):], lower[i+len(old):]
+	}
+}
+
+// redactDepartures returns the contributor report rows with logins and repository names replaced by pseudonyms
+func (m *RedactionMap) redactDepartures(departures []ContributorDeparture) []ContributorDeparture {
+	redacted := make([]ContributorDeparture, len(departures))
+	for i, d := range departures {
+		d.Login = m.User(d.Login)
+		d.Repositories = m.repositories(d.Repositories)
+		d.InactiveIn = m.repositories(d.InactiveIn)
+		redacted[i] = d
+	}
+	return redacted
+}
+
+// repositories returns the pseudonyms of repository names
+func (m *RedactionMap) repositories(names []string) []string {
+	if names == nil {
+		return nil
+	}
+	redacted := make([]string, len(names))
+	for i, name := range names {
+		redacted[i] = m.Repository(name)
+	}
+	return redacted
+}
+
+// RedactOrganization returns the pseudonym of an organization when reports are redacted, or the name itself
+func RedactOrganization(name string) string {
+	if redactionMap == nil || name == "" {
+		return name
+	}
+	return redactionMap.Organization(name)
+}
+
+// redactReport anonymizes a report when redaction is enabled
+func redactReport(repos []Repository, summary Summary) ([]Repository, Summary) {
+	if redactionMap == nil {
+		return repos, summary
+	}
+	return redactionMap.redactReport(repos, summary)
+}
+
+// SaveRedactionMap writes the mapping file of the current run, if reports are redacted
+func SaveRedactionMap() error {
+	if redactionMap == nil {
+		return nil
+	}
+	return redactionMap.Save()
+}
@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRedactionMapPseudonyms(t *testing.T) {
+	m, err := LoadRedactionMap(filepath.Join(t.TempDir(), "map.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		got  func() string
+		want string
+	}{
+		{"first repository", func() string { return m.Repository("acme/api") }, "org-1/repo-1"},
+		{"second repository of the organization", func() string { return m.Repository("acme/web") }, "org-1/repo-2"},
+		{"same repository in another case", func() string { return m.Repository("ACME/API") }, "org-1/repo-1"},
+		{"organization", func() string { return m.Organization("Acme") }, "org-1"},
+		{"other organization", func() string { return m.Repository("beta/api") }, "org-2/repo-3"},
+		{"user", func() string { return m.User("jdoe") }, "user-1"},
+		{"same user", func() string { return m.User("JDoe") }, "user-1"},
+		{"empty name", func() string { return m.User("") }, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.got(); got != tt.want {
+			t.Errorf("%s: pseudonym %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRedactionMapReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "map.json")
+	m, err := LoadRedactionMap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := []string{m.Repository("acme/api"), m.User("jdoe")}
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mapping file mode %v, want 0600", info.Mode().Perm())
+	}
+
+	reloaded, err := LoadRedactionMap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again := []string{reloaded.Repository("acme/api"), reloaded.User("jdoe")}
+	if !reflect.DeepEqual(again, first) {
+		t.Errorf("reloaded pseudonyms %q, want %q", again, first)
+	}
+	if got := reloaded.Repository("acme/new"); got != "org-1/repo-2" {
+		t.Errorf("new repository after reload = %q, want the next number org-1/repo-2", got)
+	}
+}
+
+func TestLoadRedactionMapInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"not json", "{", "failed to parse redaction map"},
+		{"wrong prefix", `{"users": {"repo-1": "jdoe"}}`, "repo-1 is not a user pseudonym"},
+		{"no number", `{"organizations": {"org-x": "acme"}}`, "org-x is not a org pseudonym"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "map.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadRedactionMap(path); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadRedactionMap error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedactRepository(t *testing.T) {
+	m, err := parseRedactionMap("map.json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := Repository{
+		Name:                       "acme/api",
+		Parent:                     "upstream/api",
+		Admins:                     []string{"ann"},
+		Contact:                    ContactUnknown,
+		ActiveContributorLogins:    []string{"bob"},
+		InactiveContributorDetails: []InactiveContributor{{Login: "ann", Reason: "inactive"}},
+		Extra:                      map[string]string{"team": "core"},
+		DaysSinceLastCommit:        400,
+		Flagged:                    true,
+	}
+
+	got := m.redactRepository(repo)
+	want := Repository{
+		Name:                       "org-1/repo-1",
+		Parent:                     "org-2/repo-2",
+		Admins:                     []string{"user-1"},
+		Contact:                    ContactUnknown,
+		ActiveContributorLogins:    []string{"user-2"},
+		InactiveContributorDetails: []InactiveContributor{{Login: "user-1", Reason: "inactive"}},
+		DaysSinceLastCommit:        400,
+		Flagged:                    true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("redacted repository = %+v, want %+v", got, want)
+	}
+	if repo.Admins[0] != "ann" || repo.InactiveContributorDetails[0].Login != "ann" {
+		t.Error("redaction changed the original repository")
+	}
+}
+
+func TestRedactText(t *testing.T) {
+	m, err := parseRedactionMap("map.json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Repository("acme/api")
+	m.Repository("acme/api-gateway")
+	m.User("jdoe")
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"failed to fetch acme/api-gateway: 404", "failed to fetch org-1/repo-2: 404"},
+		{"failed to fetch ACME/api commits", "failed to fetch org-1/repo-1 commits"},
+		{"jdoe is not a member of acme", "user-1 is not a member of org-1"},
+		{"rate limited by other/repo", "rate limited by other/repo"},
+		{"jdoes left", "jdoes left"},
+	}
+	for _, tt := range tests {
+		if got := m.redactText(tt.text); got != tt.want {
+			t.Errorf("redactText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// cachedRepository is the commit and contributor data saved for a repository, valid while its pushed_at is unchanged
+// Contributor data also expires after the contributor TTL, since members leave the organization without pushing
+type cachedRepository struct {
+	PushedAt              time.Time `json:"pushedAt"`
+	Fingerprint           string    `json:"fingerprint"`
+	ContributorsCheckedAt time.Time `json:"contributorsCheckedAt,omitempty"`
+
+	LastCommitDate             time.Time             `json:"lastCommitDate"`
+	CommitStatus               string                `json:"commitStatus,omitempty"`
+	ContributorDataComplete    bool                  `json:"contributorDataComplete"`
+	TotalContributors          int                   `json:"totalContributors"`
+	InactiveContributors       int                   `json:"inactiveContributors"`
+	SuspendedContributors      int                   `json:"suspendedContributors,omitempty"`
+	WeightedInactivePercentage *float64              `json:"weightedInactivePercentage,omitempty"`
+	InactiveContributorDetails []InactiveContributor `json:"inactiveContributorDetails,omitempty"`
+	ActiveContributorLogins    []string              `json:"activeContributorLogins,omitempty"`
+	DepartedContributors       []InactiveContributor `json:"departedContributors,omitempty"`
+}
+
+// repositoryCache holds the cached repositories of a run and the file they are saved to
+type repositoryCache struct {
+	path         string
+	mu           sync.Mutex
+	repositories map[string]cachedRepository
+}
+
+// repoCache is the repository cache of the current run, nil when none is configured
+var repoCache *repositoryCache
+
+// OpenRepositoryCache loads the repository cache from a file, starting empty if it does not exist yet
+func OpenRepositoryCache(path string) error {
+	cache := &repositoryCache{path: path, repositories: make(map[string]cachedRepository)}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read repository cache: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &cache.repositories); err != nil {
+			return fmt.Errorf("failed to parse repository cache: %w", err)
+		}
+		if cache.repositories == nil {
+			cache.repositories = make(map[string]cachedRepository)
+		}
+	}
+
+	repoCache = cache
+	return nil
+}
+
+// SaveRepositoryCache writes the repository cache back to its file; it does nothing without a cache
+func SaveRepositoryCache() error {
+	if repoCache == nil {
+		return nil
+	}
+
+	repoCache.mu.Lock()
+	data, err := json.MarshalIndent(repoCache.repositories, "", "  ")
+	repoCache.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal repository cache: %w", err)
+	}
+
+	if err := writeFileAtomic(repoCache.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write repository cache: %w", err)
+	}
+	return nil
+}
+
+// cacheFingerprint identifies the options that shape the cached commit and contributor data,
+// so a run with different options does not reuse data collected differently
+func cacheFingerprint(cfg config.Config) string {
+	return fmt.Sprintf("branch=%s path=%s path-contributors=%t scope=%s fallback=%s contributor-days=%d commits=%t contributors=%t details=%t report=%t suspended=%t weighted=%t map=%s as-of=%s",
+		cfg.Branch, commitPath, pathContributors, cfg.ContributorScope, cfg.MembershipFallback, cfg.ContributorWindowDays(),
+		collects(cfg, MetricCommits), collects(cfg, MetricContributors), describesContributors(cfg), reportsContributors(cfg), cfg.CheckSuspended, cfg.WeightedContributors,
+		contributorMap.Digest(), asOfKey())
+}
+
+// reusable reports whether a cached entry can stand in for fresh commit and contributor calls:
+// the repository must not have been pushed to since, and the data must have been collected the same way
+func (c cachedRepository) reusable(pushedAt time.Time, cfg config.Config) bool {
+	return !pushedAt.IsZero() && c.PushedAt.Equal(pushedAt) && c.Fingerprint == cacheFingerprint(cfg)
+}
+
+// contributorsFresh reports whether the cached contributor data can be reused without checking membership again:
+// contributors are not collected, no contributor TTL is set, or they were checked within it
+func (c cachedRepository) contributorsFresh(cfg config.Config) bool {
+	if !collects(cfg, MetricContributors) || cfg.RepoCacheContributorTTL == 0 {
+		return true
+	}
+	return time.Since(c.ContributorsCheckedAt) < cfg.RepoCacheContributorTTL
+}
+
+// lookupRepositoryCache returns the cached data of a repository when it is still valid
+func lookupRepositoryCache(repoFullName string, pushedAt time.Time, cfg config.Config) (cachedRepository, bool) {
+	if repoCache == nil {
+		return cachedRepository{}, false
+	}
+
+	repoCache.mu.Lock()
+	cached, ok := repoCache.repositories[repoFullName]
+	repoCache.mu.Unlock()
+
+	if !ok || !cached.reusable(pushedAt, cfg) {
+		return cachedRepository{}, false
+	}
+	return cached, true
+}
+
+// storeRepositoryCache records the commit and contributor data of a freshly analyzed repository
+// Incomplete contributor data is not cached so that the next run retries it
+func storeRepositoryCache(r Repository, pushedAt time.Time, cfg config.Config) {
+	if repoCache == nil || pushedAt.IsZero() {
+		return
+	}
+
+	repoCache.mu.Lock()
+	defer repoCache.mu.Unlock()
+
+	if collects(cfg, MetricContributors) && !r.ContributorDataComplete {
+		delete(repoCache.repositories, r.Name)
+		return
+	}
+	repoCache.repositories[r.Name] = cachedRepository{
+		PushedAt:                   pushedAt,
+		Fingerprint:                cacheFingerprint(cfg),
+		ContributorsCheckedAt:      time.Now(),
+		LastCommitDate:             r.LastCommitDate,
+		CommitStatus:               r.CommitStatus,
+		ContributorDataComplete:    r.ContributorDataComplete,
+		TotalContributors:          r.TotalContributors,
+		InactiveContributors:       r.InactiveContributors,
+		SuspendedContributors:      r.SuspendedContributors,
+		WeightedInactivePercentage: r.WeightedInactivePercentage,
+		InactiveContributorDetails: r.InactiveContributorDetails,
+		ActiveContributorLogins:    r.ActiveContributorLogins,
+		DepartedContributors:       r.DepartedContributors,
+	}
+}
+
+// applyCommits copies the cached commit data into a repository, recomputing its age as of now
+func (c cachedRepository) applyCommits(r *Repository, now time.Time) {
+	if !c.LastCommitDate.IsZero() {
+		r.LastCommitDate = c.LastCommitDate
+		r.DaysSinceLastCommit = int(now.Sub(c.LastCommitDate).Hours() / 24)
+	}
+	r.CommitStatus = c.CommitStatus
+}
+
+// applyContributors copies the cached contributor data into a repository
+func (c cachedRepository) applyContributors(r *Repository) {
+	r.ContributorDataComplete = c.ContributorDataComplete
+	r.TotalContributors = c.TotalContributors
+	r.InactiveContributors = c.InactiveContributors
+	r.SuspendedContributors = c.SuspendedContributors
+	r.WeightedInactivePercentage = c.WeightedInactivePercentage
+	r.InactiveContributorDetails = c.InactiveContributorDetails
+	r.ActiveContributorLogins = c.ActiveContributorLogins
+	r.DepartedContributors = c.DepartedContributors
+	if c.TotalContributors > 0 {
+		r.InactivePercentage = float64(c.InactiveContributors) / float64(c.TotalContributors)
+	}
+}
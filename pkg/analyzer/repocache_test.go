@@ -0,0 +1,165 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// openTestRepositoryCache opens a repository cache in a temporary directory for the duration of a test
+func openTestRepositoryCache(t *testing.T, path string) {
+	t.Helper()
+	if err := OpenRepositoryCache(path); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { repoCache = nil })
+}
+
+func TestRepositoryCacheReuse(t *testing.T) {
+	pushedAt := testNow.AddDate(0, 0, -50)
+	cfg := config.Config{MaxCommitAgeInDays: 180}
+	repo := Repository{
+		Name:                    "o/r",
+		LastCommitDate:          testNow.AddDate(0, 0, -50),
+		ContributorDataComplete: true,
+		TotalContributors:       4,
+		InactiveContributors:    1,
+		ActiveContributorLogins: []string{"ann", "bob", "cy"},
+	}
+
+	tests := []struct {
+		name     string
+		repo     Repository
+		pushedAt time.Time
+		cfg      config.Config
+		want     bool
+	}{
+		{"unchanged", repo, pushedAt, cfg, true},
+		{"pushed since", repo, pushedAt.Add(time.Hour), cfg, false},
+		{"unknown push time", repo, time.Time{}, cfg, false},
+		{"other branch", repo, pushedAt, withConfig(cfg, func(c *config.Config) { c.Branch = "dev" }), false},
+		{"other contributor window", repo, pushedAt, withConfig(cfg, func(c *config.Config) { c.ContributorDays = 30 }), false},
+		{"other repository", Repository{Name: "o/other"}, pushedAt, cfg, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			openTestRepositoryCache(t, filepath.Join(t.TempDir(), "cache.json"))
+			storeRepositoryCache(repo, pushedAt, cfg)
+
+			if _, ok := lookupRepositoryCache(tt.repo.Name, tt.pushedAt, tt.cfg); ok != tt.want {
+				t.Errorf("cache reused = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepositoryCacheIncompleteNotStored(t *testing.T) {
+	openTestRepositoryCache(t, filepath.Join(t.TempDir(), "cache.json"))
+	pushedAt := testNow
+	cfg := config.Config{MaxCommitAgeInDays: 180}
+
+	storeRepositoryCache(Repository{Name: "o/r", ContributorDataComplete: true}, pushedAt, cfg)
+	storeRepositoryCache(Repository{Name: "o/r", ContributorDataComplete: false}, pushedAt, cfg)
+	if _, ok := lookupRepositoryCache("o/r", pushedAt, cfg); ok {
+		t.Error("incomplete contributor data was cached, or left a stale entry")
+	}
+}
+
+func TestRepositoryCacheSaveAndReopen(t *testing.T) {
+	pinNow(t)
+	path := filepath.Join(t.TempDir(), "cache.json")
+	pushedAt := testNow.AddDate(0, 0, -20)
+	cfg := config.Config{MaxCommitAgeInDays: 180}
+
+	openTestRepositoryCache(t, path)
+	storeRepositoryCache(Repository{
+		Name:                    "o/r",
+		LastCommitDate:          testNow.AddDate(0, 0, -20),
+		ContributorDataComplete: true,
+		TotalContributors:       4,
+		InactiveContributors:    1,
+	}, pushedAt, cfg)
+	if err := SaveRepositoryCache(); err != nil {
+		t.Fatal(err)
+	}
+
+	openTestRepositoryCache(t, path)
+	cached, ok := lookupRepositoryCache("o/r", pushedAt, cfg)
+	if !ok {
+		t.Fatal("saved entry not reused after reopening the cache")
+	}
+
+	// The ages follow the clock of the run applying the entry
+	var repo Repository
+	cached.applyCommits(&repo, testNow.AddDate(0, 0, 10))
+	cached.applyContributors(&repo)
+	if repo.DaysSinceLastCommit != 30 || repo.InactivePercentage != 0.25 || repo.TotalContributors != 4 {
+		t.Errorf("applied repository = %+v, want 30 days since the last commit and 25%% inactive of 4", repo)
+	}
+}
+
+func TestRepositoryCacheContributorTTL(t *testing.T) {
+	pinNow(t)
+	metadata := fmt.Sprintf(`{"pushed_at":%q}`, testNow.AddDate(0, 0, -400).Format(time.RFC3339))
+	logPath := fakeGH(t, analyzeScript(metadata, 400))
+	SetCacheTTL(0)
+	fastMembershipRetries(t)
+	openTestRepositoryCache(t, filepath.Join(t.TempDir(), "cache.json"))
+	cfg := withConfig(flaggingConfig, func(c *config.Config) { c.RepoCacheContributorTTL = time.Hour; c.Silent = true })
+
+	// analyze runs the analysis and counts the commit and contributor calls it made
+	seen := 0
+	analyze := func() (commits, contributors int) {
+		t.Helper()
+		if _, _, err := AnalyzeRepository("o/r", cfg); err != nil {
+			t.Fatal(err)
+		}
+		calls := ghCalls(t, logPath)
+		for _, call := range calls[seen:] {
+			switch {
+			case strings.Contains(call, "/commits"):
+				commits++
+			case strings.Contains(call, "/contributors"):
+				contributors++
+			}
+		}
+		seen = len(calls)
+		return commits, contributors
+	}
+
+	if commits, contributors := analyze(); commits == 0 || contributors == 0 {
+		t.Fatalf("first run made %d commit and %d contributor calls, want both fetched", commits, contributors)
+	}
+	if commits, contributors := analyze(); commits != 0 || contributors != 0 {
+		t.Errorf("run within the contributor TTL made %d commit and %d contributor calls, want the cache reused", commits, contributors)
+	}
+
+	// Once the contributor data expires, contributors are checked again while the unchanged commit data is still reused
+	repoCache.mu.Lock()
+	entry := repoCache.repositories["o/r"]
+	entry.ContributorsCheckedAt = time.Now().Add(-2 * time.Hour)
+	repoCache.repositories["o/r"] = entry
+	repoCache.mu.Unlock()
+	if commits, contributors := analyze(); commits != 0 || contributors == 0 {
+		t.Errorf("run past the contributor TTL made %d commit and %d contributor calls, want only contributors fetched", commits, contributors)
+	}
+	if checked := repoCache.repositories["o/r"].ContributorsCheckedAt; time.Since(checked) > time.Minute {
+		t.Errorf("contributors checked at %s after the refresh, want now", checked)
+	}
+}
+
+func TestOpenRepositoryCacheMalformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { repoCache = nil })
+	if err := OpenRepositoryCache(path); err == nil || !strings.Contains(err.Error(), "failed to parse repository cache") {
+		t.Errorf("OpenRepositoryCache error = %v, want a parse failure", err)
+	}
+}
@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// repoListCache is the saved list of an organization's repositories
+type repoListCache struct {
+	Organization string    `json:"organization"`
+	Visibility   string    `json:"visibility"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+	Repositories []string  `json:"repositories"`
+}
+
+// listOrganizationRepositories returns the organization's repository names, reusing the
+// repository list cache while it is younger than the TTL and refreshing it otherwise
+func listOrganizationRepositories(cfg config.Config) ([]string, error) {
+	if cfg.RepoListCache == "" {
+		return fetchOrganizationRepositories(cfg)
+	}
+
+	if !cfg.RefreshRepoList {
+		names, ok, err := loadRepoListCache(cfg.RepoListCache, cfg, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			if !cfg.Silent {
+				Logf("📄 Using cached repository list from %s\n", cfg.RepoListCache)
+			}
+			return names, nil
+		}
+	}
+
+	names, err := fetchOrganizationRepositories(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := saveRepoListCache(cfg.RepoListCache, cfg, names, time.Now()); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// loadRepoListCache reads the cached repository names, reporting whether they can be used:
+// the cache must exist, match the organization and visibility, and be younger than the TTL
+func loadRepoListCache(path string, cfg config.Config, now time.Time) ([]string, bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read repository list cache: %w", err)
+	}
+
+	var cache repoListCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, false, fmt.Errorf("failed to parse repository list cache: %w", err)
+	}
+
+	if !strings.EqualFold(cache.Organization, cfg.Organization) || cache.Visibility != cfg.Visibility {
+		return nil, false, nil
+	}
+	if now.Sub(cache.FetchedAt) > cfg.RepoListCacheTTL {
+		return nil, false, nil
+	}
+
+	return cache.Repositories, true, nil
+}
+
+// saveRepoListCache writes the repository names with the time they were fetched
+func saveRepoListCache(path string, cfg config.Config, names []string, now time.Time) error {
+	data, err := json.MarshalIndent(repoListCache{
+		Organization: cfg.Organization,
+		Visibility:   cfg.Visibility,
+		FetchedAt:    now.UTC(),
+		Repositories: names,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal repository list cache: %w", err)
+	}
+
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write repository list cache: %w", err)
+	}
+	return nil
+}
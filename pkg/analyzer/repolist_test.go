@@ -0,0 +1,107 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestLoadRepoListCache(t *testing.T) {
+	cfg := config.Config{Organization: "o", Visibility: "public", RepoListCacheTTL: time.Hour}
+	names := []string{"a", "b"}
+
+	tests := []struct {
+		name string
+		cfg  config.Config
+		now  time.Time
+		want bool
+	}{
+		{"fresh", cfg, testNow.Add(30 * time.Minute), true},
+		{"organization in another case", withConfig(cfg, func(c *config.Config) { c.Organization = "O" }), testNow, true},
+		{"expired", cfg, testNow.Add(2 * time.Hour), false},
+		{"other organization", withConfig(cfg, func(c *config.Config) { c.Organization = "p" }), testNow, false},
+		{"other visibility", withConfig(cfg, func(c *config.Config) { c.Visibility = "" }), testNow, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "repos.json")
+			if err := saveRepoListCache(path, cfg, names, testNow); err != nil {
+				t.Fatal(err)
+			}
+
+			got, ok, err := loadRepoListCache(path, tt.cfg, tt.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.want {
+				t.Errorf("cache used = %v, want %v", ok, tt.want)
+			}
+			if ok && !reflect.DeepEqual(got, names) {
+				t.Errorf("cached names = %q, want %q", got, names)
+			}
+		})
+	}
+}
+
+func TestLoadRepoListCacheUnreadable(t *testing.T) {
+	dir := t.TempDir()
+	if _, ok, err := loadRepoListCache(filepath.Join(dir, "missing.json"), config.Config{}, testNow); ok || err != nil {
+		t.Errorf("missing cache used = %v with error %v, want it refetched silently", ok, err)
+	}
+
+	path := filepath.Join(dir, "repos.json")
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := loadRepoListCache(path, config.Config{}, testNow); err == nil || !strings.Contains(err.Error(), "failed to parse repository list cache") {
+		t.Errorf("loadRepoListCache error = %v, want a parse failure", err)
+	}
+}
+
+func TestListOrganizationRepositoriesCache(t *testing.T) {
+	tests := []struct {
+		name      string
+		cached    []string
+		refresh   bool
+		want      []string
+		wantCalls int
+	}{
+		{"no cache yet", nil, false, []string{"fetched"}, 1},
+		{"cached", []string{"cached"}, false, []string{"cached"}, 0},
+		{"refreshed", []string{"cached"}, true, []string{"fetched"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, `echo fetched`)
+			SetCacheTTL(0)
+			path := filepath.Join(t.TempDir(), "repos.json")
+			cfg := config.Config{Organization: "o", RepoListCache: path, RepoListCacheTTL: time.Hour, RefreshRepoList: tt.refresh, Silent: true}
+			if tt.cached != nil {
+				if err := saveRepoListCache(path, cfg, tt.cached, time.Now()); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := listOrganizationRepositories(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("repositories = %q, want %q", got, tt.want)
+			}
+			if calls := ghCalls(t, logPath); len(calls) != tt.wantCalls {
+				t.Errorf("made %d calls, want %d", len(calls), tt.wantCalls)
+			}
+
+			// A fetched list replaces the cache
+			if saved, ok, err := loadRepoListCache(path, cfg, time.Now()); err != nil || !ok || !reflect.DeepEqual(saved, tt.want) {
+				t.Errorf("cache holds %q (usable %v, error %v), want %q", saved, ok, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package analyzer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// Input formats of the repository list file
+const (
+	InputFormatLines = "lines"
+	InputFormatCSV   = "csv"
+)
+
+// RepoListEntry is a repository listed in the input file, with the extra columns carried to the report
+type RepoListEntry struct {
+	Identifier string
+	Extra      map[string]string
+}
+
+// InputFormat returns the configured input format, detecting CSV files by their extension
+func InputFormat(cfg config.Config) string {
+	if cfg.InputFormat != "" {
+		return cfg.InputFormat
+	}
+	if strings.EqualFold(filepath.Ext(cfg.RepoListFile), ".csv") {
+		return InputFormatCSV
+	}
+	return InputFormatLines
+}
+
+// ReadRepoList reads the repositories listed in the input file, one per line or from a CSV column
+func ReadRepoList(cfg config.Config) ([]RepoListEntry, error) {
+	file, err := os.Open(cfg.RepoListFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository list file: %w", err)
+	}
+	defer file.Close()
+
+	if InputFormat(cfg) == InputFormatCSV {
+		return parseRepoListCSV(file, cfg.RepoColumn, cfg.ExtraColumns)
+	}
+	return parseRepoListLines(file)
+}
+
+// DedupRepoList normalizes the listed repositories to org/repo form and drops repeated ones,
+// returning the remaining entries and how many duplicates were collapsed
+// Owners are compared case-insensitively and repository names case-sensitively; the first entry wins.
+// Entries that cannot be parsed are kept as they are, so that their analysis reports the error.
+func DedupRepoList(entries []RepoListEntry) ([]RepoListEntry, int) {
+	seen := make(map[string]bool)
+	var unique []RepoListEntry
+	duplicates := 0
+
+	for _, entry := range entries {
+		repoFullName, branch, err := ParseRepoIdentifier(entry.Identifier)
+		if err != nil {
+			unique = append(unique, entry)
+			continue
+		}
+
+		owner, name, _ := strings.Cut(repoFullName, "/")
+		key := strings.ToLower(owner) + "/" + name + "@" + branch
+		if seen[key] {
+			duplicates++
+			continue
+		}
+		seen[key] = true
+
+		entry.Identifier = repoFullName
+		if branch != "" {
+			entry.Identifier += "@" + branch
+		}
+		unique = append(unique, entry)
+	}
+
+	return unique, duplicates
+}
+
+// parseRepoListLines reads one repository per line, skipping empty lines
+func parseRepoListLines(r io.Reader) ([]RepoListEntry, error) {
+	var entries []RepoListEntry
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		entries = append(entries, RepoListEntry{Identifier: line})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading repository list file: %w", err)
+	}
+	return entries, nil
+}
+
+// parseRepoListCSV reads repositories from a column of a CSV file with a header row
+// The repository column defaults to the first one; rows with an empty repository cell are skipped
+func parseRepoListCSV(r io.Reader, repoColumn string, extraColumns []string) ([]RepoListEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	repoIndex := 0
+	if repoColumn != "" {
+		if repoIndex, err = csvColumnIndex(header, repoColumn); err != nil {
+			return nil, err
+		}
+	}
+
+	extraIndexes := make([]int, len(extraColumns))
+	for i, column := range extraColumns {
+		if extraIndexes[i], err = csvColumnIndex(header, column); err != nil {
+			return nil, err
+		}
+	}
+
+	var entries []RepoListEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		identifier := csvCell(record, repoIndex)
+		if identifier == "" {
+			continue
+		}
+
+		entry := RepoListEntry{Identifier: identifier}
+		if len(extraIndexes) > 0 {
+			entry.Extra = make(map[string]string, len(extraIndexes))
+			for _, index := range extraIndexes {
+				entry.Extra[header[index]] = csvCell(record, index)
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// csvColumnIndex resolves a column given by header name (case-insensitive) or 1-based index
+func csvColumnIndex(header []string, column string) (int, error) {
+	column = strings.TrimSpace(column)
+	for i, name := range header {
+		if strings.EqualFold(name, column) {
+			return i, nil
+		}
+	}
+
+	if n, err := strconv.Atoi(column); err == nil {
+		if n < 1 || n > len(header) {
+			return 0, fmt.Errorf("column %d out of range, the CSV has %d columns", n, len(header))
+		}
+		return n - 1, nil
+	}
+
+	return 0, fmt.Errorf("column %q not found, available columns are: %s", column, strings.Join(header, ", "))
+}
+
+// csvCell returns a trimmed cell of a record, or an empty string for short rows
+func csvCell(record []string, index int) string {
+	if index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
+
+// RepoListExtraColumns returns the extra column names carried by any entry, in the order reports list them
+func RepoListExtraColumns(entries []RepoListEntry) []string {
+	repos := make([]Repository, len(entries))
+	for i, entry := range entries {
+		repos[i].Extra = entry.Extra
+	}
+	return extraColumnNames(repos)
+}
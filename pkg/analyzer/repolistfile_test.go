@@ -0,0 +1,175 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestInputFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want string
+	}{
+		{"lines by default", config.Config{RepoListFile: "repos.txt"}, InputFormatLines},
+		{"csv by extension", config.Config{RepoListFile: "repos.CSV"}, InputFormatCSV},
+		{"explicit lines", config.Config{RepoListFile: "repos.csv", InputFormat: InputFormatLines}, InputFormatLines},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InputFormat(tt.cfg); got != tt.want {
+				t.Errorf("InputFormat = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadRepoList(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		cfg     config.Config
+		want    []RepoListEntry
+		wantErr string
+	}{
+		{
+			name:    "lines",
+			file:    "repos.txt",
+			content: "o/a\n\n  o/b@dev  \n",
+			want:    []RepoListEntry{{Identifier: "o/a"}, {Identifier: "o/b@dev"}},
+		},
+		{
+			name:    "first column",
+			file:    "repos.csv",
+			content: "repo,team\no/a,core\n,infra\no/b,web\n",
+			want:    []RepoListEntry{{Identifier: "o/a"}, {Identifier: "o/b"}},
+		},
+		{
+			name:    "named column with extras",
+			file:    "repos.csv",
+			content: "Team, Repository ,Owner\ncore,o/a,ann\nweb, o/b\n",
+			cfg:     config.Config{RepoColumn: "repository", ExtraColumns: []string{"Team", "owner"}},
+			want: []RepoListEntry{
+				{Identifier: "o/a", Extra: map[string]string{"Team": "core", "Owner": "ann"}},
+				{Identifier: "o/b", Extra: map[string]string{"Team": "web", "Owner": ""}},
+			},
+		},
+		{
+			name:    "column by index",
+			file:    "repos.csv",
+			content: "team,repo\ncore,\"o/a\"\n",
+			cfg:     config.Config{RepoColumn: "2"},
+			want:    []RepoListEntry{{Identifier: "o/a"}},
+		},
+		{
+			name:    "csv forced on another extension",
+			file:    "repos.txt",
+			content: "repo\no/a\n",
+			cfg:     config.Config{InputFormat: InputFormatCSV},
+			want:    []RepoListEntry{{Identifier: "o/a"}},
+		},
+		{
+			name:    "empty csv",
+			file:    "repos.csv",
+			content: "",
+		},
+		{
+			name:    "missing column",
+			file:    "repos.csv",
+			content: "repo,team\no/a,core\n",
+			cfg:     config.Config{RepoColumn: "name"},
+			wantErr: `column "name" not found, available columns are: repo, team`,
+		},
+		{
+			name:    "index out of range",
+			file:    "repos.csv",
+			content: "repo,team\no/a,core\n",
+			cfg:     config.Config{ExtraColumns: []string{"3"}},
+			wantErr: "column 3 out of range",
+		},
+		{
+			name:    "malformed csv",
+			file:    "repos.csv",
+			content: "repo\n\"o/a\n",
+			wantErr: "failed to read CSV",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			tt.cfg.RepoListFile = path
+
+			got, err := ReadRepoList(tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadRepoList error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadRepoList = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadRepoListMissingFile(t *testing.T) {
+	_, err := ReadRepoList(config.Config{RepoListFile: filepath.Join(t.TempDir(), "missing.txt")})
+	if err == nil || !strings.Contains(err.Error(), "failed to open repository list file") {
+		t.Errorf("ReadRepoList error = %v, want an open failure", err)
+	}
+}
+
+func TestDedupRepoList(t *testing.T) {
+	entries := func(identifiers ...string) []RepoListEntry {
+		list := make([]RepoListEntry, len(identifiers))
+		for i, identifier := range identifiers {
+			list[i] = RepoListEntry{Identifier: identifier}
+		}
+		return list
+	}
+
+	tests := []struct {
+		name           string
+		entries        []RepoListEntry
+		want           []RepoListEntry
+		wantDuplicates int
+	}{
+		{"no duplicates", entries("o/a", "o/b"), entries("o/a", "o/b"), 0},
+		{"urls normalized", entries("https://github.com/o/a.git", "http://github.com/o/b/"), entries("o/a", "o/b"), 0},
+		{"url and name", entries("o/a", "https://github.com/o/a"), entries("o/a"), 1},
+		{"owner case-insensitive", entries("Org/a", "org/a", "ORG/a"), entries("Org/a"), 2},
+		{"name case-sensitive", entries("o/a", "o/A"), entries("o/a", "o/A"), 0},
+		{"branches kept apart", entries("o/a", "o/a@dev", "o/a#dev"), entries("o/a", "o/a@dev"), 1},
+		{"unparseable kept", entries("not-a-repo", "not-a-repo", "o/a"), entries("not-a-repo", "not-a-repo", "o/a"), 0},
+		{
+			name: "first entry's extras win",
+			entries: []RepoListEntry{
+				{Identifier: "o/a", Extra: map[string]string{"team": "core"}},
+				{Identifier: "O/a", Extra: map[string]string{"team": "web"}},
+			},
+			want:           []RepoListEntry{{Identifier: "o/a", Extra: map[string]string{"team": "core"}}},
+			wantDuplicates: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, duplicates := DedupRepoList(tt.entries)
+			if !reflect.DeepEqual(got, tt.want) || duplicates != tt.wantDuplicates {
+				t.Errorf("DedupRepoList = %+v with %d duplicates, want %+v with %d", got, duplicates, tt.want, tt.wantDuplicates)
+			}
+		})
+	}
+}
@@ -0,0 +1,505 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+	"github.com/harekrishnarai/inactivity/pkg/version"
+)
+
+func init() {
+	RegisterFormatter("json", jsonFormatter{})
+	RegisterFormatter("csv", csvFormatter{})
+}
+
+// renderTextReport builds the plain text report with the summary and flagged repositories,
+// sectioned by flag reason when configured to
+// The totals come from the summary, so they stay true when the listed repositories are limited
+func renderTextReport(repos []Repository, summary Summary) []byte {
+	cfg := summary.Config
+	flaggedCount := 0
+	for _, repo := range repos {
+		if repo.Flagged {
+			flaggedCount++
+		}
+	}
+
+	var reportBuf bytes.Buffer
+	reportBuf.WriteString(fmt.Sprintf("Analysis Results for %s\n", reportSubject(cfg)))
+	reportBuf.WriteString(fmt.Sprintf("Date: %s\n", Now().Format("2006-01-02")))
+	if cfg.Organization != "" {
+		reportBuf.WriteString(fmt.Sprintf("Visibility: %s\n", cfg.Visibility))
+	}
+	reportBuf.WriteString(fmt.Sprintf("Total repositories analyzed: %d\n", summary.Total))
+	reportBuf.WriteString(fmt.Sprintf("Flagged repositories: %d (%d inactive, %d archived)\n", summary.Flagged, summary.Inactive, summary.Archived))
+	if summary.Lifecycle != nil {
+		reportBuf.WriteString(fmt.Sprintf("Lifecycle: %s\n", lifecycleSummary(summary.Lifecycle)))
+	}
+	reportBuf.WriteString(fmt.Sprintf("Report ID: %s\n", reportIDSummary(repos, cfg)))
+	if note := topNote(repos, summary); note != "" {
+		reportBuf.WriteString(note + "\n")
+	}
+	if note := deadlineNote(summary); note != "" {
+		reportBuf.WriteString(note + "\n")
+	}
+	if !cfg.Silent {
+		reportBuf.WriteString(flagLegend(cfg) + "\n")
+	}
+	reportBuf.WriteString("\n")
+
+	if flaggedCount > 0 && cfg.GroupByReason {
+		for _, group := range groupByFlagReason(repos) {
+			reportBuf.WriteString(fmt.Sprintf("🚩 %s (%d):\n", flagReasonHeading(group.Reason), len(group.Repos)))
+			reportBuf.WriteString("---------------------\n")
+			for _, repo := range group.Repos {
+				writeTextRepository(&reportBuf, repo, cfg)
+			}
+		}
+	} else if flaggedCount > 0 {
+		reportBuf.WriteString("🚩 Flagged Repositories:\n")
+		reportBuf.WriteString("---------------------\n")
+		for _, repo := range repos {
+			if repo.Flagged {
+				writeTextRepository(&reportBuf, repo, cfg)
+			}
+		}
+	}
+
+	return reportBuf.Bytes()
+}
+
+// writeTextRepository writes a flagged repository and its metrics to the plain text report
+func writeTextRepository(buf *bytes.Buffer, repo Repository, cfg config.Config) {
+	buf.WriteString(fmt.Sprintf("- %s (reason: %s)%s\n", displayName(repo), repo.FlagReason, priorityMarker(repo)))
+	buf.WriteString(fmt.Sprintf("  Last commit: %s\n", lastCommitSummary(repo, cfg)))
+	if repo.Lifecycle != "" {
+		buf.WriteString(fmt.Sprintf("  Lifecycle: %s\n", repo.Lifecycle))
+	}
+	if repo.LastSubstantiveCommitDate != nil {
+		buf.WriteString(fmt.Sprintf("  Last substantive commit: %s\n", substantiveCommitSummary(repo, cfg)))
+	}
+	if repo.SignedCommitRatio != nil {
+		buf.WriteString(fmt.Sprintf("  Signed commits: %s\n", signedCommitSummary(repo)))
+	}
+	buf.WriteString(fmt.Sprintf("  Contributors: %s\n", contributorSummary(repo)))
+	if len(repo.InactiveContributorDetails) > 0 && cfg.GroupContributors {
+		writeContributorGroups(buf, "  ", repo.InactiveContributorDetails)
+	} else if len(repo.InactiveContributorDetails) > 0 {
+		buf.WriteString(fmt.Sprintf("  Inactive contributors: %s\n", inactiveContributorSummary(repo.InactiveContributorDetails)))
+	}
+	if !repo.CreatedAt.IsZero() {
+		buf.WriteString(fmt.Sprintf("  Created: %s\n", repoAgeSummary(repo, cfg)))
+	}
+	buf.WriteString(fmt.Sprintf("  License: %s\n", repo.License))
+	if repo.HasReadme != nil {
+		buf.WriteString(fmt.Sprintf("  README: %s\n", readmeSummary(repo)))
+	}
+	if repo.LastCIStatus != "" {
+		buf.WriteString(fmt.Sprintf("  CI: %s\n", ciSummary(repo)))
+	}
+	if repo.OpenSecurityAlerts != nil || repo.SecurityAlertsUnknown {
+		buf.WriteString(fmt.Sprintf("  Open security alerts: %s\n", securityAlertSummary(repo)))
+	}
+	if collects(cfg, MetricReviews) {
+		buf.WriteString(fmt.Sprintf("  Last merged PR: %s\n", mergedPRSummary(repo, cfg)))
+	}
+	if collects(cfg, MetricEngagement) {
+		buf.WriteString(fmt.Sprintf("  Issue engagement: %s\n", engagementSummary(repo)))
+	}
+	if collects(cfg, MetricTags) {
+		buf.WriteString(fmt.Sprintf("  Last tag: %s\n", tagSummary(repo, cfg)))
+	}
+	if collects(cfg, MetricBranches) {
+		buf.WriteString(fmt.Sprintf("  Newest other branch: %s\n", newestBranchSummary(repo, cfg)))
+	}
+	if repo.CommitMomentum != nil {
+		buf.WriteString(fmt.Sprintf("  Momentum: %s\n", momentumSummary(repo)))
+	}
+	if repo.MedianCommitGapDays != nil {
+		buf.WriteString(fmt.Sprintf("  Cadence: %s\n", cadenceSummary(repo, cfg)))
+	}
+	if repo.StaleBotPRCount != nil {
+		buf.WriteString(fmt.Sprintf("  Stale bot PRs: %s\n", staleBotPRSummary(repo, cfg)))
+	}
+	if len(repo.Extra) > 0 {
+		buf.WriteString(fmt.Sprintf("  %s\n", extraSummary(repo)))
+	}
+	if cfg.Governance {
+		buf.WriteString(fmt.Sprintf("  Governance: %s\n", governanceSummary(repo)))
+	}
+	if len(repo.Admins) > 0 {
+		buf.WriteString(fmt.Sprintf("  Admins: %s\n", strings.Join(repo.Admins, ", ")))
+	}
+	if repo.Contact != "" {
+		buf.WriteString(fmt.Sprintf("  Contact: %s\n", repo.Contact))
+	}
+	if repo.Archived {
+		buf.WriteString("  Repository Status: Archived\n\n")
+	} else {
+		buf.WriteString("  Repository Status: Not Archived\n\n")
+	}
+}
+
+// renderRemovedSection lists repositories that disappeared since the previous run
+func renderRemovedSection(removed []string) []byte {
+	if len(removed) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("Removed Since Last Run:\n")
+	buf.WriteString("---------------------\n")
+	for _, name := range removed {
+		buf.WriteString(fmt.Sprintf("- %s\n", name))
+	}
+	buf.WriteString("\n")
+	return buf.Bytes()
+}
+
+// renderDuplicatesSection lists the likely duplicate clusters among the flagged repositories
+func renderDuplicatesSection(duplicates []DuplicateCluster) []byte {
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("Likely Duplicates:\n")
+	buf.WriteString("---------------------\n")
+	for _, c := range duplicates {
+		buf.WriteString(fmt.Sprintf("- %s\n", duplicateClusterSummary(c)))
+	}
+	buf.WriteString("\n")
+	return buf.Bytes()
+}
+
+// RenderSummaryText returns the plain text summary and flagged repository list
+func RenderSummaryText(analysis Analysis, cfg config.Config) string {
+	repos := FilterRepositories(analysis.Repositories, cfg)
+	summary := newSummary(repos, 0, nil, analysis.Warnings, false, cfg)
+	repos, summary = redactReport(MostInactive(repos, cfg.Top), summary)
+	return string(renderTextReport(repos, summary))
+}
+
+// RenderReport renders the analysis results in the configured format for delivery outside the terminal
+func RenderReport(analysis Analysis, cfg config.Config) ([]byte, error) {
+	repos := FilterRepositories(analysis.Repositories, cfg)
+
+	summary := newSummary(repos, analysis.Skipped, nil, analysis.Warnings, false, cfg)
+	repos, summary = redactReport(MostInactive(repos, cfg.Top), summary)
+
+	var buf bytes.Buffer
+	if err := formatterFor(cfg.OutputFormat).Format(&buf, repos, summary); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// jsonFormatter renders the JSON report, or the bare repository object for a single repository
+type jsonFormatter struct{}
+
+// Format writes the JSON report
+func (jsonFormatter) Format(w io.Writer, repos []Repository, summary Summary) error {
+	var data []byte
+	var err error
+	if summary.Single && len(repos) == 1 {
+		data, err = renderJSONObject(repos[0], summary.Config)
+	} else {
+		data, err = renderJSONReport(repos, summary)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// csvFormatter renders the CSV report
+type csvFormatter struct{}
+
+// Format writes the CSV report
+func (csvFormatter) Format(w io.Writer, repos []Repository, summary Summary) error {
+	data, err := renderCSV(repos, summary.Config)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// WriteNotes prints the analysis summary after a multi-repository CSV report
+func (csvFormatter) WriteNotes(w io.Writer, repos []Repository, summary Summary) error {
+	if !summary.Single {
+		writeSummary(w, repos, summary)
+	}
+	return nil
+}
+
+// ReportFileType returns the MIME content type and file extension for an output format
+func ReportFileType(format string) (contentType, extension string) {
+	switch {
+	case format == "json":
+		return "application/json", ".json"
+	case format == "csv":
+		return "text/csv", ".csv"
+	case IsNDJSONFormat(format):
+		return "application/x-ndjson", ".ndjson"
+	case IsMarkdownFormat(format) || format == "task-list":
+		return "text/markdown; charset=utf-8", ".md"
+	default:
+		return "text/plain; charset=utf-8", ".txt"
+	}
+}
+
+// jsonReport wraps the repositories of a multi-repository JSON report with the context of the run
+type jsonReport struct {
+	ToolVersion      string             `json:"toolVersion"`
+	ReportID         string             `json:"reportId"`
+	Organization     string             `json:"organization"`
+	ForksOf          string             `json:"forksOf,omitempty"`
+	AnalyzedAt       time.Time          `json:"analyzedAt"`
+	DaysThreshold    int                `json:"daysThreshold"`
+	ContribThreshold float64            `json:"contribThreshold"`
+	TotalAnalyzed    int                `json:"totalAnalyzed"`
+	Flagged          int                `json:"flagged"`
+	FlaggedInactive  int                `json:"flaggedInactive"`
+	Archived         int                `json:"archived"`
+	Skipped          int                `json:"skipped"`
+	Partial          bool               `json:"partial,omitempty"`     // the deadline passed before every repository was analyzed
+	NotAnalyzed      int                `json:"notAnalyzed,omitempty"` // repositories left out because the deadline passed
+	Warnings         []Warning          `json:"warnings,omitempty"`
+	Duplicates       []DuplicateCluster `json:"duplicates,omitempty"`
+	Lifecycle        map[string]int     `json:"lifecycle,omitempty"` // repositories per lifecycle stage, when classified
+	Config           config.Config      `json:"config"`
+	Repositories     interface{}        `json:"repositories"`
+}
+
+// renderJSONReport renders repositories as an indented JSON object carrying the organization,
+// analysis date, thresholds, counts, and the effective configuration, with the repositories restricted to the selected fields if any
+func renderJSONReport(repos []Repository, summary Summary) ([]byte, error) {
+	cfg := summary.Config
+	var repositories interface{} = repos
+	if repos == nil {
+		repositories = []Repository{}
+	}
+	if len(cfg.Fields) > 0 {
+		selections := make([]fieldSelection, 0, len(repos))
+		for _, repo := range repos {
+			selection, err := selectFields(repo, cfg.Fields)
+			if err != nil {
+				return nil, err
+			}
+			selections = append(selections, selection)
+		}
+		repositories = selections
+	}
+
+	reportID, err := ReportID(repos, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(jsonReport{
+		ToolVersion:      version.Version,
+		ReportID:         reportID,
+		Organization:     cfg.Organization,
+		ForksOf:          cfg.ForksOf,
+		AnalyzedAt:       Now().UTC().Truncate(time.Second),
+		DaysThreshold:    cfg.MaxCommitAgeInDays,
+		ContribThreshold: cfg.InactiveContribThreshold,
+		TotalAnalyzed:    summary.Total,
+		Flagged:          summary.Flagged,
+		FlaggedInactive:  summary.Inactive,
+		Archived:         summary.Archived,
+		Skipped:          summary.Skipped,
+		Partial:          summary.NotAnalyzed > 0,
+		NotAnalyzed:      summary.NotAnalyzed,
+		Warnings:         summary.Warnings,
+		Duplicates:       summary.Duplicates,
+		Lifecycle:        summary.Lifecycle,
+		Config:           cfg.Redacted(),
+		Repositories:     repositories,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return data, nil
+}
+
+// renderJSONObject renders a single repository as an indented JSON object
+func renderJSONObject(repo Repository, cfg config.Config) ([]byte, error) {
+	var v interface{} = repo
+	if len(cfg.Fields) > 0 {
+		selection, err := selectFields(repo, cfg.Fields)
+		if err != nil {
+			return nil, err
+		}
+		v = selection
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return data, nil
+}
+
+// renderCSV renders repositories as CSV, restricted to the selected fields if any
+func renderCSV(repos []Repository, cfg config.Config) ([]byte, error) {
+	var csvBuffer bytes.Buffer
+
+	// Extra columns from a CSV repository list follow the standard ones
+	extra := extraColumnNames(repos)
+	csvBuffer.WriteString(csvHeaderLine(extra, cfg))
+	for _, repo := range repos {
+		line, err := csvLine(repo, extra, cfg)
+		if err != nil {
+			return nil, err
+		}
+		csvBuffer.WriteString(line)
+	}
+	return csvBuffer.Bytes(), nil
+}
+
+// csvOptionalColumn is a CSV column following the standard ones when the option producing its data is set,
+// so the default layout stays the same
+type csvOptionalColumn struct {
+	header  string
+	enabled func(cfg config.Config) bool
+	value   func(repo Repository) string
+}
+
+// csvOptionalColumns lists the optional CSV columns in the order they follow the standard ones
+var csvOptionalColumns = []csvOptionalColumn{
+	{"Contact", func(cfg config.Config) bool { return cfg.OwnersMap != "" },
+		func(repo Repository) string { return repo.Contact }},
+	{"Weighted Inactive Percentage", func(cfg config.Config) bool { return cfg.WeightedContributors },
+		func(repo Repository) string { return csvPercentage(repo.WeightedInactivePercentage) }},
+	{"Lifecycle", func(cfg config.Config) bool { return cfg.Lifecycle },
+		func(repo Repository) string { return repo.Lifecycle }},
+	{"Signed Commit Ratio", func(cfg config.Config) bool { return collects(cfg, MetricSigning) },
+		func(repo Repository) string { return csvRatio(repo.SignedCommitRatio) }},
+	{"Security Review", func(cfg config.Config) bool { return cfg.MinSignedRatio > 0 },
+		func(repo Repository) string { return strconv.FormatBool(repo.SecurityReview) }},
+	{"Last CI Status", func(cfg config.Config) bool { return collects(cfg, MetricCI) },
+		func(repo Repository) string { return repo.LastCIStatus }},
+	{"Last CI Date", func(cfg config.Config) bool { return collects(cfg, MetricCI) },
+		func(repo Repository) string { return csvDate(repo.LastCIDate) }},
+	{"Created At", func(cfg config.Config) bool { return cfg.MinRepoAgeDays > 0 },
+		func(repo Repository) string { return csvDate(&repo.CreatedAt) }},
+	{"Repo Age Days", func(cfg config.Config) bool { return cfg.MinRepoAgeDays > 0 },
+		func(repo Repository) string { return strconv.Itoa(repo.RepoAgeDays) }},
+	{"Open Security Alerts", func(cfg config.Config) bool { return collects(cfg, MetricSecurity) },
+		func(repo Repository) string {
+			if repo.SecurityAlertsUnknown {
+				return "unknown"
+			}
+			return csvCount(repo.OpenSecurityAlerts)
+		}},
+	{"Urgent Security", func(cfg config.Config) bool { return collects(cfg, MetricSecurity) },
+		func(repo Repository) string { return strconv.FormatBool(repo.UrgentSecurity) }},
+	{"Visibility", func(cfg config.Config) bool { return cfg.Governance },
+		func(repo Repository) string { return repo.Visibility }},
+	{"Outside Collaborators", func(cfg config.Config) bool { return cfg.Governance },
+		func(repo Repository) string { return csvCount(repo.OutsideCollaborators) }},
+	{"Exposed To Outsiders", func(cfg config.Config) bool { return cfg.Governance },
+		func(repo Repository) string { return strconv.FormatBool(repo.ExposedToOutsiders) }},
+	{"Has Readme", func(cfg config.Config) bool { return collects(cfg, MetricReadme) },
+		func(repo Repository) string {
+			if repo.HasReadme == nil {
+				return ""
+			}
+			return strconv.FormatBool(*repo.HasReadme)
+		}},
+	{"Readme Size Bytes", func(cfg config.Config) bool { return collects(cfg, MetricReadme) },
+		func(repo Repository) string { return strconv.Itoa(repo.ReadmeSizeBytes) }},
+	{"Undocumented", func(cfg config.Config) bool { return cfg.PrioritizeUndocumented },
+		func(repo Repository) string { return strconv.FormatBool(repo.Undocumented) }},
+	{"Unlicensed", func(cfg config.Config) bool { return cfg.PrioritizeUnlicensed },
+		func(repo Repository) string { return strconv.FormatBool(repo.Unlicensed) }},
+}
+
+// csvPercentage renders an optional ratio as a percentage cell, empty when unknown
+func csvPercentage(ratio *float64) string {
+	if ratio == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", *ratio*100)
+}
+
+// csvRatio renders an optional ratio cell, empty when unknown
+func csvRatio(ratio *float64) string {
+	if ratio == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", *ratio)
+}
+
+// csvCount renders an optional count cell, empty when unknown
+func csvCount(count *int) string {
+	if count == nil {
+		return ""
+	}
+	return strconv.Itoa(*count)
+}
+
+// csvDate renders an optional date cell, empty when unknown
+func csvDate(date *time.Time) string {
+	if date == nil || date.IsZero() {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
+
+// csvHeaderLine returns the CSV header: the selected fields if any, or the standard columns followed by the extra ones
+func csvHeaderLine(extra []string, cfg config.Config) string {
+	if len(cfg.Fields) > 0 {
+		return strings.Join(cfg.Fields, ",") + "\n"
+	}
+
+	header := csvHeader()
+	for _, column := range csvOptionalColumns {
+		if column.enabled(cfg) {
+			header = strings.TrimSuffix(header, "\n") + "," + column.header + "\n"
+		}
+	}
+	if len(extra) > 0 {
+		header = strings.TrimSuffix(header, "\n") + "," + strings.Join(csvQuoteAll(extra), ",") + "\n"
+	}
+	return header
+}
+
+// csvLine returns the CSV line of a repository, matching the columns of csvHeaderLine
+func csvLine(repo Repository, extra []string, cfg config.Config) (string, error) {
+	if len(cfg.Fields) > 0 {
+		selection, err := selectFields(repo, cfg.Fields)
+		if err != nil {
+			return "", err
+		}
+		cells := make([]string, len(cfg.Fields))
+		for i, field := range cfg.Fields {
+			cells[i] = selection.csvValue(field)
+		}
+		return strings.Join(cells, ",") + "\n", nil
+	}
+
+	row := csvRow(repo)
+	for _, column := range csvOptionalColumns {
+		if column.enabled(cfg) {
+			row = strings.TrimSuffix(row, "\n") + "," + csvQuoteAll([]string{column.value(repo)})[0] + "\n"
+		}
+	}
+	if len(extra) > 0 {
+		cells := make([]string, len(extra))
+		for i, name := range extra {
+			cells[i] = repo.Extra[name]
+		}
+		row = strings.TrimSuffix(row, "\n") + "," + strings.Join(csvQuoteAll(cells), ",") + "\n"
+	}
+	return row, nil
+}
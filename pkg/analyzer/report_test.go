@@ -0,0 +1,154 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestRenderReportJSON(t *testing.T) {
+	pinNow(t)
+	cfg := config.Config{Organization: "o", OutputFormat: "json", MaxCommitAgeInDays: 180, InactiveContribThreshold: 0.5}
+	repos := []Repository{
+		{Name: "o/a"},
+		{Name: "o/b", Flagged: true, FlagReason: FlagReasonOldInactiveContributors},
+		{Name: "o/c", Flagged: true, FlagReason: FlagReasonArchived, Archived: true},
+	}
+
+	tests := []struct {
+		name      string
+		repos     []Repository
+		skipped   int
+		want      jsonReport
+		wantRepos int
+	}{
+		{
+			name:    "organization",
+			repos:   repos,
+			skipped: 2,
+			want: jsonReport{Organization: "o", AnalyzedAt: testNow, DaysThreshold: 180, ContribThreshold: 0.5,
+				TotalAnalyzed: 3, Flagged: 2, FlaggedInactive: 1, Archived: 1, Skipped: 2},
+			wantRepos: 3,
+		},
+		{
+			name: "nothing analyzed",
+			want: jsonReport{Organization: "o", AnalyzedAt: testNow, DaysThreshold: 180, ContribThreshold: 0.5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := RenderReport(Analysis{Repositories: tt.repos, Skipped: tt.skipped}, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got jsonReport
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("report is not a JSON object: %v\n%s", err, data)
+			}
+			// The repositories are an array even when empty, never null
+			if repos, ok := got.Repositories.([]interface{}); !ok || len(repos) != tt.wantRepos {
+				t.Errorf("repositories = %v, want an array of %d", got.Repositories, tt.wantRepos)
+			}
+			if got.Config.Organization != "o" || got.ToolVersion == "" || got.ReportID == "" {
+				t.Errorf("config %+v, tool version %q, and report ID %q, want the run identified", got.Config, got.ToolVersion, got.ReportID)
+			}
+
+			got.ToolVersion, got.ReportID, got.Config, got.Repositories = "", "", config.Config{}, nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("report = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONFormatterSingleRepository(t *testing.T) {
+	var b strings.Builder
+	repos := []Repository{{Name: "o/r"}}
+	if err := (jsonFormatter{}).Format(&b, repos, Summary{Single: true}); err != nil {
+		t.Fatal(err)
+	}
+	var repo Repository
+	if err := json.Unmarshal([]byte(b.String()), &repo); err != nil || repo.Name != "o/r" || strings.Contains(b.String(), "totalAnalyzed") {
+		t.Errorf("single repository report %s is not the bare repository object (%v)", b.String(), err)
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	repos := []Repository{
+		{Name: "o/a", LastCommitDate: testNow, License: "MIT", Lifecycle: LifecycleActive, Visibility: "public",
+			OutsideCollaborators: intPtr(2), Extra: map[string]string{"team": "core, platform"}},
+		{Name: "o/b", CommitStatus: CommitStatusEmpty, License: LicenseNone, Flagged: true, FlagReason: FlagReasonOldNoContributors,
+			Admins: []string{"ann", "bob"}},
+	}
+	standard := strings.Split(strings.TrimSuffix(csvHeader(), "\n"), ",")
+
+	tests := []struct {
+		name       string
+		cfg        config.Config
+		wantHeader []string
+		wantCells  map[string][]string
+	}{
+		{
+			name:       "standard columns and extra ones",
+			cfg:        config.Config{},
+			wantHeader: append(append([]string(nil), standard...), "team"),
+			wantCells: map[string][]string{
+				"Last Commit Date": {"2025-06-01", CommitStatusEmpty},
+				"Flag Reason":      {"", FlagReasonOldNoContributors},
+				"Admins":           {"", "ann;bob"},
+				"team":             {"core, platform", ""},
+			},
+		},
+		{
+			name:       "optional columns",
+			cfg:        config.Config{Lifecycle: true, Governance: true},
+			wantHeader: append(append([]string(nil), standard...), "Lifecycle", "Visibility", "Outside Collaborators", "Exposed To Outsiders", "team"),
+			wantCells: map[string][]string{
+				"Lifecycle":             {LifecycleActive, ""},
+				"Outside Collaborators": {"2", ""},
+			},
+		},
+		{
+			name:       "selected fields",
+			cfg:        config.Config{Fields: []string{"name", "license"}},
+			wantHeader: []string{"name", "license"},
+			wantCells:  map[string][]string{"license": {"MIT", LicenseNone}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := renderCSV(repos, tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+			if err != nil {
+				t.Fatalf("report is not valid CSV: %v\n%s", err, data)
+			}
+			if !reflect.DeepEqual(records[0], tt.wantHeader) {
+				t.Fatalf("header = %q, want %q", records[0], tt.wantHeader)
+			}
+			if len(records) != len(repos)+1 {
+				t.Fatalf("report has %d records, want a header and %d rows", len(records), len(repos))
+			}
+			for column, want := range tt.wantCells {
+				index := -1
+				for i, name := range records[0] {
+					if name == column {
+						index = i
+					}
+				}
+				for row, cell := range want {
+					if got := records[row+1][index]; got != cell {
+						t.Errorf("%s of %s = %q, want %q", column, repos[row].Name, got, cell)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// ReportID identifies a report by its results and the criteria that produced them, for audit trails
+// The repositories are sorted by name and only the options deciding what is measured and flagged are
+// hashed with them, so two runs over the same data with the same criteria share an ID while any change
+// in a result or criterion yields another one. Results measured in days since a date only repeat
+// across runs when the analysis time is pinned with -as-of.
+func ReportID(repos []Repository, cfg config.Config) (string, error) {
+	sorted := append([]Repository(nil), repos...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	data, err := json.Marshal(struct {
+		Criteria     reportIDCriteria
+		Repositories []Repository
+	}{newReportIDCriteria(cfg), sorted})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal report for its ID: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// reportIDCriteria holds the configuration fields that decide what is measured and which repositories are flagged
+// It lists them explicitly, so options added for presentation, delivery, or execution never change the ID.
+type reportIDCriteria struct {
+	// Scope
+	Organization     string
+	ForksOf          string
+	Branch           string
+	Path             string
+	PathContributors bool
+	Visibility       string
+	AdminOf          string
+	AsOf             time.Time
+
+	// Measurement
+	Metrics            []string
+	ContributorScope   string
+	ContributorDays    int
+	MembershipFallback string
+	ContributorMap     string
+	CheckSuspended     bool
+	ContributorDetails bool
+	ShowAdmins         bool
+	SubstantiveCommits bool
+	SignedCommits      bool
+	CIStatus           bool
+	MergedPRs          bool
+	RecentTags         bool
+	ActiveBranches     bool
+	Engagement         bool
+	Momentum           bool
+	Security           bool
+	Governance         bool
+	BotPRs             bool
+	Cadence            bool
+	Readme             bool
+
+	// Flagging
+	MaxCommitAgeInDays             int
+	InactiveContribThreshold       float64
+	WeightedContributors           bool
+	FlagOnSubstantiveCommit        bool
+	MinSignedRatio                 float64
+	MinContributors                int
+	MinInactiveCount               int
+	MinRepoAgeDays                 int
+	DropSmallRepos                 bool
+	MinContributorsIncludeArchived bool
+	PrioritizeUnlicensed           bool
+	PrioritizeUndocumented         bool
+	FlagBrokenCI                   bool
+	FlagStaleReviews               bool
+	FlagUnresponsive               bool
+	MaxResponseHours               float64
+	FlagDeclining                  bool
+	DecliningMomentum              int
+	FlagStaleBotPRs                bool
+	MaxStaleBotPRs                 int
+	FlagOnCadence                  bool
+	CadenceMultiplier              float64
+	Lifecycle                      bool
+	LifecycleActiveDays            int
+	LifecycleStaleDays             int
+	LifecycleAbandonedDays         int
+}
+
+// newReportIDCriteria picks the criteria entering the report ID out of a configuration
+func newReportIDCriteria(cfg config.Config) reportIDCriteria {
+	return reportIDCriteria{
+		Organization:     cfg.Organization,
+		ForksOf:          cfg.ForksOf,
+		Branch:           cfg.Branch,
+		Path:             cfg.Path,
+		PathContributors: cfg.PathContributors,
+		Visibility:       cfg.Visibility,
+		AdminOf:          cfg.AdminOf,
+		AsOf:             cfg.AsOf,
+
+		Metrics:            cfg.Metrics,
+		ContributorScope:   cfg.ContributorScope,
+		ContributorDays:    cfg.ContributorDays,
+		MembershipFallback: cfg.MembershipFallback,
+		ContributorMap:     cfg.ContributorMap,
+		CheckSuspended:     cfg.CheckSuspended,
+		ContributorDetails: cfg.ContributorDetails,
+		ShowAdmins:         cfg.ShowAdmins,
+		SubstantiveCommits: cfg.SubstantiveCommits,
+		SignedCommits:      cfg.SignedCommits,
+		CIStatus:           cfg.CIStatus,
+		MergedPRs:          cfg.MergedPRs,
+		RecentTags:         cfg.RecentTags,
+		ActiveBranches:     cfg.ActiveBranches,
+		Engagement:         cfg.Engagement,
+		Momentum:           cfg.Momentum,
+		Security:           cfg.Security,
+		Governance:         cfg.Governance,
+		BotPRs:             cfg.BotPRs,
+		Cadence:            cfg.Cadence,
+		Readme:             cfg.Readme,
+
+		MaxCommitAgeInDays:             cfg.MaxCommitAgeInDays,
+		InactiveContribThreshold:       cfg.InactiveContribThreshold,
+		WeightedContributors:           cfg.WeightedContributors,
+		FlagOnSubstantiveCommit:        cfg.FlagOnSubstantiveCommit,
+		MinSignedRatio:                 cfg.MinSignedRatio,
+		MinContributors:                cfg.MinContributors,
+		MinInactiveCount:               cfg.MinInactiveCount,
+		MinRepoAgeDays:                 cfg.MinRepoAgeDays,
+		DropSmallRepos:                 cfg.DropSmallRepos,
+		MinContributorsIncludeArchived: cfg.MinContributorsIncludeArchived,
+		PrioritizeUnlicensed:           cfg.PrioritizeUnlicensed,
+		PrioritizeUndocumented:         cfg.PrioritizeUndocumented,
+		FlagBrokenCI:                   cfg.FlagBrokenCI,
+		FlagStaleReviews:               cfg.FlagStaleReviews,
+		FlagUnresponsive:               cfg.FlagUnresponsive,
+		MaxResponseHours:               cfg.MaxResponseHours,
+		FlagDeclining:                  cfg.FlagDeclining,
+		DecliningMomentum:              cfg.DecliningMomentum,
+		FlagStaleBotPRs:                cfg.FlagStaleBotPRs,
+		MaxStaleBotPRs:                 cfg.MaxStaleBotPRs,
+		FlagOnCadence:                  cfg.FlagOnCadence,
+		CadenceMultiplier:              cfg.CadenceMultiplier,
+		Lifecycle:                      cfg.Lifecycle,
+		LifecycleActiveDays:            cfg.LifecycleActiveDays,
+		LifecycleStaleDays:             cfg.LifecycleStaleDays,
+		LifecycleAbandonedDays:         cfg.LifecycleAbandonedDays,
+	}
+}
+
+// reportIDSummary renders the report ID for human-readable output, or "unavailable" when it cannot be computed
+func reportIDSummary(repos []Repository, cfg config.Config) string {
+	id, err := ReportID(repos, cfg)
+	if err != nil {
+		return "unavailable"
+	}
+	return id
+}
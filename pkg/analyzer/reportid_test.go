@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// reportIDExcluded lists the configuration fields deliberately left out of the report ID: they choose
+// what is analyzed by name, how the run goes, or how and where the report is rendered and delivered
+var reportIDExcluded = map[string]bool{
+	"SingleRepository": true, "Repositories": true, "RepoListFile": true, "LocalRepository": true,
+	"InputFormat": true, "RepoColumn": true, "ExtraColumns": true,
+	"OutputFormat": true, "MarkdownStyle": true, "GroupByReason": true, "DetectDuplicates": true,
+	"OutputFile": true, "StreamOutput": true, "Redact": true, "RedactMap": true, "Silent": true,
+	"Heartbeat": true, "Deadline": true, "LogFile": true, "CPUProfile": true, "Trace": true,
+	"Banner": true, "Humanize": true, "Strict": true, "Concurrency": true, "API": true,
+	"GroupContributors": true, "ContributorReport": true, "Top": true, "Fields": true,
+	"RepoListCache": true, "RepoListCacheTTL": true, "RefreshRepoList": true, "RepoCache": true,
+	"RepoCacheContributorTTL": true, "StateFile": true, "OwnersMap": true, "GHPath": true,
+	"AbortOnInsufficientQuota": true, "CacheTTL": true, "ProgressFD": true, "ExecHook": true,
+	"EmailTo": true, "EmailFrom": true,
+	"SMTPHost": true, "SMTPPort": true, "SMTPUsername": true, "SMTPPassword": true, "AppID": true,
+	"InstallationID": true, "PrivateKeyFile": true, "CredentialsFile": true, "EmitScript": true,
+	"PublishStatus": true, "TrackingIssueRepo": true, "UpdateComment": true, "DryRun": true,
+	"DumpConfig": true,
+}
+
+// reportIDRepositories is the report the ID tests hash
+var reportIDRepositories = []Repository{
+	{Name: "o/b", DaysSinceLastCommit: 200, Flagged: true, FlagReason: FlagReasonOldNoContributors},
+	{Name: "o/a", DaysSinceLastCommit: 3, TotalContributors: 2},
+}
+
+// reportIDConfig is the configuration the ID tests start from
+var reportIDConfig = config.Config{Organization: "o", MaxCommitAgeInDays: 180, InactiveContribThreshold: 0.5}
+
+func mustReportID(t *testing.T, repos []Repository, cfg config.Config) string {
+	t.Helper()
+	id, err := ReportID(repos, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// TestReportIDCriteriaCoverConfig makes every new configuration field either enter the report ID or be
+// listed as excluded from it
+func TestReportIDCriteriaCoverConfig(t *testing.T) {
+	criteria := reflect.TypeOf(reportIDCriteria{})
+	fields := reflect.TypeOf(config.Config{})
+	for i := 0; i < fields.NumField(); i++ {
+		name := fields.Field(i).Name
+		_, included := criteria.FieldByName(name)
+		if included == reportIDExcluded[name] {
+			t.Errorf("config field %s must be either a report ID criterion or excluded from the ID, not both or neither", name)
+		}
+	}
+}
+
+func TestReportIDStable(t *testing.T) {
+	want := mustReportID(t, reportIDRepositories, reportIDConfig)
+
+	tests := []struct {
+		name  string
+		repos []Repository
+		cfg   config.Config
+	}{
+		{"same report", reportIDRepositories, reportIDConfig},
+		{"other repository order", []Repository{reportIDRepositories[1], reportIDRepositories[0]}, reportIDConfig},
+		{"output format", reportIDRepositories, withConfig(reportIDConfig, func(c *config.Config) { c.OutputFormat = "json"; c.OutputFile = "r.json" })},
+		{"strict", reportIDRepositories, withConfig(reportIDConfig, func(c *config.Config) { c.Strict = true })},
+		{"quota abort", reportIDRepositories, withConfig(reportIDConfig, func(c *config.Config) { c.AbortOnInsufficientQuota = true })},
+		{"concurrency", reportIDRepositories, withConfig(reportIDConfig, func(c *config.Config) { c.Concurrency = 8 })},
+		{"caching", reportIDRepositories, withConfig(reportIDConfig, func(c *config.Config) { c.CacheTTL = time.Minute; c.RepoCache = "cache.json" })},
+		{"delivery", reportIDRepositories, withConfig(reportIDConfig, func(c *config.Config) { c.EmailTo = "a@example.com"; c.ExecHook = "cat" })},
+		{"top", reportIDRepositories, withConfig(reportIDConfig, func(c *config.Config) { c.Top = 1 })},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mustReportID(t, tt.repos, tt.cfg); got != want {
+				t.Errorf("report ID = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestReportIDSensitive(t *testing.T) {
+	base := mustReportID(t, reportIDRepositories, reportIDConfig)
+	changed := append([]Repository(nil), reportIDRepositories...)
+	changed[1].InactiveContributors = 1
+
+	tests := []struct {
+		name  string
+		repos []Repository
+		cfg   config.Config
+	}{
+		{"changed result", changed, reportIDConfig},
+		{"missing repository", reportIDRepositories[:1], reportIDConfig},
+		{"age threshold", reportIDRepositories, withConfig(reportIDConfig, func(c *config.Config) { c.MaxCommitAgeInDays = 90 })},
+		{"contributor threshold", reportIDRepositories, withConfig(reportIDConfig, func(c *config.Config) { c.InactiveContribThreshold = 0.7 })},
+		{"flag option", reportIDRepositories, withConfig(reportIDConfig, func(c *config.Config) { c.FlagBrokenCI = true })},
+		{"metrics", reportIDRepositories, withConfig(reportIDConfig, func(c *config.Config) { c.Metrics = []string{MetricCommits} })},
+		{"as of", reportIDRepositories, withConfig(reportIDConfig, func(c *config.Config) { c.AsOf = testNow })},
+		{"organization", reportIDRepositories, withConfig(reportIDConfig, func(c *config.Config) { c.Organization = "p" })},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mustReportID(t, tt.repos, tt.cfg); got == base {
+				t.Errorf("report ID unchanged (%s)", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,389 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// ParseRepoIdentifier normalizes a repository identifier (org/repo or a GitHub URL) into org/repo form
+// A branch may be appended as org/repo@branch or org/repo#branch, and is returned separately
+func ParseRepoIdentifier(identifier string) (repoFullName, branch string, err error) {
+	repoFullName = strings.TrimSpace(identifier)
+
+	// Split off an optional branch suffix
+	if i := strings.IndexAny(repoFullName, "@#"); i >= 0 {
+		branch = repoFullName[i+1:]
+		repoFullName = repoFullName[:i]
+		if branch == "" {
+			return "", "", fmt.Errorf("empty branch name in %s", identifier)
+		}
+	}
+
+	// Extract org/repo from URL if a full GitHub URL is provided
+	if strings.HasPrefix(repoFullName, "http") {
+		// Handle URLs like https://github.com/org/repo or http://github.com/org/repo
+		urlParts := strings.Split(repoFullName, "github.com/")
+		if len(urlParts) != 2 {
+			return "", "", fmt.Errorf("invalid GitHub URL format: %s", identifier)
+		}
+
+		// Get the org/repo part
+		repoFullName = strings.TrimPrefix(urlParts[1], "/")
+
+		// Remove any trailing slash or .git extension
+		repoFullName = strings.TrimSuffix(repoFullName, "/")
+		repoFullName = strings.TrimSuffix(repoFullName, ".git")
+	}
+
+	// Validate repository parts (org/repo)
+	parts := strings.Split(repoFullName, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid repository name format, expected 'org/repo', got: %s", repoFullName)
+	}
+
+	return repoFullName, branch, nil
+}
+
+// CheckBranchExists verifies that a branch exists in the repository
+func CheckBranchExists(repoFullName, branch string) error {
+	_, err := runGH("api",
+		fmt.Sprintf("repos/%s/branches/%s", repoFullName, url.PathEscape(branch)),
+		"--silent")
+	if err != nil {
+		if StatusCode(err) == http.StatusNotFound {
+			return fmt.Errorf("branch %q not found in %s", branch, repoFullName)
+		}
+		return fmt.Errorf("failed to check branch %q in %s: %w", branch, repoFullName, err)
+	}
+	return nil
+}
+
+// AnalyzeRepository collects the inactivity metrics for a single repository and flags it, returning the warnings met
+func AnalyzeRepository(repoFullName string, cfg config.Config) (Repository, []Warning, error) {
+	warnings := &warningLog{}
+	r, err := analyzeRepository(repoFullName, cfg, warnings)
+	return r, warnings.list(), err
+}
+
+// analyzeRepository analyzes a repository like AnalyzeRepository, recording the warnings met in a log
+func analyzeRepository(repoFullName string, cfg config.Config, warnings *warningLog) (Repository, error) {
+	now := Now()
+
+	r := Repository{
+		Name:   repoFullName,
+		Branch: cfg.Branch,
+		Path:   commitPath,
+	}
+
+	// Repositories no configured credential can access are skipped
+	if err := CheckCredential(repoFullName); err != nil {
+		return r, err
+	}
+
+	// Get organization name from full repository name
+	orgName := strings.Split(repoFullName, "/")[0]
+
+	// Get repository metadata (archived status and governance settings)
+	meta, err := GetRepositoryMetadata(repoFullName)
+	if err != nil {
+		return r, err
+	}
+	r.Archived = meta.Archived
+	r.IssuesEnabled = meta.HasIssues
+	r.HasDiscussions = meta.HasDiscussions
+	r.License = meta.LicenseID()
+	r.CreatedAt = meta.CreatedAt
+	r.Parent = meta.ParentName()
+	if !meta.CreatedAt.IsZero() {
+		r.RepoAgeDays = int(now.Sub(meta.CreatedAt).Hours() / 24)
+	}
+
+	// Reuse the commit and contributor data of a repository nobody has pushed to since the cached run
+	// Contributor data past the contributor TTL is collected again, as contributors may have left the organization since
+	cached, reused := lookupRepositoryCache(repoFullName, meta.PushedAt, cfg)
+	contributorsReused := reused && cached.contributorsFresh(cfg)
+	if reused {
+		cached.applyCommits(&r, now)
+	}
+	if contributorsReused {
+		cached.applyContributors(&r)
+	}
+
+	// Get last commit date, on the requested branch if any
+	if collects(cfg, MetricCommits) && !reused {
+		lastCommitDate, err := getLastCommitDate(repoFullName, cfg.Branch)
+		if status := commitStatusOf(err); status != "" {
+			// An empty or restricted repository is reported as such rather than skipped
+			r.CommitStatus = status
+		} else if err != nil {
+			return r, fmt.Errorf("failed to get last commit date: %w", err)
+		} else {
+			r.LastCommitDate = lastCommitDate
+			r.DaysSinceLastCommit = int(now.Sub(lastCommitDate).Hours() / 24)
+		}
+	}
+
+	// Inspect the latest commits for the last substantive change, the signing ratio, and the cadence if requested
+	if (collects(cfg, MetricSubstantive) || collects(cfg, MetricSigning) || collects(cfg, MetricCadence)) && r.CommitStatus == "" {
+		commits, err := getRecentCommits(repoFullName, cfg.Branch)
+		if err != nil {
+			return r, err
+		}
+
+		if collects(cfg, MetricSubstantive) {
+			substantiveDate, err := lastSubstantiveCommitDate(commits)
+			if err != nil {
+				return r, fmt.Errorf("failed to get last substantive commit date: %w", err)
+			}
+			r.LastSubstantiveCommitDate = &substantiveDate
+			r.DaysSinceSubstantiveCommit = int(now.Sub(substantiveDate).Hours() / 24)
+		}
+
+		if collects(cfg, MetricSigning) {
+			ratio, err := signedCommitRatio(commits)
+			if err != nil {
+				return r, fmt.Errorf("failed to get signed commit ratio: %w", err)
+			}
+			r.SignedCommitRatio = &ratio
+		}
+
+		if collects(cfg, MetricCadence) {
+			if gap, ok := medianCommitGapDays(commits); ok {
+				r.MedianCommitGapDays = &gap
+			}
+		}
+	}
+
+	// Look up the latest CI run if requested
+	if collects(cfg, MetricCI) {
+		run, err := GetLastCIRun(repoFullName, cfg.Branch)
+		if err != nil {
+			return r, err
+		}
+		r.LastCIStatus = run.Status
+		if !run.Date.IsZero() {
+			r.LastCIDate = &run.Date
+		}
+	}
+
+	// Look up the last merged pull request if requested
+	if collects(cfg, MetricReviews) {
+		merged, err := GetLastMergedPRDate(repoFullName, cfg.Branch)
+		if err != nil {
+			return r, err
+		}
+		r.LastMergedPRDate = merged
+	}
+
+	// Measure how quickly maintainers answer recent issues if requested
+	if collects(cfg, MetricEngagement) {
+		engagement, err := GetIssueEngagement(repoFullName, now)
+		if err != nil {
+			return r, err
+		}
+		r.MedianIssueResponseHours = engagement.MedianResponseHours
+		r.UnansweredIssues = engagement.Unanswered
+		r.IssuesSampled = engagement.Sampled
+	}
+
+	// Look up the newest tag if requested
+	if collects(cfg, MetricTags) {
+		tag, err := GetLastTag(repoFullName)
+		if err != nil {
+			return r, err
+		}
+		if tag != nil {
+			r.LastTag = tag.Name
+			r.LastTagDate = &tag.Date
+		}
+	}
+
+	// Look up the newest commit on the other branches if requested
+	if collects(cfg, MetricBranches) && r.CommitStatus == "" {
+		analyzedBranch := cfg.Branch
+		if analyzedBranch == "" {
+			analyzedBranch = meta.DefaultBranch
+		}
+		newest, err := GetNewestBranchCommit(repoFullName, analyzedBranch)
+		if err != nil {
+			return r, err
+		}
+		if newest != nil {
+			r.NewestBranch = newest.Branch
+			r.NewestBranchCommitDate = &newest.Date
+		}
+	}
+
+	// Compare the commit counts of the last two 90-day windows if requested
+	if collects(cfg, MetricMomentum) && r.CommitStatus == "" {
+		momentum, err := GetCommitMomentum(repoFullName, cfg.Branch, now)
+		if err != nil {
+			return r, err
+		}
+		r.CommitMomentum = &momentum
+	}
+
+	// Count the bot pull requests left open past the age threshold if requested
+	if collects(cfg, MetricBotPRs) {
+		count, err := GetStaleBotPRCount(repoFullName, cfg.MaxCommitAgeInDays, now)
+		if err != nil {
+			return r, err
+		}
+		r.StaleBotPRCount = &count
+	}
+
+	// Count open Dependabot alerts if requested
+	if collects(cfg, MetricSecurity) {
+		alerts, err := GetOpenSecurityAlerts(repoFullName)
+		if err != nil {
+			return r, err
+		}
+		if alerts.Unknown {
+			r.SecurityAlertsUnknown = true
+		} else {
+			r.OpenSecurityAlerts = &alerts.Count
+		}
+	}
+
+	// Check for a README if requested
+	if collects(cfg, MetricReadme) {
+		size, err := GetReadmeSize(repoFullName, cfg.Branch)
+		if err != nil {
+			return r, err
+		}
+		hasReadme := size != nil
+		r.HasReadme = &hasReadme
+		if size != nil {
+			r.ReadmeSizeBytes = *size
+		}
+	}
+
+	// Record who can see and change the repository if governance is checked
+	if cfg.Governance {
+		r.Visibility = meta.Visibility
+		r.OutsideCollaborators, err = GetOutsideCollaboratorCount(repoFullName)
+		if err != nil {
+			return r, err
+		}
+	}
+
+	// Get contributors and check if they are still active
+	// Without contributor data the repository is reported as incomplete rather than contributor-less
+	if collects(cfg, MetricContributors) && !contributorsReused {
+		if err := analyzeContributors(&r, repoFullName, orgName, meta.OwnedByUser(), now, cfg, warnings); err != nil {
+			return r, err
+		}
+	}
+	if !contributorsReused {
+		storeRepositoryCache(r, meta.PushedAt, cfg)
+	}
+
+	// Correct impossible metrics before they decide the flag, so a computation bug shows up as a warning
+	for _, note := range r.Validate() {
+		warnings.warn(cfg, repoFullName, "Corrected impossible metric for %s: %s", repoFullName, note)
+	}
+
+	// Flag repository based on criteria
+	FlagRepository(&r, cfg)
+
+	// Look up who can act on flagged repositories if requested
+	if cfg.ShowAdmins && r.Flagged {
+		admins, err := GetRepositoryAdmins(repoFullName)
+		if err != nil {
+			if cfg.Strict {
+				return r, fmt.Errorf("failed to get admins: %w", err)
+			}
+			warnings.warn(cfg, repoFullName, "Failed to get admins for %s: %v", repoFullName, err)
+		} else {
+			r.Admins = admins
+		}
+	}
+
+	// Find who to notify about flagged repositories if an owners map is configured
+	if err := lookUpContact(&r, repoFullName); err != nil {
+		if cfg.Strict {
+			return r, err
+		}
+		warnings.warn(cfg, repoFullName, "Failed to look up the contact for %s: %v", repoFullName, err)
+	}
+
+	return r, nil
+}
+
+// analyzeContributors records the contributor counts of a repository, either by org membership
+// or by each contributor's most recent commit anywhere in the organization
+// Repositories owned by a personal account fall back to whether each contributor's account still exists
+func analyzeContributors(r *Repository, repoFullName, orgName string, ownedByUser bool, now time.Time, cfg config.Config, warnings *warningLog) error {
+	var activeContribs, inactiveContribs []string
+	var err error
+	if ownedByUser {
+		// A personal account, such as the owner of a personal fork, has no members or org-wide activity to check
+		activeContribs, inactiveContribs, err = getAccountContributorsStatus(repoFullName, cfg)
+	} else if cfg.ContributorScope == ContributorScopeOrg {
+		since := now.AddDate(0, 0, -cfg.ContributorWindowDays())
+		activeContribs, inactiveContribs, err = GetOrgContributorsStatus(repoFullName, orgName, since)
+	} else {
+		activeContribs, inactiveContribs, err = getContributorsStatus(repoFullName, orgName, cfg, warnings)
+	}
+	switch {
+	case (errors.Is(err, ErrContributorDataUnavailable) || errors.Is(err, ErrMembershipUnavailable)) && !cfg.Strict:
+		// Contributor data is missing rather than absent, so it must not count as "no contributors"
+		// or as all contributors having left
+		r.ContributorDataComplete = false
+	case err != nil:
+		return fmt.Errorf("failed to analyze contributors: %w", err)
+	default:
+		// Suspended accounts cannot contribute, whatever their membership or recent activity
+		var suspended []string
+		if cfg.CheckSuspended {
+			activeContribs, suspended, err = partitionSuspended(activeContribs, cfg, warnings)
+			if err != nil {
+				return fmt.Errorf("failed to analyze contributors: %w", err)
+			}
+			inactiveContribs = append(inactiveContribs, suspended...)
+		}
+
+		r.ContributorDataComplete = true
+		r.TotalContributors = len(activeContribs) + len(inactiveContribs)
+		r.InactiveContributors = len(inactiveContribs)
+		r.SuspendedContributors = len(suspended)
+
+		if r.TotalContributors > 0 {
+			r.InactivePercentage = float64(r.InactiveContributors) / float64(r.TotalContributors)
+		}
+
+		// Weigh each contributor by their commits in the last year if requested
+		if cfg.WeightedContributors {
+			counts, err := GetAuthorCommitCounts(repoFullName, cfg.Branch, now.AddDate(0, 0, -weightWindowDays), now)
+			if err != nil {
+				return err
+			}
+			if ratio, ok := weightedInactiveRatio(counts, activeContribs, inactiveContribs); ok {
+				r.WeightedInactivePercentage = &ratio
+			}
+		}
+
+		// Record when each inactive contributor last committed to the repository if requested
+		if describesContributors(cfg) || reportsContributors(cfg) {
+			details, err := describeInactiveContributors(repoFullName, inactiveContribs, suspended, cfg, warnings)
+			if err != nil {
+				return err
+			}
+			if describesContributors(cfg) {
+				r.InactiveContributorDetails = details
+			}
+			if reportsContributors(cfg) {
+				r.ActiveContributorLogins = activeContribs
+				r.DepartedContributors = details
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestParseRepoIdentifier(t *testing.T) {
+	tests := []struct {
+		identifier string
+		wantName   string
+		wantBranch string
+		wantErr    bool
+	}{
+		{"o/r", "o/r", "", false},
+		{" o/r ", "o/r", "", false},
+		{"https://github.com/o/r", "o/r", "", false},
+		{"https://github.com/o/r.git", "o/r", "", false},
+		{"http://github.com/o/r/", "o/r", "", false},
+		{"o/r@release/1.x", "o/r", "release/1.x", false},
+		{"o/r#dev", "o/r", "dev", false},
+		{"https://github.com/o/r@dev", "o/r", "dev", false},
+		{"o/r@", "", "", true},
+		{"o", "", "", true},
+		{"o/r/x", "", "", true},
+		{"https://gitlab.com/o/r", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.identifier, func(t *testing.T) {
+			name, branch, err := ParseRepoIdentifier(tt.identifier)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRepoIdentifier error = %v, want error %v", err, tt.wantErr)
+			}
+			if name != tt.wantName || branch != tt.wantBranch {
+				t.Errorf("ParseRepoIdentifier = %q, %q, want %q, %q", name, branch, tt.wantName, tt.wantBranch)
+			}
+		})
+	}
+}
+
+func TestCheckBranchExists(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		wantErr string
+	}{
+		{"exists", `exit 0`, ""},
+		{"missing", `echo 'gh: Not Found (HTTP 404)' >&2; exit 1`, `branch "release/1.x" not found in o/r`},
+		{"failure", `echo 'gh: Server Error (HTTP 500)' >&2; exit 1`, `failed to check branch "release/1.x" in o/r`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			SetCacheTTL(0)
+
+			err := CheckBranchExists("o/r", "release/1.x")
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("CheckBranchExists error = %v, want %q", err, tt.wantErr)
+			}
+			// The branch name is escaped into a single path segment
+			if calls := ghCalls(t, logPath); len(calls) != 1 || !strings.Contains(calls[0], "repos/o/r/branches/release%2F1.x") {
+				t.Errorf("gh calls = %q, want the escaped branch endpoint", calls)
+			}
+		})
+	}
+}
+
+// analyzeScript answers the calls of a repository analysis: the metadata, a last commit the given number
+// of days before testNow, two contributors who left the organization, and the admins
+func analyzeScript(metadata string, lastCommitDaysAgo int) string {
+	return fmt.Sprintf(`case "$*" in
+*collaborators*) printf 'ann\nbob\n';;
+*user/memberships*) echo '{"state":"active"}';;
+*contributors*) printf 'cy\ndee\n';;
+*members/*) echo 'gh: Not Found (HTTP 404)' >&2; exit 1;;
+*commits*) echo %s;;
+"api repos/o/r") echo '%s';;
+*) echo "unexpected call: $*" >&2; exit 1;;
+esac`, testDaysAgo(lastCommitDaysAgo).Format(time.RFC3339), metadata)
+}
+
+func TestAnalyzeRepositoryAdmins(t *testing.T) {
+	tests := []struct {
+		name       string
+		daysAgo    int
+		showAdmins bool
+		want       []string
+	}{
+		{"flagged", 400, true, []string{"ann", "bob"}},
+		{"not requested", 400, false, nil},
+		{"not flagged", 10, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pinNow(t)
+			logPath := fakeGH(t, analyzeScript(`{}`, tt.daysAgo))
+			SetCacheTTL(0)
+			fastMembershipRetries(t)
+
+			cfg := withConfig(flaggingConfig, func(c *config.Config) { c.ShowAdmins = tt.showAdmins; c.Silent = true })
+			repo, _, err := AnalyzeRepository("o/r", cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(repo.Admins, tt.want) {
+				t.Errorf("admins = %q, want %q", repo.Admins, tt.want)
+			}
+			var listed bool
+			for _, call := range ghCalls(t, logPath) {
+				listed = listed || strings.Contains(call, "collaborators?permission=admin")
+			}
+			if listed != (tt.want != nil) {
+				t.Errorf("listed admins = %v, want %v", listed, tt.want != nil)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepositoryDoesNotPublish(t *testing.T) {
+	pinNow(t)
+	logPath := fakeGH(t, analyzeScript(`{}`, 400))
+	SetCacheTTL(0)
+	fastMembershipRetries(t)
+
+	// Statuses are published once the report is out, not while the scan runs
+	cfg := withConfig(flaggingConfig, func(c *config.Config) { c.PublishStatus = true; c.Silent = true })
+	if _, _, err := AnalyzeRepository("o/r", cfg); err != nil {
+		t.Fatal(err)
+	}
+	for _, call := range ghCalls(t, logPath) {
+		if strings.Contains(call, "--method POST") {
+			t.Errorf("analysis wrote to GitHub: %q", call)
+		}
+	}
+}
+
+func TestAnalyzeRepositoryLicense(t *testing.T) {
+	tests := []struct {
+		name         string
+		metadata     string
+		daysAgo      int
+		wantLicense  string
+		wantPriority bool
+	}{
+		{"licensed", `{"license":{"spdx_id":"Apache-2.0"}}`, 400, "Apache-2.0", false},
+		{"null license", `{"license":null}`, 400, LicenseNone, true},
+		{"no license field", `{}`, 400, LicenseNone, true},
+		{"unlicensed but active", `{"license":null}`, 10, LicenseNone, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pinNow(t)
+			fakeGH(t, analyzeScript(tt.metadata, tt.daysAgo))
+			SetCacheTTL(0)
+			fastMembershipRetries(t)
+
+			cfg := withConfig(flaggingConfig, func(c *config.Config) { c.PrioritizeUnlicensed = true; c.Silent = true })
+			repo, _, err := AnalyzeRepository("o/r", cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if repo.License != tt.wantLicense {
+				t.Errorf("license = %q, want %q", repo.License, tt.wantLicense)
+			}
+			if repo.HighPriority != tt.wantPriority {
+				t.Errorf("high priority = %v, want %v", repo.HighPriority, tt.wantPriority)
+			}
+			if marked := strings.Contains(priorityMarker(repo), "no license"); marked != tt.wantPriority {
+				t.Errorf("priority marker %q, want the missing license noted = %v", priorityMarker(repo), tt.wantPriority)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRepositoryMinRepoAge(t *testing.T) {
+	tests := []struct {
+		name        string
+		metadata    string
+		minAge      int
+		wantAge     int
+		wantFlagged bool
+	}{
+		{"young repository exempt", fmt.Sprintf(`{"created_at":%q}`, testDaysAgo(250).Format(time.RFC3339)), 365, 250, false},
+		{"old enough", fmt.Sprintf(`{"created_at":%q}`, testDaysAgo(500).Format(time.RFC3339)), 365, 500, true},
+		{"no minimum", fmt.Sprintf(`{"created_at":%q}`, testDaysAgo(250).Format(time.RFC3339)), 0, 250, true},
+		{"unknown creation date", `{}`, 365, 0, true},
+		{"young but archived", fmt.Sprintf(`{"archived":true,"created_at":%q}`, testDaysAgo(250).Format(time.RFC3339)), 365, 250, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pinNow(t)
+			fakeGH(t, analyzeScript(tt.metadata, 200))
+			SetCacheTTL(0)
+			fastMembershipRetries(t)
+
+			cfg := withConfig(flaggingConfig, func(c *config.Config) { c.MinRepoAgeDays = tt.minAge; c.Silent = true })
+			repo, _, err := AnalyzeRepository("o/r", cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if repo.RepoAgeDays != tt.wantAge {
+				t.Errorf("repository age = %d days, want %d", repo.RepoAgeDays, tt.wantAge)
+			}
+			if repo.Flagged != tt.wantFlagged {
+				t.Errorf("flagged = %v (%s), want %v", repo.Flagged, repo.FlagReason, tt.wantFlagged)
+			}
+		})
+	}
+}
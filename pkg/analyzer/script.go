@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+)
+
+// Remediation script actions selectable with -emit-script
+const (
+	ScriptActionArchive  = "archive"
+	ScriptActionDelete   = "delete"
+	ScriptActionTransfer = "transfer"
+)
+
+// scriptFormatter renders a shell script of gh commands acting on the flagged repositories
+// Every command is commented out so that nothing runs until it has been reviewed
+type scriptFormatter struct {
+	action string
+}
+
+// Format writes the remediation script
+func (f scriptFormatter) Format(w io.Writer, repos []Repository, summary Summary) error {
+	cfg := summary.Config
+
+	fmt.Fprintln(w, "#!/bin/sh")
+	fmt.Fprintf(w, "# Remediation script generated by inactivity on %s\n", Now().Format("2006-01-02"))
+	fmt.Fprintf(w, "# Action: %s flagged repositories (last commit older than %d days, %.0f%% inactive contributors)\n",
+		f.action, cfg.MaxCommitAgeInDays, cfg.InactiveContribThreshold*100)
+	fmt.Fprintln(w, "#")
+	fmt.Fprintln(w, "# SAFETY: every command below is commented out. Review each repository, then")
+	fmt.Fprintln(w, "# uncomment only the lines you want to run. Some actions cannot be undone.")
+	if f.action == ScriptActionTransfer {
+		fmt.Fprintln(w, "#")
+		fmt.Fprintln(w, "# Set NEW_OWNER to the user or organization receiving the repositories.")
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "set -eu")
+	if f.action == ScriptActionTransfer {
+		fmt.Fprintln(w, `NEW_OWNER="${NEW_OWNER:?set NEW_OWNER to the receiving owner}"`)
+	}
+	fmt.Fprintln(w)
+
+	count := 0
+	for _, repo := range repos {
+		if !repo.Flagged {
+			continue
+		}
+		// Archiving an archived repository fails, so those are listed without a command
+		if f.action == ScriptActionArchive && repo.Archived {
+			fmt.Fprintf(w, "# %s is already archived\n", repo.Name)
+			continue
+		}
+		fmt.Fprintf(w, "# %s (reason: %s)%s\n", repo.Name, repo.FlagReason, priorityMarker(repo))
+		fmt.Fprintf(w, "# %s\n", scriptCommand(f.action, repo.Name))
+		count++
+	}
+
+	if count == 0 {
+		fmt.Fprintln(w, "# No flagged repositories to act on")
+	}
+	return nil
+}
+
+// scriptCommand returns the gh command performing an action on a repository
+func scriptCommand(action, repoFullName string) string {
+	switch action {
+	case ScriptActionDelete:
+		return fmt.Sprintf("gh repo delete %s --yes", repoFullName)
+	case ScriptActionTransfer:
+		return fmt.Sprintf(`gh api --method POST repos/%s/transfer -f new_owner="$NEW_OWNER"`, repoFullName)
+	default:
+		return fmt.Sprintf("gh repo archive %s --yes", repoFullName)
+	}
+}
@@ -0,0 +1,90 @@
+package analyzer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestScriptFormatter(t *testing.T) {
+	pinNow(t)
+	repos := []Repository{
+		{Name: "acme/old", Flagged: true, FlagReason: FlagReasonOldNoContributors},
+		{Name: "acme/api"},
+		{Name: "acme/frozen", Archived: true, Flagged: true, FlagReason: FlagReasonArchived},
+	}
+
+	tests := []struct {
+		name     string
+		action   string
+		repos    []Repository
+		want     []string
+		dontWant []string
+	}{
+		{
+			name:   "archive",
+			action: ScriptActionArchive,
+			repos:  repos,
+			want: []string{
+				"# Remediation script generated by inactivity on 2025-06-01\n",
+				"# Action: archive flagged repositories (last commit older than 180 days, 50% inactive contributors)\n",
+				"# acme/old (reason: " + FlagReasonOldNoContributors + ")\n# gh repo archive acme/old --yes\n",
+				"# acme/frozen is already archived\n",
+			},
+			dontWant: []string{"acme/api", "NEW_OWNER", "archive acme/frozen"},
+		},
+		{
+			name:   "delete",
+			action: ScriptActionDelete,
+			repos:  repos,
+			want:   []string{"# gh repo delete acme/old --yes\n", "# gh repo delete acme/frozen --yes\n"},
+		},
+		{
+			name:   "transfer",
+			action: ScriptActionTransfer,
+			repos:  repos,
+			want: []string{
+				"# Set NEW_OWNER to the user or organization receiving the repositories.\n",
+				`NEW_OWNER="${NEW_OWNER:?set NEW_OWNER to the receiving owner}"` + "\n",
+				`# gh api --method POST repos/acme/old/transfer -f new_owner="$NEW_OWNER"` + "\n",
+			},
+		},
+		{
+			name:   "nothing flagged",
+			action: ScriptActionArchive,
+			repos:  repos[1:2],
+			want:   []string{"# No flagged repositories to act on\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := Summary{Config: config.Config{MaxCommitAgeInDays: 180, InactiveContribThreshold: 0.5}}
+			var buf bytes.Buffer
+			if err := (scriptFormatter{action: tt.action}).Format(&buf, tt.repos, summary); err != nil {
+				t.Fatal(err)
+			}
+			script := buf.String()
+
+			if !strings.HasPrefix(script, "#!/bin/sh\n") || !strings.Contains(script, "\nset -eu\n") {
+				t.Errorf("script lacks the shebang or set -eu:\n%s", script)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(script, want) {
+					t.Errorf("script lacks %q:\n%s", want, script)
+				}
+			}
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(script, dontWant) {
+					t.Errorf("script contains %q:\n%s", dontWant, script)
+				}
+			}
+			for _, line := range strings.Split(script, "\n") {
+				if strings.Contains(line, "gh ") && !strings.HasPrefix(line, "# ") {
+					t.Errorf("command %q is not commented out", line)
+				}
+			}
+		})
+	}
+}
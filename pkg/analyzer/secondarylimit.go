@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// secondaryRateLimitPattern matches the messages GitHub returns when a secondary rate limit
+// (formerly the abuse detection mechanism) rejects a request
+var secondaryRateLimitPattern = regexp.MustCompile(`(?i)secondary rate limit|abuse detection`)
+
+// retryAfterPattern matches a wait stated in a secondary rate limit response, as a Retry-After header
+// or in the message text
+var retryAfterPattern = regexp.MustCompile(`(?i)retry-after:\s*(\d+)|retry (?:your request )?after (\d+) seconds`)
+
+// defaultSecondaryRateLimitWait is the pause when the response states no wait,
+// the minute GitHub recommends before retrying
+var defaultSecondaryRateLimitWait = time.Minute
+
+// secondaryRateLimitAttempts is how many times a call rejected by a secondary rate limit is tried
+const secondaryRateLimitAttempts = 3
+
+// isSecondaryRateLimit reports whether a failed call's message is a secondary rate limit rejection
+func isSecondaryRateLimit(message string) bool {
+	return secondaryRateLimitPattern.MatchString(message)
+}
+
+// secondaryRateLimitWait returns how long to pause after a secondary rate limit rejection,
+// honoring the Retry-After it states
+func secondaryRateLimitWait(message string) time.Duration {
+	if match := retryAfterPattern.FindStringSubmatch(message); match != nil {
+		value := match[1]
+		if value == "" {
+			value = match[2]
+		}
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return defaultSecondaryRateLimitWait
+}
+
+// apiPause holds back every API call until a secondary rate limit has passed, so that concurrent
+// workers pause together instead of each running into the limit again
+var apiPause = struct {
+	mu    sync.Mutex
+	until time.Time
+}{}
+
+// pauseAPICalls holds back all API calls for the given duration from now, unless a longer pause is already set
+// It reports whether the pause was extended, so that only one caller announces it
+func pauseAPICalls(wait time.Duration) bool {
+	apiPause.mu.Lock()
+	defer apiPause.mu.Unlock()
+
+	until := time.Now().Add(wait)
+	if !until.After(apiPause.until) {
+		return false
+	}
+	apiPause.until = until
+	return true
+}
+
+// waitForAPIPause blocks while a secondary rate limit pause is in effect
+func waitForAPIPause() {
+	for {
+		apiPause.mu.Lock()
+		remaining := time.Until(apiPause.until)
+		apiPause.mu.Unlock()
+
+		if remaining <= 0 {
+			return
+		}
+		time.Sleep(remaining)
+	}
+}
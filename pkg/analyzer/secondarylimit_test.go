@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSecondaryRateLimitWait(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		limited  bool
+		wantWait time.Duration
+	}{
+		{"secondary limit", "You have exceeded a secondary rate limit. Please wait a few minutes before you try again.", true, time.Minute},
+		{"abuse detection", "You have triggered an abuse detection mechanism.", true, time.Minute},
+		{"retry-after header", "Retry-After: 30\nYou have exceeded a secondary rate limit", true, 30 * time.Second},
+		{"retry in message", "Secondary rate limit exceeded, retry your request after 45 seconds", true, 45 * time.Second},
+		{"zero retry", "secondary rate limit, Retry-After: 0", true, time.Minute},
+		{"primary limit", "API rate limit exceeded for user ID 1.", false, time.Minute},
+		{"not found", "Not Found", false, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSecondaryRateLimit(tt.message); got != tt.limited {
+				t.Errorf("isSecondaryRateLimit = %v, want %v", got, tt.limited)
+			}
+			if got := secondaryRateLimitWait(tt.message); got != tt.wantWait {
+				t.Errorf("secondaryRateLimitWait = %s, want %s", got, tt.wantWait)
+			}
+		})
+	}
+}
+
+// resetAPIPause clears any secondary rate limit pause once a test ends
+func resetAPIPause(t *testing.T) {
+	t.Cleanup(func() {
+		apiPause.mu.Lock()
+		apiPause.until = time.Time{}
+		apiPause.mu.Unlock()
+	})
+}
+
+func TestPauseAPICalls(t *testing.T) {
+	resetAPIPause(t)
+
+	if !pauseAPICalls(50 * time.Millisecond) {
+		t.Error("first pause not set")
+	}
+	if pauseAPICalls(10 * time.Millisecond) {
+		t.Error("shorter pause replaced a longer one")
+	}
+	if !pauseAPICalls(100 * time.Millisecond) {
+		t.Error("longer pause not set")
+	}
+
+	start := time.Now()
+	waitForAPIPause()
+	if waited := time.Since(start); waited < 90*time.Millisecond {
+		t.Errorf("waited %s, want the longest pause of 100ms", waited)
+	}
+}
+
+func TestRunGHSecondaryRateLimitRetry(t *testing.T) {
+	const limited = `echo 'gh: You have exceeded a secondary rate limit. (HTTP 403)' >&2; exit 1`
+	tests := []struct {
+		name      string
+		script    string
+		wantCalls int
+		wantErr   bool
+	}{
+		{"retried until accepted", `if [ $(wc -l < "$GH_LOG") -lt 3 ]; then ` + limited + `; fi; echo ok`, 3, false},
+		{"gives up after the attempts", limited, secondaryRateLimitAttempts, true},
+		{"other errors not retried", `echo 'gh: Forbidden (HTTP 403)' >&2; exit 1`, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			SetCacheTTL(0)
+			resetAPIPause(t)
+			previousWait := defaultSecondaryRateLimitWait
+			defaultSecondaryRateLimitWait = 10 * time.Millisecond
+			t.Cleanup(func() { defaultSecondaryRateLimitWait = previousWait })
+
+			out, err := runGH("api", "repos/o/r")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runGH error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(out) != "ok\n" {
+				t.Errorf("output = %q, want ok", out)
+			}
+			if calls := ghCalls(t, logPath); len(calls) != tt.wantCalls {
+				t.Errorf("made %d calls, want %d", len(calls), tt.wantCalls)
+			}
+		})
+	}
+}
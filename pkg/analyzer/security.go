@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// securityAlerts is the open Dependabot alert count of a repository
+type securityAlerts struct {
+	Count   int
+	Unknown bool
+}
+
+// GetOpenSecurityAlerts counts the open Dependabot security alerts of a repository
+// Repositories with Dependabot alerts disabled, or whose alerts the caller may not read, are reported as unknown
+func GetOpenSecurityAlerts(repoFullName string) (securityAlerts, error) {
+	out, err := runGH("api",
+		fmt.Sprintf("repos/%s/dependabot/alerts?state=open&per_page=100", repoFullName),
+		"--paginate", "--jq", "length")
+	if err != nil {
+		switch StatusCode(err) {
+		case http.StatusForbidden, http.StatusNotFound:
+			return securityAlerts{Unknown: true}, nil
+		}
+		return securityAlerts{}, fmt.Errorf("failed to get Dependabot alerts: %w", err)
+	}
+
+	count, err := parseAlertCount(out)
+	if err != nil {
+		return securityAlerts{}, err
+	}
+	return securityAlerts{Count: count}, nil
+}
+
+// parseAlertCount sums the per-page alert counts printed by a paginated length query
+func parseAlertCount(data []byte) (int, error) {
+	total := 0
+	for _, line := range bytes.Fields(data) {
+		n, err := strconv.Atoi(string(line))
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse Dependabot alert count: %w", err)
+		}
+		total += n
+	}
+	return total, nil
+}
@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAlertCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    int
+		wantErr bool
+	}{
+		{"single page", "3\n", 3, false},
+		{"several pages", "100\n100\n7\n", 207, false},
+		{"no output", "", 0, false},
+		{"malformed", "{}\n", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAlertCount([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAlertCount error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseAlertCount = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetOpenSecurityAlerts(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		want    securityAlerts
+		wantErr bool
+	}{
+		{"open alerts", `printf '100\n2\n'`, securityAlerts{Count: 102}, false},
+		{"no alerts", `echo 0`, securityAlerts{}, false},
+		{"alerts disabled", `echo 'gh: Dependabot alerts are disabled for this repository. (HTTP 403)' >&2; exit 1`, securityAlerts{Unknown: true}, false},
+		{"not readable", `echo 'gh: Not Found (HTTP 404)' >&2; exit 1`, securityAlerts{Unknown: true}, false},
+		{"failure", `echo 'gh: Server Error (HTTP 500)' >&2; exit 1`, securityAlerts{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, tt.script)
+			SetCacheTTL(0)
+
+			got, err := GetOpenSecurityAlerts("o/r")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetOpenSecurityAlerts error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("alerts = %+v, want %+v", got, tt.want)
+			}
+			if calls := ghCalls(t, logPath); !strings.Contains(calls[0], "repos/o/r/dependabot/alerts?state=open") {
+				t.Errorf("gh call %q does not list the open alerts", calls[0])
+			}
+		})
+	}
+}
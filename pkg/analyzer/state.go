@@ -0,0 +1,127 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// DeltaNew marks a repository that was not present in the previous run
+const DeltaNew = "new"
+
+// RepositoryState is the per-repository snapshot recorded between runs
+type RepositoryState struct {
+	DaysSinceLastCommit  int  `json:"daysSinceLastCommit"`
+	TotalContributors    int  `json:"totalContributors"`
+	InactiveContributors int  `json:"inactiveContributors"`
+	Flagged              bool `json:"flagged"`
+}
+
+// State is the saved result of a previous run used to compute deltas
+type State struct {
+	SavedAt      time.Time                  `json:"savedAt"`
+	Repositories map[string]RepositoryState `json:"repositories"`
+}
+
+// LoadState reads a state file, returning an empty state if it does not exist yet
+func LoadState(path string) (State, error) {
+	state := State{Repositories: make(map[string]RepositoryState)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	if state.Repositories == nil {
+		state.Repositories = make(map[string]RepositoryState)
+	}
+
+	return state, nil
+}
+
+// SaveState records the metrics of the analyzed repositories
+// When merge is true, repositories from the previous state that were not analyzed are kept
+func SaveState(path string, previous State, repos []Repository, merge bool) error {
+	state := State{
+		SavedAt:      time.Now(),
+		Repositories: make(map[string]RepositoryState),
+	}
+	if merge {
+		for name, rs := range previous.Repositories {
+			state.Repositories[name] = rs
+		}
+	}
+	for _, repo := range repos {
+		state.Repositories[repo.Name] = RepositoryState{
+			DaysSinceLastCommit:  repo.DaysSinceLastCommit,
+			TotalContributors:    repo.TotalContributors,
+			InactiveContributors: repo.InactiveContributors,
+			Flagged:              repo.Flagged,
+		}
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyStateDeltas annotates each repository with how its days since last commit changed
+// since the previous run and returns the repositories that are no longer present
+func ApplyStateDeltas(previous State, repos []Repository) []string {
+	seen := make(map[string]bool, len(repos))
+	for i := range repos {
+		seen[repos[i].Name] = true
+
+		prev, ok := previous.Repositories[repos[i].Name]
+		if !ok {
+			repos[i].SinceLastRun = DeltaNew
+			continue
+		}
+		repos[i].SinceLastRun = formatDaysDelta(repos[i].DaysSinceLastCommit - prev.DaysSinceLastCommit)
+	}
+
+	var removed []string
+	for name := range previous.Repositories {
+		if !seen[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+
+	return removed
+}
+
+// formatDaysDelta renders a day difference such as "+7d" or "-3d"
+func formatDaysDelta(delta int) string {
+	if delta < 0 {
+		return fmt.Sprintf("%dd", delta)
+	}
+	return fmt.Sprintf("+%dd", delta)
+}
+
+// sinceLastRunSuffix renders the delta for inclusion in a human-readable line
+func sinceLastRunSuffix(repo Repository) string {
+	switch repo.SinceLastRun {
+	case "":
+		return ""
+	case DeltaNew:
+		return ", new since last run"
+	default:
+		return fmt.Sprintf(", %s since last run", repo.SinceLastRun)
+	}
+}
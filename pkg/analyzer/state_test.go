@@ -0,0 +1,123 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApplyStateDeltas(t *testing.T) {
+	previous := State{Repositories: map[string]RepositoryState{
+		"o/a":    {DaysSinceLastCommit: 100},
+		"o/b":    {DaysSinceLastCommit: 30},
+		"o/c":    {DaysSinceLastCommit: 5},
+		"o/zed":  {DaysSinceLastCommit: 1},
+		"o/gone": {DaysSinceLastCommit: 1},
+	}}
+	repos := []Repository{
+		{Name: "o/a", DaysSinceLastCommit: 107},
+		{Name: "o/b", DaysSinceLastCommit: 2},
+		{Name: "o/c", DaysSinceLastCommit: 5},
+		{Name: "o/new", DaysSinceLastCommit: 9},
+	}
+
+	removed := ApplyStateDeltas(previous, repos)
+	if want := []string{"o/gone", "o/zed"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %q, want %q", removed, want)
+	}
+
+	tests := []struct {
+		repo       string
+		wantDelta  string
+		wantSuffix string
+	}{
+		{"o/a", "+7d", ", +7d since last run"},
+		{"o/b", "-28d", ", -28d since last run"},
+		{"o/c", "+0d", ", +0d since last run"},
+		{"o/new", DeltaNew, ", new since last run"},
+	}
+	for i, tt := range tests {
+		if repos[i].SinceLastRun != tt.wantDelta || sinceLastRunSuffix(repos[i]) != tt.wantSuffix {
+			t.Errorf("%s: delta %q with suffix %q, want %q and %q", tt.repo, repos[i].SinceLastRun, sinceLastRunSuffix(repos[i]), tt.wantDelta, tt.wantSuffix)
+		}
+	}
+	if got := sinceLastRunSuffix(Repository{}); got != "" {
+		t.Errorf("suffix without a previous run = %q", got)
+	}
+}
+
+func TestSaveState(t *testing.T) {
+	previous := State{Repositories: map[string]RepositoryState{
+		"o/a":   {DaysSinceLastCommit: 100, Flagged: true},
+		"o/old": {DaysSinceLastCommit: 500, Flagged: true},
+	}}
+	repos := []Repository{{Name: "o/a", DaysSinceLastCommit: 7, TotalContributors: 3, InactiveContributors: 1}}
+
+	tests := []struct {
+		name  string
+		merge bool
+		want  map[string]RepositoryState
+	}{
+		{"replaced", false, map[string]RepositoryState{
+			"o/a": {DaysSinceLastCommit: 7, TotalContributors: 3, InactiveContributors: 1},
+		}},
+		{"merged", true, map[string]RepositoryState{
+			"o/a":   {DaysSinceLastCommit: 7, TotalContributors: 3, InactiveContributors: 1},
+			"o/old": {DaysSinceLastCommit: 500, Flagged: true},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.json")
+			if err := SaveState(path, previous, repos, tt.merge); err != nil {
+				t.Fatal(err)
+			}
+			state, err := LoadState(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(state.Repositories, tt.want) || state.SavedAt.IsZero() {
+				t.Errorf("saved state = %+v, want repositories %+v", state, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadState(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  *string
+		wantRepo int
+		wantErr  string
+	}{
+		{"missing file", nil, 0, ""},
+		{"no repositories", strPtr(`{"savedAt":"2025-06-01T00:00:00Z"}`), 0, ""},
+		{"repositories", strPtr(`{"repositories":{"o/a":{"daysSinceLastCommit":3}}}`), 1, ""},
+		{"malformed", strPtr(`{"repositories":`), 0, "failed to parse state file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.json")
+			if tt.content != nil {
+				if err := os.WriteFile(path, []byte(*tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			state, err := LoadState(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("LoadState error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if state.Repositories == nil || len(state.Repositories) != tt.wantRepo {
+				t.Errorf("loaded %v repositories, want %d in a non-nil map", state.Repositories, tt.wantRepo)
+			}
+		})
+	}
+}
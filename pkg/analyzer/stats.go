@@ -0,0 +1,209 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// OrgStats holds aggregate health metrics for an organization without per-repository detail
+type OrgStats struct {
+	Organization              string    `json:"organization"`
+	AnalyzedAt                time.Time `json:"analyzedAt"`
+	TotalRepositories         int       `json:"totalRepositories"`
+	FlaggedRepositories       int       `json:"flaggedRepositories"`
+	FlaggedRatio              float64   `json:"flaggedRatio"`
+	InactiveRepositories      int       `json:"inactiveRepositories"`
+	ArchivedRepositories      int       `json:"archivedRepositories"`
+	InactiveRatio             float64   `json:"inactiveRatio"`
+	MedianDaysSinceLastCommit float64   `json:"medianDaysSinceLastCommit"`
+	TotalContributors         int       `json:"totalContributors"`
+	ActiveContributors        int       `json:"activeContributors"`
+	InactiveContributors      int       `json:"inactiveContributors"`
+	Skipped                   int       `json:"skipped"`
+	NotAnalyzed               int       `json:"notAnalyzed,omitempty"` // repositories left out because the deadline passed
+	HealthScore               int       `json:"healthScore"`
+	Coverage                  float64   `json:"coverage"`
+	LowCoverage               bool      `json:"lowCoverage,omitempty"`
+
+	// Lifecycle counts the repositories per lifecycle stage, when repositories are classified
+	Lifecycle map[string]int `json:"lifecycle,omitempty"`
+}
+
+// Health score weights, summing to 1
+const (
+	healthWeightUnflagged = 0.5
+	healthWeightActive    = 0.3
+	healthWeightFresh     = 0.2
+)
+
+// incompleteRepoWeight is how much a repository without contributor data counts toward the health score,
+// as only its commit data could be measured
+const incompleteRepoWeight = 0.5
+
+// lowCoverageThreshold is the coverage under which the health score is reported as unreliable
+const lowCoverageThreshold = 0.8
+
+// ComputeOrgStats aggregates repository results into organization-wide metrics
+// Contributor totals are summed over repositories, so people contributing to several repositories count once per repository
+func ComputeOrgStats(repos []Repository, skipped int, cfg config.Config) OrgStats {
+	stats := OrgStats{
+		Organization:      cfg.Organization,
+		AnalyzedAt:        Now().UTC().Truncate(time.Second),
+		TotalRepositories: len(repos),
+		Skipped:           skipped,
+		NotAnalyzed:       NotStarted(),
+	}
+
+	days := make([]int, 0, len(repos))
+	var weights, unflaggedWeight, freshWeight float64
+	for _, repo := range repos {
+		w := repoCompleteness(repo, cfg)
+		weights += w
+		if repo.Flagged {
+			stats.FlaggedRepositories++
+		}
+		if !repo.Flagged || isFlaggedArchived(repo) {
+			unflaggedWeight += w
+		}
+		if repo.DaysSinceLastCommit <= cfg.MaxCommitAgeInDays {
+			freshWeight += w
+		}
+		days = append(days, repo.DaysSinceLastCommit)
+
+		if repo.ContributorDataComplete {
+			stats.TotalContributors += repo.TotalContributors
+			stats.InactiveContributors += repo.InactiveContributors
+		}
+	}
+	stats.ActiveContributors = stats.TotalContributors - stats.InactiveContributors
+	stats.InactiveRepositories, stats.ArchivedRepositories = countFlagged(repos)
+	if cfg.Lifecycle {
+		stats.Lifecycle = countLifecycleStages(repos)
+	}
+
+	if len(repos) > 0 {
+		stats.FlaggedRatio = float64(stats.FlaggedRepositories) / float64(len(repos))
+		stats.InactiveRatio = float64(stats.InactiveRepositories) / float64(len(repos))
+	}
+	stats.MedianDaysSinceLastCommit = median(days)
+	if len(repos) > 0 {
+		stats.Coverage = weights / float64(len(repos))
+		stats.LowCoverage = stats.Coverage < lowCoverageThreshold
+		stats.HealthScore = healthScore(stats, unflaggedWeight/weights, freshWeight/weights)
+	}
+
+	return stats
+}
+
+// repoCompleteness weights a repository by how much of its health data was measured: fully when its
+// contributor data is complete or was not asked for, and incompleteRepoWeight when it is missing
+func repoCompleteness(repo Repository, cfg config.Config) float64 {
+	if repo.ContributorDataComplete || !collects(cfg, MetricContributors) {
+		return 1
+	}
+	return incompleteRepoWeight
+}
+
+// healthScore combines the share of repositories not flagged for inactivity (50%), the active contributor share (30%),
+// and the share of repositories committed to within the age limit (20%) into a 0-100 score
+// The repository shares are weighted by data completeness, so repositories missing contributor data count less
+// Archived repositories were retired on purpose, so they do not count against the score
+// Without contributor data the contributor component is left out and the others are reweighted
+func healthScore(stats OrgStats, unflaggedShare, freshShare float64) int {
+	if stats.TotalRepositories == 0 {
+		return 0
+	}
+
+	score := healthWeightUnflagged*unflaggedShare + healthWeightFresh*freshShare
+	weights := healthWeightUnflagged + healthWeightFresh
+
+	if stats.TotalContributors > 0 {
+		score += healthWeightActive * float64(stats.ActiveContributors) / float64(stats.TotalContributors)
+		weights += healthWeightActive
+	}
+
+	return int(math.Round(100 * score / weights))
+}
+
+// median returns the median of the values, or 0 when there are none
+func median(values []int) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return float64(sorted[mid-1]+sorted[mid]) / 2
+	}
+	return float64(sorted[mid])
+}
+
+// renderStatsText renders the aggregate metrics for human-readable output
+func renderStatsText(stats OrgStats) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("\n📊 Organization Health for %s\n", stats.Organization))
+	buf.WriteString(fmt.Sprintf("Health score: %d/100 (%.0f%% coverage)\n", stats.HealthScore, stats.Coverage*100))
+	if stats.LowCoverage {
+		buf.WriteString(fmt.Sprintf("⚠️ Low coverage (under %.0f%%): contributor data is missing for many repositories, so the score rests on partial data\n",
+			lowCoverageThreshold*100))
+	}
+	buf.WriteString(fmt.Sprintf("Repositories analyzed: %d", stats.TotalRepositories))
+	if stats.Skipped > 0 {
+		buf.WriteString(fmt.Sprintf(" (%d skipped)", stats.Skipped))
+	}
+	if stats.NotAnalyzed > 0 {
+		buf.WriteString(fmt.Sprintf(" (deadline reached, results partial: %d not analyzed)", stats.NotAnalyzed))
+	}
+	buf.WriteString("\n")
+	buf.WriteString(fmt.Sprintf("🚩 Flagged: %d (%.1f%%): %d inactive (%.1f%%), %d archived\n",
+		stats.FlaggedRepositories, stats.FlaggedRatio*100,
+		stats.InactiveRepositories, stats.InactiveRatio*100, stats.ArchivedRepositories))
+	if stats.Lifecycle != nil {
+		buf.WriteString(fmt.Sprintf("🌱 Lifecycle: %s\n", lifecycleSummary(stats.Lifecycle)))
+	}
+	buf.WriteString(fmt.Sprintf("Median days since last commit: %.1f\n", stats.MedianDaysSinceLastCommit))
+	buf.WriteString(fmt.Sprintf("Contributors: %d total, %d active, %d inactive\n",
+		stats.TotalContributors, stats.ActiveContributors, stats.InactiveContributors))
+	return buf.Bytes()
+}
+
+// IsStatsFormat reports whether the stats command supports the output format
+func IsStatsFormat(format string) bool {
+	return format == "console" || format == "json"
+}
+
+// OutputStats outputs the aggregate metrics as JSON or console text
+func OutputStats(stats OrgStats, cfg config.Config) error {
+	stats.Organization = RedactOrganization(stats.Organization)
+	var data []byte
+	if cfg.OutputFormat == "json" {
+		var err error
+		data, err = json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		data = append(data, '\n')
+	} else {
+		data = renderStatsText(stats)
+	}
+
+	if cfg.OutputFile != "" {
+		if err := writeOutputFile(cfg, data); err != nil {
+			return err
+		}
+		Logf("💾 Results saved to %s\n", cfg.OutputFile)
+		return nil
+	}
+
+	fmt.Print(string(data))
+	return nil
+}
@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestComputeOrgStats(t *testing.T) {
+	pinNow(t)
+	cfg := config.Config{Organization: "o", MaxCommitAgeInDays: 180}
+	repos := []Repository{
+		{Name: "o/a", DaysSinceLastCommit: 10, ContributorDataComplete: true, TotalContributors: 4, InactiveContributors: 1},
+		{Name: "o/b", DaysSinceLastCommit: 400, ContributorDataComplete: true, TotalContributors: 2, InactiveContributors: 2,
+			Flagged: true, FlagReason: FlagReasonOldInactiveContributors},
+		{Name: "o/c", DaysSinceLastCommit: 900, Archived: true, Flagged: true, FlagReason: FlagReasonArchived,
+			ContributorDataComplete: true, TotalContributors: 1},
+		{Name: "o/d", DaysSinceLastCommit: 30, TotalContributors: 7},
+	}
+
+	stats := ComputeOrgStats(repos, 2, cfg)
+	want := OrgStats{
+		Organization:              "o",
+		AnalyzedAt:                testNow,
+		TotalRepositories:         4,
+		FlaggedRepositories:       2,
+		FlaggedRatio:              0.5,
+		InactiveRepositories:      1,
+		ArchivedRepositories:      1,
+		InactiveRatio:             0.25,
+		MedianDaysSinceLastCommit: 215,
+		TotalContributors:         7, // o/d's contributor data is incomplete
+		ActiveContributors:        4,
+		InactiveContributors:      3,
+		Skipped:                   2,
+		HealthScore:               61,
+		Coverage:                  0.875,
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("ComputeOrgStats = %+v, want %+v", stats, want)
+	}
+}
+
+func TestComputeOrgStatsNothingAnalyzed(t *testing.T) {
+	stats := ComputeOrgStats(nil, 3, config.Config{Organization: "o"})
+	if stats.TotalRepositories != 0 || stats.HealthScore != 0 || stats.FlaggedRatio != 0 || stats.Skipped != 3 {
+		t.Errorf("ComputeOrgStats of no repositories = %+v, want zero ratios and score", stats)
+	}
+}
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		values []int
+		want   float64
+	}{
+		{nil, 0},
+		{[]int{5}, 5},
+		{[]int{30, 10, 20}, 20},
+		{[]int{40, 10, 30, 20}, 25},
+	}
+	for _, tt := range tests {
+		if got := median(tt.values); got != tt.want {
+			t.Errorf("median(%v) = %v, want %v", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestComputeOrgStatsCoverage(t *testing.T) {
+	complete := Repository{Name: "o/a", DaysSinceLastCommit: 10, ContributorDataComplete: true, TotalContributors: 2}
+	staleIncomplete := Repository{Name: "o/b", DaysSinceLastCommit: 400}
+	freshIncomplete := Repository{Name: "o/c", DaysSinceLastCommit: 20}
+	cfg := config.Config{Organization: "o", MaxCommitAgeInDays: 180}
+
+	tests := []struct {
+		name         string
+		repos        []Repository
+		cfg          config.Config
+		wantCoverage float64
+		wantScore    int
+		wantLow      bool
+	}{
+		{"complete data", []Repository{complete, complete}, cfg, 1, 100, false},
+		// The stale repository missing contributor data counts half, so the fresh share is 1/1.5 rather than 1/2
+		{"incomplete repository weighted down", []Repository{complete, staleIncomplete}, cfg, 0.75, 93, true},
+		{"contributor data not asked for", []Repository{freshIncomplete, staleIncomplete},
+			withConfig(cfg, func(c *config.Config) { c.Metrics = []string{MetricCommits} }), 1, 86, false},
+		{"no contributor data", []Repository{freshIncomplete, staleIncomplete}, cfg, 0.5, 86, true},
+		{"coverage at the threshold", []Repository{complete, complete, complete, freshIncomplete, freshIncomplete}, cfg, 0.8, 100, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pinNow(t)
+			stats := ComputeOrgStats(tt.repos, 0, tt.cfg)
+			if stats.Coverage != tt.wantCoverage || stats.HealthScore != tt.wantScore || stats.LowCoverage != tt.wantLow {
+				t.Errorf("coverage %v, score %d, low %v, want %v, %d, %v",
+					stats.Coverage, stats.HealthScore, stats.LowCoverage, tt.wantCoverage, tt.wantScore, tt.wantLow)
+			}
+			text := string(renderStatsText(stats))
+			if shown := strings.Contains(text, "Low coverage"); shown != tt.wantLow {
+				t.Errorf("low coverage shown = %v, want %v:\n%s", shown, tt.wantLow, text)
+			}
+		})
+	}
+}
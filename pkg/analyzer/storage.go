@@ -0,0 +1,278 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// objectUploadTimeout bounds an upload of the output to object storage
+const objectUploadTimeout = 2 * time.Minute
+
+// gcsTokenURL is the metadata server endpoint returning the access token of the instance service account
+const gcsTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// objectUploader uploads data to an object in a bucket with a content type
+type objectUploader func(ctx context.Context, bucket, key, contentType string, data []byte) error
+
+// objectStorageUploaders maps object storage URL schemes to their uploader; both take their credentials
+// and endpoint from the environment
+var objectStorageUploaders = map[string]objectUploader{
+	"s3://": uploadS3Object,
+	"gs://": uploadGCSObject,
+}
+
+// IsObjectStorageURL reports whether an output path is an s3:// or gs:// URL
+func IsObjectStorageURL(path string) bool {
+	_, _, _, ok := parseObjectStorageURL(path)
+	return ok
+}
+
+// parseObjectStorageURL splits an object storage URL into its uploader, bucket, and key
+func parseObjectStorageURL(path string) (uploader objectUploader, bucket, key string, ok bool) {
+	for scheme, uploader := range objectStorageUploaders {
+		rest, found := strings.CutPrefix(path, scheme)
+		if !found {
+			continue
+		}
+		bucket, key, _ = strings.Cut(rest, "/")
+		if bucket == "" || key == "" {
+			return nil, "", "", false
+		}
+		return uploader, bucket, key, true
+	}
+	return nil, "", "", false
+}
+
+// uploadS3Object uploads an object to S3, or to the S3-compatible endpoint set with AWS_ENDPOINT_URL,
+// with a PUT request signed with the access key in AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+func uploadS3Object(ctx context.Context, bucket, key, contentType string, data []byte) error {
+	accessKey, secretKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set")
+	}
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	// S3-compatible endpoints rarely resolve bucket subdomains, so they are addressed path-style
+	objectURL := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, region, escapeS3Key(key))
+	if endpoint := os.Getenv("AWS_ENDPOINT_URL"); endpoint != "" {
+		objectURL = strings.TrimSuffix(endpoint, "/") + "/" + bucket + "/" + escapeS3Key(key)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if token := os.Getenv("AWS_SESSION_TOKEN"); token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+	signS3Request(req, data, accessKey, secretKey, region, time.Now().UTC())
+	return doUpload(req)
+}
+
+// escapeS3Key escapes an object key for an S3 URL path the way request signing expects, keeping its slashes
+func escapeS3Key(key string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(key) {
+		switch {
+		case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9', strings.IndexByte("-._~/", b) >= 0:
+			escaped.WriteByte(b)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
+
+// signS3Request adds an AWS Signature Version 4 authorization header to an S3 request
+func signS3Request(req *http.Request, payload []byte, accessKey, secretKey, region string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+	if req.Header.Get("X-Amz-Security-Token") != "" {
+		signedHeaders = append(signedHeaders, "x-amz-security-token")
+	}
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaders {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, strings.TrimSpace(value))
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	signingKey := []byte("AWS4" + secretKey)
+	for _, part := range []string{date, region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, strings.Join(signedHeaders, ";"), hex.EncodeToString(hmacSHA256(signingKey, stringToSign))))
+}
+
+// sha256Hex returns the hex-encoded SHA-256 digest of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of a message under a key
+func hmacSHA256(key []byte, message string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+// uploadGCSObject uploads an object to Google Cloud Storage with a JSON API multipart upload, or to the
+// emulator set with STORAGE_EMULATOR_HOST
+func uploadGCSObject(ctx context.Context, bucket, key, contentType string, data []byte) error {
+	endpoint := "https://storage.googleapis.com"
+	emulator := os.Getenv("STORAGE_EMULATOR_HOST")
+	if emulator != "" {
+		endpoint = emulator
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "http://" + endpoint
+		}
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	metadata, err := json.Marshal(map[string]string{"bucket": bucket, "name": key, "contentType": contentType})
+	if err != nil {
+		return err
+	}
+	for _, part := range []struct {
+		contentType string
+		data        []byte
+	}{
+		{"application/json; charset=UTF-8", metadata},
+		{contentType, data},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+		if err != nil {
+			return err
+		}
+		w.Write(part.data)
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	uploadURL := strings.TrimSuffix(endpoint, "/") + "/upload/storage/v1/b/" + url.PathEscape(bucket) + "/o?uploadType=multipart"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "multipart/related; boundary="+writer.Boundary())
+	if emulator == "" {
+		token, err := gcsAccessToken(ctx)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return doUpload(req)
+}
+
+// gcsAccessToken returns the access token in GOOGLE_OAUTH_ACCESS_TOKEN, or the one of the service account
+// of the Google Cloud instance the tool runs on
+func gcsAccessToken(ctx context.Context) (string, error) {
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcsTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get a Google Cloud access token, set GOOGLE_OAUTH_ACCESS_TOKEN: %w", err)
+	}
+	defer resp.Body.Close()
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get a Google Cloud access token, set GOOGLE_OAUTH_ACCESS_TOKEN: %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil || token.AccessToken == "" {
+		return "", fmt.Errorf("failed to parse the Google Cloud access token")
+	}
+	return token.AccessToken, nil
+}
+
+// doUpload sends an upload request, returning the status and response body of a rejected upload as an error
+func doUpload(req *http.Request) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(message)))
+}
+
+// outputContentType returns the content type of the output written with a configuration
+func outputContentType(cfg config.Config) string {
+	if cfg.EmitScript != "" {
+		return "text/x-shellscript; charset=utf-8"
+	}
+	contentType, _ := ReportFileType(cfg.OutputFormat)
+	return contentType
+}
+
+// writeOutputFile writes rendered output to the output file, or uploads it when the output is an object storage URL
+func writeOutputFile(cfg config.Config, data []byte) error {
+	uploader, bucket, key, ok := parseObjectStorageURL(cfg.OutputFile)
+	if !ok {
+		if err := writeFileAtomic(cfg.OutputFile, data, 0644); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), objectUploadTimeout)
+	defer cancel()
+	if err := uploader(ctx, bucket, key, outputContentType(cfg), data); err != nil {
+		return fmt.Errorf("failed to upload output to %s: %w", cfg.OutputFile, err)
+	}
+	return nil
+}
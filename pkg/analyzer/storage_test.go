@@ -0,0 +1,217 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// storedObject records an object uploaded to a fake storage endpoint
+type storedObject struct {
+	path        string
+	contentType string
+	body        string
+}
+
+// fakeStorage serves a fake storage endpoint, recording each upload decoded by its protocol handler
+func fakeStorage(t *testing.T, decode func(r *http.Request) (storedObject, error)) (*httptest.Server, *[]storedObject) {
+	t.Helper()
+	var mu sync.Mutex
+	var objects []storedObject
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		object, err := decode(r)
+		if err != nil {
+			t.Errorf("bad upload request %s %s: %v", r.Method, r.URL, err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		objects = append(objects, object)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"name":"object","bucket":"bucket"}`)
+	}))
+	t.Cleanup(server.Close)
+	return server, &objects
+}
+
+// decodeS3Upload decodes an S3 PutObject request signed with the test access key
+func decodeS3Upload(r *http.Request) (storedObject, error) {
+	if r.Method != http.MethodPut {
+		return storedObject{}, fmt.Errorf("unexpected method %s", r.Method)
+	}
+	if auth := r.Header.Get("Authorization"); !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=test/") ||
+		!strings.Contains(auth, "/us-east-1/s3/aws4_request, SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature=") {
+		return storedObject{}, fmt.Errorf("unexpected authorization %q", auth)
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return storedObject{}, err
+	}
+	if got := r.Header.Get("X-Amz-Content-Sha256"); got != sha256Hex(body) {
+		return storedObject{}, fmt.Errorf("payload hash %q does not match the body", got)
+	}
+	return storedObject{path: r.URL.Path, contentType: r.Header.Get("Content-Type"), body: string(body)}, nil
+}
+
+// decodeGCSUpload decodes a Cloud Storage JSON API multipart upload
+func decodeGCSUpload(r *http.Request) (storedObject, error) {
+	if r.Method != http.MethodPost || r.URL.Query().Get("uploadType") != "multipart" {
+		return storedObject{}, fmt.Errorf("unexpected request %s %s", r.Method, r.URL)
+	}
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return storedObject{}, err
+	}
+	reader := multipart.NewReader(r.Body, params["boundary"])
+
+	metadataPart, err := reader.NextPart()
+	if err != nil {
+		return storedObject{}, err
+	}
+	var metadata struct {
+		Bucket      string `json:"bucket"`
+		Name        string `json:"name"`
+		ContentType string `json:"contentType"`
+	}
+	if err := json.NewDecoder(metadataPart).Decode(&metadata); err != nil {
+		return storedObject{}, err
+	}
+
+	mediaPart, err := reader.NextPart()
+	if err != nil {
+		return storedObject{}, err
+	}
+	body, err := io.ReadAll(mediaPart)
+	if err != nil {
+		return storedObject{}, err
+	}
+	return storedObject{path: "/" + metadata.Bucket + "/" + metadata.Name, contentType: metadata.ContentType, body: string(body)}, nil
+}
+
+func TestParseObjectStorageURL(t *testing.T) {
+	tests := []struct {
+		url    string
+		bucket string
+		key    string
+		ok     bool
+	}{
+		{"s3://reports/daily/report.json", "reports", "daily/report.json", true},
+		{"gs://reports/report.csv", "reports", "report.csv", true},
+		{"s3://reports", "", "", false},
+		{"s3://reports/", "", "", false},
+		{"gs://", "", "", false},
+		{"reports/report.json", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			_, bucket, key, ok := parseObjectStorageURL(tt.url)
+			if bucket != tt.bucket || key != tt.key || ok != tt.ok {
+				t.Errorf("parseObjectStorageURL(%q) = %q, %q, %v, want %q, %q, %v", tt.url, bucket, key, ok, tt.bucket, tt.key, tt.ok)
+			}
+			if got := IsObjectStorageURL(tt.url); got != tt.ok {
+				t.Errorf("IsObjectStorageURL(%q) = %v, want %v", tt.url, got, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSignS3Request(t *testing.T) {
+	data := []byte(`{"ok":true}`)
+	req, err := http.NewRequest(http.MethodPut, "https://reports.s3.eu-west-1.amazonaws.com/"+escapeS3Key("daily/report 1+2.json"), bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	signS3Request(req, data, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "eu-west-1", time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	if got, want := req.URL.EscapedPath(), "/daily/report%201%2B2.json"; got != want {
+		t.Errorf("path = %q, want %q", got, want)
+	}
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240501/eu-west-1/s3/aws4_request, " +
+		"SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, " +
+		"Signature=f8e0af2cd1a9801ac5a53c61332ea26e504b756bc2262eef01a292bb0abf68c0"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization = %q, want %q", got, want)
+	}
+	if got := req.Header.Get("X-Amz-Date"); got != "20240501T120000Z" {
+		t.Errorf("X-Amz-Date = %q, want 20240501T120000Z", got)
+	}
+}
+
+func TestWriteOutputFileUpload(t *testing.T) {
+	tests := []struct {
+		name   string
+		decode func(r *http.Request) (storedObject, error)
+		env    func(t *testing.T, endpoint string)
+		output string
+		format string
+		want   storedObject
+	}{
+		{
+			name:   "s3",
+			decode: decodeS3Upload,
+			env: func(t *testing.T, endpoint string) {
+				t.Setenv("AWS_ENDPOINT_URL", endpoint)
+				t.Setenv("AWS_REGION", "us-east-1")
+				t.Setenv("AWS_ACCESS_KEY_ID", "test")
+				t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+				t.Setenv("AWS_SESSION_TOKEN", "")
+			},
+			output: "s3://reports/daily/report.json",
+			format: "json",
+			want:   storedObject{path: "/reports/daily/report.json", contentType: "application/json", body: `{"ok":true}`},
+		},
+		{
+			name:   "gcs",
+			decode: decodeGCSUpload,
+			env: func(t *testing.T, endpoint string) {
+				t.Setenv("STORAGE_EMULATOR_HOST", strings.TrimPrefix(endpoint, "http://"))
+			},
+			output: "gs://reports/report.csv",
+			format: "csv",
+			want:   storedObject{path: "/reports/report.csv", contentType: "text/csv", body: "a,b\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, objects := fakeStorage(t, tt.decode)
+			tt.env(t, server.URL)
+
+			cfg := config.Config{OutputFile: tt.output, OutputFormat: tt.format}
+			if err := writeOutputFile(cfg, []byte(tt.want.body)); err != nil {
+				t.Fatalf("writeOutputFile: %v", err)
+			}
+			if len(*objects) != 1 {
+				t.Fatalf("got %d uploads, want 1", len(*objects))
+			}
+			if got := (*objects)[0]; got != tt.want {
+				t.Errorf("uploaded %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteOutputFileUploadFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "denied", http.StatusForbidden)
+	}))
+	defer server.Close()
+	t.Setenv("STORAGE_EMULATOR_HOST", strings.TrimPrefix(server.URL, "http://"))
+
+	err := writeOutputFile(config.Config{OutputFile: "gs://reports/report.json", OutputFormat: "json"}, []byte("{}"))
+	if err == nil || !strings.Contains(err.Error(), "failed to upload output to gs://reports/report.json") {
+		t.Errorf("writeOutputFile error = %v, want an upload failure", err)
+	}
+}
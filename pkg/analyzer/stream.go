@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// outputStream writes repositories to the output file as they are analyzed, so long scans can be
+// followed with tail; each line is written straight to the file rather than buffered
+type outputStream struct {
+	file   *os.File
+	rw     *ResultWriter
+	ndjson *NDJSONStream // nil for CSV
+	extra  []string      // extra CSV columns
+	cfg    config.Config
+}
+
+// activeStream is the output stream of the current run, nil when none is open, and whether one was opened
+var activeStream struct {
+	mu       sync.Mutex
+	s        *outputStream
+	streamed bool
+}
+
+// StartOutputStream opens the output file and writes the CSV header or NDJSON meta line when streaming
+// output is enabled; total is the number of repositories to come and extra the extra CSV columns
+// It does nothing when streaming is disabled or a stream is already open.
+func StartOutputStream(total int, extra []string, cfg config.Config) error {
+	if !cfg.StreamOutput {
+		return nil
+	}
+
+	activeStream.mu.Lock()
+	defer activeStream.mu.Unlock()
+	if activeStream.s != nil {
+		return nil
+	}
+
+	file, err := os.Create(cfg.OutputFile)
+	if err != nil {
+		return fmt.Errorf("failed to open output stream: %w", err)
+	}
+	s := &outputStream{file: file, rw: NewResultWriter(file), extra: extra, cfg: cfg}
+
+	if IsNDJSONFormat(cfg.OutputFormat) {
+		s.ndjson = NewNDJSONStream(s.rw)
+		err = s.ndjson.WriteMeta(total, RedactOrganization(cfg.Organization))
+	} else {
+		err = s.rw.WriteLine([]byte(csvHeaderLine(extra, cfg)))
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	activeStream.s = s
+	activeStream.streamed = true
+	return nil
+}
+
+// StreamRepository writes an analyzed repository to the open output stream, if any
+// It is safe for concurrent use; repositories are written in the order they finish.
+func StreamRepository(repo Repository) error {
+	activeStream.mu.Lock()
+	s := activeStream.s
+	activeStream.mu.Unlock()
+	if s == nil {
+		return nil
+	}
+	if redactionMap != nil {
+		repo = redactionMap.redactRepository(repo)
+	}
+
+	if s.ndjson != nil {
+		return s.ndjson.WriteRepository(repo)
+	}
+	line, err := csvLine(repo, s.extra, s.cfg)
+	if err != nil {
+		return err
+	}
+	return s.rw.WriteLine([]byte(line))
+}
+
+// CloseOutputStream writes the NDJSON summary line, if any, and closes the output stream
+// It is safe to call when no stream is open and more than once, e.g. on completion and on interruption.
+func CloseOutputStream() error {
+	activeStream.mu.Lock()
+	s := activeStream.s
+	activeStream.s = nil
+	activeStream.mu.Unlock()
+	if s == nil {
+		return nil
+	}
+
+	var err error
+	if s.ndjson != nil {
+		err = s.ndjson.WriteSummary()
+	}
+	if syncErr := s.file.Sync(); err == nil && syncErr != nil {
+		err = fmt.Errorf("failed to flush output stream: %w", syncErr)
+	}
+	if closeErr := s.file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close output stream: %w", closeErr)
+	}
+	return err
+}
+
+// outputStreamed reports whether the repositories of this run were written to the output file as they were analyzed
+func outputStreamed() bool {
+	activeStream.mu.Lock()
+	defer activeStream.mu.Unlock()
+	return activeStream.streamed
+}
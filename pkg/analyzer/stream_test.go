@@ -0,0 +1,110 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// readStream returns the lines written to the output file so far
+func readStream(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func TestOutputStream(t *testing.T) {
+	repos := []Repository{
+		{Name: "o/a", Flagged: true, FlagReason: FlagReasonArchived, Archived: true},
+		{Name: "o/b"},
+	}
+
+	tests := []struct {
+		format    string
+		firstLine string
+		// Prefixes of each repository line and of the line written on closing, if any
+		repoLines []string
+		closeLine string
+	}{
+		{"csv", "Repository Name,", []string{"o/a,", "o/b,"}, ""},
+		{"ndjson", `{"type":"meta"`, []string{`{"type":"repo","name":"o/a"`, `{"type":"repo","name":"o/b"`}, `{"type":"summary"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out."+tt.format)
+			cfg := config.Config{Organization: "o", OutputFormat: tt.format, OutputFile: path, StreamOutput: true}
+			t.Cleanup(func() {
+				CloseOutputStream()
+				activeStream.mu.Lock()
+				activeStream.streamed = false
+				activeStream.mu.Unlock()
+			})
+
+			if err := StartOutputStream(len(repos), nil, cfg); err != nil {
+				t.Fatal(err)
+			}
+			// Starting again keeps the open stream
+			if err := StartOutputStream(len(repos), nil, cfg); err != nil {
+				t.Fatal(err)
+			}
+			lines := readStream(t, path)
+			if len(lines) != 1 || !strings.HasPrefix(lines[0], tt.firstLine) {
+				t.Fatalf("output before any repository = %q, want only %s...", lines, tt.firstLine)
+			}
+
+			// Each repository is in the file as soon as it is streamed
+			for i, repo := range repos {
+				if err := StreamRepository(repo); err != nil {
+					t.Fatal(err)
+				}
+				lines = readStream(t, path)
+				if len(lines) != i+2 || !strings.HasPrefix(lines[i+1], tt.repoLines[i]) {
+					t.Fatalf("output after %s = %q, want line %s...", repo.Name, lines, tt.repoLines[i])
+				}
+			}
+
+			if err := CloseOutputStream(); err != nil {
+				t.Fatal(err)
+			}
+			if err := CloseOutputStream(); err != nil {
+				t.Errorf("closing again = %v, want nothing to do", err)
+			}
+			lines = readStream(t, path)
+			if tt.closeLine != "" && (len(lines) != 4 || !strings.HasPrefix(lines[3], tt.closeLine)) {
+				t.Errorf("output after closing = %q, want a last line %s...", lines, tt.closeLine)
+			}
+			if tt.closeLine == "" && len(lines) != 3 {
+				t.Errorf("output after closing = %q, want the header and 2 rows", lines)
+			}
+			if !outputStreamed() {
+				t.Error("output not reported as streamed")
+			}
+			// Repositories analyzed after closing are not written
+			if err := StreamRepository(Repository{Name: "o/late"}); err != nil || len(readStream(t, path)) != len(lines) {
+				t.Errorf("streamed a repository after closing: %v", err)
+			}
+		})
+	}
+}
+
+func TestStartOutputStreamDisabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.csv")
+	if err := StartOutputStream(1, nil, config.Config{OutputFormat: "csv", OutputFile: path}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("output file created without streaming: %v", err)
+	}
+	if err := StreamRepository(Repository{Name: "o/a"}); err != nil {
+		t.Errorf("StreamRepository without a stream = %v", err)
+	}
+}
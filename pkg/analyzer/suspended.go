@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// userAccount is what the users endpoint tells about a contributor's account
+type userAccount struct {
+	Exists    bool // false when the account was deleted
+	Suspended bool
+}
+
+// userAccounts caches the account of each login, since contributors recur across the repositories
+// of an organization or fork network
+var userAccounts = struct {
+	mu      sync.Mutex
+	results map[string]userAccount
+}{results: make(map[string]userAccount)}
+
+// lookupUserAccount reports whether a user's account exists and is suspended
+func lookupUserAccount(login string) (userAccount, error) {
+	key := strings.ToLower(login)
+	userAccounts.mu.Lock()
+	account, ok := userAccounts.results[key]
+	userAccounts.mu.Unlock()
+	if ok {
+		return account, nil
+	}
+
+	out, err := runGH("api", fmt.Sprintf("users/%s", login), "--jq", ".suspended_at // empty")
+	switch {
+	case err == nil:
+		account = userAccount{Exists: true, Suspended: parseSuspendedAt(out)}
+	case StatusCode(err) == http.StatusNotFound:
+		account = userAccount{}
+	default:
+		return userAccount{}, fmt.Errorf("failed to look up user %s: %w", login, err)
+	}
+
+	userAccounts.mu.Lock()
+	userAccounts.results[key] = account
+	userAccounts.mu.Unlock()
+
+	return account, nil
+}
+
+// isUserSuspended reports whether a user's account is suspended
+// Unknown users are not considered suspended
+func isUserSuspended(login string) (bool, error) {
+	account, err := lookupUserAccount(login)
+	if err != nil {
+		return false, err
+	}
+	return account.Suspended, nil
+}
+
+// parseSuspendedAt reports whether the suspended_at value extracted from a user response is set
+func parseSuspendedAt(data []byte) bool {
+	value := strings.TrimSpace(string(data))
+	return value != "" && value != "null"
+}
+
+// partitionSuspended splits the active contributors into those still active and those whose account is suspended
+// Lookup failures are warnings unless strict mode is enabled, and leave the contributor active
+func partitionSuspended(logins []string, cfg config.Config, warnings *warningLog) (active, suspended []string, err error) {
+	for _, login := range logins {
+		isSuspended, err := isUserSuspended(login)
+		if err != nil {
+			if cfg.Strict {
+				return nil, nil, err
+			}
+			warnings.warn(cfg, "", "%v", err)
+		}
+
+		if isSuspended {
+			suspended = append(suspended, login)
+		} else {
+			active = append(active, login)
+		}
+	}
+	return active, suspended, nil
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// resetUserAccounts forgets the accounts looked up by earlier tests
+func resetUserAccounts(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		userAccounts.mu.Lock()
+		userAccounts.results = make(map[string]userAccount)
+		userAccounts.mu.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestParseSuspendedAt(t *testing.T) {
+	tests := []struct {
+		data string
+		want bool
+	}{
+		{"2024-01-02T03:04:05Z\n", true},
+		{"\n", false},
+		{"null", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := parseSuspendedAt([]byte(tt.data)); got != tt.want {
+			t.Errorf("parseSuspendedAt(%q) = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestPartitionSuspended(t *testing.T) {
+	// ann is suspended, bob is not, gone was deleted, and looking up cy fails
+	const script = `case "$*" in
+*users/ann*) echo 2024-01-02T03:04:05Z;;
+*users/bob*) echo;;
+*users/gone*) echo 'gh: Not Found (HTTP 404)' >&2; exit 1;;
+*) echo 'gh: Server Error (HTTP 500)' >&2; exit 1;;
+esac`
+
+	tests := []struct {
+		name          string
+		logins        []string
+		strict        bool
+		wantActive    []string
+		wantSuspended []string
+		wantErr       bool
+		wantWarnings  int
+	}{
+		{"suspended account", []string{"ann", "bob"}, false, []string{"bob"}, []string{"ann"}, false, 0},
+		{"deleted account not suspended", []string{"gone"}, false, []string{"gone"}, nil, false, 0},
+		{"lookup failure left active", []string{"ann", "cy"}, false, []string{"cy"}, []string{"ann"}, false, 1},
+		{"lookup failure in strict mode", []string{"ann", "cy"}, true, nil, nil, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeGH(t, script)
+			SetCacheTTL(0)
+			resetUserAccounts(t)
+			warnings := &warningLog{}
+
+			active, suspended, err := partitionSuspended(tt.logins, config.Config{Strict: tt.strict, Silent: true}, warnings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("partitionSuspended error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "failed to look up user cy") {
+				t.Errorf("error = %v, want the failed login named", err)
+			}
+			if !reflect.DeepEqual(active, tt.wantActive) || !reflect.DeepEqual(suspended, tt.wantSuspended) {
+				t.Errorf("active %q and suspended %q, want %q and %q", active, suspended, tt.wantActive, tt.wantSuspended)
+			}
+			if got := len(warnings.list()); got != tt.wantWarnings {
+				t.Errorf("recorded %d warnings, want %d", got, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestLookupUserAccountCached(t *testing.T) {
+	logPath := fakeGH(t, `echo 2024-01-02T03:04:05Z`)
+	SetCacheTTL(0)
+	resetUserAccounts(t)
+
+	for _, login := range []string{"ann", "Ann", "ann"} {
+		account, err := lookupUserAccount(login)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !account.Exists || !account.Suspended {
+			t.Errorf("account of %s = %+v, want an existing suspended account", login, account)
+		}
+	}
+	if calls := ghCalls(t, logPath); len(calls) != 1 {
+		t.Errorf("made %d calls, want the account looked up once: %q", len(calls), calls)
+	}
+}
@@ -0,0 +1,186 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"golang.org/x/term"
+)
+
+func init() {
+	RegisterFormatter("table", tableFormatter{})
+	RegisterFormatter("ascii-table", tableFormatter{})
+}
+
+// defaultTableWidth is used when the terminal width cannot be detected
+const defaultTableWidth = 120
+
+// minNameWidth is the narrowest the repository column is truncated to
+const minNameWidth = 12
+
+// tableColumn describes a column in the ASCII table
+type tableColumn struct {
+	title      string
+	rightAlign bool
+}
+
+var tableColumns = []tableColumn{
+	{title: " "},
+	{title: "Repository"},
+	{title: "Last Commit"},
+	{title: "Days", rightAlign: true},
+	{title: "Contributors", rightAlign: true},
+	{title: "Inactive", rightAlign: true},
+	{title: "Inactive %", rightAlign: true},
+	{title: "Archived"},
+	{title: "License"},
+	{title: "Reason"},
+}
+
+// TerminalWidth returns the width of the terminal attached to stdout, or a fallback
+func TerminalWidth() int {
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return defaultTableWidth
+}
+
+// RenderTable writes the repositories as a box-drawing table that fits within the given width
+// Flagged repositories are marked with an asterisk in the first column
+func RenderTable(w io.Writer, repos []Repository, width int) error {
+	rows := make([][]string, 0, len(repos))
+	for _, repo := range repos {
+		marker := ""
+		if repo.Flagged {
+			marker = "*"
+		}
+		rows = append(rows, []string{
+			marker,
+			displayName(repo),
+			lastCommitDateText(repo),
+			strconv.Itoa(repo.DaysSinceLastCommit),
+			strconv.Itoa(repo.TotalContributors),
+			strconv.Itoa(repo.InactiveContributors),
+			fmt.Sprintf("%.1f", repo.InactivePercentage*100),
+			strconv.FormatBool(repo.Archived),
+			repo.License,
+			repo.FlagReason + priorityMarker(repo),
+		})
+	}
+
+	// Size each column to its widest cell
+	widths := make([]int, len(tableColumns))
+	for i, col := range tableColumns {
+		widths[i] = runewidth.StringWidth(col.title)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := runewidth.StringWidth(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	// Shrink the repository column so the table fits the available width
+	// Each column adds two spaces of padding and one border character
+	total := 1
+	for _, cw := range widths {
+		total += cw + 3
+	}
+	if total > width {
+		nameWidth := widths[1] - (total - width)
+		if nameWidth < minNameWidth {
+			nameWidth = minNameWidth
+		}
+		widths[1] = nameWidth
+	}
+
+	var b strings.Builder
+	writeBorder := func(left, middle, right string) {
+		b.WriteString(left)
+		for i, cw := range widths {
+			b.WriteString(strings.Repeat("─", cw+2))
+			if i < len(widths)-1 {
+				b.WriteString(middle)
+			}
+		}
+		b.WriteString(right + "\n")
+	}
+	writeRow := func(cells []string) {
+		b.WriteString("│")
+		for i, cell := range cells {
+			cell = truncate(cell, widths[i])
+			padding := strings.Repeat(" ", widths[i]-runewidth.StringWidth(cell))
+			if tableColumns[i].rightAlign {
+				b.WriteString(" " + padding + cell + " │")
+			} else {
+				b.WriteString(" " + cell + padding + " │")
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	headers := make([]string, len(tableColumns))
+	for i, col := range tableColumns {
+		headers[i] = col.title
+	}
+
+	writeBorder("┌", "┬", "┐")
+	writeRow(headers)
+	writeBorder("├", "┼", "┤")
+	for _, row := range rows {
+		writeRow(row)
+	}
+	writeBorder("└", "┴", "┘")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// truncate shortens a string to the given number of terminal cells, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	if runewidth.StringWidth(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return runewidth.Truncate(s, width, "")
+	}
+	return runewidth.Truncate(s, width, "…")
+}
+
+// IsTableFormat reports whether the output format is the ASCII table
+func IsTableFormat(format string) bool {
+	return format == "table" || format == "ascii-table"
+}
+
+// tableFormatter renders the ASCII table, sized to the terminal when printed there
+type tableFormatter struct{}
+
+// Format writes the table
+func (tableFormatter) Format(w io.Writer, repos []Repository, summary Summary) error {
+	width := defaultTableWidth
+	if summary.Terminal {
+		width = TerminalWidth()
+	}
+	if err := RenderTable(w, repos, width); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+	return nil
+}
+
+// WriteNotes prints the analysis summary below the table
+func (tableFormatter) WriteNotes(w io.Writer, repos []Repository, summary Summary) error {
+	if summary.Single {
+		return nil
+	}
+	writeSummary(w, repos, summary)
+	fmt.Fprintln(w, "Flagged repositories are marked with *")
+	return nil
+}
@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// tableLines renders repositories as a table of the given width and returns its lines
+func tableLines(t *testing.T, repos []Repository, width int) []string {
+	t.Helper()
+	var b strings.Builder
+	if err := RenderTable(&b, repos, width); err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+}
+
+func TestRenderTable(t *testing.T) {
+	repos := []Repository{
+		{Name: "o/active", LastCommitDate: testNow, TotalContributors: 12, License: "MIT"},
+		{Name: "o/a-repository-with-a-very-long-name", LastCommitDate: testNow.AddDate(-1, 0, 0), DaysSinceLastCommit: 365,
+			TotalContributors: 3, InactiveContributors: 2, InactivePercentage: 2.0 / 3, Flagged: true, FlagReason: FlagReasonOldInactiveContributors, License: LicenseNone},
+	}
+
+	tests := []struct {
+		name     string
+		width    int
+		want     []string
+		wantCuts bool
+	}{
+		{"wide", 200, []string{"│ * │ o/a-repository-with-a-very-long-name │", "│  365 │", "│       66.7 │", "│ MIT "}, false},
+		{"narrow", 140, []string{"│ * │ o/a-repository-with-a-… │"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := tableLines(t, repos, tt.width)
+			if len(lines) != 6 {
+				t.Fatalf("table has %d lines, want borders, a header, and 2 rows:\n%s", len(lines), strings.Join(lines, "\n"))
+			}
+			table := strings.Join(lines, "\n")
+			for _, want := range tt.want {
+				if !strings.Contains(table, want) {
+					t.Errorf("table does not contain %q:\n%s", want, table)
+				}
+			}
+			if cut := strings.Contains(table, "…"); cut != tt.wantCuts {
+				t.Errorf("repository names truncated = %v, want %v:\n%s", cut, tt.wantCuts, table)
+			}
+			// Every line has the same width, within the available width when the names can shrink enough
+			for _, line := range lines {
+				if n := runewidth.StringWidth(line); n != runewidth.StringWidth(lines[0]) || n > tt.width {
+					t.Errorf("line %q is %d cells wide, want %d within %d", line, n, runewidth.StringWidth(lines[0]), tt.width)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderTableMinimumNameWidth(t *testing.T) {
+	lines := tableLines(t, []Repository{{Name: "o/a-repository-with-a-very-long-name"}}, 40)
+	if want := "│   │ o/a-reposit… │"; !strings.Contains(lines[3], want) {
+		t.Errorf("row %q does not keep the repository column at %d cells (%q)", lines[3], minNameWidth, want)
+	}
+}
+
+func TestTerminalWidth(t *testing.T) {
+	// Tests do not run on a terminal, so the width comes from COLUMNS or the default
+	tests := []struct {
+		columns string
+		want    int
+	}{
+		{"80", 80},
+		{"", defaultTableWidth},
+		{"wide", defaultTableWidth},
+		{"-5", defaultTableWidth},
+	}
+	for _, tt := range tests {
+		t.Setenv("COLUMNS", tt.columns)
+		if got := TerminalWidth(); got != tt.want {
+			t.Errorf("TerminalWidth with COLUMNS=%q = %d, want %d", tt.columns, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"o/repo", 10, "o/repo"},
+		{"o/repository", 8, "o/repos…"},
+		{"o/界界界", 8, "o/界界界"},
+		{"o/界界界", 7, "o/界界…"},
+		{"o/界界界", 6, "o/界…"},
+		{"o/界界界", 5, "o/界…"},
+		{"o/📊📊", 4, "o/…"},
+		{"o/repository", 1, "o"},
+	}
+	for _, tt := range tests {
+		got := truncate(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		if n := runewidth.StringWidth(got); n > tt.width {
+			t.Errorf("truncate(%q, %d) is %d cells wide", tt.s, tt.width, n)
+		}
+	}
+}
+
+func TestRenderTableWideGlyphs(t *testing.T) {
+	// Names with double-width characters line up with the narrow ones, whole or truncated
+	repos := []Repository{
+		{Name: "o/plain"},
+		{Name: "o/界界界界界界界界界界"},
+		{Name: "o/📊-metrics"},
+	}
+	for _, width := range []int{200, 100} {
+		lines := tableLines(t, repos, width)
+		for _, line := range lines {
+			if n := runewidth.StringWidth(line); n != runewidth.StringWidth(lines[0]) {
+				t.Errorf("width %d: line %q is %d cells wide, want %d", width, line, n, runewidth.StringWidth(lines[0]))
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// tagInfo is the newest tag of a repository and the date of the commit it points to
+type tagInfo struct {
+	Name string
+	Date time.Time
+}
+
+// GetLastTag returns the first tag GitHub lists for a repository (the highest version for the usual
+// version tags) with its commit date, or nil when the repository has no tags
+// Tags count whether or not a GitHub Release was published for them
+func GetLastTag(repoFullName string) (*tagInfo, error) {
+	out, err := runGH("api",
+		fmt.Sprintf("repos/%s/tags?per_page=1", repoFullName),
+		"--jq", ".[0] | select(. != null) | .name + \" \" + .commit.sha")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	name, sha, ok := parseTagRef(out)
+	if !ok {
+		return nil, nil
+	}
+
+	date, err := runGHParsed(parseTagCommitDate, "api",
+		fmt.Sprintf("repos/%s/commits/%s", repoFullName, sha),
+		"--jq", ".commit.committer.date")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the commit of tag %s: %w", name, err)
+	}
+	return &tagInfo{Name: name, Date: date}, nil
+}
+
+// parseTagRef splits the "name sha" line printed for the newest tag
+// An empty response means the repository has no tags
+func parseTagRef(data []byte) (name, sha string, ok bool) {
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return "", "", false
+	}
+	return fields[0], fields[1], true
+}
+
+// parseTagCommitDate parses the committer date of a tagged commit
+func parseTagCommitDate(data []byte) (time.Time, error) {
+	date, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse tag commit date: %w", err)
+	}
+	return date, nil
+}
+
+// hasRecentTag reports whether the newest tag points to a commit made within maxAgeDays
+// Repositories without tags, or whose tags were not checked, have no recent tag
+func hasRecentTag(r Repository, maxAgeDays int) bool {
+	return r.LastTagDate != nil && Now().Sub(*r.LastTagDate) <= time.Duration(maxAgeDays)*24*time.Hour
+}
@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetLastTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     string
+		commit   string
+		wantName string
+		wantDate string
+		wantErr  string
+	}{
+		{"newest tag", "echo 'v1.4.0 abc123'", "echo 2025-04-01T10:00:00Z", "v1.4.0", "2025-04-01T10:00:00Z", ""},
+		{"no tags", "exit 0", "", "", "", ""},
+		{"tags unreadable", `echo 'gh: Server Error (HTTP 500)' >&2; exit 1`, "", "", "", "failed to get tags"},
+		{"tagged commit missing", "echo 'v1.4.0 abc123'", `echo 'gh: Not Found (HTTP 404)' >&2; exit 1`, "", "", "failed to get the commit of tag v1.4.0"},
+		{"malformed commit date", "echo 'v1.4.0 abc123'", "echo yesterday", "", "", "failed to parse tag commit date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, `case "$*" in
+*tags*) `+tt.tags+`;;
+*commits/abc123*) `+tt.commit+`;;
+*) echo "unexpected call: $*" >&2; exit 1;;
+esac`)
+			SetCacheTTL(0)
+
+			tag, err := GetLastTag("o/r")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("GetLastTag error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantName == "" {
+				if tag != nil {
+					t.Errorf("tag = %+v, want none", tag)
+				}
+				if calls := ghCalls(t, logPath); len(calls) != 1 {
+					t.Errorf("made %d calls, want only the tag list: %q", len(calls), calls)
+				}
+				return
+			}
+			if tag == nil || tag.Name != tt.wantName || tag.Date.Format(time.RFC3339) != tt.wantDate {
+				t.Errorf("tag = %+v, want %s at %s", tag, tt.wantName, tt.wantDate)
+			}
+		})
+	}
+}
+
+func TestHasRecentTag(t *testing.T) {
+	pinNow(t)
+	tests := []struct {
+		name    string
+		lastTag *time.Time
+		want    bool
+	}{
+		{"no tags", nil, false},
+		{"recent tag", testDaysAgo(30), true},
+		{"tag at the threshold", testDaysAgo(180), true},
+		{"old tag", testDaysAgo(181), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasRecentTag(Repository{LastTagDate: tt.lastTag}, 180); got != tt.want {
+				t.Errorf("hasRecentTag = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
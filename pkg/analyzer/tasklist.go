@@ -0,0 +1,187 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func init() {
+	RegisterFormatter("task-list", taskListFormatter{})
+}
+
+// trackingIssueMarker is hidden in the tracking issue body so later runs find and update the same issue
+const trackingIssueMarker = "<!-- inactivity-tracking-issue -->"
+
+// taskListFormatter renders the flagged repositories as a Markdown checklist to paste into an issue
+type taskListFormatter struct{}
+
+// Format writes the checklist
+func (taskListFormatter) Format(w io.Writer, repos []Repository, summary Summary) error {
+	_, err := w.Write(renderTaskList(repos))
+	return err
+}
+
+// renderTaskList builds one unchecked task list item per flagged repository
+func renderTaskList(repos []Repository) []byte {
+	var buf bytes.Buffer
+	for _, repo := range repos {
+		if repo.Flagged {
+			buf.WriteString(taskListItem(repo, false))
+		}
+	}
+	return buf.Bytes()
+}
+
+// taskListItem renders a flagged repository as a task list item
+func taskListItem(repo Repository, checked bool) string {
+	box := "[ ]"
+	if checked {
+		box = "[x]"
+	}
+	return fmt.Sprintf("- %s %s — %d days stale (%s)\n",
+		box, markdownEscape(displayName(repo)), repo.DaysSinceLastCommit, markdownEscape(repo.FlagReason))
+}
+
+// checkedTaskNames returns the repositories whose task list items are checked in an issue body
+func checkedTaskNames(body string) map[string]bool {
+	checked := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		rest, ok := strings.CutPrefix(line, "- [x] ")
+		if !ok {
+			rest, ok = strings.CutPrefix(line, "- [X] ")
+		}
+		if !ok {
+			continue
+		}
+		if name, _, found := strings.Cut(rest, " — "); found {
+			checked[name] = true
+		}
+	}
+	return checked
+}
+
+// trackingIssueBody builds the tracking issue body, keeping the items already checked in the previous body
+// so that progress recorded in the issue survives an update
+func trackingIssueBody(repos []Repository, previous string, cfg config.Config) string {
+	checked := checkedTaskNames(previous)
+
+	var buf bytes.Buffer
+	buf.WriteString(trackingIssueMarker + "\n")
+	buf.WriteString(fmt.Sprintf("Repositories flagged as inactive (last commit older than %d days, %.0f%% inactive contributors). "+
+		"Check off each one once it has been archived, transferred, or confirmed as still maintained.\n\n",
+		cfg.MaxCommitAgeInDays, cfg.InactiveContribThreshold*100))
+	for _, repo := range repos {
+		if repo.Flagged {
+			buf.WriteString(taskListItem(repo, checked[markdownEscape(displayName(repo))]))
+		}
+	}
+	return buf.String()
+}
+
+// trackingIssueTitle names the tracking issue, per organization or parent repository when one was scanned
+func trackingIssueTitle(cfg config.Config) string {
+	if subject := reportSubject(cfg); subject != "" {
+		return fmt.Sprintf("Inactive repository cleanup for %s", subject)
+	}
+	return "Inactive repository cleanup"
+}
+
+// trackingIssue is an open issue of the tracking repository
+type trackingIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+}
+
+// findTrackingIssue returns the open issue a previous run filed with the same title, or nil when there is none
+// Issues are recognized by the hidden marker, so issues people opened with a similar title are left alone
+func findTrackingIssue(issues []trackingIssue, title string) *trackingIssue {
+	for i := range issues {
+		if issues[i].Title == title && strings.Contains(issues[i].Body, trackingIssueMarker) {
+			return &issues[i]
+		}
+	}
+	return nil
+}
+
+// parseTrackingIssues decodes the open issues printed one JSON object per line
+func parseTrackingIssues(data []byte) ([]trackingIssue, error) {
+	var issues []trackingIssue
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var issue trackingIssue
+		if err := json.Unmarshal(line, &issue); err != nil {
+			return nil, fmt.Errorf("failed to parse issues: %w", err)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// FileTrackingIssue files the flagged repositories as a checklist in an issue of the tracking repository,
+// updating the issue a previous run filed instead of opening another one
+// With dry run enabled, the issue is only logged.
+func FileTrackingIssue(repos []Repository, cfg config.Config) error {
+	target := cfg.TrackingIssueRepo
+	title := trackingIssueTitle(cfg)
+
+	// The issue list is read live, as a cached one would miss the issue filed by a recent run
+	out, err := runGH("api",
+		fmt.Sprintf("repos/%s/issues?state=open&per_page=100", target),
+		"--paginate", "--jq", ".[] | select(.pull_request == null) | {number, title, body}",
+		"--cache", "0s")
+	if err != nil {
+		return fmt.Errorf("failed to list issues of %s: %w", target, err)
+	}
+	issues, err := parseTrackingIssues(out)
+	if err != nil {
+		return err
+	}
+
+	existing := findTrackingIssue(issues, title)
+	previous := ""
+	if existing != nil {
+		previous = existing.Body
+	}
+	body := trackingIssueBody(repos, previous, cfg)
+
+	if cfg.DryRun {
+		if existing != nil {
+			Logf("🧪 Dry run: would update tracking issue %s#%d\n", target, existing.Number)
+		} else {
+			Logf("🧪 Dry run: would open tracking issue %q in %s\n", title, target)
+		}
+		return nil
+	}
+
+	if existing != nil {
+		_, err = runGH("api", "--method", "PATCH",
+			fmt.Sprintf("repos/%s/issues/%d", target, existing.Number),
+			"-f", "body="+body)
+		if err != nil {
+			return fmt.Errorf("failed to update tracking issue %s#%d: %w", target, existing.Number, err)
+		}
+		Logf("📋 Updated tracking issue %s#%d\n", target, existing.Number)
+		return nil
+	}
+
+	_, err = runGH("api", "--method", "POST",
+		fmt.Sprintf("repos/%s/issues", target),
+		"-f", "title="+title,
+		"-f", "body="+body)
+	if err != nil {
+		return fmt.Errorf("failed to open tracking issue in %s: %w", target, err)
+	}
+	Logf("📋 Opened tracking issue %q in %s\n", title, target)
+	return nil
+}
@@ -0,0 +1,144 @@
+package analyzer
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// taskListRepositories are the repositories the task list tests render
+var taskListRepositories = []Repository{
+	{Name: "acme/old", DaysSinceLastCommit: 400, Flagged: true, FlagReason: FlagReasonOldInactiveContributors},
+	{Name: "acme/api", DaysSinceLastCommit: 3},
+	{Name: "acme/web", Branch: "v1", DaysSinceLastCommit: 200, Flagged: true, FlagReason: FlagReasonArchived},
+}
+
+func TestRenderTaskList(t *testing.T) {
+	want := "- [ ] acme/old — 400 days stale (" + FlagReasonOldInactiveContributors + ")\n" +
+		"- [ ] acme/web@v1 — 200 days stale (" + FlagReasonArchived + ")\n"
+	if got := string(renderTaskList(taskListRepositories)); got != want {
+		t.Errorf("renderTaskList =\n%s\nwant\n%s", got, want)
+	}
+	if got := renderTaskList(taskListRepositories[1:2]); len(got) != 0 {
+		t.Errorf("renderTaskList without flagged repositories = %q, want nothing", got)
+	}
+}
+
+func TestCheckedTaskNames(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want map[string]bool
+	}{
+		{"empty", "", map[string]bool{}},
+		{"checked either case", "- [x] acme/old — 400 days stale (x)\n  - [X] acme/web@v1 — 1 days stale (y)\n", map[string]bool{"acme/old": true, "acme/web@v1": true}},
+		{"unchecked and other lines", "intro\n- [ ] acme/api — 3 days stale (z)\n- [x] no separator\n", map[string]bool{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkedTaskNames(tt.body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkedTaskNames = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrackingIssueBody(t *testing.T) {
+	cfg := config.Config{Organization: "acme", MaxCommitAgeInDays: 180, InactiveContribThreshold: 0.5}
+	previous := trackingIssueMarker + "\n- [x] acme/old — 380 days stale (old reason)\n- [x] acme/gone — 900 days stale (x)\n"
+
+	body := trackingIssueBody(taskListRepositories, previous, cfg)
+	for _, want := range []string{
+		trackingIssueMarker + "\n",
+		"older than 180 days, 50% inactive contributors",
+		"- [x] acme/old — 400 days stale",
+		"- [ ] acme/web@v1 — 200 days stale",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body lacks %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "acme/gone") || strings.Contains(body, "acme/api") {
+		t.Errorf("body lists a repository that is not flagged:\n%s", body)
+	}
+}
+
+func TestFindTrackingIssue(t *testing.T) {
+	issues, err := parseTrackingIssues([]byte(
+		`{"number":1,"title":"Inactive repository cleanup for acme","body":"opened by hand"}` + "\n\n" +
+			`{"number":2,"title":"Inactive repository cleanup for acme","body":"` + trackingIssueMarker + `"}` + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		title string
+		want  int
+	}{
+		{"Inactive repository cleanup for acme", 2},
+		{"Inactive repository cleanup for beta", 0},
+	}
+	for _, tt := range tests {
+		got := 0
+		if issue := findTrackingIssue(issues, tt.title); issue != nil {
+			got = issue.Number
+		}
+		if got != tt.want {
+			t.Errorf("findTrackingIssue(%q) = #%d, want #%d", tt.title, got, tt.want)
+		}
+	}
+
+	if _, err := parseTrackingIssues([]byte("not json\n")); err == nil {
+		t.Error("parseTrackingIssues succeeded on malformed output")
+	}
+}
+
+func TestFileTrackingIssue(t *testing.T) {
+	const existing = `{"number":7,"title":"Inactive repository cleanup for acme","body":"` + trackingIssueMarker +
+		`\n- [x] acme/old — 380 days stale (x)"}`
+
+	tests := []struct {
+		name     string
+		issues   string
+		dryRun   bool
+		wantCall string
+		wantBody string
+	}{
+		{"opened", "", false, "api --method POST repos/acme/tracker/issues -f title=Inactive repository cleanup for acme", "- [ ] acme/old"},
+		{"updated", existing, false, "api --method PATCH repos/acme/tracker/issues/7", "- [x] acme/old"},
+		{"dry run", existing, true, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeGH(t, `case "$*" in *"issues?state=open"*) printf '%s\n' '`+tt.issues+`';; esac`)
+			SetCacheTTL(0)
+
+			cfg := config.Config{Organization: "acme", TrackingIssueRepo: "acme/tracker", MaxCommitAgeInDays: 180, DryRun: tt.dryRun, Silent: true}
+			if err := FileTrackingIssue(taskListRepositories, cfg); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(logPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			log := string(data)
+			if !strings.HasPrefix(log, "api repos/acme/tracker/issues?state=open&per_page=100 --paginate") || !strings.Contains(log, "--cache 0s") {
+				t.Errorf("issues not listed live first:\n%s", log)
+			}
+			calls := strings.Count(log, "\napi ")
+			if tt.wantCall == "" {
+				if calls != 0 {
+					t.Errorf("dry run wrote to the tracking repository:\n%s", log)
+				}
+				return
+			}
+			if calls != 1 || !strings.Contains(log, tt.wantCall) || !strings.Contains(log, tt.wantBody) {
+				t.Errorf("calls:\n%s\nwant one %q with %q", log, tt.wantCall, tt.wantBody)
+			}
+		})
+	}
+}
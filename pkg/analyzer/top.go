@@ -0,0 +1,36 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MostInactive returns the n most inactive repositories, ordered by days since the last commit
+// and then by inactive contributor share; n of 0 or less keeps every repository in its original order
+func MostInactive(repos []Repository, n int) []Repository {
+	if n <= 0 {
+		return repos
+	}
+
+	sorted := make([]Repository, len(repos))
+	copy(sorted, repos)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].DaysSinceLastCommit != sorted[j].DaysSinceLastCommit {
+			return sorted[i].DaysSinceLastCommit > sorted[j].DaysSinceLastCommit
+		}
+		return sorted[i].InactivePercentage > sorted[j].InactivePercentage
+	})
+
+	if n < len(sorted) {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// topNote describes a report limited with -top, or returns an empty string for a full report
+func topNote(repos []Repository, summary Summary) string {
+	if summary.Config.Top <= 0 || len(repos) >= summary.Total {
+		return ""
+	}
+	return fmt.Sprintf("Showing the %d most inactive of %d repositories", len(repos), summary.Total)
+}
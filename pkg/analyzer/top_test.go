@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestMostInactive(t *testing.T) {
+	repos := []Repository{
+		{Name: "o/a", DaysSinceLastCommit: 10},
+		{Name: "o/b", DaysSinceLastCommit: 400, InactivePercentage: 0.5},
+		{Name: "o/c", DaysSinceLastCommit: 400, InactivePercentage: 1},
+		{Name: "o/d", DaysSinceLastCommit: 90},
+		{Name: "o/e", DaysSinceLastCommit: 400, InactivePercentage: 0.5},
+	}
+	names := func(repos []Repository) []string {
+		var names []string
+		for _, repo := range repos {
+			names = append(names, repo.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name string
+		n    int
+		want []string
+	}{
+		{"all kept in order", 0, []string{"o/a", "o/b", "o/c", "o/d", "o/e"}},
+		{"negative keeps all", -1, []string{"o/a", "o/b", "o/c", "o/d", "o/e"}},
+		{"ties by inactive share, then listed order", 3, []string{"o/c", "o/b", "o/e"}},
+		{"more than listed", 10, []string{"o/c", "o/b", "o/e", "o/d", "o/a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(MostInactive(repos, tt.n)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MostInactive(%d) = %q, want %q", tt.n, got, tt.want)
+			}
+		})
+	}
+	if repos[0].Name != "o/a" {
+		t.Error("MostInactive reordered the given repositories")
+	}
+}
+
+func TestTopNote(t *testing.T) {
+	repos := make([]Repository, 3)
+	tests := []struct {
+		name  string
+		top   int
+		total int
+		want  string
+	}{
+		{"no limit", 0, 10, ""},
+		{"limited", 3, 10, "Showing the 3 most inactive of 10 repositories"},
+		{"limit covers all", 5, 3, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := Summary{Config: config.Config{Top: tt.top}, Total: tt.total}
+			if got := topNote(repos, summary); got != tt.want {
+				t.Errorf("topNote = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
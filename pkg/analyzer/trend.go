@@ -0,0 +1,236 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// sparkBlocks are the bar heights of a sparkline, from lowest to highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// TrendPoint is the flagged count of one stored report
+type TrendPoint struct {
+	AnalyzedAt time.Time `json:"analyzedAt"`
+	Total      int       `json:"total"`
+	Flagged    int       `json:"flagged"`
+}
+
+// FlaggedSince tells since which report a repository flagged in the latest report has been flagged without a break
+type FlaggedSince struct {
+	Repository string    `json:"repository"`
+	Since      time.Time `json:"since"`
+	// FirstReport is set when the repository was already flagged in the earliest report, so it may have been flagged before
+	FirstReport bool `json:"firstReport,omitempty"`
+}
+
+// Trend is the flagged count of an organization over its stored reports, oldest first
+type Trend struct {
+	Organization string         `json:"organization"`
+	Points       []TrendPoint   `json:"points"`
+	FlaggedSince []FlaggedSince `json:"flaggedSince"`
+	Warnings     []Warning      `json:"-"` // Stored files skipped as not being multi-repository reports
+}
+
+// trendReport is the part of a stored multi-repository JSON report the trend is built from
+type trendReport struct {
+	Organization  string    `json:"organization"`
+	AnalyzedAt    time.Time `json:"analyzedAt"`
+	TotalAnalyzed int       `json:"totalAnalyzed"`
+	Repositories  []struct {
+		Name    string `json:"name"`
+		Flagged bool   `json:"flagged"`
+	} `json:"repositories"`
+}
+
+// LoadTrend reads the JSON reports stored in a directory and builds the trend of their flagged count
+// Files that are not multi-repository JSON reports, such as single-repository ones, are skipped with a warning.
+func LoadTrend(dir string, cfg config.Config) (Trend, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return Trend{}, fmt.Errorf("failed to list reports: %w", err)
+	}
+
+	warnings := &warningLog{}
+	var reports []trendReport
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Trend{}, fmt.Errorf("failed to read report: %w", err)
+		}
+		var report trendReport
+		if err := json.Unmarshal(data, &report); err != nil || report.AnalyzedAt.IsZero() {
+			warnings.warn(cfg, "", "Skipping %s, which is not a JSON report of an org, file, or multi-repository run", path)
+			continue
+		}
+		reports = append(reports, report)
+	}
+	if len(reports) == 0 {
+		return Trend{}, fmt.Errorf("no JSON reports found in %s", dir)
+	}
+
+	trend, err := buildTrend(reports)
+	if err != nil {
+		return Trend{}, err
+	}
+	trend.Warnings = warnings.list()
+	return trend, nil
+}
+
+// buildTrend orders the reports by analysis date and derives the flagged count series and,
+// for each repository flagged in the latest report, since when it has been flagged
+func buildTrend(reports []trendReport) (Trend, error) {
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].AnalyzedAt.Before(reports[j].AnalyzedAt)
+	})
+
+	trend := Trend{Organization: reports[0].Organization}
+	for _, report := range reports {
+		if report.Organization != trend.Organization {
+			return Trend{}, fmt.Errorf("reports cover several organizations (%s and %s), keep one organization per directory",
+				trend.Organization, report.Organization)
+		}
+
+		point := TrendPoint{AnalyzedAt: report.AnalyzedAt, Total: report.TotalAnalyzed}
+		for _, repo := range report.Repositories {
+			if repo.Flagged {
+				point.Flagged++
+			}
+		}
+		trend.Points = append(trend.Points, point)
+	}
+
+	// Walk back from the latest report while each repository stays flagged
+	latest := len(reports) - 1
+	for _, repo := range reports[latest].Repositories {
+		if !repo.Flagged {
+			continue
+		}
+		since := latest
+		for since > 0 && flaggedIn(reports[since-1], repo.Name) {
+			since--
+		}
+		trend.FlaggedSince = append(trend.FlaggedSince, FlaggedSince{
+			Repository:  repo.Name,
+			Since:       reports[since].AnalyzedAt,
+			FirstReport: since == 0,
+		})
+	}
+	sort.SliceStable(trend.FlaggedSince, func(i, j int) bool {
+		return trend.FlaggedSince[i].Since.Before(trend.FlaggedSince[j].Since)
+	})
+	return trend, nil
+}
+
+// flaggedIn reports whether a report lists a repository as flagged
+func flaggedIn(report trendReport, name string) bool {
+	for _, repo := range report.Repositories {
+		if repo.Name == name {
+			return repo.Flagged
+		}
+	}
+	return false
+}
+
+// sparkline renders values as bars scaled from zero to the largest value
+func sparkline(values []int) string {
+	highest := 0
+	for _, v := range values {
+		highest = max(highest, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if highest > 0 {
+			level = v * (len(sparkBlocks) - 1) / highest
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// IsTrendFormat reports whether the trend command supports an output format
+func IsTrendFormat(format string) bool {
+	return format == "console" || format == "csv" || format == "json"
+}
+
+// renderTrendText renders the trend for human-readable output
+func renderTrendText(trend Trend) []byte {
+	var buf bytes.Buffer
+	first, last := trend.Points[0], trend.Points[len(trend.Points)-1]
+	buf.WriteString(fmt.Sprintf("\n📈 Flagged Repository Trend for %s\n", trend.Organization))
+	buf.WriteString(fmt.Sprintf("Reports: %d, from %s to %s\n", len(trend.Points),
+		first.AnalyzedAt.Format("2006-01-02"), last.AnalyzedAt.Format("2006-01-02")))
+
+	counts := make([]int, len(trend.Points))
+	lowest, highest := first.Flagged, first.Flagged
+	for i, p := range trend.Points {
+		counts[i] = p.Flagged
+		lowest, highest = min(lowest, p.Flagged), max(highest, p.Flagged)
+	}
+	buf.WriteString(fmt.Sprintf("🚩 Flagged: %s  %d → %d (min %d, max %d)\n\n", sparkline(counts), first.Flagged, last.Flagged, lowest, highest))
+
+	buf.WriteString("Date        Total  Flagged\n")
+	for _, p := range trend.Points {
+		buf.WriteString(fmt.Sprintf("%-10s  %5d  %7d\n", p.AnalyzedAt.Format("2006-01-02"), p.Total, p.Flagged))
+	}
+
+	if len(trend.FlaggedSince) > 0 {
+		buf.WriteString("\nFlagged since:\n")
+		for _, f := range trend.FlaggedSince {
+			note := ""
+			if f.FirstReport {
+				note = " (first report)"
+			}
+			buf.WriteString(fmt.Sprintf("  - %s: %s%s\n", f.Repository, f.Since.Format("2006-01-02"), note))
+		}
+	}
+	return buf.Bytes()
+}
+
+// renderTrendCSV renders the flagged count series as CSV, one row per report
+func renderTrendCSV(trend Trend) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("Date,Total Repositories,Flagged Repositories\n")
+	for _, p := range trend.Points {
+		buf.WriteString(fmt.Sprintf("%s,%d,%d\n", p.AnalyzedAt.Format(time.RFC3339), p.Total, p.Flagged))
+	}
+	return buf.Bytes()
+}
+
+// OutputTrend outputs the trend as console text, CSV, or JSON
+func OutputTrend(trend Trend, cfg config.Config) error {
+	var data []byte
+	switch cfg.OutputFormat {
+	case "json":
+		var err error
+		data, err = json.MarshalIndent(trend, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		data = append(data, '\n')
+	case "csv":
+		data = renderTrendCSV(trend)
+	default:
+		data = renderTrendText(trend)
+	}
+
+	if cfg.OutputFile != "" {
+		if err := writeOutputFile(cfg, data); err != nil {
+			return err
+		}
+		Logf("💾 Results saved to %s\n", cfg.OutputFile)
+		return nil
+	}
+
+	fmt.Print(string(data))
+	return nil
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// writeTrendReport stores a JSON report analyzed the given number of days before testNow,
+// flagging the listed repositories
+func writeTrendReport(t *testing.T, dir, org string, daysAgo int, flagged ...string) {
+	t.Helper()
+	var repos []string
+	for _, name := range flagged {
+		repos = append(repos, fmt.Sprintf(`{"name":%q,"flagged":true}`, name))
+	}
+	repos = append(repos, `{"name":"o/active","flagged":false}`)
+	data := fmt.Sprintf(`{"organization":%q,"analyzedAt":%q,"totalAnalyzed":%d,"repositories":[%s]}`,
+		org, testDaysAgo(daysAgo).Format("2006-01-02T15:04:05Z"), len(repos), strings.Join(repos, ","))
+	path := filepath.Join(dir, fmt.Sprintf("report-%d.json", daysAgo))
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadTrend(t *testing.T) {
+	dir := t.TempDir()
+	// Written out of order, as file names need not sort by date
+	writeTrendReport(t, dir, "o", 10, "o/a", "o/b")
+	writeTrendReport(t, dir, "o", 30, "o/a")
+	writeTrendReport(t, dir, "o", 0, "o/a", "o/b", "o/c")
+	writeTrendReport(t, dir, "o", 20, "o/a", "o/c")
+	if err := os.WriteFile(filepath.Join(dir, "single.json"), []byte(`{"name":"o/a"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	trend, err := LoadTrend(dir, config.Config{Silent: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var flagged []int
+	for _, p := range trend.Points {
+		flagged = append(flagged, p.Flagged)
+	}
+	if want := []int{1, 2, 2, 3}; !reflect.DeepEqual(flagged, want) || trend.Organization != "o" {
+		t.Errorf("flagged counts %v of %s, want %v oldest first", flagged, trend.Organization, want)
+	}
+
+	want := []FlaggedSince{
+		{Repository: "o/a", Since: *testDaysAgo(30), FirstReport: true},
+		{Repository: "o/b", Since: *testDaysAgo(10)},
+		{Repository: "o/c", Since: *testDaysAgo(0)},
+	}
+	if !reflect.DeepEqual(trend.FlaggedSince, want) {
+		t.Errorf("flagged since = %+v, want %+v", trend.FlaggedSince, want)
+	}
+	if len(trend.Warnings) != 1 {
+		t.Errorf("warnings = %v, want one for the single-repository report", trend.Warnings)
+	}
+}
+
+func TestLoadTrendInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		orgs    []string
+		wantErr string
+	}{
+		{"no reports", nil, "no JSON reports found"},
+		{"several organizations", []string{"o", "p"}, "reports cover several organizations (o and p)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for i, org := range tt.orgs {
+				writeTrendReport(t, dir, org, 10-i)
+			}
+			if _, err := LoadTrend(dir, config.Config{Silent: true}); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadTrend error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []int
+		want   string
+	}{
+		{nil, ""},
+		{[]int{0, 0}, "▁▁"},
+		{[]int{0, 7, 14}, "▁▄█"},
+		{[]int{5, 5}, "██"},
+	}
+	for _, tt := range tests {
+		if got := sparkline(tt.values); got != tt.want {
+			t.Errorf("sparkline(%v) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
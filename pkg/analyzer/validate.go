@@ -0,0 +1,75 @@
+package analyzer
+
+import "fmt"
+
+// Validate corrects the metrics of an analyzed repository that cannot be right, such as more inactive
+// contributors than contributors, and returns a note for each correction so it can be reported
+// A last commit dated in the future, from clock skew or a forged committer date, counts as made today.
+func (r *Repository) Validate() []string {
+	var notes []string
+
+	if r.DaysSinceLastCommit < 0 {
+		notes = append(notes, fmt.Sprintf("last commit is dated %s in the future (%s), treated as 0 days old",
+			plural(-r.DaysSinceLastCommit, "1 day", "days"), r.LastCommitDate.Format("2006-01-02")))
+		r.DaysSinceLastCommit = 0
+	}
+	if r.DaysSinceSubstantiveCommit < 0 {
+		notes = append(notes, fmt.Sprintf("last substantive commit is dated %s in the future, treated as 0 days old",
+			plural(-r.DaysSinceSubstantiveCommit, "1 day", "days")))
+		r.DaysSinceSubstantiveCommit = 0
+	}
+	if r.RepoAgeDays < 0 {
+		notes = append(notes, fmt.Sprintf("creation date is %s in the future, treated as 0 days old", plural(-r.RepoAgeDays, "1 day", "days")))
+		r.RepoAgeDays = 0
+	}
+
+	if r.TotalContributors < 0 {
+		notes = append(notes, fmt.Sprintf("negative contributor count %d, treated as 0", r.TotalContributors))
+		r.TotalContributors = 0
+	}
+	if r.InactiveContributors < 0 {
+		notes = append(notes, fmt.Sprintf("negative inactive contributor count %d, treated as 0", r.InactiveContributors))
+		r.InactiveContributors = 0
+	}
+	if r.InactiveContributors > r.TotalContributors {
+		notes = append(notes, fmt.Sprintf("%d inactive contributors out of %d, capped at %d",
+			r.InactiveContributors, r.TotalContributors, r.TotalContributors))
+		r.InactiveContributors = r.TotalContributors
+	}
+	if r.SuspendedContributors > r.InactiveContributors {
+		notes = append(notes, fmt.Sprintf("%d suspended contributors out of %d inactive, capped at %d",
+			r.SuspendedContributors, r.InactiveContributors, r.InactiveContributors))
+		r.SuspendedContributors = r.InactiveContributors
+	}
+
+	// The percentage follows from the counts, so it is recomputed rather than clamped on its own
+	percentage := 0.0
+	if r.TotalContributors > 0 {
+		percentage = float64(r.InactiveContributors) / float64(r.TotalContributors)
+	}
+	if r.InactivePercentage != percentage {
+		notes = append(notes, fmt.Sprintf("inactive percentage %.1f%% does not match %d of %d contributors, corrected to %.1f%%",
+			r.InactivePercentage*100, r.InactiveContributors, r.TotalContributors, percentage*100))
+		r.InactivePercentage = percentage
+	}
+
+	if r.WeightedInactivePercentage != nil && (*r.WeightedInactivePercentage < 0 || *r.WeightedInactivePercentage > 1) {
+		ratio := min(max(*r.WeightedInactivePercentage, 0), 1)
+		notes = append(notes, fmt.Sprintf("weighted inactive percentage %.1f%% is out of range, clamped to %.0f%%",
+			*r.WeightedInactivePercentage*100, ratio*100))
+		r.WeightedInactivePercentage = &ratio
+	}
+
+	if r.UnansweredIssues > r.IssuesSampled {
+		notes = append(notes, fmt.Sprintf("%d unanswered issues out of %d sampled, capped at %d",
+			r.UnansweredIssues, r.IssuesSampled, r.IssuesSampled))
+		r.UnansweredIssues = r.IssuesSampled
+	}
+	if r.SignedCommitRatio != nil && (*r.SignedCommitRatio < 0 || *r.SignedCommitRatio > 1) {
+		ratio := min(max(*r.SignedCommitRatio, 0), 1)
+		notes = append(notes, fmt.Sprintf("signed commit ratio %.2f is out of range, clamped to %.0f", *r.SignedCommitRatio, ratio))
+		r.SignedCommitRatio = &ratio
+	}
+
+	return notes
+}
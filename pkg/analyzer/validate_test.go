@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRepositoryValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		repo      Repository
+		want      Repository
+		wantNotes []string
+	}{
+		{
+			name: "consistent",
+			repo: Repository{TotalContributors: 4, InactiveContributors: 1, InactivePercentage: 0.25, DaysSinceLastCommit: 10},
+			want: Repository{TotalContributors: 4, InactiveContributors: 1, InactivePercentage: 0.25, DaysSinceLastCommit: 10},
+		},
+		{
+			name:      "future commit",
+			repo:      Repository{DaysSinceLastCommit: -3, LastCommitDate: time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC), DaysSinceSubstantiveCommit: -1},
+			want:      Repository{DaysSinceLastCommit: 0, LastCommitDate: time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)},
+			wantNotes: []string{"last commit is dated 3 days in the future (2025-06-04)", "last substantive commit is dated 1 day in the future"},
+		},
+		{
+			name:      "future creation",
+			repo:      Repository{RepoAgeDays: -2},
+			want:      Repository{},
+			wantNotes: []string{"creation date is 2 days in the future"},
+		},
+		{
+			name:      "negative counts",
+			repo:      Repository{TotalContributors: -1, InactiveContributors: -2},
+			want:      Repository{},
+			wantNotes: []string{"negative contributor count -1", "negative inactive contributor count -2"},
+		},
+		{
+			name:      "more inactive than contributors",
+			repo:      Repository{TotalContributors: 2, InactiveContributors: 5, SuspendedContributors: 6, InactivePercentage: 2.5},
+			want:      Repository{TotalContributors: 2, InactiveContributors: 2, SuspendedContributors: 2, InactivePercentage: 1},
+			wantNotes: []string{"5 inactive contributors out of 2, capped at 2", "6 suspended contributors out of 2 inactive", "inactive percentage 250.0% does not match 2 of 2 contributors, corrected to 100.0%"},
+		},
+		{
+			name:      "stale percentage",
+			repo:      Repository{TotalContributors: 4, InactiveContributors: 2, InactivePercentage: 0.75},
+			want:      Repository{TotalContributors: 4, InactiveContributors: 2, InactivePercentage: 0.5},
+			wantNotes: []string{"inactive percentage 75.0% does not match 2 of 4 contributors, corrected to 50.0%"},
+		},
+		{
+			name:      "ratios out of range",
+			repo:      Repository{WeightedInactivePercentage: floatPtr(1.2), SignedCommitRatio: floatPtr(-0.5)},
+			want:      Repository{WeightedInactivePercentage: floatPtr(1), SignedCommitRatio: floatPtr(0)},
+			wantNotes: []string{"weighted inactive percentage 120.0% is out of range, clamped to 100%", "signed commit ratio -0.50 is out of range, clamped to 0"},
+		},
+		{
+			name:      "unanswered issues",
+			repo:      Repository{IssuesSampled: 3, UnansweredIssues: 4},
+			want:      Repository{IssuesSampled: 3, UnansweredIssues: 3},
+			wantNotes: []string{"4 unanswered issues out of 3 sampled, capped at 3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := tt.repo
+			notes := repo.Validate()
+			if !reflect.DeepEqual(repo, tt.want) {
+				t.Errorf("validated repository = %+v, want %+v", repo, tt.want)
+			}
+			if len(notes) != len(tt.wantNotes) {
+				t.Fatalf("notes = %q, want %d of them", notes, len(tt.wantNotes))
+			}
+			for i, want := range tt.wantNotes {
+				if !strings.Contains(notes[i], want) {
+					t.Errorf("note %d = %q, want %q", i+1, notes[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// Warning severities
+const (
+	// SeverityWarning is a non-fatal issue; the affected data may be incomplete
+	SeverityWarning = "warning"
+
+	// SeverityError is a repository whose analysis failed and was skipped
+	SeverityError = "error"
+)
+
+// Warning is a non-fatal issue met during a run, kept so that callers can inspect it after the run
+type Warning struct {
+	Repository string `json:"repository,omitempty"`
+	Message    string `json:"message"`
+	Severity   string `json:"severity"`
+}
+
+// warningLog collects the warnings of one analysis; it is safe for concurrent use, and a nil log keeps none
+type warningLog struct {
+	mu       sync.Mutex
+	warnings []Warning
+}
+
+// record adds warnings to the log
+func (l *warningLog) record(warnings ...Warning) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	l.warnings = append(l.warnings, warnings...)
+	l.mu.Unlock()
+}
+
+// list returns the warnings recorded so far, in the order they occurred
+func (l *warningLog) list() []Warning {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Warning(nil), l.warnings...)
+}
+
+// warn records a warning about a repository (empty for the run as a whole) and logs it unless silent
+func (l *warningLog) warn(cfg config.Config, repository, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	l.record(Warning{Repository: repository, Message: message, Severity: SeverityWarning})
+	if !cfg.Silent {
+		Logf("⚠️ Warning: %s\n", message)
+	}
+}
+
+// writeWarnings writes the warnings section of the human-readable outputs, if there were any
+func writeWarnings(w io.Writer, warnings []Warning) {
+	if len(warnings) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "⚠️ Warnings:")
+	fmt.Fprintln(w, "---------------------")
+	for _, warning := range warnings {
+		fmt.Fprintf(w, "- %s\n", warningSummary(warning))
+	}
+	fmt.Fprintln(w)
+}
+
+// renderWarningsSection renders the warnings section of the plain text report
+func renderWarningsSection(warnings []Warning) []byte {
+	var buf bytes.Buffer
+	writeWarnings(&buf, warnings)
+	return buf.Bytes()
+}
+
+// warningSummary renders a warning on one line, e.g. "[error] org/repo: failed to get metadata"
+func warningSummary(w Warning) string {
+	if w.Repository == "" {
+		return fmt.Sprintf("[%s] %s", w.Severity, w.Message)
+	}
+	return fmt.Sprintf("[%s] %s: %s", w.Severity, w.Repository, w.Message)
+}
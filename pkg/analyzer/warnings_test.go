@@ -0,0 +1,102 @@
+package analyzer
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestWarn(t *testing.T) {
+	tests := []struct {
+		name       string
+		silent     bool
+		repository string
+		want       Warning
+		wantLog    string
+	}{
+		{"logged", false, "o/r", Warning{Repository: "o/r", Message: "failed to check archived status", Severity: SeverityWarning},
+			"⚠️ Warning: failed to check archived status\n"},
+		{"captured when silent", true, "o/r", Warning{Repository: "o/r", Message: "failed to check archived status", Severity: SeverityWarning}, ""},
+		{"whole run", false, "", Warning{Message: "failed to check archived status", Severity: SeverityWarning},
+			"⚠️ Warning: failed to check archived status\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureLog(t)
+			warnings := &warningLog{}
+
+			warnings.warn(config.Config{Silent: tt.silent}, tt.repository, "failed to check %s status", "archived")
+			if got := warnings.list(); !reflect.DeepEqual(got, []Warning{tt.want}) {
+				t.Errorf("recorded %+v, want %+v", got, []Warning{tt.want})
+			}
+			if logs.String() != tt.wantLog {
+				t.Errorf("log = %q, want %q", logs.String(), tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestWarningLogConcurrent(t *testing.T) {
+	log := &warningLog{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log.record(Warning{Message: "m", Severity: SeverityError})
+		}()
+	}
+	wg.Wait()
+
+	warnings := log.list()
+	if len(warnings) != 50 {
+		t.Fatalf("recorded %d warnings, want 50", len(warnings))
+	}
+	// The returned slice is a copy
+	warnings[0].Message = "changed"
+	if log.list()[0].Message != "m" {
+		t.Error("changing the returned warnings changed the recorded ones")
+	}
+
+	// A nil log keeps nothing
+	var none *warningLog
+	none.record(Warning{Message: "m"})
+	if got := none.list(); got != nil {
+		t.Errorf("nil log returned %+v, want none", got)
+	}
+}
+
+func TestWriteWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		warnings []Warning
+		want     string
+	}{
+		{"none", nil, ""},
+		{
+			name: "repository and run warnings",
+			warnings: []Warning{
+				{Repository: "o/r", Message: "failed to get metadata", Severity: SeverityError},
+				{Message: "quota unavailable", Severity: SeverityWarning},
+			},
+			want: "⚠️ Warnings:\n---------------------\n- [error] o/r: failed to get metadata\n- [warning] quota unavailable\n\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeWarnings(&buf, tt.warnings)
+			if buf.String() != tt.want {
+				t.Errorf("warnings section = %q, want %q", buf.String(), tt.want)
+			}
+			if section := string(renderWarningsSection(tt.warnings)); !strings.Contains(section, strings.TrimSpace(tt.want)) {
+				t.Errorf("plain text warnings section = %q, want %q", section, tt.want)
+			}
+		})
+	}
+}
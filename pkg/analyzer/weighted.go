@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// weightWindowDays is the window whose commits weigh each contributor with -weighted-contributors
+const weightWindowDays = 365
+
+// GetAuthorCommitCounts counts the commits each author made to the branch (empty means default) between
+// since and until, keyed by lower-case login after the contributor map is applied
+// Commits whose author has no GitHub account are not attributed to anyone.
+func GetAuthorCommitCounts(repoFullName, branch string, since, until time.Time) (map[string]int, error) {
+	endpoint := commitsEndpoint(repoFullName, branch)
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+
+	out, err := runGH("api",
+		fmt.Sprintf("%s%ssince=%s&until=%s&per_page=100", endpoint, separator,
+			url.QueryEscape(since.UTC().Format(time.RFC3339)), url.QueryEscape(until.UTC().Format(time.RFC3339))),
+		"--paginate", "--jq", ".[].author.login // empty")
+	if err != nil {
+		return nil, fmt.Errorf("failed to count commits per author: %w", err)
+	}
+
+	// One login per commit, each mapped on its own so that merged aliases add up
+	counts := make(map[string]int)
+	for _, login := range parseLogins(string(out)) {
+		if mapped := contributorMap.Apply([]string{login}); len(mapped) == 1 {
+			counts[strings.ToLower(mapped[0])]++
+		}
+	}
+	return counts, nil
+}
+
+// weightedInactiveRatio returns the share of the commits made by the inactive contributors among those
+// made by all contributors, or false when none of them committed in the window
+func weightedInactiveRatio(counts map[string]int, active, inactive []string) (float64, bool) {
+	sum := func(logins []string) int {
+		total := 0
+		for _, login := range logins {
+			total += counts[strings.ToLower(login)]
+		}
+		return total
+	}
+
+	inactiveCommits := sum(inactive)
+	total := inactiveCommits + sum(active)
+	if total == 0 {
+		return 0, false
+	}
+	return float64(inactiveCommits) / float64(total), true
+}
+
+// inactiveRatio returns the inactive contributor ratio a repository is judged on: weighted by commits
+// when contributors are weighted and any of them committed in the window, and by head count otherwise
+func inactiveRatio(r Repository, cfg config.Config) float64 {
+	if cfg.WeightedContributors && r.WeightedInactivePercentage != nil {
+		return *r.WeightedInactivePercentage
+	}
+	return r.InactivePercentage
+}
@@ -0,0 +1,131 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+func TestWeightedInactiveRatio(t *testing.T) {
+	// One prolific maintainer and three drive-by contributors
+	counts := map[string]int{"ann": 200, "bob": 1, "cy": 1, "dee": 1}
+
+	tests := []struct {
+		name           string
+		active         []string
+		inactive       []string
+		wantUnweighted float64
+		wantWeighted   float64
+		wantOK         bool
+	}{
+		{"drive-by contributors left", []string{"ann"}, []string{"bob", "cy", "dee"}, 0.75, 3.0 / 203, true},
+		{"maintainer left", []string{"bob", "cy", "dee"}, []string{"ann"}, 0.25, 200.0 / 203, true},
+		{"logins matched case-insensitively", []string{"Bob"}, []string{"ANN"}, 0.5, 200.0 / 201, true},
+		{"contributors without commits in the window", []string{"eve"}, []string{"fay"}, 0.5, 0, false},
+		{"nobody left", []string{"ann", "bob"}, nil, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unweighted := float64(len(tt.inactive)) / float64(len(tt.active)+len(tt.inactive))
+			if unweighted != tt.wantUnweighted {
+				t.Errorf("unweighted ratio = %v, want %v", unweighted, tt.wantUnweighted)
+			}
+			got, ok := weightedInactiveRatio(counts, tt.active, tt.inactive)
+			if ok != tt.wantOK || math.Abs(got-tt.wantWeighted) > 1e-9 {
+				t.Errorf("weightedInactiveRatio = %v (%v), want %v (%v)", got, ok, tt.wantWeighted, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGetAuthorCommitCounts(t *testing.T) {
+	// One login per commit; the commit by an author without an account prints nothing
+	logPath := fakeGH(t, `printf 'ann\nAnn\nbob\n\nann-laptop\nrelease-bot\n'`)
+	SetCacheTTL(0)
+	m, err := parseContributorMap([]byte(testContributorMap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetContributorMap(m)
+	t.Cleanup(func() { SetContributorMap(nil) })
+
+	counts, err := GetAuthorCommitCounts("o/r", "dev", testNow.AddDate(0, 0, -weightWindowDays), testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]int{"ann": 3, "bob": 1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("counts = %v, want %v", counts, want)
+	}
+	call := ghCalls(t, logPath)[0]
+	if !strings.HasPrefix(call, "api repos/o/r/commits?sha=dev&since=2024-06-01T00%3A00%3A00Z&until=2025-06-01T00%3A00%3A00Z&per_page=100 --paginate") {
+		t.Errorf("call %q does not page through the last year of commits on the branch", call)
+	}
+
+	fakeGH(t, `echo 'gh: Server Error (HTTP 500)' >&2; exit 1`)
+	if _, err := GetAuthorCommitCounts("o/r", "", testNow.AddDate(0, 0, -weightWindowDays), testNow); err == nil {
+		t.Error("GetAuthorCommitCounts succeeded on a failed call")
+	}
+}
+
+func TestAnalyzeRepositoryWeighted(t *testing.T) {
+	// ann is the only member left and made 200 of the 203 commits of the last year
+	script := `case "$*" in
+*since=*) yes ann | head -n 200; printf 'bob\ncy\ndee\n';;
+*user/memberships*) echo '{"state":"active"}';;
+*contributors*) printf 'ann\nbob\ncy\ndee\n';;
+*members/ann*) exit 0;;
+*members/*) echo 'gh: Not Found (HTTP 404)' >&2; exit 1;;
+*commits*) echo ` + testDaysAgo(400).Format(time.RFC3339) + `;;
+"api repos/o/r") echo '{}';;
+*) echo "unexpected call: $*" >&2; exit 1;;
+esac`
+
+	tests := []struct {
+		name         string
+		weighted     bool
+		wantWeighted *float64
+		wantFlagged  bool
+	}{
+		{"head count", false, nil, true},
+		{"weighted by commits", true, floatPtr(3.0 / 203), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pinNow(t)
+			fakeGH(t, script)
+			SetCacheTTL(0)
+			fastMembershipRetries(t)
+
+			cfg := withConfig(flaggingConfig, func(c *config.Config) { c.WeightedContributors = tt.weighted; c.Silent = true })
+			repo, _, err := AnalyzeRepository("o/r", cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The raw ratio is kept alongside the weighted one
+			if repo.InactivePercentage != 0.75 {
+				t.Errorf("inactive percentage = %v, want 0.75", repo.InactivePercentage)
+			}
+			if (repo.WeightedInactivePercentage == nil) != (tt.wantWeighted == nil) ||
+				repo.WeightedInactivePercentage != nil && math.Abs(*repo.WeightedInactivePercentage-*tt.wantWeighted) > 1e-9 {
+				t.Errorf("weighted inactive percentage = %s, want %s",
+					formatOptionalRatio(repo.WeightedInactivePercentage), formatOptionalRatio(tt.wantWeighted))
+			}
+			if repo.Flagged != tt.wantFlagged {
+				t.Errorf("flagged = %v (%s), want %v", repo.Flagged, repo.FlagReason, tt.wantFlagged)
+			}
+		})
+	}
+}
+
+// formatOptionalRatio renders an optional ratio for failure messages
+func formatOptionalRatio(ratio *float64) string {
+	if ratio == nil {
+		return "nil"
+	}
+	return fmt.Sprint(*ratio)
+}
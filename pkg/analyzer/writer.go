@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ResultWriter serializes line writes from concurrent producers so that every
+// line reaches the underlying writer whole and never interleaved with another
+type ResultWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewResultWriter wraps a writer for use by streaming output formats
+func NewResultWriter(w io.Writer) *ResultWriter {
+	return &ResultWriter{w: w}
+}
+
+// WriteLine writes a single line in one call, adding the trailing newline if missing
+func (rw *ResultWriter) WriteLine(line []byte) error {
+	if !bytes.HasSuffix(line, []byte("\n")) {
+		line = append(line[:len(line):len(line)], '\n')
+	}
+
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if _, err := rw.w.Write(line); err != nil {
+		return fmt.Errorf("failed to write result line: %w", err)
+	}
+	return nil
+}
+
+// WriteJSON encodes a value as a single JSON line
+func (rw *ResultWriter) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result line: %w", err)
+	}
+	return rw.WriteLine(data)
+}
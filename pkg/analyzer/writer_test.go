@@ -0,0 +1,204 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/harekrishnarai/inactivity/pkg/config"
+)
+
+// exclusiveWriter fails the test when two writes overlap, and writes byte by byte to widen the window
+type exclusiveWriter struct {
+	t       *testing.T
+	writing int32
+	buf     bytes.Buffer
+}
+
+func (w *exclusiveWriter) Write(p []byte) (int, error) {
+	if !atomic.CompareAndSwapInt32(&w.writing, 0, 1) {
+		w.t.Error("concurrent writes to the underlying writer")
+		return 0, fmt.Errorf("concurrent write")
+	}
+	defer atomic.StoreInt32(&w.writing, 0)
+	for _, b := range p {
+		w.buf.WriteByte(b)
+	}
+	return len(p), nil
+}
+
+// writeConcurrently runs write for each of n producers at once
+func writeConcurrently(n int, write func(i int) error) error {
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := write(i); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+func TestResultWriterConcurrentLines(t *testing.T) {
+	const producers = 50
+	tests := []struct {
+		name  string
+		write func(rw *ResultWriter, i int) error
+		want  func(i int) string
+	}{
+		{
+			name: "lines",
+			write: func(rw *ResultWriter, i int) error {
+				return rw.WriteLine([]byte(fmt.Sprintf("line-%d,%s", i, strings.Repeat("x", 200))))
+			},
+			want: func(i int) string { return fmt.Sprintf("line-%d,%s", i, strings.Repeat("x", 200)) },
+		},
+		{
+			name:  "lines with newline",
+			write: func(rw *ResultWriter, i int) error { return rw.WriteLine([]byte(fmt.Sprintf("line-%d\n", i))) },
+			want:  func(i int) string { return fmt.Sprintf("line-%d", i) },
+		},
+		{
+			name:  "json",
+			write: func(rw *ResultWriter, i int) error { return rw.WriteJSON(map[string]int{"n": i}) },
+			want:  func(i int) string { return fmt.Sprintf(`{"n":%d}`, i) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &exclusiveWriter{t: t}
+			rw := NewResultWriter(w)
+			if err := writeConcurrently(producers, func(i int) error { return tt.write(rw, i) }); err != nil {
+				t.Fatal(err)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(w.buf.String(), "\n"), "\n")
+			if len(lines) != producers {
+				t.Fatalf("got %d lines, want %d", len(lines), producers)
+			}
+			want := make(map[string]bool, producers)
+			for i := 0; i < producers; i++ {
+				want[tt.want(i)] = true
+			}
+			for _, line := range lines {
+				if !want[line] {
+					t.Errorf("unexpected or interleaved line %q", line)
+				}
+				delete(want, line)
+			}
+		})
+	}
+}
+
+func TestNDJSONStreamConcurrentRepositories(t *testing.T) {
+	const repos = 40
+	w := &exclusiveWriter{t: t}
+	stream := NewNDJSONStream(NewResultWriter(w))
+
+	if err := stream.WriteMeta(repos, "o"); err != nil {
+		t.Fatal(err)
+	}
+	err := writeConcurrently(repos, func(i int) error {
+		return stream.WriteRepository(Repository{Name: fmt.Sprintf("o/r%d", i), Flagged: i%2 == 0})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.WriteSummary(); err != nil {
+		t.Fatal(err)
+	}
+
+	var types []string
+	scanner := bufio.NewScanner(&w.buf)
+	for scanner.Scan() {
+		var line struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		types = append(types, line.Type)
+		if line.Type != "summary" {
+			continue
+		}
+		var summary ndjsonSummary
+		if err := json.Unmarshal(scanner.Bytes(), &summary); err != nil {
+			t.Fatal(err)
+		}
+		if summary.Total != repos || summary.Flagged != repos/2 {
+			t.Errorf("summary counts %d repositories and %d flagged, want %d and %d", summary.Total, summary.Flagged, repos, repos/2)
+		}
+	}
+	if len(types) != repos+2 || types[0] != "meta" || types[len(types)-1] != "summary" {
+		t.Errorf("line types = %q, want meta, %d repositories, then summary", types, repos)
+	}
+}
+
+func TestOutputStreamConcurrentRepositories(t *testing.T) {
+	const repos = 30
+	tests := []struct {
+		format string
+		header string
+		lines  int
+	}{
+		{"csv", "Repository Name,", repos + 1},
+		{"ndjson", `{"type":"meta","total":30`, repos + 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "report."+tt.format)
+			cfg := config.Config{OutputFile: path, OutputFormat: tt.format, StreamOutput: true}
+			if err := StartOutputStream(repos, nil, cfg); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() {
+				CloseOutputStream()
+				activeStream.mu.Lock()
+				activeStream.streamed = false
+				activeStream.mu.Unlock()
+			})
+
+			err := writeConcurrently(repos, func(i int) error {
+				return StreamRepository(Repository{Name: fmt.Sprintf("o/r%d", i)})
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := CloseOutputStream(); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+			if len(lines) != tt.lines || !strings.HasPrefix(lines[0], tt.header) {
+				t.Fatalf("stream has %d lines starting with %q, want %d starting with %q", len(lines), lines[0], tt.lines, tt.header)
+			}
+			seen := make(map[string]bool)
+			for _, line := range lines[1 : repos+1] {
+				name := strings.TrimPrefix(line, `{"type":"repo","name":"`)
+				name, _, _ = strings.Cut(name, `"`)
+				name, _, _ = strings.Cut(name, ",")
+				if seen[name] || !strings.HasPrefix(name, "o/r") {
+					t.Errorf("unexpected or duplicated line %q", line)
+				}
+				seen[name] = true
+			}
+		})
+	}
+}
@@ -1,5 +1,11 @@
 package config
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 // Config holds the configuration for the inactivity analyzer
 type Config struct {
 	// Organization to analyze
@@ -8,21 +14,541 @@ type Config struct {
 	// SingleRepository is the name of a single repository to analyze (org/repo format)
 	SingleRepository string // Single repository name to analyze
 
+	// Branch is the branch whose commits are analyzed (empty means the default branch)
+	Branch string
+
+	// Repositories lists every repository given to the repo command (org/repo format)
+	Repositories []string
+
+	// ForksOf is the repository whose forks the forks command analyzes (org/repo format)
+	ForksOf string
+
 	// RepoListFile is the path to a file containing repository URLs to analyze
 	RepoListFile string // Path to a file with repository URLs
 
+	// LocalRepository is a local git clone analyzed with git log instead of the GitHub API
+	LocalRepository string
+
+	// InputFormat is the format of the repository list file: lines or csv (empty detects it by extension)
+	InputFormat string
+
+	// RepoColumn is the CSV column holding the repository, by header name or 1-based index
+	RepoColumn string
+
+	// ExtraColumns are CSV columns carried through to the report
+	ExtraColumns []string
+
 	// MaxCommitAgeInDays is the maximum age of last commit in days
 	MaxCommitAgeInDays int // Maximum age of last commit in days
 
+	// ContributorDays is the inactivity window of the org contributor scope (0 means MaxCommitAgeInDays)
+	ContributorDays int
+
 	// InactiveContribThreshold is the threshold percentage of inactive contributors (0.0-1.0)
 	InactiveContribThreshold float64 // Threshold of inactive contributors (0.0-1.0)
 
 	// OutputFormat is the format of the output (console or json)
 	OutputFormat string // Output format (console, json, csv)
 
+	// MarkdownStyle selects the Markdown report layout: table or details
+	MarkdownStyle string
+
+	// GroupByReason sections flagged repositories by flag reason in text and Markdown reports
+	GroupByReason bool
+
+	// DetectDuplicates reports flagged repositories whose names suggest copies of the same project
+	DetectDuplicates bool
+
 	// OutputFile is the path to the output file (optional)
 	OutputFile string // Output file path (optional)
 
+	// StreamOutput writes each CSV or NDJSON row to OutputFile as soon as it is analyzed
+	StreamOutput bool
+
+	// Redact replaces organization, repository, and user names in reports with stable pseudonyms
+	Redact bool
+
+	// RedactMap is the file mapping pseudonyms back to names
+	RedactMap string
+
 	// Silent is whether to suppress non-essential output
 	Silent bool // Whether to suppress non-essential output
+
+	// Heartbeat is how often progress is logged when the log is not a terminal (0 disables)
+	Heartbeat time.Duration
+
+	// Deadline bounds the total run time, reporting the repositories analyzed so far (0 disables)
+	Deadline time.Duration
+
+	// LogFile receives progress, warnings, and skip notices instead of the terminal (optional)
+	LogFile string
+
+	// CPUProfile receives a pprof CPU profile of the run (optional)
+	CPUProfile string
+
+	// Trace receives a runtime execution trace of the run (optional)
+	Trace string
+
+	// Banner selects how much of the start-up banner is shown: full, minimal, or none
+	Banner string
+
+	// Humanize renders ages as relative times in human-readable output
+	Humanize bool
+
+	// AsOf pins the time the analysis is made at (zero means now)
+	AsOf time.Time
+
+	// Strict aborts on the first per-repository error or warning instead of skipping it
+	Strict bool
+
+	// Concurrency is how many repositories are analyzed at a time
+	Concurrency int
+
+	// Visibility restricts an organization scan to public, private, or all repositories
+	Visibility string
+
+	// Path scopes the commit queries to a file path or subdirectory (optional)
+	Path string
+
+	// PathContributors counts only the authors of commits under Path as contributors
+	PathContributors bool
+
+	// ContributorScope decides where contributor activity is measured: repo or org
+	ContributorScope string
+
+	// API selects how org membership is checked: rest, graphql, or members
+	API string
+
+	// Metrics selects the per-repository metrics to collect (empty means commits and contributors)
+	Metrics []string
+
+	// SubstantiveCommits reports the last commit that is neither a merge nor made by a bot
+	SubstantiveCommits bool
+
+	// FlagOnSubstantiveCommit measures repository age from the last substantive commit
+	FlagOnSubstantiveCommit bool
+
+	// SignedCommits reports the share of recent commits with a verified signature
+	SignedCommits bool
+
+	// MinSignedRatio marks flagged repositories signing fewer recent commits for security review (0 disables)
+	MinSignedRatio float64
+
+	// MembershipFallback decides how hidden private membership is handled: unknown or public
+	MembershipFallback string
+
+	// CheckSuspended counts contributors with a suspended account as inactive
+	CheckSuspended bool
+
+	// ContributorDetails lists each inactive contributor with their last commit
+	ContributorDetails bool
+
+	// ContributorMap is a file merging contributor aliases and dropping service accounts (optional)
+	ContributorMap string
+
+	// GroupContributors groups inactive contributors in console output by when they last committed
+	GroupContributors bool
+
+	// ContributorReport is a file receiving one row per contributor across the analyzed repositories (optional)
+	ContributorReport string
+
+	// MinContributors exempts repositories with fewer contributors from flagging (0 disables)
+	MinContributors int
+
+	// MinInactiveCount is the fewest inactive contributors flagged on the inactive contributor criterion (0 disables)
+	MinInactiveCount int
+
+	// MinRepoAgeDays exempts repositories created fewer days ago from flagging (0 disables)
+	MinRepoAgeDays int
+
+	// DropSmallRepos removes repositories below MinContributors from the report
+	DropSmallRepos bool
+
+	// MinContributorsIncludeArchived applies MinContributors to archived repositories too
+	MinContributorsIncludeArchived bool
+
+	// Top limits the listed repositories to the most inactive ones (0 lists all)
+	Top int
+
+	// Fields restricts JSON and CSV output to these JSON field names (empty means all)
+	Fields []string
+
+	// RepoListCache saves an organization's repository names between runs (optional)
+	RepoListCache string
+
+	// RepoListCacheTTL is how long the cached repository list is reused
+	RepoListCacheTTL time.Duration
+
+	// RefreshRepoList re-fetches the repository list even if the cache is fresh
+	RefreshRepoList bool
+
+	// RepoCache saves each repository's data until it is pushed to again (optional)
+	RepoCache string
+
+	// RepoCacheContributorTTL is how long cached contributor data is reused before membership is checked again (0 reuses it until the next push)
+	RepoCacheContributorTTL time.Duration
+
+	// StateFile records per-repository metrics between runs to show deltas (optional)
+	StateFile string
+
+	// AdminOf restricts an organization scan to repositories this user administers (optional)
+	AdminOf string
+
+	// ShowAdmins lists the admins of flagged repositories
+	ShowAdmins bool
+
+	// OwnersMap is a file mapping repositories and teams to the contact for them (optional)
+	OwnersMap string
+
+	// AbortOnInsufficientQuota aborts before scanning when the API quota looks too low
+	AbortOnInsufficientQuota bool
+
+	// GHPath is the GitHub CLI binary to run instead of the gh in PATH (optional)
+	GHPath string
+
+	// CacheTTL is how long read-only API responses are cached (0 disables caching)
+	CacheTTL time.Duration
+
+	// ProgressFD is the file descriptor receiving JSON progress events (0 disables)
+	ProgressFD int
+
+	// PrioritizeUnlicensed marks flagged repositories without a license as high priority
+	PrioritizeUnlicensed bool
+
+	// Readme reports whether each repository has a README and its size
+	Readme bool
+
+	// PrioritizeUndocumented marks flagged repositories without a README as high priority
+	PrioritizeUndocumented bool
+
+	// CIStatus reports the status and date of the latest GitHub Actions run
+	CIStatus bool
+
+	// FlagBrokenCI flags old repositories whose CI is absent, failing, or stale
+	FlagBrokenCI bool
+
+	// MergedPRs reports when the last pull request was merged
+	MergedPRs bool
+
+	// FlagStaleReviews flags repositories whose last merged pull request is old
+	FlagStaleReviews bool
+
+	// RecentTags lets a recent tag keep a repository from counting as old
+	RecentTags bool
+
+	// ActiveBranches lets a recent commit on another branch keep a repository from counting as old
+	ActiveBranches bool
+
+	// Engagement measures the median time to the first maintainer response on recent issues
+	Engagement bool
+
+	// FlagUnresponsive flags repositories whose maintainers stopped answering issues
+	FlagUnresponsive bool
+
+	// MaxResponseHours is the longest acceptable median time to first response, in hours
+	MaxResponseHours float64
+
+	// Momentum reports the change in commit count between the last two 90-day windows
+	Momentum bool
+
+	// FlagDeclining flags repositories whose momentum dropped by at least DecliningMomentum commits
+	FlagDeclining bool
+
+	// DecliningMomentum is the smallest drop in commits flagged as declining
+	DecliningMomentum int
+
+	// Cadence reports the median gap between consecutive recent commits
+	Cadence bool
+
+	// FlagOnCadence raises each repository's age threshold to CadenceMultiplier times its median commit gap
+	FlagOnCadence bool
+
+	// CadenceMultiplier is the multiple of the median commit gap a repository may go without commits
+	CadenceMultiplier float64
+
+	// BotPRs counts open pull requests opened by bots more than MaxCommitAgeInDays ago
+	BotPRs bool
+
+	// FlagStaleBotPRs flags repositories with more than MaxStaleBotPRs stale bot pull requests
+	FlagStaleBotPRs bool
+
+	// MaxStaleBotPRs is the most stale open bot pull requests tolerated
+	MaxStaleBotPRs int
+
+	// Security counts open Dependabot alerts and marks flagged repositories carrying them as urgent
+	Security bool
+
+	// Governance factors governance settings such as disabled issues into flagging
+	Governance bool
+
+	// WeightedContributors weighs each contributor by their commits in the last year
+	WeightedContributors bool
+
+	// Lifecycle assigns each repository a lifecycle stage and breaks the summaries down by stage
+	Lifecycle bool
+
+	// LifecycleActiveDays is the most days since the last commit of an active repository
+	LifecycleActiveDays int
+
+	// LifecycleStaleDays is the age past which a repository is stale (0 means MaxCommitAgeInDays)
+	LifecycleStaleDays int
+
+	// LifecycleAbandonedDays is the age past which a repository is abandoned
+	LifecycleAbandonedDays int
+
+	// ExecHook is a shell command run after analysis with the JSON report on its stdin (optional)
+	ExecHook string
+
+	// EmailTo is a comma-separated list of recipients for the emailed report (optional)
+	EmailTo string
+
+	// EmailFrom is the sender address of the emailed report
+	EmailFrom string
+
+	// SMTPHost is the SMTP server used to send the report
+	SMTPHost string
+
+	// SMTPPort is the SMTP server port (465 uses implicit TLS)
+	SMTPPort int
+
+	// SMTPUsername is the SMTP login (optional)
+	SMTPUsername string
+
+	// SMTPPassword is the SMTP password, read from SMTP_PASSWORD when not given as a flag
+	SMTPPassword string
+
+	// AppID is the GitHub App ID used to generate an installation token (optional)
+	AppID int64
+
+	// InstallationID is the GitHub App installation ID
+	InstallationID int64
+
+	// PrivateKeyFile is the path to the GitHub App private key in PEM format
+	PrivateKeyFile string
+
+	// CredentialsFile maps repository owners to the tokens used for them (optional)
+	CredentialsFile string
+
+	// EmitScript replaces the report with a script applying an action to the flagged repositories (optional)
+	EmitScript string
+
+	// PublishStatus publishes each repository's result as a check run or commit status
+	PublishStatus bool
+
+	// TrackingIssueRepo is the repository holding the checklist issue of flagged repositories (optional)
+	TrackingIssueRepo string
+
+	// UpdateComment is the issue comment replaced with the Markdown report on every run (optional)
+	UpdateComment string
+
+	// DryRun logs the changes that would be made to repositories without making them
+	DryRun bool
+
+	// DumpConfig prints the effective configuration as JSON and exits
+	DumpConfig bool
+}
+
+// redacted replaces secret values in a dumped configuration
+const redacted = "REDACTED"
+
+// Redacted returns a copy of the configuration with secrets replaced, safe to print or embed in reports
+func (c Config) Redacted() Config {
+	if c.SMTPPassword != "" {
+		c.SMTPPassword = redacted
+	}
+	return c
+}
+
+// ContributorWindowDays returns the contributor recency window, defaulting to the commit age threshold
+func (c Config) ContributorWindowDays() int {
+	if c.ContributorDays > 0 {
+		return c.ContributorDays
+	}
+	return c.MaxCommitAgeInDays
+}
+
+// LifecycleStaleAfterDays returns the days since the last commit past which a repository is stale
+func (c Config) LifecycleStaleAfterDays() int {
+	if c.LifecycleStaleDays > 0 {
+		return c.LifecycleStaleDays
+	}
+	return c.MaxCommitAgeInDays
+}
+
+// Validate checks that the configuration values are consistent
+func (c Config) Validate() error {
+	if c.ContributorScope != "" && c.ContributorScope != "repo" && c.ContributorScope != "org" {
+		return fmt.Errorf("invalid contributor scope %q, expected repo or org", c.ContributorScope)
+	}
+
+	if c.PathContributors && c.Path == "" {
+		return fmt.Errorf("path contributors require a path")
+	}
+
+	if c.API != "" && c.API != "rest" && c.API != "graphql" && c.API != "members" {
+		return fmt.Errorf("invalid API %q, expected rest, graphql, or members", c.API)
+	}
+
+	if c.EmailTo != "" && (c.SMTPHost == "" || c.EmailFrom == "") {
+		return fmt.Errorf("-smtp-host and -email-from are required with -email-to")
+	}
+
+	if c.RepoListCacheTTL < 0 {
+		return fmt.Errorf("invalid repository list TTL %s, expected 0 or more", c.RepoListCacheTTL)
+	}
+	if c.RepoCacheContributorTTL < 0 {
+		return fmt.Errorf("invalid contributor cache TTL %s, expected 0 or more", c.RepoCacheContributorTTL)
+	}
+
+	if c.Heartbeat < 0 {
+		return fmt.Errorf("invalid heartbeat %s, expected 0 or more", c.Heartbeat)
+	}
+	if c.Deadline < 0 {
+		return fmt.Errorf("invalid deadline %s, expected 0 or more", c.Deadline)
+	}
+
+	if c.CacheTTL < 0 {
+		return fmt.Errorf("invalid cache TTL %s, expected 0 or more", c.CacheTTL)
+	}
+
+	if c.MinRepoAgeDays < 0 {
+		return fmt.Errorf("invalid minimum repository age %d, expected 0 or more", c.MinRepoAgeDays)
+	}
+
+	if c.ContributorDays < 0 {
+		return fmt.Errorf("invalid contributor days %d, expected 0 or more", c.ContributorDays)
+	}
+
+	if c.Top < 0 {
+		return fmt.Errorf("invalid top %d, expected 0 or more", c.Top)
+	}
+
+	if c.MinContributors < 0 {
+		return fmt.Errorf("invalid minimum contributors %d, expected 0 or more", c.MinContributors)
+	}
+
+	if c.MinInactiveCount < 0 {
+		return fmt.Errorf("invalid minimum inactive count %d, expected 0 or more", c.MinInactiveCount)
+	}
+
+	if c.FlagUnresponsive && c.MaxResponseHours <= 0 {
+		return fmt.Errorf("invalid maximum response hours %g, expected more than 0", c.MaxResponseHours)
+	}
+
+	if c.FlagDeclining && c.DecliningMomentum < 1 {
+		return fmt.Errorf("invalid declining momentum %d, expected 1 or more", c.DecliningMomentum)
+	}
+	if c.FlagOnCadence && c.CadenceMultiplier <= 0 {
+		return fmt.Errorf("invalid cadence multiplier %g, expected more than 0", c.CadenceMultiplier)
+	}
+	if c.MaxStaleBotPRs < 0 {
+		return fmt.Errorf("invalid maximum stale bot pull requests %d, expected 0 or more", c.MaxStaleBotPRs)
+	}
+
+	if c.Lifecycle {
+		if c.LifecycleActiveDays < 0 || c.LifecycleStaleDays < 0 {
+			return fmt.Errorf("invalid lifecycle thresholds, expected 0 or more days")
+		}
+		if stale := c.LifecycleStaleAfterDays(); c.LifecycleActiveDays >= stale || stale >= c.LifecycleAbandonedDays {
+			return fmt.Errorf("invalid lifecycle thresholds (active %d, stale %d, abandoned %d days), expected each larger than the last",
+				c.LifecycleActiveDays, stale, c.LifecycleAbandonedDays)
+		}
+	}
+
+	if c.StreamOutput {
+		if c.OutputFormat != "csv" && c.OutputFormat != "ndjson" {
+			return fmt.Errorf("stream output needs the csv or ndjson format, got %q", c.OutputFormat)
+		}
+		if c.OutputFile == "" || strings.Contains(c.OutputFile, "://") {
+			return fmt.Errorf("stream output needs a local output file")
+		}
+		if c.EmitScript != "" {
+			return fmt.Errorf("stream output cannot be combined with an emitted script")
+		}
+	}
+
+	if c.Redact {
+		if c.RedactMap == "" || strings.Contains(c.RedactMap, "://") {
+			return fmt.Errorf("redaction needs a local mapping file")
+		}
+		if c.EmitScript != "" {
+			return fmt.Errorf("redaction cannot be combined with an emitted script, whose commands need the real names")
+		}
+	}
+
+	// A local clone is analyzed without the GitHub API, so the options acting through it cannot apply
+	if c.LocalRepository != "" {
+		switch {
+		case c.TrackingIssueRepo != "":
+			return fmt.Errorf("-create-tracking-issue needs the GitHub API, which the local command does not use")
+		case c.UpdateComment != "":
+			return fmt.Errorf("-update-comment needs the GitHub API, which the local command does not use")
+		case c.PublishStatus:
+			return fmt.Errorf("-publish-status needs the GitHub API, which the local command does not use")
+		case c.AbortOnInsufficientQuota:
+			return fmt.Errorf("-abort-on-insufficient-quota needs the GitHub API, which the local command does not use")
+		}
+	}
+
+	if c.ForksOf != "" {
+		if parts := strings.Split(c.ForksOf, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid parent repository %q, expected org/repo", c.ForksOf)
+		}
+	}
+
+	if c.TrackingIssueRepo != "" {
+		if parts := strings.Split(c.TrackingIssueRepo, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid tracking issue repository %q, expected org/repo", c.TrackingIssueRepo)
+		}
+	}
+
+	if c.AsOf.After(time.Now()) {
+		return fmt.Errorf("invalid as-of time %s, expected a time in the past", c.AsOf.Format(time.RFC3339))
+	}
+
+	if c.Concurrency < 0 {
+		return fmt.Errorf("invalid concurrency %d, expected 1 or more", c.Concurrency)
+	}
+
+	if c.MinSignedRatio < 0 || c.MinSignedRatio > 1 {
+		return fmt.Errorf("invalid minimum signed commit ratio %g, expected 0.0-1.0", c.MinSignedRatio)
+	}
+
+	switch c.InputFormat {
+	case "", "lines", "csv":
+	default:
+		return fmt.Errorf("invalid input format %q, expected lines or csv", c.InputFormat)
+	}
+
+	switch c.MembershipFallback {
+	case "", "unknown", "public":
+	default:
+		return fmt.Errorf("invalid membership fallback %q, expected unknown or public", c.MembershipFallback)
+	}
+
+	switch c.Banner {
+	case "", "full", "minimal", "none":
+	default:
+		return fmt.Errorf("invalid banner %q, expected full, minimal, or none", c.Banner)
+	}
+
+	switch c.MarkdownStyle {
+	case "", "table", "details":
+	default:
+		return fmt.Errorf("invalid markdown style %q, expected table or details", c.MarkdownStyle)
+	}
+
+	switch c.EmitScript {
+	case "", "archive", "delete", "transfer":
+	default:
+		return fmt.Errorf("invalid script action %q, expected archive, delete, or transfer", c.EmitScript)
+	}
+
+	switch c.Visibility {
+	case "", "all", "public", "private":
+	default:
+		return fmt.Errorf("invalid visibility %q, expected public, private, or all", c.Visibility)
+	}
+
+	return nil
 }
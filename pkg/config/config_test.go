@@ -0,0 +1,116 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// valid is a configuration Validate accepts, which the test cases change one option at a time
+var valid = Config{Organization: "o", MaxCommitAgeInDays: 180, InactiveContribThreshold: 0.5}
+
+func with(change func(*Config)) Config {
+	c := valid
+	change(&c)
+	return c
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"valid", valid, ""},
+		{"email", with(func(c *Config) { c.EmailTo = "a@example.com"; c.SMTPHost = "smtp"; c.EmailFrom = "bot@example.com" }), ""},
+		{"email without host", with(func(c *Config) { c.EmailTo = "a@example.com"; c.EmailFrom = "bot@example.com" }), "-smtp-host and -email-from are required"},
+		{"email without sender", with(func(c *Config) { c.EmailTo = "a@example.com"; c.SMTPHost = "smtp" }), "-smtp-host and -email-from are required"},
+		{"local", with(func(c *Config) { c.LocalRepository = "." }), ""},
+		{"local tracking issue", with(func(c *Config) { c.LocalRepository = "."; c.TrackingIssueRepo = "o/r" }), "-create-tracking-issue needs the GitHub API"},
+		{"local comment", with(func(c *Config) { c.LocalRepository = "."; c.UpdateComment = "o/r#1" }), "-update-comment needs the GitHub API"},
+		{"local status", with(func(c *Config) { c.LocalRepository = "."; c.PublishStatus = true }), "-publish-status needs the GitHub API"},
+		{"local quota abort", with(func(c *Config) { c.LocalRepository = "."; c.AbortOnInsufficientQuota = true }), "-abort-on-insufficient-quota needs the GitHub API"},
+		{"contributor scope", with(func(c *Config) { c.ContributorScope = "team" }), "invalid contributor scope"},
+		{"path contributors without path", with(func(c *Config) { c.PathContributors = true }), "path contributors require a path"},
+		{"api", with(func(c *Config) { c.API = "soap" }), "invalid API"},
+		{"negative deadline", with(func(c *Config) { c.Deadline = -time.Second }), "invalid deadline"},
+		{"lifecycle", with(func(c *Config) { c.Lifecycle = true; c.LifecycleActiveDays = 30; c.LifecycleAbandonedDays = 365 }), ""},
+		{"lifecycle out of order", with(func(c *Config) { c.Lifecycle = true; c.LifecycleActiveDays = 200; c.LifecycleAbandonedDays = 365 }), "invalid lifecycle thresholds"},
+		{"stream json", with(func(c *Config) { c.StreamOutput = true; c.OutputFormat = "json"; c.OutputFile = "r.json" }), "stream output needs the csv or ndjson format"},
+		{"stream remote", with(func(c *Config) { c.StreamOutput = true; c.OutputFormat = "csv"; c.OutputFile = "s3://b/r.csv" }), "stream output needs a local output file"},
+		{"redact without map", with(func(c *Config) { c.Redact = true }), "redaction needs a local mapping file"},
+		{"redact with script", with(func(c *Config) { c.Redact = true; c.RedactMap = "map.json"; c.EmitScript = "archive" }), "redaction cannot be combined"},
+		{"forks of", with(func(c *Config) { c.ForksOf = "o" }), "invalid parent repository"},
+		{"tracking issue repository", with(func(c *Config) { c.TrackingIssueRepo = "o/r/x" }), "invalid tracking issue repository"},
+		{"as of in the future", with(func(c *Config) { c.AsOf = time.Now().Add(time.Hour) }), "invalid as-of time"},
+		{"signed ratio", with(func(c *Config) { c.MinSignedRatio = 1.5 }), "invalid minimum signed commit ratio"},
+		{"input format", with(func(c *Config) { c.InputFormat = "tsv" }), "invalid input format"},
+		{"banner", with(func(c *Config) { c.Banner = "loud" }), "invalid banner"},
+		{"script action", with(func(c *Config) { c.EmitScript = "rename" }), "invalid script action"},
+		{"visibility", with(func(c *Config) { c.Visibility = "internal" }), "invalid visibility"},
+		{"contributor cache TTL", with(func(c *Config) { c.RepoCacheContributorTTL = -time.Hour }), "invalid contributor cache TTL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	c := with(func(c *Config) { c.SMTPPassword = "secret"; c.SMTPUsername = "bot" })
+	r := c.Redacted()
+	if r.SMTPPassword != redacted || r.SMTPUsername != "bot" {
+		t.Errorf("redacted credentials %q and %q, want %q and bot", r.SMTPUsername, r.SMTPPassword, redacted)
+	}
+	if c.SMTPPassword != "secret" {
+		t.Error("Redacted changed the original configuration")
+	}
+	if got := valid.Redacted().SMTPPassword; got != "" {
+		t.Errorf("unset password redacted to %q", got)
+	}
+}
+
+func TestRedactedJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want map[string]any
+	}{
+		{"thresholds", valid, map[string]any{"Organization": "o", "MaxCommitAgeInDays": 180.0, "InactiveContribThreshold": 0.5, "SMTPPassword": ""}},
+		{"password", with(func(c *Config) { c.SMTPPassword = "secret"; c.SMTPUsername = "bot" }),
+			map[string]any{"SMTPUsername": "bot", "SMTPPassword": redacted}},
+		{"merged options", with(func(c *Config) { c.Strict = true; c.CacheTTL = time.Hour }),
+			map[string]any{"Strict": true, "CacheTTL": float64(time.Hour)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.cfg.Redacted())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(data), "secret") {
+				t.Errorf("dumped configuration leaks the password: %s", data)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			for field, want := range tt.want {
+				if got[field] != want {
+					t.Errorf("dumped %s = %v, want %v", field, got[field], want)
+				}
+			}
+		})
+	}
+}
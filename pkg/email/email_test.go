@@ -0,0 +1,245 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+// smtpSession records what a client sent to the mock SMTP server
+type smtpSession struct {
+	auth  string   // decoded AUTH PLAIN credentials
+	from  string   // MAIL FROM argument
+	rcpts []string // RCPT TO arguments
+	data  string   // message sent with DATA
+}
+
+// mockSMTP serves one SMTP session on a local port, rejecting the recipients listed in reject,
+// and returns the port and a channel receiving the session once it ends
+func mockSMTP(t *testing.T, reject ...string) (int, <-chan smtpSession) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var s smtpSession
+		defer func() { sessions <- s }()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+		reply("220 mock ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			verb, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO", "HELO":
+				reply("250-mock")
+				reply("250 AUTH PLAIN")
+			case "AUTH":
+				_, credentials, _ := strings.Cut(arg, " ")
+				decoded, _ := base64.StdEncoding.DecodeString(credentials)
+				s.auth = string(decoded)
+				reply("235 authenticated")
+			case "MAIL":
+				s.from = arg
+				reply("250 ok")
+			case "RCPT":
+				rejected := false
+				for _, r := range reject {
+					rejected = rejected || strings.Contains(arg, r)
+				}
+				if rejected {
+					reply("550 no such user")
+					continue
+				}
+				s.rcpts = append(s.rcpts, arg)
+				reply("250 ok")
+			case "DATA":
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(strings.TrimPrefix(line, "."))
+				}
+				s.data = data.String()
+				reply("250 queued")
+			case "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port, sessions
+}
+
+// messageParts parses a sent message into its headers and the decoded content of each part
+func messageParts(t *testing.T, data string) (mail.Header, []*multipart.Part, [][]byte) {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("message does not parse: %v", err)
+	}
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var parts []*multipart.Part
+	var contents [][]byte
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoded, _ := io.ReadAll(part)
+		content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+		if err != nil {
+			t.Fatalf("part is not base64: %v", err)
+		}
+		parts = append(parts, part)
+		contents = append(contents, content)
+	}
+	return msg.Header, parts, contents
+}
+
+func TestBuildMessage(t *testing.T) {
+	report := bytes.Repeat([]byte(`{"name":"o/r"}`), 20)
+	tests := []struct {
+		name       string
+		attachment *Attachment
+		wantParts  int
+	}{
+		{"body only", nil, 1},
+		{"with attachment", &Attachment{Filename: "inactivity-2025-06-01.json", ContentType: "application/json", Data: report}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := BuildMessage(Message{
+				From:       "bot@example.com",
+				To:         []string{"a@example.com", "b@example.com"},
+				Subject:    "Inactivity report: 3 flagged — acme",
+				Body:       "3 repositories flagged",
+				Attachment: tt.attachment,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, line := range strings.Split(string(data), "\r\n") {
+				if len(line) > 998 {
+					t.Fatalf("line of %d characters exceeds the SMTP limit", len(line))
+				}
+			}
+
+			header, parts, contents := messageParts(t, string(data))
+			subject, err := new(mime.WordDecoder).DecodeHeader(header.Get("Subject"))
+			if err != nil || subject != "Inactivity report: 3 flagged — acme" {
+				t.Errorf("subject = %q (%v)", subject, err)
+			}
+			if header.Get("To") != "a@example.com, b@example.com" {
+				t.Errorf("To = %q", header.Get("To"))
+			}
+			if len(parts) != tt.wantParts {
+				t.Fatalf("got %d parts, want %d", len(parts), tt.wantParts)
+			}
+			if string(contents[0]) != "3 repositories flagged" {
+				t.Errorf("body = %q", contents[0])
+			}
+			if tt.attachment != nil {
+				if parts[1].FileName() != tt.attachment.Filename || parts[1].Header.Get("Content-Type") != tt.attachment.ContentType {
+					t.Errorf("attachment %q of type %q, want %q of type %q", parts[1].FileName(), parts[1].Header.Get("Content-Type"), tt.attachment.Filename, tt.attachment.ContentType)
+				}
+				if !bytes.Equal(contents[1], tt.attachment.Data) {
+					t.Errorf("attachment content differs")
+				}
+			}
+		})
+	}
+}
+
+func TestSend(t *testing.T) {
+	tests := []struct {
+		name      string
+		username  string
+		reject    []string
+		wantAuth  string
+		wantRcpts int
+		wantErr   string
+	}{
+		{"anonymous", "", nil, "", 2, ""},
+		{"authenticated", "bot", nil, "\x00bot\x00secret", 2, ""},
+		{"rejected recipient", "", []string{"b@example.com"}, "", 1, "RCPT TO b@example.com failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port, sessions := mockSMTP(t, tt.reject...)
+			cfg := SMTPConfig{Host: "127.0.0.1", Port: port, Username: tt.username}
+			if tt.username != "" {
+				cfg.Password = "secret"
+			}
+
+			err := Send(cfg, Message{
+				From:    "bot@example.com",
+				To:      []string{"a@example.com", "b@example.com"},
+				Subject: "Inactivity report",
+				Body:    "report attached",
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Send error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			s := <-sessions
+			if s.auth != tt.wantAuth {
+				t.Errorf("credentials %q, want %q", s.auth, tt.wantAuth)
+			}
+			if s.from != "FROM:<bot@example.com>" {
+				t.Errorf("MAIL %s, want FROM:<bot@example.com>", s.from)
+			}
+			if len(s.rcpts) != tt.wantRcpts {
+				t.Errorf("recipients %q, want %d of them", s.rcpts, tt.wantRcpts)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if _, _, contents := messageParts(t, s.data); string(contents[0]) != "report attached" {
+				t.Errorf("delivered body = %q", contents[0])
+			}
+		})
+	}
+}
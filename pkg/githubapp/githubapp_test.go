@@ -0,0 +1,223 @@
+package githubapp
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testKey generates an RSA key for signing test JWTs
+func testKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// verifyJWT checks the RS256 signature of a JWT and returns its claims
+func verifyJWT(t *testing.T, jwt string, key *rsa.PublicKey) map[string]interface{} {
+	t.Helper()
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("JWT %q has %d parts, want 3", jwt, len(parts))
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("failed to decode signature: %v", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		t.Fatalf("JWT signature does not verify: %v", err)
+	}
+
+	var header map[string]string
+	decodeSegment(t, parts[0], &header)
+	if header["alg"] != "RS256" || header["typ"] != "JWT" {
+		t.Errorf("header = %v, want RS256 JWT", header)
+	}
+
+	var claims map[string]interface{}
+	decodeSegment(t, parts[1], &claims)
+	return claims
+}
+
+// decodeSegment decodes a base64url JSON segment of a JWT
+func decodeSegment(t *testing.T, segment string, v interface{}) {
+	t.Helper()
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		t.Fatalf("failed to decode segment: %v", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("failed to parse segment: %v", err)
+	}
+}
+
+func TestSignJWT(t *testing.T) {
+	key := testKey(t)
+	ts := &TokenSource{AppID: 12345, key: key}
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	jwt, err := ts.SignJWT(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := verifyJWT(t, jwt, &key.PublicKey)
+
+	tests := []struct {
+		claim string
+		want  interface{}
+	}{
+		{"iss", "12345"},
+		{"iat", float64(now.Add(-time.Minute).Unix())},
+		{"exp", float64(now.Add(9 * time.Minute).Unix())},
+	}
+	for _, tt := range tests {
+		if got := claims[tt.claim]; got != tt.want {
+			t.Errorf("claim %s = %v, want %v", tt.claim, got, tt.want)
+		}
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key := testKey(t)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{"pkcs1", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), ""},
+		{"pkcs8", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), ""},
+		{"not pem", []byte("not a key"), "not PEM encoded"},
+		{"garbage", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")}), "failed to parse private key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParsePrivateKey(tt.data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !parsed.Equal(key) {
+				t.Error("parsed key differs from the encoded one")
+			}
+		})
+	}
+}
+
+// tokenServer serves the installation token endpoint, verifying the App JWT and answering with the
+// given status and expiry, and counts the exchanges made
+func tokenServer(t *testing.T, key *rsa.PublicKey, status int, expiresIn time.Duration) (*httptest.Server, *int32) {
+	t.Helper()
+	var exchanges int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&exchanges, 1)
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/678/access_tokens" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		jwt, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			t.Errorf("request has no bearer token")
+		}
+		if claims := verifyJWT(t, jwt, key); claims["iss"] != "12345" {
+			t.Errorf("JWT issuer = %v, want the App ID", claims["iss"])
+		}
+
+		w.WriteHeader(status)
+		if status != http.StatusCreated {
+			fmt.Fprint(w, `{"message":"Bad credentials"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"token":"ghs_%d","expires_at":%q}`, n, time.Now().Add(expiresIn).Format(time.RFC3339))
+	}))
+	t.Cleanup(server.Close)
+	return server, &exchanges
+}
+
+func TestTokenExchange(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		expiresIn     time.Duration
+		wantTokens    []string
+		wantExchanges int32
+		wantErr       string
+	}{
+		{"cached until close to expiry", http.StatusCreated, time.Hour, []string{"ghs_1", "ghs_1"}, 1, ""},
+		{"refreshed close to expiry", http.StatusCreated, time.Minute, []string{"ghs_1", "ghs_2"}, 2, ""},
+		{"rejected", http.StatusUnauthorized, time.Hour, nil, 1, "status 401"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := testKey(t)
+			server, exchanges := tokenServer(t, &key.PublicKey, tt.status, tt.expiresIn)
+			ts := &TokenSource{AppID: 12345, InstallationID: 678, BaseURL: server.URL + "/", key: key}
+
+			if tt.wantErr != "" {
+				if _, err := ts.Token(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Token error = %v, want %q", err, tt.wantErr)
+				}
+			}
+			for i, want := range tt.wantTokens {
+				token, err := ts.Token()
+				if err != nil {
+					t.Fatalf("call %d: %v", i+1, err)
+				}
+				if token != want {
+					t.Errorf("call %d token = %q, want %q", i+1, token, want)
+				}
+			}
+			if got := atomic.LoadInt32(exchanges); got != tt.wantExchanges {
+				t.Errorf("made %d exchanges, want %d", got, tt.wantExchanges)
+			}
+		})
+	}
+}
+
+func TestNewTokenSource(t *testing.T) {
+	key := testKey(t)
+	path := filepath.Join(t.TempDir(), "app.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ts, err := NewTokenSource(1, 2, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts.BaseURL != DefaultBaseURL || !ts.key.Equal(key) {
+		t.Errorf("token source = %+v, want the default base URL and the file's key", ts)
+	}
+
+	if _, err := NewTokenSource(1, 2, filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+		t.Error("NewTokenSource succeeded with a missing key file")
+	}
+}
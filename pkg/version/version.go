@@ -0,0 +1,47 @@
+package version
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Build metadata, injected at build time with
+// -ldflags "-X github.com/harekrishnarai/inactivity/pkg/version.Version=v1.2.3 -X ...Commit=abc1234 -X ...BuildDate=2025-01-01"
+var (
+	// Version is the release version of the tool
+	Version = "dev"
+
+	// Commit is the git commit the tool was built from
+	Commit = "unknown"
+
+	// BuildDate is when the tool was built
+	BuildDate = "unknown"
+)
+
+func init() {
+	// Builds without -ldflags, such as go install, still carry the module version and VCS revision
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	if Version == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		Version = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && Commit == "unknown":
+			Commit = setting.Value
+		case setting.Key == "vcs.time" && BuildDate == "unknown":
+			BuildDate = setting.Value
+		}
+	}
+}
+
+// String renders the build metadata, e.g. "inactivity v1.2.3 (commit abc1234, built 2025-01-01)"
+func String() string {
+	commit := Commit
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	return fmt.Sprintf("inactivity %s (commit %s, built %s)", Version, commit, BuildDate)
+}
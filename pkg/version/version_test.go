@@ -0,0 +1,28 @@
+package version
+
+import "testing"
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   string
+		commit    string
+		buildDate string
+		want      string
+	}{
+		{"release", "v1.2.3", "abc1234", "2025-01-01", "inactivity v1.2.3 (commit abc1234, built 2025-01-01)"},
+		{"full commit hash shortened", "v1.2.3", "abc1234def5678", "2025-01-01", "inactivity v1.2.3 (commit abc1234, built 2025-01-01)"},
+		{"development build", "dev", "unknown", "unknown", "inactivity dev (commit unknown, built unknown)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previousVersion, previousCommit, previousBuildDate := Version, Commit, BuildDate
+			t.Cleanup(func() { Version, Commit, BuildDate = previousVersion, previousCommit, previousBuildDate })
+			Version, Commit, BuildDate = tt.version, tt.commit, tt.buildDate
+
+			if got := String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}